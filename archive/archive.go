@@ -0,0 +1,94 @@
+package archive
+
+import (
+	"path/filepath"
+
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/geometry"
+)
+
+const (
+	// PMTilesExt represents the file extension of PMTiles archives. Archives
+	// with any other extension are MBTiles archives.
+	PMTilesExt = ".pmtiles"
+)
+
+// Metadata represents the metadata of a tile archive.
+type Metadata struct {
+	// Name is the name of the tileset.
+	Name string
+	// Format is the format of the tile data, such as `pbf`, `png` or `bin`.
+	Format string
+	// MinZoom and MaxZoom are the inclusive zoom range of the tileset.
+	MinZoom uint32
+	MaxZoom uint32
+	// Bounds are the longitude / latitude bounds of the tileset, with left /
+	// right as longitude and bottom / top as latitude. If nil, the whole
+	// world is covered.
+	Bounds *geometry.Bounds
+	// Extra is any additional metadata to record in the archive.
+	Extra map[string]string
+}
+
+// Writer represents an interface for writing tiles into an archive. Tiles
+// use TMS coordinates, with (0, 0) being at the bottom-left.
+type Writer interface {
+	WriteTile(*binning.TileCoord, []byte) error
+	Close() error
+}
+
+// Reader represents an interface for reading tiles from an archive. Missing
+// tiles are returned as nil without error.
+type Reader interface {
+	ReadTile(*binning.TileCoord) ([]byte, error)
+	Close() error
+}
+
+// NewWriter creates an archive at the provided path, as PMTiles if the path
+// has the `.pmtiles` extension and as MBTiles otherwise.
+func NewWriter(path string, meta *Metadata) (Writer, error) {
+	if isPMTiles(path) {
+		writer, err := NewPMTilesWriter(path, meta)
+		if err != nil {
+			return nil, err
+		}
+		return writer, nil
+	}
+	writer, err := NewMBTilesWriter(path, meta)
+	if err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+// Open opens the archive at the provided path, as PMTiles if the path has the
+// `.pmtiles` extension and as MBTiles otherwise.
+func Open(path string) (Reader, error) {
+	if isPMTiles(path) {
+		reader, err := OpenPMTiles(path)
+		if err != nil {
+			return nil, err
+		}
+		return reader, nil
+	}
+	reader, err := OpenMBTiles(path)
+	if err != nil {
+		return nil, err
+	}
+	return reader, nil
+}
+
+func isPMTiles(path string) bool {
+	return filepath.Ext(path) == PMTilesExt
+}
+
+// getBounds returns the longitude / latitude bounds of the metadata,
+// defaulting to the whole world.
+func getBounds(meta *Metadata) *geometry.Bounds {
+	if meta.Bounds != nil {
+		return meta.Bounds
+	}
+	min := binning.NewLonLat(-180, -90)
+	max := binning.NewLonLat(180, 90)
+	return geometry.NewBounds(min.Lon, max.Lon, min.Lat, max.Lat)
+}
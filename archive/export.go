@@ -0,0 +1,76 @@
+package archive
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+)
+
+// Export generates every tile of the request within the zoom range and bounds
+// of the metadata, and writes them into an archive at the provided path. The
+// request JSON is that of veldt.Pipeline.NewTileRequest, without the `coord`.
+// Tiles are generated through the pipeline store, so previously generated
+// tiles are not regenerated. Empty tiles are omitted from the archive.
+//
+// Ex:
+//     err := archive.Export(pipeline, req, &archive.Metadata{
+//         Name:    "sample",
+//         Format:  "png",
+//         MinZoom: 0,
+//         MaxZoom: 8,
+//     }, "sample.pmtiles")
+//
+func Export(pipeline *veldt.Pipeline, args map[string]interface{}, meta *Metadata, path string) error {
+	if meta.MinZoom > meta.MaxZoom {
+		return fmt.Errorf("min zoom %d is greater than max zoom %d", meta.MinZoom, meta.MaxZoom)
+	}
+	writer, err := NewWriter(path, meta)
+	if err != nil {
+		return err
+	}
+	for _, coord := range GetCoords(meta) {
+		err = exportTile(pipeline, args, coord, writer)
+		if err != nil {
+			writer.Close()
+			return err
+		}
+	}
+	return writer.Close()
+}
+
+func exportTile(pipeline *veldt.Pipeline, args map[string]interface{}, coord *binning.TileCoord, writer Writer) error {
+	// copy the args to add the coord
+	tileArgs := make(map[string]interface{}, len(args)+1)
+	for key, val := range args {
+		tileArgs[key] = val
+	}
+	tileArgs["coord"] = map[string]interface{}{
+		"z": float64(coord.Z),
+		"x": float64(coord.X),
+		"y": float64(coord.Y),
+	}
+	req, err := pipeline.NewTileRequest(tileArgs)
+	if err != nil {
+		return err
+	}
+	data, err := pipeline.GenerateAndGet(req)
+	if err != nil {
+		return err
+	}
+	Debugf("Exported tile %d/%d/%d", coord.Z, coord.X, coord.Y)
+	return writer.WriteTile(coord, data)
+}
+
+// GetCoords returns the TMS coordinates of every tile within the zoom range
+// and bounds of the metadata.
+func GetCoords(meta *Metadata) []*binning.TileCoord {
+	bounds := getBounds(meta)
+	var coords []*binning.TileCoord
+	for z := meta.MinZoom; z <= meta.MaxZoom; z++ {
+		min := binning.LonLatToFractionalTile(binning.NewLonLat(bounds.Left, bounds.Bottom), z)
+		max := binning.LonLatToFractionalTile(binning.NewLonLat(bounds.Right, bounds.Top), z)
+		coords = append(coords, binning.GetTilesInRange(min, max)...)
+	}
+	return coords
+}
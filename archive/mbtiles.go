@@ -0,0 +1,150 @@
+package archive
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"strconv"
+
+	// register the sqlite3 driver
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/unchartedsoftware/veldt/binning"
+)
+
+const (
+	mbtilesSchema = `
+		CREATE TABLE metadata (name text, value text);
+		CREATE TABLE tiles (zoom_level integer, tile_column integer, tile_row integer, tile_data blob);
+		CREATE UNIQUE INDEX tile_index ON tiles (zoom_level, tile_column, tile_row);`
+	mbtilesInsert = `INSERT OR REPLACE INTO tiles (zoom_level, tile_column, tile_row, tile_data) VALUES (?, ?, ?, ?)`
+	mbtilesSelect = `SELECT tile_data FROM tiles WHERE zoom_level = ? AND tile_column = ? AND tile_row = ?`
+)
+
+// MBTilesWriter represents a writer of MBTiles (SQLite) archives. MBTiles use
+// TMS coordinates, so tiles are stored under their coordinate as is.
+type MBTilesWriter struct {
+	db   *sql.DB
+	tx   *sql.Tx
+	stmt *sql.Stmt
+	meta *Metadata
+}
+
+// NewMBTilesWriter creates an MBTiles archive at the provided path, replacing
+// any existing file. Tiles are written in a single transaction committed on
+// close.
+func NewMBTilesWriter(path string, meta *Metadata) (*MBTilesWriter, error) {
+	err := os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+	_, err = db.Exec(mbtilesSchema)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	stmt, err := tx.Prepare(mbtilesInsert)
+	if err != nil {
+		tx.Rollback()
+		db.Close()
+		return nil, err
+	}
+	return &MBTilesWriter{
+		db:   db,
+		tx:   tx,
+		stmt: stmt,
+		meta: meta,
+	}, nil
+}
+
+// WriteTile writes the tile data under the coordinate. Empty tiles are
+// omitted.
+func (w *MBTilesWriter) WriteTile(coord *binning.TileCoord, data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	_, err := w.stmt.Exec(coord.Z, coord.X, coord.Y, data)
+	return err
+}
+
+// Close writes the metadata and commits the archive.
+func (w *MBTilesWriter) Close() error {
+	defer w.db.Close()
+	w.stmt.Close()
+	for name, value := range getMBTilesMetadata(w.meta) {
+		_, err := w.tx.Exec(`INSERT INTO metadata (name, value) VALUES (?, ?)`, name, value)
+		if err != nil {
+			w.tx.Rollback()
+			return err
+		}
+	}
+	return w.tx.Commit()
+}
+
+func getMBTilesMetadata(meta *Metadata) map[string]string {
+	values := make(map[string]string, len(meta.Extra)+5)
+	for name, value := range meta.Extra {
+		values[name] = value
+	}
+	bounds := getBounds(meta)
+	values["name"] = meta.Name
+	values["format"] = meta.Format
+	values["minzoom"] = strconv.FormatUint(uint64(meta.MinZoom), 10)
+	values["maxzoom"] = strconv.FormatUint(uint64(meta.MaxZoom), 10)
+	values["bounds"] = fmt.Sprintf("%g,%g,%g,%g",
+		bounds.Left,
+		bounds.Bottom,
+		bounds.Right,
+		bounds.Top)
+	return values
+}
+
+// MBTilesReader represents a reader of MBTiles (SQLite) archives. It is safe
+// for concurrent use.
+type MBTilesReader struct {
+	db *sql.DB
+}
+
+// OpenMBTiles opens the MBTiles archive at the provided path.
+func OpenMBTiles(path string) (*MBTilesReader, error) {
+	// ensure the archive exists, as sqlite would otherwise create it
+	_, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+	return &MBTilesReader{
+		db: db,
+	}, nil
+}
+
+// ReadTile returns the tile data under the coordinate, or nil if the tile
+// does not exist.
+func (r *MBTilesReader) ReadTile(coord *binning.TileCoord) ([]byte, error) {
+	var data []byte
+	err := r.db.QueryRow(mbtilesSelect, coord.Z, coord.X, coord.Y).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Close closes the archive.
+func (r *MBTilesReader) Close() error {
+	return r.db.Close()
+}
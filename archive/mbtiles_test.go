@@ -0,0 +1,135 @@
+package archive_test
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/unchartedsoftware/veldt/archive"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/geometry"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MBTiles", func() {
+
+	var dir string
+	var path string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "veldt-archive")
+		Expect(err).To(BeNil())
+		path = filepath.Join(dir, "test.mbtiles")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	write := func(tiles map[binning.TileCoord][]byte) {
+		writer, err := archive.NewWriter(path, &archive.Metadata{
+			Name:    "test",
+			Format:  "bin",
+			MinZoom: 1,
+			MaxZoom: 4,
+			Bounds:  geometry.NewBounds(-10, 10, -5, 5),
+			Extra: map[string]string{
+				"attribution": "veldt",
+			},
+		})
+		Expect(err).To(BeNil())
+		for coord, data := range tiles {
+			c := coord
+			Expect(writer.WriteTile(&c, data)).To(BeNil())
+		}
+		Expect(writer.Close()).To(BeNil())
+	}
+
+	It("should read the tiles that were written", func() {
+		tiles := map[binning.TileCoord][]byte{
+			{Z: 0, X: 0, Y: 0}: []byte("a"),
+			{Z: 1, X: 0, Y: 1}: []byte("b"),
+			{Z: 3, X: 5, Y: 2}: []byte("c"),
+		}
+		write(tiles)
+		reader, err := archive.Open(path)
+		Expect(err).To(BeNil())
+		defer reader.Close()
+		for coord, data := range tiles {
+			c := coord
+			res, err := reader.ReadTile(&c)
+			Expect(err).To(BeNil())
+			Expect(res).To(Equal(data))
+		}
+	})
+
+	It("should return nil for missing and empty tiles", func() {
+		write(map[binning.TileCoord][]byte{
+			{Z: 0, X: 0, Y: 0}: []byte("a"),
+			{Z: 1, X: 1, Y: 1}: {},
+		})
+		reader, err := archive.Open(path)
+		Expect(err).To(BeNil())
+		defer reader.Close()
+		res, err := reader.ReadTile(&binning.TileCoord{Z: 1, X: 0, Y: 0})
+		Expect(err).To(BeNil())
+		Expect(res).To(BeNil())
+		res, err = reader.ReadTile(&binning.TileCoord{Z: 1, X: 1, Y: 1})
+		Expect(err).To(BeNil())
+		Expect(res).To(BeNil())
+	})
+
+	It("should write the metadata table", func() {
+		write(map[binning.TileCoord][]byte{})
+		db, err := sql.Open("sqlite3", path)
+		Expect(err).To(BeNil())
+		defer db.Close()
+		rows, err := db.Query(`SELECT name, value FROM metadata`)
+		Expect(err).To(BeNil())
+		defer rows.Close()
+		meta := make(map[string]string)
+		for rows.Next() {
+			var name, value string
+			Expect(rows.Scan(&name, &value)).To(BeNil())
+			meta[name] = value
+		}
+		Expect(rows.Err()).To(BeNil())
+		Expect(meta).To(Equal(map[string]string{
+			"name":        "test",
+			"format":      "bin",
+			"minzoom":     "1",
+			"maxzoom":     "4",
+			"bounds":      "-10,-5,10,5",
+			"attribution": "veldt",
+		}))
+	})
+
+	It("should replace an existing archive", func() {
+		write(map[binning.TileCoord][]byte{
+			{Z: 0, X: 0, Y: 0}: []byte("a"),
+		})
+		write(map[binning.TileCoord][]byte{
+			{Z: 1, X: 0, Y: 0}: []byte("b"),
+		})
+		reader, err := archive.Open(path)
+		Expect(err).To(BeNil())
+		defer reader.Close()
+		res, err := reader.ReadTile(&binning.TileCoord{Z: 0, X: 0, Y: 0})
+		Expect(err).To(BeNil())
+		Expect(res).To(BeNil())
+		res, err = reader.ReadTile(&binning.TileCoord{Z: 1, X: 0, Y: 0})
+		Expect(err).To(BeNil())
+		Expect(res).To(Equal([]byte("b")))
+	})
+
+	It("should return an error for a missing archive", func() {
+		_, err := archive.Open(path)
+		Expect(err).NotTo(BeNil())
+		_, err = os.Stat(path)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+})
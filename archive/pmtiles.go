@@ -0,0 +1,663 @@
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/unchartedsoftware/veldt/binning"
+)
+
+const (
+	pmtilesMagic      = "PMTiles"
+	pmtilesVersion    = 3
+	pmtilesHeaderSize = 127
+	// the header and root directory must fit within the first 16KiB
+	pmtilesRootSize = 16384 - pmtilesHeaderSize
+	// compression types
+	pmtilesCompressionNone = 1
+	pmtilesCompressionGzip = 2
+	// tile types
+	pmtilesTypeUnknown = 0
+	pmtilesTypeMVT     = 1
+	pmtilesTypePNG     = 2
+	pmtilesTypeJPEG    = 3
+	pmtilesTypeWebP    = 4
+	// initial number of entries per leaf directory
+	pmtilesLeafSize = 4096
+)
+
+// pmtilesEntry represents a directory entry. An entry with a run length of
+// zero points to a leaf directory.
+type pmtilesEntry struct {
+	tileID    uint64
+	offset    uint64
+	length    uint32
+	runLength uint32
+}
+
+type entryArray []pmtilesEntry
+
+func (e entryArray) Len() int {
+	return len(e)
+}
+func (e entryArray) Swap(i, j int) {
+	e[i], e[j] = e[j], e[i]
+}
+func (e entryArray) Less(i, j int) bool {
+	return e[i].tileID < e[j].tileID
+}
+
+// pmtilesHeader represents the fixed size header of a PMTiles archive.
+type pmtilesHeader struct {
+	rootOffset          uint64
+	rootLength          uint64
+	metadataOffset      uint64
+	metadataLength      uint64
+	leafOffset          uint64
+	leafLength          uint64
+	dataOffset          uint64
+	dataLength          uint64
+	numAddressedTiles   uint64
+	numTileEntries      uint64
+	numTileContents     uint64
+	clustered           bool
+	internalCompression uint8
+	tileCompression     uint8
+	tileType            uint8
+	minZoom             uint8
+	maxZoom             uint8
+	minLon              int32
+	minLat              int32
+	maxLon              int32
+	maxLat              int32
+	centerZoom          uint8
+	centerLon           int32
+	centerLat           int32
+}
+
+// PMTilesWriter represents a writer of PMTiles (version 3) archives. Tile
+// data is buffered in a temporary file until the archive is closed, as the
+// archive must be ordered by tile ID. Identical tiles are stored once.
+type PMTilesWriter struct {
+	path     string
+	meta     *Metadata
+	tmp      *os.File
+	size     uint64
+	entries  []pmtilesEntry
+	contents map[[sha1.Size]byte]uint64
+}
+
+// NewPMTilesWriter creates a PMTiles archive at the provided path, replacing
+// any existing file once closed.
+func NewPMTilesWriter(path string, meta *Metadata) (*PMTilesWriter, error) {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return nil, err
+	}
+	return &PMTilesWriter{
+		path:     path,
+		meta:     meta,
+		tmp:      tmp,
+		contents: make(map[[sha1.Size]byte]uint64),
+	}, nil
+}
+
+// WriteTile buffers the tile data under the coordinate. Empty tiles are
+// omitted.
+func (w *PMTilesWriter) WriteTile(coord *binning.TileCoord, data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	hash := sha1.Sum(data)
+	offset, ok := w.contents[hash]
+	if !ok {
+		_, err := w.tmp.Write(data)
+		if err != nil {
+			return err
+		}
+		offset = w.size
+		w.contents[hash] = offset
+		w.size += uint64(len(data))
+	}
+	w.entries = append(w.entries, pmtilesEntry{
+		tileID:    getTileID(coord),
+		offset:    offset,
+		length:    uint32(len(data)),
+		runLength: 1,
+	})
+	return nil
+}
+
+// Close writes the archive, ordering the tile data by tile ID.
+func (w *PMTilesWriter) Close() error {
+	defer os.Remove(w.tmp.Name())
+	defer w.tmp.Close()
+	// order entries and tile data by tile ID
+	sort.Sort(entryArray(w.entries))
+	entries, chunks := clusterEntries(w.entries)
+	// build directories
+	root, leaves, err := buildDirectories(entries)
+	if err != nil {
+		return err
+	}
+	metadata, err := w.getMetadata()
+	if err != nil {
+		return err
+	}
+	header := w.getHeader()
+	header.rootOffset = pmtilesHeaderSize
+	header.rootLength = uint64(len(root))
+	header.metadataOffset = header.rootOffset + header.rootLength
+	header.metadataLength = uint64(len(metadata))
+	header.leafOffset = header.metadataOffset + header.metadataLength
+	header.leafLength = uint64(len(leaves))
+	header.dataOffset = header.leafOffset + header.leafLength
+	header.numAddressedTiles = uint64(len(w.entries))
+	header.numTileEntries = uint64(len(entries))
+	header.numTileContents = uint64(len(chunks))
+	for _, chunk := range chunks {
+		header.dataLength += uint64(chunk.length)
+	}
+	// write to a temporary file, renamed into place once complete
+	file, err := ioutil.TempFile(filepath.Dir(w.path), ".tmp-")
+	if err != nil {
+		return err
+	}
+	err = w.writeArchive(file, header, root, metadata, leaves, chunks)
+	if err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	err = file.Close()
+	if err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	return os.Rename(file.Name(), w.path)
+}
+
+func (w *PMTilesWriter) writeArchive(file *os.File, header *pmtilesHeader, root []byte, metadata []byte, leaves []byte, chunks []pmtilesEntry) error {
+	for _, section := range [][]byte{serializeHeader(header), root, metadata, leaves} {
+		_, err := file.Write(section)
+		if err != nil {
+			return err
+		}
+	}
+	for _, chunk := range chunks {
+		_, err := io.Copy(file, io.NewSectionReader(w.tmp, int64(chunk.offset), int64(chunk.length)))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *PMTilesWriter) getMetadata() ([]byte, error) {
+	values := make(map[string]string, len(w.meta.Extra)+2)
+	for name, value := range w.meta.Extra {
+		values[name] = value
+	}
+	values["name"] = w.meta.Name
+	values["format"] = w.meta.Format
+	bytes, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	return compressGzip(bytes)
+}
+
+func (w *PMTilesWriter) getHeader() *pmtilesHeader {
+	bounds := getBounds(w.meta)
+	return &pmtilesHeader{
+		clustered:           true,
+		internalCompression: pmtilesCompressionGzip,
+		tileCompression:     pmtilesCompressionNone,
+		tileType:            getTileType(w.meta.Format),
+		minZoom:             uint8(w.meta.MinZoom),
+		maxZoom:             uint8(w.meta.MaxZoom),
+		minLon:              toE7(bounds.Left),
+		minLat:              toE7(bounds.Bottom),
+		maxLon:              toE7(bounds.Right),
+		maxLat:              toE7(bounds.Top),
+		centerZoom:          uint8(w.meta.MinZoom),
+		centerLon:           toE7((bounds.Left + bounds.Right) / 2),
+		centerLat:           toE7((bounds.Bottom + bounds.Top) / 2),
+	}
+}
+
+// clusterEntries assigns the offsets of the tile data ordered by tile ID,
+// merging runs of consecutive tiles with identical data. It returns the
+// entries and the buffered data chunks in the order they are written.
+func clusterEntries(sorted []pmtilesEntry) ([]pmtilesEntry, []pmtilesEntry) {
+	var entries []pmtilesEntry
+	var chunks []pmtilesEntry
+	offsets := make(map[uint64]uint64)
+	size := uint64(0)
+	for _, entry := range sorted {
+		offset, ok := offsets[entry.offset]
+		if !ok {
+			offset = size
+			offsets[entry.offset] = offset
+			chunks = append(chunks, entry)
+			size += uint64(entry.length)
+		}
+		if len(entries) > 0 {
+			last := &entries[len(entries)-1]
+			if last.tileID+uint64(last.runLength) == entry.tileID && last.offset == offset {
+				last.runLength++
+				continue
+			}
+		}
+		entries = append(entries, pmtilesEntry{
+			tileID:    entry.tileID,
+			offset:    offset,
+			length:    entry.length,
+			runLength: 1,
+		})
+	}
+	return entries, chunks
+}
+
+// buildDirectories returns the serialized root directory and leaf
+// directories, splitting the entries into leaves if the root would not fit.
+func buildDirectories(entries []pmtilesEntry) ([]byte, []byte, error) {
+	root, err := serializeDirectory(entries)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(root) <= pmtilesRootSize {
+		return root, nil, nil
+	}
+	for leafSize := pmtilesLeafSize; ; leafSize *= 2 {
+		var leaves []byte
+		var rootEntries []pmtilesEntry
+		for i := 0; i < len(entries); i += leafSize {
+			end := i + leafSize
+			if end > len(entries) {
+				end = len(entries)
+			}
+			leaf, err := serializeDirectory(entries[i:end])
+			if err != nil {
+				return nil, nil, err
+			}
+			rootEntries = append(rootEntries, pmtilesEntry{
+				tileID: entries[i].tileID,
+				offset: uint64(len(leaves)),
+				length: uint32(len(leaf)),
+			})
+			leaves = append(leaves, leaf...)
+		}
+		root, err = serializeDirectory(rootEntries)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(root) <= pmtilesRootSize {
+			return root, leaves, nil
+		}
+	}
+}
+
+func serializeDirectory(entries []pmtilesEntry) ([]byte, error) {
+	var buf []byte
+	buf = appendUvarint(buf, uint64(len(entries)))
+	last := uint64(0)
+	for _, entry := range entries {
+		buf = appendUvarint(buf, entry.tileID-last)
+		last = entry.tileID
+	}
+	for _, entry := range entries {
+		buf = appendUvarint(buf, uint64(entry.runLength))
+	}
+	for _, entry := range entries {
+		buf = appendUvarint(buf, uint64(entry.length))
+	}
+	for i, entry := range entries {
+		if i > 0 && entry.offset == entries[i-1].offset+uint64(entries[i-1].length) {
+			// contiguous with the previous entry
+			buf = appendUvarint(buf, 0)
+		} else {
+			buf = appendUvarint(buf, entry.offset+1)
+		}
+	}
+	return compressGzip(buf)
+}
+
+func deserializeDirectory(buf []byte) ([]pmtilesEntry, error) {
+	reader := bytes.NewReader(buf)
+	num, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]pmtilesEntry, num)
+	last := uint64(0)
+	for i := range entries {
+		delta, err := binary.ReadUvarint(reader)
+		if err != nil {
+			return nil, err
+		}
+		last += delta
+		entries[i].tileID = last
+	}
+	for i := range entries {
+		runLength, err := binary.ReadUvarint(reader)
+		if err != nil {
+			return nil, err
+		}
+		entries[i].runLength = uint32(runLength)
+	}
+	for i := range entries {
+		length, err := binary.ReadUvarint(reader)
+		if err != nil {
+			return nil, err
+		}
+		entries[i].length = uint32(length)
+	}
+	for i := range entries {
+		offset, err := binary.ReadUvarint(reader)
+		if err != nil {
+			return nil, err
+		}
+		if offset == 0 && i > 0 {
+			entries[i].offset = entries[i-1].offset + uint64(entries[i-1].length)
+		} else {
+			entries[i].offset = offset - 1
+		}
+	}
+	return entries, nil
+}
+
+func serializeHeader(h *pmtilesHeader) []byte {
+	buf := make([]byte, pmtilesHeaderSize)
+	copy(buf[0:7], pmtilesMagic)
+	buf[7] = pmtilesVersion
+	binary.LittleEndian.PutUint64(buf[8:], h.rootOffset)
+	binary.LittleEndian.PutUint64(buf[16:], h.rootLength)
+	binary.LittleEndian.PutUint64(buf[24:], h.metadataOffset)
+	binary.LittleEndian.PutUint64(buf[32:], h.metadataLength)
+	binary.LittleEndian.PutUint64(buf[40:], h.leafOffset)
+	binary.LittleEndian.PutUint64(buf[48:], h.leafLength)
+	binary.LittleEndian.PutUint64(buf[56:], h.dataOffset)
+	binary.LittleEndian.PutUint64(buf[64:], h.dataLength)
+	binary.LittleEndian.PutUint64(buf[72:], h.numAddressedTiles)
+	binary.LittleEndian.PutUint64(buf[80:], h.numTileEntries)
+	binary.LittleEndian.PutUint64(buf[88:], h.numTileContents)
+	if h.clustered {
+		buf[96] = 1
+	}
+	buf[97] = h.internalCompression
+	buf[98] = h.tileCompression
+	buf[99] = h.tileType
+	buf[100] = h.minZoom
+	buf[101] = h.maxZoom
+	binary.LittleEndian.PutUint32(buf[102:], uint32(h.minLon))
+	binary.LittleEndian.PutUint32(buf[106:], uint32(h.minLat))
+	binary.LittleEndian.PutUint32(buf[110:], uint32(h.maxLon))
+	binary.LittleEndian.PutUint32(buf[114:], uint32(h.maxLat))
+	buf[118] = h.centerZoom
+	binary.LittleEndian.PutUint32(buf[119:], uint32(h.centerLon))
+	binary.LittleEndian.PutUint32(buf[123:], uint32(h.centerLat))
+	return buf
+}
+
+func deserializeHeader(buf []byte) (*pmtilesHeader, error) {
+	if len(buf) < pmtilesHeaderSize || string(buf[0:7]) != pmtilesMagic {
+		return nil, fmt.Errorf("not a PMTiles archive")
+	}
+	if buf[7] != pmtilesVersion {
+		return nil, fmt.Errorf("unsupported PMTiles version %d", buf[7])
+	}
+	return &pmtilesHeader{
+		rootOffset:          binary.LittleEndian.Uint64(buf[8:]),
+		rootLength:          binary.LittleEndian.Uint64(buf[16:]),
+		metadataOffset:      binary.LittleEndian.Uint64(buf[24:]),
+		metadataLength:      binary.LittleEndian.Uint64(buf[32:]),
+		leafOffset:          binary.LittleEndian.Uint64(buf[40:]),
+		leafLength:          binary.LittleEndian.Uint64(buf[48:]),
+		dataOffset:          binary.LittleEndian.Uint64(buf[56:]),
+		dataLength:          binary.LittleEndian.Uint64(buf[64:]),
+		numAddressedTiles:   binary.LittleEndian.Uint64(buf[72:]),
+		numTileEntries:      binary.LittleEndian.Uint64(buf[80:]),
+		numTileContents:     binary.LittleEndian.Uint64(buf[88:]),
+		clustered:           buf[96] == 1,
+		internalCompression: buf[97],
+		tileCompression:     buf[98],
+		tileType:            buf[99],
+		minZoom:             buf[100],
+		maxZoom:             buf[101],
+		minLon:              int32(binary.LittleEndian.Uint32(buf[102:])),
+		minLat:              int32(binary.LittleEndian.Uint32(buf[106:])),
+		maxLon:              int32(binary.LittleEndian.Uint32(buf[110:])),
+		maxLat:              int32(binary.LittleEndian.Uint32(buf[114:])),
+		centerZoom:          buf[118],
+		centerLon:           int32(binary.LittleEndian.Uint32(buf[119:])),
+		centerLat:           int32(binary.LittleEndian.Uint32(buf[123:])),
+	}, nil
+}
+
+// PMTilesReader represents a reader of PMTiles (version 3) archives. It is
+// safe for concurrent use.
+type PMTilesReader struct {
+	file   *os.File
+	header *pmtilesHeader
+	root   []pmtilesEntry
+	mutex  sync.Mutex
+	leaves map[uint64][]pmtilesEntry
+}
+
+// OpenPMTiles opens the PMTiles archive at the provided path.
+func OpenPMTiles(path string) (*PMTilesReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &PMTilesReader{
+		file:   file,
+		leaves: make(map[uint64][]pmtilesEntry),
+	}
+	err = r.init()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *PMTilesReader) init() error {
+	buf := make([]byte, pmtilesHeaderSize)
+	_, err := r.file.ReadAt(buf, 0)
+	if err != nil {
+		return err
+	}
+	r.header, err = deserializeHeader(buf)
+	if err != nil {
+		return err
+	}
+	r.root, err = r.readDirectory(r.header.rootOffset, r.header.rootLength)
+	return err
+}
+
+// ReadTile returns the tile data under the coordinate, or nil if the tile
+// does not exist.
+func (r *PMTilesReader) ReadTile(coord *binning.TileCoord) ([]byte, error) {
+	tileID := getTileID(coord)
+	entries := r.root
+	// the spec allows at most three levels of leaf directories
+	for depth := 0; depth < 4; depth++ {
+		entry, ok := findEntry(entries, tileID)
+		if !ok {
+			return nil, nil
+		}
+		if entry.runLength > 0 {
+			return r.readTile(entry)
+		}
+		var err error
+		entries, err = r.getLeaf(entry)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("PMTiles leaf directories are too deep")
+}
+
+func (r *PMTilesReader) readTile(entry *pmtilesEntry) ([]byte, error) {
+	buf := make([]byte, entry.length)
+	_, err := r.file.ReadAt(buf, int64(r.header.dataOffset+entry.offset))
+	if err != nil {
+		return nil, err
+	}
+	switch r.header.tileCompression {
+	case pmtilesCompressionGzip:
+		return decompressGzip(buf)
+	case pmtilesCompressionNone, 0:
+		return buf, nil
+	}
+	return nil, fmt.Errorf("unsupported PMTiles tile compression %d", r.header.tileCompression)
+}
+
+func (r *PMTilesReader) getLeaf(entry *pmtilesEntry) ([]pmtilesEntry, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	leaf, ok := r.leaves[entry.offset]
+	if ok {
+		return leaf, nil
+	}
+	leaf, err := r.readDirectory(r.header.leafOffset+entry.offset, uint64(entry.length))
+	if err != nil {
+		return nil, err
+	}
+	r.leaves[entry.offset] = leaf
+	return leaf, nil
+}
+
+func (r *PMTilesReader) readDirectory(offset uint64, length uint64) ([]pmtilesEntry, error) {
+	buf := make([]byte, length)
+	_, err := r.file.ReadAt(buf, int64(offset))
+	if err != nil {
+		return nil, err
+	}
+	switch r.header.internalCompression {
+	case pmtilesCompressionGzip:
+		buf, err = decompressGzip(buf)
+		if err != nil {
+			return nil, err
+		}
+	case pmtilesCompressionNone:
+	default:
+		return nil, fmt.Errorf("unsupported PMTiles internal compression %d", r.header.internalCompression)
+	}
+	return deserializeDirectory(buf)
+}
+
+// Close closes the archive.
+func (r *PMTilesReader) Close() error {
+	return r.file.Close()
+}
+
+// findEntry returns the entry containing the tile ID, which is the last entry
+// with a tile ID not greater than it.
+func findEntry(entries []pmtilesEntry, tileID uint64) (*pmtilesEntry, bool) {
+	index := sort.Search(len(entries), func(i int) bool {
+		return entries[i].tileID > tileID
+	}) - 1
+	if index < 0 {
+		return nil, false
+	}
+	entry := &entries[index]
+	if entry.runLength == 0 {
+		// leaf directory
+		return entry, true
+	}
+	if tileID-entry.tileID < uint64(entry.runLength) {
+		return entry, true
+	}
+	return nil, false
+}
+
+// getTileID returns the PMTiles tile ID of the TMS coordinate, which is its
+// position along the Hilbert curve of its level, offset by the number of
+// tiles in all lower levels.
+func getTileID(coord *binning.TileCoord) uint64 {
+	n := uint64(1) << coord.Z
+	// PMTiles use XYZ coordinates, with (0, 0) being at the top-left
+	x := uint64(coord.X)
+	y := n - 1 - uint64(coord.Y)
+	// number of tiles in lower levels
+	id := (n*n - 1) / 3
+	for s := n / 2; s > 0; s /= 2 {
+		var rx, ry uint64
+		if x&s > 0 {
+			rx = 1
+		}
+		if y&s > 0 {
+			ry = 1
+		}
+		id += s * s * ((3 * rx) ^ ry)
+		// rotate
+		if ry == 0 {
+			if rx == 1 {
+				x = n - 1 - x
+				y = n - 1 - y
+			}
+			x, y = y, x
+		}
+	}
+	return id
+}
+
+func getTileType(format string) uint8 {
+	switch format {
+	case "pbf", "mvt":
+		return pmtilesTypeMVT
+	case "png":
+		return pmtilesTypePNG
+	case "jpg", "jpeg":
+		return pmtilesTypeJPEG
+	case "webp":
+		return pmtilesTypeWebP
+	}
+	return pmtilesTypeUnknown
+}
+
+func toE7(degrees float64) int32 {
+	return int32(math.Floor(degrees*1e7 + 0.5))
+}
+
+func appendUvarint(buf []byte, n uint64) []byte {
+	tmp := make([]byte, binary.MaxVarintLen64)
+	return append(buf, tmp[:binary.PutUvarint(tmp, n)]...)
+}
+
+func compressGzip(data []byte) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	_, err := writer.Write(data)
+	if err != nil {
+		return nil, err
+	}
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func decompressGzip(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return ioutil.ReadAll(reader)
+}
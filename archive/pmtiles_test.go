@@ -0,0 +1,158 @@
+package archive_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/archive"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/geometry"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+type coordTile struct{}
+
+func (t *coordTile) Parse(params map[string]interface{}) error {
+	return nil
+}
+
+func (t *coordTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return []byte(fmt.Sprintf("%d/%d/%d", coord.Z, coord.X, coord.Y)), nil
+}
+
+var _ = Describe("PMTiles", func() {
+
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "veldt-archive")
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	write := func(path string, tiles map[binning.TileCoord][]byte) {
+		writer, err := archive.NewWriter(path, &archive.Metadata{
+			Name:    "test",
+			Format:  "bin",
+			MaxZoom: 4,
+		})
+		Expect(err).To(BeNil())
+		for coord, data := range tiles {
+			c := coord
+			Expect(writer.WriteTile(&c, data)).To(BeNil())
+		}
+		Expect(writer.Close()).To(BeNil())
+	}
+
+	It("should read the tiles that were written", func() {
+		path := filepath.Join(dir, "test.pmtiles")
+		tiles := map[binning.TileCoord][]byte{
+			{Z: 0, X: 0, Y: 0}: []byte("a"),
+			{Z: 1, X: 0, Y: 1}: []byte("b"),
+			{Z: 1, X: 1, Y: 1}: []byte("b"),
+			{Z: 3, X: 5, Y: 2}: []byte("c"),
+		}
+		write(path, tiles)
+		reader, err := archive.Open(path)
+		Expect(err).To(BeNil())
+		defer reader.Close()
+		for coord, data := range tiles {
+			c := coord
+			res, err := reader.ReadTile(&c)
+			Expect(err).To(BeNil())
+			Expect(res).To(Equal(data))
+		}
+		// missing tiles
+		res, err := reader.ReadTile(&binning.TileCoord{Z: 1, X: 0, Y: 0})
+		Expect(err).To(BeNil())
+		Expect(res).To(BeNil())
+		res, err = reader.ReadTile(&binning.TileCoord{Z: 4, X: 0, Y: 0})
+		Expect(err).To(BeNil())
+		Expect(res).To(BeNil())
+	})
+
+	It("should split large directories into leaves", func() {
+		path := filepath.Join(dir, "test.pmtiles")
+		tiles := make(map[binning.TileCoord][]byte)
+		for x := uint32(0); x < 128; x++ {
+			for y := uint32(0); y < 128; y++ {
+				tiles[binning.TileCoord{Z: 7, X: x, Y: y}] = []byte(fmt.Sprintf("%d/%d", x, y))
+			}
+		}
+		write(path, tiles)
+		reader, err := archive.Open(path)
+		Expect(err).To(BeNil())
+		defer reader.Close()
+		for coord, data := range tiles {
+			c := coord
+			res, err := reader.ReadTile(&c)
+			Expect(err).To(BeNil())
+			Expect(res).To(Equal(data))
+		}
+	})
+
+	It("should return an error for a file that is not an archive", func() {
+		path := filepath.Join(dir, "test.pmtiles")
+		Expect(ioutil.WriteFile(path, []byte("test"), 0644)).To(BeNil())
+		_, err := archive.Open(path)
+		Expect(err).NotTo(BeNil())
+	})
+})
+
+var _ = Describe("Export", func() {
+
+	It("should return the coordinates within the bounds", func() {
+		coords := archive.GetCoords(&archive.Metadata{
+			MinZoom: 0,
+			MaxZoom: 2,
+			Bounds:  geometry.NewBounds(1, 179, 1, 80),
+		})
+		Expect(coords).To(Equal([]*binning.TileCoord{
+			{Z: 0, X: 0, Y: 0},
+			{Z: 1, X: 1, Y: 1},
+			{Z: 2, X: 2, Y: 2},
+			{Z: 2, X: 2, Y: 3},
+			{Z: 2, X: 3, Y: 2},
+			{Z: 2, X: 3, Y: 3},
+		}))
+	})
+
+	It("should export every tile generated by the pipeline", func() {
+		dir, err := ioutil.TempDir("", "veldt-archive")
+		Expect(err).To(BeNil())
+		defer os.RemoveAll(dir)
+		pipeline := veldt.NewPipeline()
+		pipeline.Tile("coord", func() (veldt.Tile, error) {
+			return &coordTile{}, nil
+		})
+		pipeline.Store(NewMapStore().Ctor())
+		path := filepath.Join(dir, "test.pmtiles")
+		err = archive.Export(pipeline, JSON(`{
+			"uri": "test",
+			"tile": {
+				"coord": {}
+			}
+		}`), &archive.Metadata{
+			Name:    "test",
+			MinZoom: 0,
+			MaxZoom: 2,
+		}, path)
+		Expect(err).To(BeNil())
+		reader, err := archive.Open(path)
+		Expect(err).To(BeNil())
+		defer reader.Close()
+		res, err := reader.ReadTile(&binning.TileCoord{Z: 2, X: 3, Y: 1})
+		Expect(err).To(BeNil())
+		Expect(res).To(Equal([]byte("2/3/1")))
+	})
+})
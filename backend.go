@@ -0,0 +1,127 @@
+package veldt
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// TileFactory represents a function that instantiates a tile constructor from
+// the connection parameters of its backend, such as the host and port of a
+// database.
+type TileFactory func(conn map[string]interface{}) (TileCtor, error)
+
+// MetaFactory represents a function that instantiates a meta constructor from
+// the connection parameters of its backend.
+type MetaFactory func(conn map[string]interface{}) (MetaCtor, error)
+
+// StoreFactory represents a function that instantiates a store constructor
+// from its parameters.
+type StoreFactory func(params map[string]interface{}) (StoreCtor, error)
+
+// RendererFactory represents a function that instantiates a renderer
+// constructor from its parameters.
+type RendererFactory func(params map[string]interface{}) (RendererCtor, error)
+
+// Backend represents the types of a generation backend, registered by name
+// such that pipelines can be declared in configuration documents. Queries do
+// not depend on the connection to the backend, while tiles and metadata are
+// instantiated from it.
+type Backend struct {
+	Binary  QueryCtor
+	Unary   QueryCtor
+	Queries map[string]QueryCtor
+	Tiles   map[string]TileFactory
+	Metas   map[string]MetaFactory
+}
+
+var (
+	factoryMutex = sync.RWMutex{}
+	backends     = make(map[string]*Backend)
+	stores       = make(map[string]StoreFactory)
+	renderers    = make(map[string]RendererFactory)
+)
+
+// RegisterBackend registers the types of a backend under the provided name.
+// Backend packages register themselves when imported.
+func RegisterBackend(name string, backend *Backend) {
+	factoryMutex.Lock()
+	backends[name] = backend
+	factoryMutex.Unlock()
+}
+
+// GetBackend returns the backend registered under the provided name.
+func GetBackend(name string) (*Backend, error) {
+	factoryMutex.RLock()
+	defer factoryMutex.RUnlock()
+	backend, ok := backends[name]
+	if !ok {
+		return nil, fmt.Errorf("backend `%s` is not registered, registered backends are %v",
+			name, getNames(backends))
+	}
+	return backend, nil
+}
+
+// RegisterStoreFactory registers the store factory under the provided type
+// name. Store packages register themselves when imported.
+func RegisterStoreFactory(name string, factory StoreFactory) {
+	factoryMutex.Lock()
+	stores[name] = factory
+	factoryMutex.Unlock()
+}
+
+// GetStoreFactory returns the store factory registered under the provided
+// type name.
+func GetStoreFactory(name string) (StoreFactory, error) {
+	factoryMutex.RLock()
+	defer factoryMutex.RUnlock()
+	factory, ok := stores[name]
+	if !ok {
+		return nil, fmt.Errorf("store `%s` is not registered, registered stores are %v",
+			name, getNames(stores))
+	}
+	return factory, nil
+}
+
+// RegisterRendererFactory registers the renderer factory under the provided
+// type name. Renderer packages register themselves when imported.
+func RegisterRendererFactory(name string, factory RendererFactory) {
+	factoryMutex.Lock()
+	renderers[name] = factory
+	factoryMutex.Unlock()
+}
+
+// GetRendererFactory returns the renderer factory registered under the
+// provided type name.
+func GetRendererFactory(name string) (RendererFactory, error) {
+	factoryMutex.RLock()
+	defer factoryMutex.RUnlock()
+	factory, ok := renderers[name]
+	if !ok {
+		return nil, fmt.Errorf("renderer `%s` is not registered, registered renderers are %v",
+			name, getNames(renderers))
+	}
+	return factory, nil
+}
+
+// getNames returns the sorted keys of a registry map. Must be called with the
+// lock held.
+func getNames(registry interface{}) []string {
+	var names []string
+	switch r := registry.(type) {
+	case map[string]*Backend:
+		for name := range r {
+			names = append(names, name)
+		}
+	case map[string]StoreFactory:
+		for name := range r {
+			names = append(names, name)
+		}
+	case map[string]RendererFactory:
+		for name := range r {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
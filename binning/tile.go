@@ -1,5 +1,9 @@
 package binning
 
+import (
+	"math"
+)
+
 // TileCoord represents a TMS tile's coordinates (0,0) being at the bottom-left.
 type TileCoord struct {
 	X uint32 `json:"x"`
@@ -13,3 +17,71 @@ type FractionalTileCoord struct {
 	Y float64
 	Z uint32
 }
+
+// TileRange represents the tiles of a level within an inclusive range of
+// columns and rows.
+type TileRange struct {
+	Z    uint32
+	MinX uint32
+	MaxX uint32
+	MinY uint32
+	MaxY uint32
+}
+
+// GetTileRange returns the range of tiles of the level of min that overlap
+// the fractional tile coordinate range between min and max, clamped to the
+// tiles of the level. A range ending on a tile edge does not include the next
+// tile.
+func GetTileRange(min *FractionalTileCoord, max *FractionalTileCoord) *TileRange {
+	last := math.Pow(2, float64(min.Z)) - 1
+	minX, maxX := getRange(min.X, max.X, last)
+	minY, maxY := getRange(min.Y, max.Y, last)
+	return &TileRange{
+		Z:    min.Z,
+		MinX: minX,
+		MaxX: maxX,
+		MinY: minY,
+		MaxY: maxY,
+	}
+}
+
+// Count returns the number of tiles in the range. The count of a level
+// deeper than 31 may overflow.
+func (r *TileRange) Count() uint64 {
+	return (uint64(r.MaxX-r.MinX) + 1) * (uint64(r.MaxY-r.MinY) + 1)
+}
+
+// At returns the tile coordinate at the provided index of the range, ordered
+// by column and then by row.
+func (r *TileRange) At(index uint64) *TileCoord {
+	rows := uint64(r.MaxY-r.MinY) + 1
+	return &TileCoord{
+		X: r.MinX + uint32(index/rows),
+		Y: r.MinY + uint32(index%rows),
+		Z: r.Z,
+	}
+}
+
+// GetTilesInRange returns the tile coordinates of the level of min that
+// overlap the fractional tile coordinate range between min and max, clamped
+// to the tiles of the level. A range ending on a tile edge does not include
+// the next tile.
+func GetTilesInRange(min *FractionalTileCoord, max *FractionalTileCoord) []*TileCoord {
+	r := GetTileRange(min, max)
+	count := r.Count()
+	coords := make([]*TileCoord, 0, count)
+	for i := uint64(0); i < count; i++ {
+		coords = append(coords, r.At(i))
+	}
+	return coords
+}
+
+func getRange(min float64, max float64, last float64) (uint32, uint32) {
+	from := math.Floor(math.Min(min, max))
+	to := math.Max(from, math.Ceil(math.Max(min, max))-1)
+	return uint32(clamp(from, last)), uint32(clamp(to, last))
+}
+
+func clamp(val float64, max float64) float64 {
+	return math.Min(max, math.Max(0, val))
+}
@@ -0,0 +1,51 @@
+package binning_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/unchartedsoftware/veldt/binning"
+)
+
+var _ = Describe("tile", func() {
+
+	Describe("GetTilesInRange", func() {
+		It("should return the tiles overlapping the range", func() {
+			coords := binning.GetTilesInRange(
+				&binning.FractionalTileCoord{X: 0.5, Y: 1.5, Z: 2},
+				&binning.FractionalTileCoord{X: 1.5, Y: 2, Z: 2})
+			Expect(coords).To(Equal([]*binning.TileCoord{
+				{X: 0, Y: 1, Z: 2},
+				{X: 1, Y: 1, Z: 2},
+			}))
+		})
+		It("should clamp the range to the tiles of the level", func() {
+			coords := binning.GetTilesInRange(
+				&binning.FractionalTileCoord{X: -1, Y: -1, Z: 1},
+				&binning.FractionalTileCoord{X: 4, Y: 4, Z: 1})
+			Expect(len(coords)).To(Equal(4))
+		})
+		It("should return the containing tile of a point", func() {
+			coords := binning.GetTilesInRange(
+				&binning.FractionalTileCoord{X: 1, Y: 1, Z: 1},
+				&binning.FractionalTileCoord{X: 1, Y: 1, Z: 1})
+			Expect(coords).To(Equal([]*binning.TileCoord{
+				{X: 1, Y: 1, Z: 1},
+			}))
+		})
+	})
+
+	Describe("GetTileRange", func() {
+		It("should count and index the tiles of the deepest level", func() {
+			r := binning.GetTileRange(
+				&binning.FractionalTileCoord{X: -1, Y: -1, Z: 31},
+				&binning.FractionalTileCoord{X: 1 << 32, Y: 1 << 32, Z: 31})
+			Expect(r.Count()).To(Equal(uint64(1) << 62))
+			Expect(r.At(0)).To(Equal(&binning.TileCoord{X: 0, Y: 0, Z: 31}))
+			Expect(r.At(r.Count() - 1)).To(Equal(&binning.TileCoord{
+				X: 1<<31 - 1,
+				Y: 1<<31 - 1,
+				Z: 31,
+			}))
+		})
+	})
+})
@@ -58,6 +58,32 @@ func (b *BinaryExpression) Parse(params map[string]interface{}) error {
 	return nil
 }
 
+// GetOperands returns the operands of the expression, flattening any operands
+// that are binary expressions of the same operator, such that a chain of AND
+// or OR expressions may be translated into a single n-ary expression.
+func (b *BinaryExpression) GetOperands() []Query {
+	var operands []Query
+	for _, operand := range []Query{b.Left, b.Right} {
+		child, ok := operand.(binaryExpression)
+		if ok && child.getBinaryExpression().Op == b.Op {
+			operands = append(operands, child.getBinaryExpression().GetOperands()...)
+			continue
+		}
+		operands = append(operands, operand)
+	}
+	return operands
+}
+
+// binaryExpression represents a binary expression of any generator, which
+// embeds the BinaryExpression type.
+type binaryExpression interface {
+	getBinaryExpression() *BinaryExpression
+}
+
+func (b *BinaryExpression) getBinaryExpression() *BinaryExpression {
+	return b
+}
+
 // UnaryExpression represents a unary boolean expression.
 type UnaryExpression struct {
 	Query Query
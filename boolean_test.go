@@ -69,6 +69,20 @@ var _ = Describe("BinaryExpression", func() {
 			Expect(err).NotTo(BeNil())
 		})
 	})
+
+	Describe("GetOperands", func() {
+		It("should flatten operands of the same operator", func() {
+			a := &query.Exists{Field: "a"}
+			b := &query.Exists{Field: "b"}
+			c := &query.Exists{Field: "c"}
+			d := &query.Exists{Field: "d"}
+			or := &veldt.BinaryExpression{Left: c, Op: veldt.Or, Right: d}
+			binary.Left = &veldt.BinaryExpression{Left: a, Op: veldt.And, Right: b}
+			binary.Op = veldt.And
+			binary.Right = or
+			Expect(binary.GetOperands()).To(Equal([]veldt.Query{a, b, or}))
+		})
+	})
 })
 
 var _ = Describe("UnaryExpression", func() {
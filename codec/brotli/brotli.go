@@ -0,0 +1,47 @@
+// +build brotli
+
+// Package brotli provides a Brotli codec. It is only built with the `brotli`
+// build tag, as its dependency requires Go 1.12 or later:
+//
+//     go build -tags brotli
+//
+package brotli
+
+import (
+	"io"
+	"io/ioutil"
+
+	"github.com/andybalholm/brotli"
+
+	"github.com/unchartedsoftware/veldt/codec"
+)
+
+// Name is the name the codec is conventionally registered under.
+const Name = "brotli"
+
+// Codec represents a Brotli codec.
+type Codec struct{}
+
+// NewCodec instantiates and returns a new Brotli codec.
+//
+// Ex:
+//     codec.Register(brotli.Name, brotli.NewCodec())
+//
+func NewCodec() codec.Codec {
+	return &Codec{}
+}
+
+// NewWriter returns a writer compressing into the provided writer.
+func (c *Codec) NewWriter(writer io.Writer) (io.WriteCloser, error) {
+	return brotli.NewWriter(writer), nil
+}
+
+// NewReader returns a reader decompressing from the provided reader.
+func (c *Codec) NewReader(reader io.Reader) (io.ReadCloser, error) {
+	return ioutil.NopCloser(brotli.NewReader(reader)), nil
+}
+
+// ContentEncoding returns the `br` HTTP content coding.
+func (c *Codec) ContentEncoding() string {
+	return "br"
+}
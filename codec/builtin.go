@@ -0,0 +1,59 @@
+package codec
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"io/ioutil"
+)
+
+type identity struct{}
+
+func (c *identity) NewWriter(writer io.Writer) (io.WriteCloser, error) {
+	return &nopWriteCloser{writer}, nil
+}
+
+func (c *identity) NewReader(reader io.Reader) (io.ReadCloser, error) {
+	return ioutil.NopCloser(reader), nil
+}
+
+func (c *identity) ContentEncoding() string {
+	return Identity
+}
+
+type gzipCodec struct{}
+
+func (c *gzipCodec) NewWriter(writer io.Writer) (io.WriteCloser, error) {
+	return gzip.NewWriter(writer), nil
+}
+
+func (c *gzipCodec) NewReader(reader io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(reader)
+}
+
+func (c *gzipCodec) ContentEncoding() string {
+	return "gzip"
+}
+
+type zlibCodec struct{}
+
+func (c *zlibCodec) NewWriter(writer io.Writer) (io.WriteCloser, error) {
+	return zlib.NewWriter(writer), nil
+}
+
+func (c *zlibCodec) NewReader(reader io.Reader) (io.ReadCloser, error) {
+	return zlib.NewReader(reader)
+}
+
+func (c *zlibCodec) ContentEncoding() string {
+	// the HTTP `deflate` coding is the zlib format
+	return "deflate"
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (w *nopWriteCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,106 @@
+package codec
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+)
+
+// The names of the built-in codecs.
+const (
+	// Identity stores data as is.
+	Identity = "identity"
+	// Gzip compresses data in the gzip format.
+	Gzip = "gzip"
+	// Zlib compresses data in the zlib format.
+	Zlib = "zlib"
+)
+
+// Codec represents a compression format for generated data.
+type Codec interface {
+	// NewWriter returns a writer compressing into the provided writer. The
+	// compressed data is complete once the writer is closed.
+	NewWriter(io.Writer) (io.WriteCloser, error)
+	// NewReader returns a reader decompressing from the provided reader.
+	NewReader(io.Reader) (io.ReadCloser, error)
+	// ContentEncoding returns the HTTP content coding of the format, such as
+	// `gzip`, or an empty string if there is none.
+	ContentEncoding() string
+}
+
+var (
+	mutex  = sync.RWMutex{}
+	codecs = map[string]Codec{
+		Identity: &identity{},
+		Gzip:     &gzipCodec{},
+		Zlib:     &zlibCodec{},
+	}
+)
+
+// Register registers a codec under the provided name, replacing any codec
+// already registered under it.
+func Register(name string, codec Codec) {
+	mutex.Lock()
+	codecs[name] = codec
+	mutex.Unlock()
+}
+
+// Get returns the codec registered under the provided name.
+func Get(name string) (Codec, error) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	codec, ok := codecs[name]
+	if !ok {
+		return nil, fmt.Errorf("unrecognized codec `%s`", name)
+	}
+	return codec, nil
+}
+
+// Encode compresses the data with the codec registered under the provided
+// name.
+func Encode(name string, data []byte) ([]byte, error) {
+	codec, err := Get(name)
+	if err != nil {
+		return nil, err
+	}
+	var buffer bytes.Buffer
+	writer, err := codec.NewWriter(&buffer)
+	if err != nil {
+		return nil, err
+	}
+	_, err = writer.Write(data)
+	if err != nil {
+		writer.Close()
+		return nil, err
+	}
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// Decode decompresses the data with the codec registered under the provided
+// name.
+func Decode(name string, data []byte) ([]byte, error) {
+	codec, err := Get(name)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := codec.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	res, err := ioutil.ReadAll(reader)
+	if err != nil {
+		reader.Close()
+		return nil, err
+	}
+	err = reader.Close()
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
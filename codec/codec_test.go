@@ -0,0 +1,77 @@
+package codec_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+
+	"github.com/unchartedsoftware/veldt/codec"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("codec", func() {
+
+	data := bytes.Repeat([]byte("veldt"), 64)
+
+	Describe("Encode", func() {
+
+		It("should round trip data through the built-in codecs", func() {
+			for _, name := range []string{codec.Identity, codec.Gzip, codec.Zlib} {
+				encoded, err := codec.Encode(name, data)
+				Expect(err).To(BeNil())
+				decoded, err := codec.Decode(name, encoded)
+				Expect(err).To(BeNil())
+				Expect(decoded).To(Equal(data))
+			}
+		})
+
+		It("should compress data in the format of the codec", func() {
+			encoded, err := codec.Encode(codec.Gzip, data)
+			Expect(err).To(BeNil())
+			reader, err := gzip.NewReader(bytes.NewReader(encoded))
+			Expect(err).To(BeNil())
+			decoded, err := ioutil.ReadAll(reader)
+			Expect(err).To(BeNil())
+			Expect(decoded).To(Equal(data))
+		})
+
+		It("should leave data as is for the identity codec", func() {
+			encoded, err := codec.Encode(codec.Identity, data)
+			Expect(err).To(BeNil())
+			Expect(encoded).To(Equal(data))
+		})
+
+		It("should return an error for an unrecognized codec", func() {
+			_, err := codec.Encode("missing", data)
+			Expect(err).NotTo(BeNil())
+			_, err = codec.Decode("missing", data)
+			Expect(err).NotTo(BeNil())
+		})
+
+	})
+
+	Describe("Decode", func() {
+
+		It("should return an error for data not in the format of the codec", func() {
+			_, err := codec.Decode(codec.Gzip, data)
+			Expect(err).NotTo(BeNil())
+		})
+
+	})
+
+	Describe("Register", func() {
+
+		It("should register a codec under the provided name", func() {
+			gzip, err := codec.Get(codec.Gzip)
+			Expect(err).To(BeNil())
+			codec.Register("test", gzip)
+			c, err := codec.Get("test")
+			Expect(err).To(BeNil())
+			Expect(c.ContentEncoding()).To(Equal("gzip"))
+		})
+
+	})
+
+})
@@ -0,0 +1,41 @@
+package snappy
+
+import (
+	"io"
+	"io/ioutil"
+
+	"github.com/golang/snappy"
+
+	"github.com/unchartedsoftware/veldt/codec"
+)
+
+// Name is the name the codec is conventionally registered under.
+const Name = "snappy"
+
+// Codec represents a Snappy codec, using the framed stream format.
+type Codec struct{}
+
+// NewCodec instantiates and returns a new Snappy codec.
+//
+// Ex:
+//     codec.Register(snappy.Name, snappy.NewCodec())
+//
+func NewCodec() codec.Codec {
+	return &Codec{}
+}
+
+// NewWriter returns a writer compressing into the provided writer.
+func (c *Codec) NewWriter(writer io.Writer) (io.WriteCloser, error) {
+	return snappy.NewBufferedWriter(writer), nil
+}
+
+// NewReader returns a reader decompressing from the provided reader.
+func (c *Codec) NewReader(reader io.Reader) (io.ReadCloser, error) {
+	return ioutil.NopCloser(snappy.NewReader(reader)), nil
+}
+
+// ContentEncoding returns an empty string, as there is no HTTP content coding
+// for Snappy. Data is decompressed before being served over HTTP.
+func (c *Codec) ContentEncoding() string {
+	return ""
+}
@@ -0,0 +1,50 @@
+// +build zstd
+
+// Package zstd provides a Zstandard codec. It is only built with the `zstd`
+// build tag, as its dependency requires Go 1.12 or later:
+//
+//     go build -tags zstd
+//
+package zstd
+
+import (
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/unchartedsoftware/veldt/codec"
+)
+
+// Name is the name the codec is conventionally registered under.
+const Name = "zstd"
+
+// Codec represents a Zstandard codec.
+type Codec struct{}
+
+// NewCodec instantiates and returns a new Zstandard codec.
+//
+// Ex:
+//     codec.Register(zstd.Name, zstd.NewCodec())
+//
+func NewCodec() codec.Codec {
+	return &Codec{}
+}
+
+// NewWriter returns a writer compressing into the provided writer.
+func (c *Codec) NewWriter(writer io.Writer) (io.WriteCloser, error) {
+	return zstd.NewWriter(writer)
+}
+
+// NewReader returns a reader decompressing from the provided reader.
+func (c *Codec) NewReader(reader io.Reader) (io.ReadCloser, error) {
+	decoder, err := zstd.NewReader(reader)
+	if err != nil {
+		return nil, err
+	}
+	return decoder.IOReadCloser(), nil
+}
+
+// ContentEncoding returns the `zstd` HTTP content coding.
+func (c *Codec) ContentEncoding() string {
+	return "zstd"
+}
@@ -0,0 +1,19 @@
+package veldt
+
+// Compressor represents a tile, metadata or renderer type that selects the
+// compression codec of the data it generates, overriding that of the
+// pipeline. For example, a type generating already compressed images may
+// select the `identity` codec. An empty string defers to the pipeline.
+type Compressor interface {
+	Compression() string
+}
+
+// getCompression returns the codec selected by the type, if any.
+func getCompression(typ interface{}) (string, bool) {
+	compressor, ok := typ.(Compressor)
+	if !ok {
+		return "", false
+	}
+	name := compressor.Compression()
+	return name, name != ""
+}
@@ -0,0 +1,525 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/codec"
+	jsonutil "github.com/unchartedsoftware/veldt/util/json"
+)
+
+// The keys recognized in a configuration document.
+var (
+	documentKeys = []string{"backends", "pipelines"}
+	pipelineKeys = []string{"backend", "queries", "tiles", "metas", "renderers", "store", "compression", "queue"}
+	queueKeys    = []string{"maxConcurrent", "length", "maxWait", "adaptive"}
+	typeKeys     = []string{"type", "backend"}
+)
+
+// Error represents the problems found while validating a configuration
+// document, each prefixed by the path of the offending value.
+type Error struct {
+	Errors []string
+}
+
+// Error returns the error message.
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid configuration:\n    %s", strings.Join(e.Errors, "\n    "))
+}
+
+// Parse parses a JSON or YAML configuration document into its JSON
+// representation. Documents starting with `{` are parsed as JSON.
+func Parse(data []byte) (map[string]interface{}, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		var doc map[string]interface{}
+		err := json.Unmarshal(trimmed, &doc)
+		if err != nil {
+			return nil, err
+		}
+		return doc, nil
+	}
+	var doc interface{}
+	err := yaml.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		return make(map[string]interface{}), nil
+	}
+	obj, ok := normalize(doc).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("configuration document is not an object")
+	}
+	return obj, nil
+}
+
+// normalize converts the values parsed from YAML into the types parsed from
+// JSON, such that both are read the same way.
+func normalize(val interface{}) interface{} {
+	switch v := val.(type) {
+	case map[interface{}]interface{}:
+		obj := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			obj[fmt.Sprintf("%v", key)] = normalize(child)
+		}
+		return obj
+	case []interface{}:
+		arr := make([]interface{}, len(v))
+		for i, child := range v {
+			arr[i] = normalize(child)
+		}
+		return arr
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	}
+	return val
+}
+
+// Build validates the configuration document and instantiates the pipelines
+// it declares, keyed by ID. The backends, stores and renderers it refers to
+// must be registered by importing their packages. All problems found are
+// returned together as an Error.
+//
+// Ex:
+//     backends:
+//       es:
+//         type: elastic
+//         host: localhost
+//         port: 9200
+//     pipelines:
+//       elastic:
+//         backend: es
+//         queries: [equals, exists, has, range]
+//         tiles:
+//           heatmap: heatmap
+//           top: {type: topTermCount}
+//         metas:
+//           default: default
+//         renderers:
+//           png: png
+//         store:
+//           - type: freecache
+//             size: 268435456
+//             expiry: 60
+//           - type: redis
+//             host: localhost
+//             port: 6379
+//         compression: gzip
+//         queue:
+//           maxConcurrent: 32
+//           length: 1024
+//           maxWait: 10s
+//
+func Build(doc map[string]interface{}) (map[string]*veldt.Pipeline, error) {
+	b := &builder{
+		backends:   make(map[string]*connection),
+		undeclared: make(map[string]bool),
+	}
+	b.checkKeys("", doc, documentKeys)
+	backends, ok := jsonutil.GetChild(doc, "backends")
+	if !ok && jsonutil.Exists(doc, "backends") {
+		b.errorf("backends", "is not an object")
+	}
+	for _, name := range getKeys(backends) {
+		b.addBackend(name, backends[name])
+	}
+	pipelines, ok := jsonutil.GetChild(doc, "pipelines")
+	if !ok {
+		b.errorf("pipelines", "is missing or not an object")
+	}
+	res := make(map[string]*veldt.Pipeline, len(pipelines))
+	for _, id := range getKeys(pipelines) {
+		res[id] = b.buildPipeline(id, pipelines[id])
+	}
+	if len(b.errs) > 0 {
+		return nil, &Error{
+			Errors: b.errs,
+		}
+	}
+	return res, nil
+}
+
+// connection represents a declared connection to a registered backend.
+type connection struct {
+	backend *veldt.Backend
+	params  map[string]interface{}
+}
+
+// builder accumulates the problems found while building pipelines.
+type builder struct {
+	backends   map[string]*connection
+	undeclared map[string]bool
+	errs       []string
+}
+
+func (b *builder) errorf(path string, format string, args ...interface{}) {
+	b.errs = append(b.errs, path+": "+fmt.Sprintf(format, args...))
+}
+
+func (b *builder) checkKeys(path string, obj map[string]interface{}, keys []string) {
+	for _, key := range getKeys(obj) {
+		if !contains(keys, key) {
+			b.errorf(join(path, key), "is not recognized, expected one of %v", keys)
+		}
+	}
+}
+
+func (b *builder) addBackend(name string, val interface{}) {
+	path := join("backends", name)
+	// invalid backends are reported here, and not again by their types
+	b.undeclared[name] = true
+	params, ok := val.(map[string]interface{})
+	if !ok {
+		b.errorf(path, "is not an object")
+		return
+	}
+	typ, ok := jsonutil.GetString(params, "type")
+	if !ok {
+		b.errorf(path, "`type` is missing")
+		return
+	}
+	backend, err := veldt.GetBackend(typ)
+	if err != nil {
+		b.errorf(join(path, "type"), "%v", err)
+		return
+	}
+	b.backends[name] = &connection{
+		backend: backend,
+		params:  params,
+	}
+	delete(b.undeclared, name)
+}
+
+func (b *builder) buildPipeline(id string, val interface{}) *veldt.Pipeline {
+	path := join("pipelines", id)
+	params, ok := val.(map[string]interface{})
+	if !ok {
+		b.errorf(path, "is not an object")
+		return nil
+	}
+	b.checkKeys(path, params, pipelineKeys)
+	pipeline := veldt.NewPipeline()
+	// the default backend of the pipeline's types
+	def := jsonutil.GetStringDefault(params, "", "backend")
+	if def != "" {
+		conn := b.getConnection(join(path, "backend"), def)
+		if conn != nil {
+			if conn.backend.Binary != nil {
+				pipeline.Binary(conn.backend.Binary)
+			}
+			if conn.backend.Unary != nil {
+				pipeline.Unary(conn.backend.Unary)
+			}
+		}
+	}
+	for _, decl := range b.getTypes(path, params, "queries", def) {
+		conn := b.getConnection(decl.path, decl.backend)
+		if conn == nil {
+			continue
+		}
+		ctor, ok := conn.backend.Queries[decl.typ]
+		if !ok {
+			b.errorf(decl.path, "query type `%s` is not supported by backend `%s`", decl.typ, decl.backend)
+			continue
+		}
+		pipeline.Query(decl.id, ctor)
+	}
+	for _, decl := range b.getTypes(path, params, "tiles", def) {
+		conn := b.getConnection(decl.path, decl.backend)
+		if conn == nil {
+			continue
+		}
+		factory, ok := conn.backend.Tiles[decl.typ]
+		if !ok {
+			b.errorf(decl.path, "tile type `%s` is not supported by backend `%s`", decl.typ, decl.backend)
+			continue
+		}
+		ctor, err := factory(conn.params)
+		if err != nil {
+			b.errorf(decl.path, "%v", err)
+			continue
+		}
+		pipeline.Tile(decl.id, ctor)
+	}
+	for _, decl := range b.getTypes(path, params, "metas", def) {
+		conn := b.getConnection(decl.path, decl.backend)
+		if conn == nil {
+			continue
+		}
+		factory, ok := conn.backend.Metas[decl.typ]
+		if !ok {
+			b.errorf(decl.path, "meta type `%s` is not supported by backend `%s`", decl.typ, decl.backend)
+			continue
+		}
+		ctor, err := factory(conn.params)
+		if err != nil {
+			b.errorf(decl.path, "%v", err)
+			continue
+		}
+		pipeline.Meta(decl.id, ctor)
+	}
+	b.addRenderers(path, params, pipeline)
+	b.addStore(path, params, pipeline)
+	b.setCompression(path, params, pipeline)
+	b.setQueue(path, params, pipeline)
+	return pipeline
+}
+
+func (b *builder) getConnection(path string, name string) *connection {
+	if name == "" {
+		b.errorf(path, "no backend is declared for the type or pipeline")
+		return nil
+	}
+	conn, ok := b.backends[name]
+	if !ok {
+		// report each undeclared backend once, rather than once per type
+		if !b.undeclared[name] {
+			b.errorf(path, "backend `%s` is not declared under `backends`", name)
+			b.undeclared[name] = true
+		}
+		return nil
+	}
+	return conn
+}
+
+// declaration represents a query, tile or metadata type exposed by a
+// pipeline under an ID.
+type declaration struct {
+	path    string
+	id      string
+	typ     string
+	backend string
+}
+
+// getTypes returns the declarations under the key, which is either an array
+// of type names, exposed under their own names, or an object of IDs to type
+// names or to objects with a `type` and `backend`.
+func (b *builder) getTypes(path string, params map[string]interface{}, key string, def string) []*declaration {
+	path = join(path, key)
+	val, ok := params[key]
+	if !ok {
+		return nil
+	}
+	var decls []*declaration
+	switch v := val.(type) {
+	case []interface{}:
+		for i, elem := range v {
+			typ, ok := elem.(string)
+			if !ok {
+				b.errorf(fmt.Sprintf("%s[%d]", path, i), "is not a type name")
+				continue
+			}
+			decls = append(decls, &declaration{
+				path:    fmt.Sprintf("%s[%d]", path, i),
+				id:      typ,
+				typ:     typ,
+				backend: def,
+			})
+		}
+	case map[string]interface{}:
+		for _, id := range getKeys(v) {
+			decl := &declaration{
+				path:    join(path, id),
+				id:      id,
+				backend: def,
+			}
+			switch t := v[id].(type) {
+			case string:
+				decl.typ = t
+			case map[string]interface{}:
+				b.checkKeys(decl.path, t, typeKeys)
+				decl.typ, ok = jsonutil.GetString(t, "type")
+				if !ok {
+					b.errorf(decl.path, "`type` is missing")
+					continue
+				}
+				decl.backend = jsonutil.GetStringDefault(t, def, "backend")
+			default:
+				b.errorf(decl.path, "is not a type name or object")
+				continue
+			}
+			decls = append(decls, decl)
+		}
+	default:
+		b.errorf(path, "is not an array or object")
+	}
+	return decls
+}
+
+func (b *builder) addRenderers(path string, params map[string]interface{}, pipeline *veldt.Pipeline) {
+	path = join(path, "renderers")
+	renderers, ok := jsonutil.GetChild(params, "renderers")
+	if !ok {
+		if jsonutil.Exists(params, "renderers") {
+			b.errorf(path, "is not an object")
+		}
+		return
+	}
+	for _, id := range getKeys(renderers) {
+		p := join(path, id)
+		args := make(map[string]interface{})
+		switch r := renderers[id].(type) {
+		case string:
+			args["type"] = r
+		case map[string]interface{}:
+			args = r
+		default:
+			b.errorf(p, "is not a type name or object")
+			continue
+		}
+		typ, ok := jsonutil.GetString(args, "type")
+		if !ok {
+			b.errorf(p, "`type` is missing")
+			continue
+		}
+		factory, err := veldt.GetRendererFactory(typ)
+		if err != nil {
+			b.errorf(p, "%v", err)
+			continue
+		}
+		ctor, err := factory(args)
+		if err != nil {
+			b.errorf(p, "%v", err)
+			continue
+		}
+		pipeline.Render(id, ctor)
+	}
+}
+
+// addStore adds the store of the pipeline, which is either an object with a
+// `type`, or an array of them forming the tiers of a tiered store.
+func (b *builder) addStore(path string, params map[string]interface{}, pipeline *veldt.Pipeline) {
+	path = join(path, "store")
+	args := make(map[string]interface{})
+	switch s := params["store"].(type) {
+	case nil:
+		b.errorf(path, "is missing")
+		return
+	case map[string]interface{}:
+		args = s
+	case []interface{}:
+		args["type"] = "tiered"
+		args["tiers"] = s
+	default:
+		b.errorf(path, "is not an object or array")
+		return
+	}
+	typ, ok := jsonutil.GetString(args, "type")
+	if !ok {
+		b.errorf(path, "`type` is missing")
+		return
+	}
+	factory, err := veldt.GetStoreFactory(typ)
+	if err != nil {
+		b.errorf(path, "%v", err)
+		return
+	}
+	ctor, err := factory(args)
+	if err != nil {
+		b.errorf(path, "%v", err)
+		return
+	}
+	pipeline.Store(ctor)
+}
+
+func (b *builder) setCompression(path string, params map[string]interface{}, pipeline *veldt.Pipeline) {
+	if !jsonutil.Exists(params, "compression") {
+		return
+	}
+	path = join(path, "compression")
+	name, ok := jsonutil.GetString(params, "compression")
+	if !ok {
+		b.errorf(path, "is not a codec name")
+		return
+	}
+	_, err := codec.Get(name)
+	if err != nil {
+		b.errorf(path, "%v", err)
+		return
+	}
+	pipeline.SetCompression(name)
+}
+
+func (b *builder) setQueue(path string, params map[string]interface{}, pipeline *veldt.Pipeline) {
+	if !jsonutil.Exists(params, "queue") {
+		return
+	}
+	path = join(path, "queue")
+	queue, ok := jsonutil.GetChild(params, "queue")
+	if !ok {
+		b.errorf(path, "is not an object")
+		return
+	}
+	b.checkKeys(path, queue, queueKeys)
+	if jsonutil.Exists(queue, "maxConcurrent") {
+		max, ok := jsonutil.GetInt(queue, "maxConcurrent")
+		if !ok || max < 1 {
+			b.errorf(join(path, "maxConcurrent"), "is not a positive integer")
+		} else {
+			pipeline.SetMaxConcurrent(max)
+		}
+	}
+	if jsonutil.Exists(queue, "length") {
+		length, ok := jsonutil.GetInt(queue, "length")
+		if !ok || length < 0 {
+			b.errorf(join(path, "length"), "is not a non-negative integer")
+		} else {
+			pipeline.SetQueueLength(length)
+		}
+	}
+	if jsonutil.Exists(queue, "maxWait") {
+		str, _ := jsonutil.GetString(queue, "maxWait")
+		wait, err := time.ParseDuration(str)
+		if err != nil || wait < 0 {
+			b.errorf(join(path, "maxWait"), "is not a duration, such as `10s`")
+		} else {
+			pipeline.SetQueueMaxWait(wait)
+		}
+	}
+	if jsonutil.Exists(queue, "adaptive") {
+		min, minOk := jsonutil.GetInt(queue, "adaptive", "min")
+		max, maxOk := jsonutil.GetInt(queue, "adaptive", "max")
+		if !minOk || !maxOk || min < 1 || max < min {
+			b.errorf(join(path, "adaptive"), "is not an object with a positive `min` and a `max` no less than it")
+		} else {
+			pipeline.SetAdaptiveConcurrency(min, max)
+		}
+	}
+}
+
+func join(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func contains(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+func getKeys(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
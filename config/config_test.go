@@ -0,0 +1,240 @@
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/config"
+	_ "github.com/unchartedsoftware/veldt/generation/elastic"
+	_ "github.com/unchartedsoftware/veldt/generation/memory"
+	_ "github.com/unchartedsoftware/veldt/render"
+	_ "github.com/unchartedsoftware/veldt/store/freecache"
+	_ "github.com/unchartedsoftware/veldt/store/tiered"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const yamlDoc = `
+backends:
+  mem:
+    type: memory
+pipelines:
+  memory:
+    backend: mem
+    queries: [equals, range]
+    tiles:
+      heatmap: heatmap
+      top: {type: topTermCount}
+    metas:
+      default: default
+    renderers:
+      png: png
+    store:
+      - type: freecache
+        size: 1048576
+        expiry: 60
+      - type: freecache
+        size: 2097152
+    compression: gzip
+    queue:
+      maxConcurrent: 8
+      length: 64
+      maxWait: 10s
+`
+
+const jsonDoc = `
+{
+	"backends": {
+		"mem": {
+			"type": "memory"
+		}
+	},
+	"pipelines": {
+		"memory": {
+			"backend": "mem",
+			"queries": ["equals"],
+			"tiles": {
+				"heatmap": "heatmap"
+			},
+			"store": {
+				"type": "freecache",
+				"size": 1048576
+			}
+		}
+	}
+}
+`
+
+func build(data string) (map[string]*veldt.Pipeline, error) {
+	doc, err := config.Parse([]byte(data))
+	Expect(err).To(BeNil())
+	return config.Build(doc)
+}
+
+// pipeline returns a document of a single pipeline named `test` over the
+// in-memory backend.
+func pipeline(body string) string {
+	return `
+backends:
+  mem:
+    type: memory
+pipelines:
+  test:
+    backend: mem
+` + body
+}
+
+var _ = Describe("config", func() {
+
+	Describe("Build", func() {
+
+		It("should build pipelines from YAML", func() {
+			pipelines, err := build(yamlDoc)
+			Expect(err).To(BeNil())
+			p := pipelines["memory"]
+			Expect(p).NotTo(BeNil())
+			_, err = p.GetQuery("equals", map[string]interface{}{
+				"field": "name",
+				"value": "john",
+			})
+			Expect(err).To(BeNil())
+			_, err = p.GetQuery("has", map[string]interface{}{})
+			Expect(err).NotTo(BeNil())
+			store, err := p.GetStore()
+			Expect(err).To(BeNil())
+			store.Close()
+			Expect(p.GetRequestCompression(&veldt.TileRequest{})).To(Equal("gzip"))
+		})
+
+		It("should build pipelines from JSON", func() {
+			pipelines, err := build(jsonDoc)
+			Expect(err).To(BeNil())
+			Expect(pipelines["memory"]).NotTo(BeNil())
+		})
+
+		It("should report every problem with the path of its value", func() {
+			_, err := build(`
+backends:
+  es:
+    type: elastic
+    port: 9200
+  bad:
+    type: missing
+pipelines:
+  test:
+    backend: mem
+    tiles:
+      heatmap: {type: heatmap, backend: es}
+      other: {type: heatmap, backend: bad}
+    queue:
+      maxWait: soon
+    unknown: true
+`)
+			Expect(err).To(BeAssignableToTypeOf(&config.Error{}))
+			errs := err.(*config.Error).Errors
+			Expect(errs).To(ConsistOf(
+				"backends.bad.type: backend `missing` is not registered, registered backends are [elastic memory]",
+				"pipelines.test.backend: backend `mem` is not declared under `backends`",
+				"pipelines.test.tiles.heatmap: `host` parameter missing from elastic connection",
+				"pipelines.test.store: is missing",
+				"pipelines.test.queue.maxWait: is not a duration, such as `10s`",
+				"pipelines.test.unknown: is not recognized, expected one of [backend queries tiles metas renderers store compression queue]",
+			))
+		})
+
+		It("should return an error for types the backend does not support", func() {
+			_, err := build(pipeline(`
+    tiles: [missing]
+    store: {type: freecache, size: 1024}
+`))
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("pipelines.test.tiles[0]: tile type `missing` is not supported by backend `mem`"))
+		})
+
+		It("should return an error for unregistered stores and codecs", func() {
+			_, err := build(pipeline(`
+    store: {type: missing}
+    compression: missing
+`))
+			Expect(err).NotTo(BeNil())
+			errs := err.(*config.Error).Errors
+			Expect(len(errs)).To(Equal(2))
+		})
+
+	})
+
+	Describe("Loader", func() {
+
+		var dir string
+		var path string
+
+		registered := func(id string) func() error {
+			return func() error {
+				_, err := veldt.GetPipeline(id)
+				return err
+			}
+		}
+
+		write := func(id string) {
+			doc := `
+backends:
+  mem:
+    type: memory
+pipelines:
+  ` + id + `:
+    backend: mem
+    store: {type: freecache, size: 1048576}
+`
+			Expect(ioutil.WriteFile(path, []byte(doc), 0644)).To(BeNil())
+		}
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "config")
+			Expect(err).To(BeNil())
+			path = filepath.Join(dir, "veldt.yaml")
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("should replace the pipelines of the previous load", func() {
+			loader := config.NewLoader(path)
+			write("first")
+			Expect(loader.Load()).To(BeNil())
+			Expect(registered("first")()).To(BeNil())
+			write("second")
+			Expect(loader.Load()).To(BeNil())
+			Expect(registered("first")()).NotTo(BeNil())
+			Expect(registered("second")()).To(BeNil())
+			Expect(loader.GetPipelineIDs()).To(Equal([]string{"second"}))
+		})
+
+		It("should keep the previous pipelines if the file is invalid", func() {
+			loader := config.NewLoader(path)
+			write("kept")
+			Expect(loader.Load()).To(BeNil())
+			Expect(ioutil.WriteFile(path, []byte("pipelines: [invalid"), 0644)).To(BeNil())
+			Expect(loader.Load()).NotTo(BeNil())
+			Expect(registered("kept")()).To(BeNil())
+		})
+
+		It("should reload the file when it changes while watching", func() {
+			loader := config.NewLoader(path)
+			write("before")
+			Expect(loader.Load()).To(BeNil())
+			loader.Watch(time.Millisecond * 10)
+			defer loader.Stop()
+			write("after")
+			Eventually(registered("after")).Should(BeNil())
+			Expect(registered("before")()).NotTo(BeNil())
+		})
+
+	})
+
+})
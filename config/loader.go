@@ -0,0 +1,147 @@
+package config
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/unchartedsoftware/veldt"
+)
+
+// Loader represents the pipelines registered from a configuration file. The
+// file may be reloaded, swapping the pipelines it declares in a single step,
+// such that requests see either the previous or the new set of pipelines.
+type Loader struct {
+	path   string
+	mu     sync.Mutex
+	ids    []string
+	digest []byte
+	quit   chan struct{}
+	done   chan struct{}
+}
+
+// NewLoader instantiates and returns a new loader of the configuration file.
+func NewLoader(path string) *Loader {
+	return &Loader{
+		path: path,
+	}
+}
+
+// Load reads the configuration file, builds its pipelines and registers them,
+// replacing the pipelines registered by the previous load. If the file is
+// invalid, the previous pipelines remain registered and the error is
+// returned.
+func (l *Loader) Load() error {
+	data, err := ioutil.ReadFile(l.path)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.load(data)
+}
+
+// GetPipelineIDs returns the IDs of the pipelines registered by the last
+// successful load.
+func (l *Loader) GetPipelineIDs() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	ids := make([]string, len(l.ids))
+	copy(ids, l.ids)
+	return ids
+}
+
+// Watch polls the configuration file at the provided interval in the
+// background, reloading it whenever its contents change. Failed reloads are
+// logged and leave the previous pipelines registered. Calling Watch again
+// replaces the previous interval.
+func (l *Loader) Watch(interval time.Duration) {
+	l.Stop()
+	l.mu.Lock()
+	quit := make(chan struct{})
+	done := make(chan struct{})
+	l.quit = quit
+	l.done = done
+	l.mu.Unlock()
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				l.reload()
+			case <-quit:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops watching the configuration file, waiting for any reload in
+// progress to finish.
+func (l *Loader) Stop() {
+	l.mu.Lock()
+	quit := l.quit
+	done := l.done
+	l.quit = nil
+	l.done = nil
+	l.mu.Unlock()
+	if quit != nil {
+		close(quit)
+		<-done
+	}
+}
+
+func (l *Loader) reload() {
+	data, err := ioutil.ReadFile(l.path)
+	if err != nil {
+		Errorf("Failed to read configuration file `%s`: %v", l.path, err)
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if bytes.Equal(getDigest(data), l.digest) {
+		return
+	}
+	err = l.load(data)
+	if err != nil {
+		Errorf("Failed to reload configuration file `%s`, keeping previous pipelines: %v", l.path, err)
+		return
+	}
+	Infof("Reloaded configuration file `%s`", l.path)
+}
+
+// load builds and registers the pipelines of the document. Must be called
+// with the lock held.
+func (l *Loader) load(data []byte) error {
+	// record the digest of failed loads too, so that the same invalid
+	// contents are not reported on every poll
+	l.digest = getDigest(data)
+	doc, err := Parse(data)
+	if err != nil {
+		return err
+	}
+	pipelines, err := Build(doc)
+	if err != nil {
+		return err
+	}
+	veldt.Replace(l.ids, pipelines)
+	l.ids = getPipelineIDs(pipelines)
+	return nil
+}
+
+func getDigest(data []byte) []byte {
+	digest := sha256.Sum256(data)
+	return digest[:]
+}
+
+func getPipelineIDs(pipelines map[string]*veldt.Pipeline) []string {
+	ids := make([]string, 0, len(pipelines))
+	for id := range pipelines {
+		ids = append(ids, id)
+	}
+	return ids
+}
@@ -0,0 +1,128 @@
+package veldt
+
+import (
+	"github.com/unchartedsoftware/veldt/codec"
+	"github.com/unchartedsoftware/veldt/schema"
+)
+
+// Schematic represents a tile, query, metadata or renderer type that declares
+// the JSON Schema of its parameters. Requests are validated against the
+// schema before the parameters are parsed, such that unrecognized keys are
+// rejected and every invalid parameter is reported at once. A nil schema
+// defers entirely to the Parse method of the type.
+type Schematic interface {
+	Schema() *schema.Schema
+}
+
+// getSchema returns the schema declared by the type, if any.
+func getSchema(typ interface{}) *schema.Schema {
+	schematic, ok := typ.(Schematic)
+	if !ok {
+		return nil
+	}
+	return schematic.Schema()
+}
+
+// TypeDescription represents the description of a registered query, tile,
+// metadata or renderer type.
+type TypeDescription struct {
+	// Schema is the JSON Schema of the parameters of the type, or nil if the
+	// type does not declare one.
+	Schema *schema.Schema `json:"schema,omitempty"`
+	// Compression is the name of the codec the generated data is compressed
+	// with. It is empty for queries.
+	Compression string `json:"compression,omitempty"`
+	// ContentEncoding is the HTTP content coding the compressed data may be
+	// served with, if any.
+	ContentEncoding string `json:"contentEncoding,omitempty"`
+}
+
+// Description represents the capabilities of a pipeline, listing the IDs of
+// its registered types along with the schemas of their parameters, the
+// boolean operators of its query expressions and the encodings of the data it
+// generates. It marshals into JSON, such that clients can discover the
+// requests a pipeline accepts.
+//
+// Ex:
+//     {
+//         "id": "elastic",
+//         "queries": {
+//             "equals": {
+//                 "schema": { ... }
+//             }
+//         },
+//         "operators": ["AND", "OR", "NOT"],
+//         "tiles": {
+//             "heatmap": {
+//                 "schema": { ... },
+//                 "compression": "gzip",
+//                 "contentEncoding": "gzip"
+//             }
+//         },
+//         "metas": { ... },
+//         "renderers": { ... },
+//         "compression": "gzip"
+//     }
+//
+type Description struct {
+	ID          string                      `json:"id"`
+	Queries     map[string]*TypeDescription `json:"queries"`
+	Operators   []string                    `json:"operators"`
+	Tiles       map[string]*TypeDescription `json:"tiles"`
+	Metas       map[string]*TypeDescription `json:"metas"`
+	Renderers   map[string]*TypeDescription `json:"renderers"`
+	Compression string                      `json:"compression"`
+}
+
+// Describe returns the description of the capabilities of the pipeline.
+func (p *Pipeline) Describe() *Description {
+	desc := &Description{
+		ID:          p.id,
+		Queries:     make(map[string]*TypeDescription, len(p.queries)),
+		Operators:   make([]string, 0, 3),
+		Tiles:       make(map[string]*TypeDescription, len(p.tiles)),
+		Metas:       make(map[string]*TypeDescription, len(p.metas)),
+		Renderers:   make(map[string]*TypeDescription, len(p.renderers)),
+		Compression: p.compression,
+	}
+	for id := range p.queries {
+		desc.Queries[id] = &TypeDescription{
+			Schema: p.schemas[getIdentityKey(queryKind, id)],
+		}
+	}
+	if p.binary != nil {
+		desc.Operators = append(desc.Operators, And, Or)
+	}
+	if p.unary != nil {
+		desc.Operators = append(desc.Operators, Not)
+	}
+	for id, ctor := range p.tiles {
+		desc.Tiles[id] = p.describeType(tileKind, id, ctor)
+	}
+	for id, ctor := range p.metas {
+		desc.Metas[id] = p.describeType(metaKind, id, ctor)
+	}
+	for id, ctor := range p.renderers {
+		desc.Renderers[id] = p.describeType(renderKind, id, ctor)
+	}
+	return desc
+}
+
+// describeType returns the description of a registered type that generates
+// data, which is compressed with the codec it selects, or the codec of the
+// pipeline otherwise.
+func (p *Pipeline) describeType(kind string, id string, ctor interface{}) *TypeDescription {
+	name, ok := getCompression(getInstance(ctor))
+	if !ok {
+		name = p.compression
+	}
+	desc := &TypeDescription{
+		Schema:      p.schemas[getIdentityKey(kind, id)],
+		Compression: name,
+	}
+	c, err := codec.Get(name)
+	if err == nil && c.ContentEncoding() != codec.Identity {
+		desc.ContentEncoding = c.ContentEncoding()
+	}
+	return desc
+}
@@ -0,0 +1,68 @@
+package veldt
+
+import (
+	"github.com/unchartedsoftware/veldt/metrics"
+	"github.com/unchartedsoftware/veldt/util/queue"
+)
+
+// Endpoint represents a tile or metadata type that generates its data from a
+// backend endpoint, such as the host and port of a database. With adaptive
+// concurrency, each endpoint is given its own queue, such that a degraded
+// endpoint does not hold back the others.
+type Endpoint interface {
+	GetEndpoint() string
+}
+
+// adaptiveBounds represents the bounds of adaptive concurrency limits.
+type adaptiveBounds struct {
+	min int
+	max int
+}
+
+func (b *adaptiveBounds) newLimiter() *queue.Limiter {
+	return queue.NewLimiter(b.min, b.max)
+}
+
+// getEndpoint returns the endpoint of the tile or metadata generator, falling
+// back to the name of the package implementing it.
+func getEndpoint(generator interface{}) string {
+	endpoint, ok := generator.(Endpoint)
+	if ok {
+		return endpoint.GetEndpoint()
+	}
+	_, backend := getTypeAndBackend(generator)
+	return backend
+}
+
+// configureQueues applies the option to every queue of the pipeline,
+// including the queues of endpoints created later.
+func (p *Pipeline) configureQueues(option func(*queue.Queue)) {
+	p.queueMu.Lock()
+	defer p.queueMu.Unlock()
+	p.options = append(p.options, option)
+	option(p.queue)
+	for _, q := range p.endpoints {
+		option(q)
+	}
+}
+
+// getQueue returns the queue the request is generated through.
+func (p *Pipeline) getQueue(req Request) *queue.Queue {
+	p.queueMu.Lock()
+	defer p.queueMu.Unlock()
+	if p.adaptive == nil {
+		return p.queue
+	}
+	endpoint := getEndpoint(getGenerator(req))
+	q, ok := p.endpoints[endpoint]
+	if !ok {
+		q = queue.NewQueue()
+		for _, option := range p.options {
+			option(q)
+		}
+		q.SetLabels(p.labels().With(metrics.EndpointLabel, endpoint))
+		q.SetLimiter(p.adaptive.newLimiter())
+		p.endpoints[endpoint] = q
+	}
+	return q
+}
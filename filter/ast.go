@@ -0,0 +1,93 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// And represents an AND binary operator.
+	And = "AND"
+	// Or represents an OR binary operator.
+	Or = "OR"
+	// Not represents a NOT unary operator.
+	Not = "NOT"
+)
+
+// The IDs of the query types that the comparison syntax compiles into. These
+// are the IDs the types of the `query` package are conventionally registered
+// under.
+const (
+	EqualsType        = "equals"
+	ExistsType        = "exists"
+	HasType           = "has"
+	MatchesStringType = "matchesString"
+	RangeType         = "range"
+)
+
+// Node represents a node of the AST of a filter expression.
+type Node interface {
+	// String returns the node formatted as a filter expression.
+	String() string
+}
+
+// Binary represents two expressions joined by the AND or OR operator.
+type Binary struct {
+	Left  Node
+	Op    string
+	Right Node
+}
+
+// String returns the node formatted as a filter expression.
+func (b *Binary) String() string {
+	return Format(b)
+}
+
+// Unary represents an expression negated by the NOT operator.
+type Unary struct {
+	Op      string
+	Operand Node
+}
+
+// String returns the node formatted as a filter expression.
+func (u *Unary) String() string {
+	return Format(u)
+}
+
+// Query represents a query of the type registered under the ID, with its
+// JSON parameters.
+type Query struct {
+	Type   string
+	Params map[string]interface{}
+}
+
+// String returns the node formatted as a filter expression.
+func (q *Query) String() string {
+	return Format(q)
+}
+
+// SyntaxError represents an error in the syntax of a filter expression at a
+// position of the input. Lines and columns start at 1, and columns count
+// characters rather than bytes.
+type SyntaxError struct {
+	Msg    string
+	Offset int
+	Line   int
+	Column int
+}
+
+func newSyntaxError(input string, offset int, format string, args ...interface{}) *SyntaxError {
+	prefix := input[:offset]
+	line := strings.Count(prefix, "\n") + 1
+	start := strings.LastIndex(prefix, "\n") + 1
+	return &SyntaxError{
+		Msg:    fmt.Sprintf(format, args...),
+		Offset: offset,
+		Line:   line,
+		Column: len([]rune(prefix[start:])) + 1,
+	}
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s at line %d, column %d", e.Msg, e.Line, e.Column)
+}
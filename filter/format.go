@@ -0,0 +1,224 @@
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	orPrecedence = iota + 1
+	andPrecedence
+	notPrecedence
+	queryPrecedence
+)
+
+// Format returns the AST formatted as a filter expression, such that parsing
+// the result returns an equivalent AST. Queries of the types the comparison
+// syntax compiles into are formatted as comparisons when their parameters
+// allow, and parentheses are only added where precedence requires them.
+//
+// Ex:
+//     name = "john" AND (age >= 18 OR NOT exists(email))
+//
+func Format(node Node) string {
+	return format(node, orPrecedence)
+}
+
+func format(node Node, min int) string {
+	str, prec := formatNode(node)
+	if prec < min {
+		return "(" + str + ")"
+	}
+	return str
+}
+
+func formatNode(node Node) (string, int) {
+	switch n := node.(type) {
+	case *Binary:
+		prec := getPrecedence(n.Op)
+		return format(n.Left, prec) + " " + n.Op + " " + format(n.Right, prec), prec
+	case *Unary:
+		if q, ok := n.Operand.(*Query); ok {
+			if str, ok := formatNegated(q); ok {
+				return str, queryPrecedence
+			}
+		}
+		return n.Op + " " + format(n.Operand, notPrecedence), notPrecedence
+	case *Query:
+		return formatQuery(n), queryPrecedence
+	}
+	return fmt.Sprintf("%v", node), queryPrecedence
+}
+
+func getPrecedence(op string) int {
+	switch op {
+	case And:
+		return andPrecedence
+	case Not:
+		return notPrecedence
+	}
+	return orPrecedence
+}
+
+// formatNegated formats the negation of a query as a negated comparison, if
+// one exists.
+func formatNegated(q *Query) (string, bool) {
+	switch q.Type {
+	case EqualsType:
+		field, ok := getComparisonField(q, "value")
+		if ok {
+			return field + " != " + formatValue(q.Params["value"]), true
+		}
+	case HasType:
+		field, ok := getComparisonField(q, "values")
+		values, isArray := q.Params["values"].([]interface{})
+		if ok && isArray {
+			return field + " NOT IN " + formatList(values, "(", ")"), true
+		}
+	}
+	return "", false
+}
+
+func formatQuery(q *Query) string {
+	switch q.Type {
+	case EqualsType:
+		field, ok := getComparisonField(q, "value")
+		if ok {
+			return field + " = " + formatValue(q.Params["value"])
+		}
+	case HasType:
+		field, ok := getComparisonField(q, "values")
+		values, isArray := q.Params["values"].([]interface{})
+		if ok && isArray {
+			return field + " IN " + formatList(values, "(", ")")
+		}
+	case RangeType:
+		for op, key := range rangeKeys {
+			field, ok := getComparisonField(q, key)
+			if ok {
+				return field + " " + op + " " + formatValue(q.Params[key])
+			}
+		}
+	case MatchesStringType:
+		match, isString := q.Params["match"].(string)
+		fields, isArray := q.Params["fields"].([]interface{})
+		if isString && isArray && len(fields) == 1 && len(q.Params) == 2 {
+			field, ok := fields[0].(string)
+			if ok {
+				return formatField(field) + " ~ " + strconv.Quote(match)
+			}
+		}
+	}
+	if len(q.Params) == 1 {
+		field, ok := q.Params["field"].(string)
+		if ok {
+			return q.Type + "(" + formatField(field) + ")"
+		}
+	}
+	keys := make([]string, 0, len(q.Params))
+	for key := range q.Params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	params := make([]string, len(keys))
+	for i, key := range keys {
+		params[i] = formatField(key) + ": " + formatValue(q.Params[key])
+	}
+	return q.Type + "(" + strings.Join(params, ", ") + ")"
+}
+
+// getComparisonField returns the formatted field of a query whose parameters
+// are exactly its field and the provided key.
+func getComparisonField(q *Query, key string) (string, bool) {
+	if len(q.Params) != 2 {
+		return "", false
+	}
+	if _, ok := q.Params[key]; !ok {
+		return "", false
+	}
+	field, ok := q.Params["field"].(string)
+	if !ok {
+		return "", false
+	}
+	return formatField(field), true
+}
+
+// formatField returns the field as an identifier, or enclosed in backticks if
+// it is not a valid identifier.
+func formatField(field string) string {
+	if isIdent(field) {
+		return field
+	}
+	return "`" + field + "`"
+}
+
+func isIdent(str string) bool {
+	if str == "" {
+		return false
+	}
+	for i, r := range str {
+		if i == 0 && !isIdentStart(r) {
+			return false
+		}
+		if !isIdentPart(r) {
+			return false
+		}
+	}
+	t := token{typ: identToken, val: str}
+	return !isReserved(t)
+}
+
+func formatValue(val interface{}) string {
+	switch v := val.(type) {
+	case nil:
+		return "null"
+	case string:
+		return strconv.Quote(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case []interface{}:
+		return formatList(v, "[", "]")
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		members := make([]string, len(keys))
+		for i, key := range keys {
+			members[i] = formatField(key) + ": " + formatValue(v[key])
+		}
+		return "{" + strings.Join(members, ", ") + "}"
+	}
+	switch reflect.ValueOf(val).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32:
+		return fmt.Sprintf("%v", val)
+	}
+	// fall back to the JSON encoding of any other type
+	bytes, err := json.Marshal(val)
+	if err != nil {
+		return strconv.Quote(fmt.Sprintf("%v", val))
+	}
+	var decoded interface{}
+	err = json.Unmarshal(bytes, &decoded)
+	if err != nil {
+		return strconv.Quote(fmt.Sprintf("%v", val))
+	}
+	return formatValue(decoded)
+}
+
+func formatList(values []interface{}, open string, close string) string {
+	strs := make([]string, len(values))
+	for i, val := range values {
+		strs[i] = formatValue(val)
+	}
+	return open + strings.Join(strs, ", ") + close
+}
@@ -0,0 +1,76 @@
+package filter_test
+
+import (
+	"encoding/json"
+
+	"github.com/unchartedsoftware/veldt/filter"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func format(input string) string {
+	node, err := filter.Parse(input)
+	Expect(err).To(BeNil())
+	return node.String()
+}
+
+var _ = Describe("Format", func() {
+
+	It("should format queries as comparisons", func() {
+		Expect(format(`name="john"`)).To(Equal(`name = "john"`))
+		Expect(format(`age>=18`)).To(Equal(`age >= 18`))
+		Expect(format(`tag in ("a","b")`)).To(Equal(`tag IN ("a", "b")`))
+		Expect(format(`text~"foo*"`)).To(Equal(`text ~ "foo*"`))
+		Expect(format(`exists( email )`)).To(Equal(`exists(email)`))
+		Expect(format(`name != "john"`)).To(Equal(`name != "john"`))
+		Expect(format(`tag not in (1)`)).To(Equal(`tag NOT IN (1)`))
+	})
+
+	It("should format other queries as calls with sorted parameters", func() {
+		Expect(format(`range(lt: 65, field: age, gte: 18)`)).To(Equal(
+			`range(field: "age", gte: 18, lt: 65)`))
+		Expect(format(`custom(b: {d: false, c: 1}, a: [1, "b"])`)).To(Equal(
+			`custom(a: [1, "b"], b: {c: 1, d: false})`))
+	})
+
+	It("should quote fields that are not identifiers", func() {
+		Expect(format("`first name` = 1")).To(Equal("`first name` = 1"))
+		Expect(format("`or` = 1")).To(Equal("`or` = 1"))
+	})
+
+	It("should only add parentheses where precedence requires them", func() {
+		Expect(format(`(a = 1 AND b = 2) OR c = 3`)).To(Equal(`a = 1 AND b = 2 OR c = 3`))
+		Expect(format(`a = 1 AND (b = 2 OR c = 3)`)).To(Equal(`a = 1 AND (b = 2 OR c = 3)`))
+		Expect(format(`NOT (a = 1 AND b = 2)`)).To(Equal(`NOT (a = 1 AND b = 2)`))
+		Expect(format(`NOT NOT a >= 1`)).To(Equal(`NOT NOT a >= 1`))
+	})
+
+	It("should round trip through the JSON form", func() {
+		input := `name = "john" AND (age >= 18 OR NOT exists(email)) AND tag IN ("a", "b") AND text ~ "foo*"`
+		compiled, err := filter.Compile(input)
+		Expect(err).To(BeNil())
+		// marshal and unmarshal to match a request
+		bytes, err := json.Marshal(compiled)
+		Expect(err).To(BeNil())
+		var decoded interface{}
+		err = json.Unmarshal(bytes, &decoded)
+		Expect(err).To(BeNil())
+		node, err := filter.FromJSON(decoded)
+		Expect(err).To(BeNil())
+		Expect(filter.Format(node)).To(Equal(input))
+	})
+
+	It("should error on invalid JSON expressions", func() {
+		_, err := filter.FromJSON([]interface{}{"AND"})
+		Expect(err).To(HaveOccurred())
+		_, err = filter.FromJSON([]interface{}{
+			map[string]interface{}{"exists": map[string]interface{}{"field": "a"}},
+			"AND",
+		})
+		Expect(err).To(HaveOccurred())
+		_, err = filter.FromJSON(map[string]interface{}{"exists": "a"})
+		Expect(err).To(HaveOccurred())
+	})
+
+})
@@ -0,0 +1,183 @@
+package filter
+
+import (
+	"fmt"
+)
+
+// Compile parses the filter expression and returns the equivalent query
+// expression in the JSON form accepted by a pipeline.
+//
+// Ex:
+//     age >= 18 AND NOT exists(email)
+//
+// Compiles into:
+//     [
+//         {
+//             "range": {
+//                 "field": "age",
+//                 "gte": 18
+//             }
+//         },
+//         "AND",
+//         "NOT",
+//         {
+//             "exists": {
+//                 "field": "email"
+//             }
+//         }
+//     ]
+//
+func Compile(input string) (interface{}, error) {
+	node, err := Parse(input)
+	if err != nil {
+		return nil, err
+	}
+	return ToJSON(node), nil
+}
+
+// ToJSON returns the AST as a query expression in the JSON form accepted by a
+// pipeline. Chains of the same binary operator are flattened into a single
+// array, while operands of lower precedence are nested in their own array.
+func ToJSON(node Node) interface{} {
+	switch n := node.(type) {
+	case *Query:
+		return map[string]interface{}{
+			n.Type: n.Params,
+		}
+	case *Unary:
+		return []interface{}{
+			n.Op,
+			ToJSON(n.Operand),
+		}
+	case *Binary:
+		return toTokens(n)
+	}
+	return nil
+}
+
+// toTokens returns the tokens of a binary expression.
+func toTokens(b *Binary) []interface{} {
+	var tokens []interface{}
+	tokens = append(tokens, toOperandTokens(b.Left, b.Op)...)
+	tokens = append(tokens, b.Op)
+	tokens = append(tokens, toOperandTokens(b.Right, b.Op)...)
+	return tokens
+}
+
+func toOperandTokens(node Node, op string) []interface{} {
+	switch n := node.(type) {
+	case *Binary:
+		if n.Op == op {
+			return toTokens(n)
+		}
+		return []interface{}{toTokens(n)}
+	case *Unary:
+		return ToJSON(n).([]interface{})
+	}
+	return []interface{}{ToJSON(node)}
+}
+
+// FromJSON returns the AST of a query expression in the JSON form accepted by
+// a pipeline, such that it can be formatted as a filter expression.
+func FromJSON(arg interface{}) (Node, error) {
+	switch a := arg.(type) {
+	case map[string]interface{}:
+		return fromQueryJSON(a)
+	case []interface{}:
+		p := &jsonParser{
+			tokens: a,
+		}
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.index < len(p.tokens) {
+			return nil, fmt.Errorf("unexpected token `%v`", p.tokens[p.index])
+		}
+		return node, nil
+	}
+	return nil, fmt.Errorf("`%v` is not a query or expression", arg)
+}
+
+func fromQueryJSON(arg map[string]interface{}) (Node, error) {
+	if len(arg) != 1 {
+		return nil, fmt.Errorf("query `%v` must have exactly one type", arg)
+	}
+	for typ, val := range arg {
+		params, ok := val.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("parameters of `%s` query are not of type object", typ)
+		}
+		return &Query{
+			Type:   typ,
+			Params: params,
+		}, nil
+	}
+	return nil, nil
+}
+
+// jsonParser parses the tokens of a query expression with the same
+// precedence as the pipeline.
+type jsonParser struct {
+	tokens []interface{}
+	index  int
+}
+
+func (p *jsonParser) isOperator(op string) bool {
+	if p.index >= len(p.tokens) {
+		return false
+	}
+	str, ok := p.tokens[p.index].(string)
+	return ok && str == op
+}
+
+func (p *jsonParser) parseOr() (Node, error) {
+	return p.parseBinary(Or, p.parseAnd)
+}
+
+func (p *jsonParser) parseAnd() (Node, error) {
+	return p.parseBinary(And, p.parseUnary)
+}
+
+func (p *jsonParser) parseBinary(op string, operand func() (Node, error)) (Node, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator(op) {
+		p.index++
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = &Binary{
+			Left:  left,
+			Op:    op,
+			Right: right,
+		}
+	}
+	return left, nil
+}
+
+func (p *jsonParser) parseUnary() (Node, error) {
+	if p.isOperator(Not) {
+		p.index++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Unary{
+			Op:      Not,
+			Operand: operand,
+		}, nil
+	}
+	if p.index >= len(p.tokens) {
+		return nil, fmt.Errorf("expected operand missing")
+	}
+	token := p.tokens[p.index]
+	if _, ok := token.(string); ok {
+		return nil, fmt.Errorf("unexpected operator `%v`", token)
+	}
+	p.index++
+	return FromJSON(token)
+}
@@ -0,0 +1,176 @@
+package filter
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenType int
+
+const (
+	eofToken tokenType = iota
+	identToken
+	fieldToken
+	stringToken
+	numberToken
+	operatorToken
+	punctToken
+)
+
+// token represents a lexical token of a filter expression, along with its
+// byte offset in the input.
+type token struct {
+	typ tokenType
+	val string
+	pos int
+}
+
+func (t token) String() string {
+	switch t.typ {
+	case eofToken:
+		return "end of input"
+	case stringToken:
+		return strconv.Quote(t.val)
+	}
+	return "`" + t.val + "`"
+}
+
+// operators are the comparison operators, longest first such that `>=` is
+// not lexed as `>`.
+var operators = []string{
+	"!=",
+	">=",
+	"<=",
+	"=",
+	">",
+	"<",
+	"~",
+}
+
+// lex splits the input into tokens.
+func lex(input string) ([]token, error) {
+	var tokens []token
+	pos := 0
+	for pos < len(input) {
+		r, width := utf8.DecodeRuneInString(input[pos:])
+		switch {
+		case unicode.IsSpace(r):
+			pos += width
+		case r == '"':
+			end, val, err := lexString(input, pos)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{stringToken, val, pos})
+			pos = end
+		case r == '`':
+			end := strings.IndexRune(input[pos+1:], '`')
+			if end < 0 {
+				return nil, newSyntaxError(input, pos, "unterminated quoted field")
+			}
+			tokens = append(tokens, token{fieldToken, input[pos+1 : pos+1+end], pos})
+			pos += end + 2
+		case r == '-' || isDigit(r):
+			end := lexNumber(input, pos)
+			if end == pos+1 && r == '-' {
+				return nil, newSyntaxError(input, pos, "unexpected character `-`")
+			}
+			tokens = append(tokens, token{numberToken, input[pos:end], pos})
+			pos = end
+		case isIdentStart(r):
+			end := pos
+			for end < len(input) {
+				r, width := utf8.DecodeRuneInString(input[end:])
+				if !isIdentPart(r) {
+					break
+				}
+				end += width
+			}
+			tokens = append(tokens, token{identToken, input[pos:end], pos})
+			pos = end
+		case strings.ContainsRune("(),:[]{}", r):
+			tokens = append(tokens, token{punctToken, string(r), pos})
+			pos += width
+		default:
+			op := ""
+			for _, o := range operators {
+				if strings.HasPrefix(input[pos:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, newSyntaxError(input, pos, "unexpected character `%c`", r)
+			}
+			tokens = append(tokens, token{operatorToken, op, pos})
+			pos += len(op)
+		}
+	}
+	tokens = append(tokens, token{eofToken, "", len(input)})
+	return tokens, nil
+}
+
+// lexString returns the end offset and unescaped value of the double quoted
+// string starting at the offset. Escapes are those of Go string literals,
+// which include those of JSON.
+func lexString(input string, pos int) (int, string, error) {
+	end := pos + 1
+	for end < len(input) {
+		switch input[end] {
+		case '\\':
+			end += 2
+			continue
+		case '"':
+			val, err := strconv.Unquote(input[pos : end+1])
+			if err != nil {
+				return 0, "", newSyntaxError(input, pos, "invalid string literal")
+			}
+			return end + 1, val, nil
+		}
+		end++
+	}
+	return 0, "", newSyntaxError(input, pos, "unterminated string")
+}
+
+// lexNumber returns the end offset of the number starting at the offset.
+func lexNumber(input string, pos int) int {
+	end := pos
+	if input[end] == '-' {
+		end++
+	}
+	end = skipDigits(input, end)
+	if end < len(input) && input[end] == '.' {
+		end = skipDigits(input, end+1)
+	}
+	if end < len(input) && (input[end] == 'e' || input[end] == 'E') {
+		exp := end + 1
+		if exp < len(input) && (input[exp] == '+' || input[exp] == '-') {
+			exp++
+		}
+		if exp < len(input) && isDigit(rune(input[exp])) {
+			end = skipDigits(input, exp)
+		}
+	}
+	return end
+}
+
+func skipDigits(input string, pos int) int {
+	for pos < len(input) && isDigit(rune(input[pos])) {
+		pos++
+	}
+	return pos
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isIdentPart(r rune) bool {
+	return r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
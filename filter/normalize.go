@@ -0,0 +1,547 @@
+package filter
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// Normalize returns the logically equivalent normal form of the AST, such
+// that equivalent filters share a single representation. It pushes NOT down
+// to the queries with De Morgan's laws, flattens chains of the same operator,
+// removes duplicate operands and sorts the operands of each operator. Within
+// an AND, ranges of the same field are intersected, and negated has queries
+// of the same field are merged into a single negated has. Within an OR, has
+// queries of the same field are merged into a single has. An operand and its
+// negation are contradictory within an AND and exhaustive within an OR, as is
+// an empty intersection of ranges. A nil result represents an expression that
+// every document matches, while an expression that no document matches is
+// reduced to a query AND its negation.
+//
+// Intersecting ranges assumes that fields hold a single value, as a range of
+// a field holding multiple values may match a document whose values each
+// satisfy one bound. Equals queries are never merged into has queries, as a
+// has query tests membership of a scalar in some backends, but overlap of an
+// array in others.
+//
+// Ex:
+//     NOT (a IN (1) OR a IN (2)) AND (b >= 1 AND b < 10) AND b > 5
+//
+// Normalizes into:
+//     a NOT IN (1, 2) AND range(field: "b", gt: 5, lt: 10)
+//
+func Normalize(node Node) Node {
+	if !isNormalizable(node) {
+		return node
+	}
+	return toNode(toNNF(node, false))
+}
+
+// isNormalizable returns whether the AST only contains the operators and
+// queries that Normalize understands.
+func isNormalizable(node Node) bool {
+	switch n := node.(type) {
+	case *Binary:
+		return (n.Op == And || n.Op == Or) &&
+			isNormalizable(n.Left) &&
+			isNormalizable(n.Right)
+	case *Unary:
+		return n.Op == Not && isNormalizable(n.Operand)
+	case *Query:
+		return n != nil
+	}
+	return false
+}
+
+const (
+	trueOp  = "TRUE"
+	falseOp = "FALSE"
+)
+
+// expr represents a node of an AST in negation normal form, where operators
+// are n-ary and NOT only applies to queries.
+type expr struct {
+	op       string
+	query    *Query
+	operands []*expr
+	// witness is the query that makes a FALSE expression contradictory.
+	witness *Query
+	key     string
+}
+
+func newLeaf(query *Query) *expr {
+	query = canonicalize(query)
+	return &expr{
+		query: query,
+		key: getJSON(map[string]interface{}{
+			query.Type: query.Params,
+		}),
+	}
+}
+
+func newNegation(leaf *expr) *expr {
+	return &expr{
+		op:       Not,
+		operands: []*expr{leaf},
+		key:      Not + " " + leaf.key,
+	}
+}
+
+func newFalse(witness *Query) *expr {
+	return &expr{
+		op:      falseOp,
+		witness: witness,
+		key:     falseOp,
+	}
+}
+
+func (e *expr) isLeaf() bool {
+	return e.op == ""
+}
+
+// toNNF returns the negation normal form of the node, negated if specified.
+func toNNF(node Node, negate bool) *expr {
+	switch n := node.(type) {
+	case *Unary:
+		return toNNF(n.Operand, !negate)
+	case *Binary:
+		op := n.Op
+		if negate {
+			// De Morgan's laws
+			if op == And {
+				op = Or
+			} else {
+				op = And
+			}
+		}
+		return simplify(op, []*expr{
+			toNNF(n.Left, negate),
+			toNNF(n.Right, negate),
+		})
+	}
+	leaf := newLeaf(node.(*Query))
+	if negate {
+		return newNegation(leaf)
+	}
+	return leaf
+}
+
+// simplify returns the simplified expression of the operands joined by the
+// operator.
+func simplify(op string, operands []*expr) *expr {
+	identity, absorbing := trueOp, falseOp
+	if op == Or {
+		identity, absorbing = falseOp, trueOp
+	}
+	// flatten chains of the same operator, and remove identities
+	var flat []*expr
+	var lastFalse *expr
+	for _, operand := range operands {
+		switch {
+		case operand.op == absorbing:
+			return operand
+		case operand.op == identity:
+			if operand.op == falseOp {
+				lastFalse = operand
+			}
+		case operand.op == op:
+			flat = append(flat, operand.operands...)
+		default:
+			flat = append(flat, operand)
+		}
+	}
+	flat = dedupe(flat)
+	if op == And {
+		flat = mergeRanges(flat)
+		for _, operand := range flat {
+			if operand.op == falseOp {
+				return operand
+			}
+		}
+		flat = mergeValues(flat, true)
+	} else {
+		flat = mergeValues(flat, false)
+	}
+	flat = dedupe(flat)
+	// an operand along with its negation is either contradictory or
+	// exhaustive
+	keys := make(map[string]*expr, len(flat))
+	for _, operand := range flat {
+		keys[operand.key] = operand
+	}
+	for _, operand := range flat {
+		if operand.op != Not {
+			continue
+		}
+		leaf, ok := keys[operand.operands[0].key]
+		if !ok {
+			continue
+		}
+		if op == And {
+			return newFalse(leaf.query)
+		}
+		return &expr{op: trueOp, key: trueOp}
+	}
+	switch len(flat) {
+	case 0:
+		if lastFalse != nil {
+			return lastFalse
+		}
+		return &expr{op: trueOp, key: trueOp}
+	case 1:
+		return flat[0]
+	}
+	sort.Sort(exprsByKey(flat))
+	keyStrs := make([]string, len(flat))
+	for i, operand := range flat {
+		keyStrs[i] = operand.key
+	}
+	return &expr{
+		op:       op,
+		operands: flat,
+		key:      op + "(" + strings.Join(keyStrs, ", ") + ")",
+	}
+}
+
+// exprsByKey sorts queries before negated queries, and negated queries before
+// nested expressions, each by their key.
+type exprsByKey []*expr
+
+func (e exprsByKey) Len() int      { return len(e) }
+func (e exprsByKey) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+func (e exprsByKey) Less(i, j int) bool {
+	a, b := e[i].getRank(), e[j].getRank()
+	if a != b {
+		return a < b
+	}
+	return e[i].key < e[j].key
+}
+
+func (e *expr) getRank() int {
+	switch e.op {
+	case "":
+		return 0
+	case Not:
+		return 1
+	}
+	return 2
+}
+
+// dedupe removes duplicate operands, retaining the first of each.
+func dedupe(operands []*expr) []*expr {
+	seen := make(map[string]bool, len(operands))
+	res := operands[:0]
+	for _, operand := range operands {
+		if seen[operand.key] {
+			continue
+		}
+		seen[operand.key] = true
+		res = append(res, operand)
+	}
+	return res
+}
+
+// bound represents the lower or upper bound of a range.
+type bound struct {
+	key   string
+	value float64
+}
+
+// getRange returns the field and numeric bounds of a range query, if it only
+// has numeric bounds.
+func getRange(q *Query) (string, *bound, *bound, bool) {
+	if q.Type != RangeType {
+		return "", nil, nil, false
+	}
+	field, ok := q.Params["field"].(string)
+	if !ok {
+		return "", nil, nil, false
+	}
+	var lower, upper *bound
+	for key, val := range q.Params {
+		if key == "field" {
+			continue
+		}
+		num, ok := val.(float64)
+		if !ok {
+			return "", nil, nil, false
+		}
+		switch key {
+		case "gte", "gt":
+			if lower != nil {
+				return "", nil, nil, false
+			}
+			lower = &bound{key, num}
+		case "lte", "lt":
+			if upper != nil {
+				return "", nil, nil, false
+			}
+			upper = &bound{key, num}
+		default:
+			return "", nil, nil, false
+		}
+	}
+	return field, lower, upper, true
+}
+
+// isEmpty returns whether no value is within both bounds.
+func isEmpty(lower *bound, upper *bound) bool {
+	if lower == nil || upper == nil {
+		return false
+	}
+	if lower.value != upper.value {
+		return lower.value > upper.value
+	}
+	return lower.key == "gt" || upper.key == "lt"
+}
+
+// mergeRanges intersects the ranges of each field into a single range. An
+// empty intersection is reduced to FALSE.
+func mergeRanges(operands []*expr) []*expr {
+	type intersection struct {
+		first *expr
+		lower *bound
+		upper *bound
+		count int
+	}
+	ranges := make(map[string]*intersection)
+	for _, operand := range operands {
+		if !operand.isLeaf() {
+			continue
+		}
+		field, lower, upper, ok := getRange(operand.query)
+		if !ok {
+			continue
+		}
+		r, ok := ranges[field]
+		if !ok {
+			ranges[field] = &intersection{
+				first: operand,
+				lower: lower,
+				upper: upper,
+				count: 1,
+			}
+			continue
+		}
+		// the greater lower bound, the exclusive one if equal
+		if lower != nil && (r.lower == nil || lower.value > r.lower.value ||
+			(lower.value == r.lower.value && lower.key == "gt")) {
+			r.lower = lower
+		}
+		// the lesser upper bound, the exclusive one if equal
+		if upper != nil && (r.upper == nil || upper.value < r.upper.value ||
+			(upper.value == r.upper.value && upper.key == "lt")) {
+			r.upper = upper
+		}
+		r.count++
+	}
+	var res []*expr
+	for _, operand := range operands {
+		if !operand.isLeaf() {
+			res = append(res, operand)
+			continue
+		}
+		field, _, _, ok := getRange(operand.query)
+		if !ok {
+			res = append(res, operand)
+			continue
+		}
+		r := ranges[field]
+		if r.first != operand {
+			// merged into the first range of the field
+			continue
+		}
+		if r.count == 1 && !isEmpty(r.lower, r.upper) {
+			res = append(res, operand)
+			continue
+		}
+		params := map[string]interface{}{
+			"field": field,
+		}
+		if r.lower != nil {
+			params[r.lower.key] = r.lower.value
+		}
+		if r.upper != nil {
+			params[r.upper.key] = r.upper.value
+		}
+		merged := &Query{
+			Type:   RangeType,
+			Params: params,
+		}
+		if isEmpty(r.lower, r.upper) {
+			res = append(res, newFalse(merged))
+			continue
+		}
+		res = append(res, newLeaf(merged))
+	}
+	return res
+}
+
+// getValues returns the field and values of a has query.
+func getValues(q *Query) (string, []interface{}, bool) {
+	if len(q.Params) != 2 {
+		return "", nil, false
+	}
+	field, ok := q.Params["field"].(string)
+	if !ok {
+		return "", nil, false
+	}
+	if q.Type != HasType {
+		return "", nil, false
+	}
+	vals, ok := q.Params["values"].([]interface{})
+	if !ok {
+		return "", nil, false
+	}
+	return field, vals, true
+}
+
+// mergeValues merges the has queries of each field into a single has query. If negated, the negations of the queries are merged within an
+// AND, otherwise the queries are merged within an OR.
+func mergeValues(operands []*expr, negated bool) []*expr {
+	getLeaf := func(operand *expr) (*expr, bool) {
+		if negated {
+			if operand.op != Not {
+				return nil, false
+			}
+			return operand.operands[0], true
+		}
+		return operand, operand.isLeaf()
+	}
+	type union struct {
+		first  *expr
+		values []interface{}
+		count  int
+	}
+	unions := make(map[string]*union)
+	for _, operand := range operands {
+		leaf, ok := getLeaf(operand)
+		if !ok {
+			continue
+		}
+		field, values, ok := getValues(leaf.query)
+		if !ok {
+			continue
+		}
+		u, ok := unions[field]
+		if !ok {
+			unions[field] = &union{
+				first:  operand,
+				values: values,
+				count:  1,
+			}
+			continue
+		}
+		u.values = append(u.values, values...)
+		u.count++
+	}
+	var res []*expr
+	for _, operand := range operands {
+		leaf, ok := getLeaf(operand)
+		if !ok {
+			res = append(res, operand)
+			continue
+		}
+		field, _, ok := getValues(leaf.query)
+		if !ok || unions[field].count == 1 {
+			res = append(res, operand)
+			continue
+		}
+		u := unions[field]
+		if u.first != operand {
+			// merged into the first query of the field
+			continue
+		}
+		merged := newLeaf(&Query{
+			Type: HasType,
+			Params: map[string]interface{}{
+				"field":  field,
+				"values": uniqueValues(u.values),
+			},
+		})
+		if negated {
+			merged = newNegation(merged)
+		}
+		res = append(res, merged)
+	}
+	return res
+}
+
+// uniqueValues returns the distinct values, in order of their JSON encoding.
+func uniqueValues(values []interface{}) []interface{} {
+	encoded := make(map[string]interface{}, len(values))
+	for _, val := range values {
+		encoded[getJSON(val)] = val
+	}
+	keys := make([]string, 0, len(encoded))
+	for key := range encoded {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	res := make([]interface{}, len(keys))
+	for i, key := range keys {
+		res[i] = encoded[key]
+	}
+	return res
+}
+
+// canonicalize returns the query with the values of a has query deduplicated
+// and sorted, as their order is insignificant.
+func canonicalize(q *Query) *Query {
+	vals, ok := q.Params["values"].([]interface{})
+	if q.Type != HasType || !ok {
+		return q
+	}
+	params := make(map[string]interface{}, len(q.Params))
+	for key, val := range q.Params {
+		params[key] = val
+	}
+	params["values"] = uniqueValues(vals)
+	return &Query{
+		Type:   q.Type,
+		Params: params,
+	}
+}
+
+func getJSON(val interface{}) string {
+	// map keys are sorted when marshalled
+	bytes, err := json.Marshal(val)
+	if err != nil {
+		return formatValue(val)
+	}
+	return string(bytes)
+}
+
+// toNode returns the AST of the expression, with the operands of each
+// operator chained from the left.
+func toNode(e *expr) Node {
+	switch e.op {
+	case trueOp:
+		return nil
+	case falseOp:
+		return &Binary{
+			Left: e.witness,
+			Op:   And,
+			Right: &Unary{
+				Op:      Not,
+				Operand: e.witness,
+			},
+		}
+	case Not:
+		return &Unary{
+			Op:      Not,
+			Operand: e.operands[0].query,
+		}
+	case And, Or:
+		node := toNode(e.operands[0])
+		for _, operand := range e.operands[1:] {
+			node = &Binary{
+				Left:  node,
+				Op:    e.op,
+				Right: toNode(operand),
+			}
+		}
+		return node
+	}
+	return e.query
+}
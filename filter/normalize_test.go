@@ -0,0 +1,96 @@
+package filter_test
+
+import (
+	"github.com/unchartedsoftware/veldt/filter"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func normalize(input string) string {
+	node, err := filter.Parse(input)
+	Expect(err).To(BeNil())
+	normalized := filter.Normalize(node)
+	if normalized == nil {
+		return ""
+	}
+	return normalized.String()
+}
+
+var _ = Describe("Normalize", func() {
+
+	It("should flatten and sort chains of the same operator", func() {
+		Expect(normalize(`c = 3 AND (b = 2 AND a = 1)`)).To(Equal(
+			`a = 1 AND b = 2 AND c = 3`))
+		Expect(normalize(`(c = 3 OR b = 2) AND a = 1`)).To(Equal(
+			`a = 1 AND (b = 2 OR c = 3)`))
+	})
+
+	It("should push NOT down with De Morgan's laws", func() {
+		Expect(normalize(`NOT (exists(a) AND exists(b))`)).To(Equal(
+			`NOT exists(a) OR NOT exists(b)`))
+		Expect(normalize(`NOT (exists(a) OR NOT exists(b))`)).To(Equal(
+			`exists(b) AND NOT exists(a)`))
+		Expect(normalize(`NOT NOT exists(a)`)).To(Equal(`exists(a)`))
+	})
+
+	It("should remove duplicate operands", func() {
+		Expect(normalize(`exists(a) AND exists(b) AND exists(a)`)).To(Equal(
+			`exists(a) AND exists(b)`))
+		Expect(normalize(`tag IN ("a", "b") OR tag IN ("b", "a")`)).To(Equal(
+			`tag IN ("a", "b")`))
+	})
+
+	It("should reduce contradictory and exhaustive operands", func() {
+		Expect(normalize(`exists(b) AND exists(a) AND NOT exists(a)`)).To(Equal(
+			`exists(a) AND NOT exists(a)`))
+		Expect(normalize(`exists(a) OR NOT exists(a)`)).To(Equal(``))
+		Expect(normalize(`exists(b) AND (exists(a) OR NOT exists(a))`)).To(Equal(
+			`exists(b)`))
+		Expect(normalize(`exists(b) OR (exists(a) AND NOT exists(a))`)).To(Equal(
+			`exists(b)`))
+	})
+
+	It("should intersect ranges of the same field", func() {
+		Expect(normalize(`a >= 1 AND a < 10 AND a > 5 AND b < 2`)).To(Equal(
+			`range(field: "a", gt: 5, lt: 10) AND b < 2`))
+		Expect(normalize(`a >= 5 AND a > 5 AND a <= 7 AND a < 7`)).To(Equal(
+			`range(field: "a", gt: 5, lt: 7)`))
+		Expect(normalize(`a >= "2017" AND a < 10`)).To(Equal(
+			`a >= "2017" AND a < 10`))
+	})
+
+	It("should reduce empty intersections of ranges", func() {
+		Expect(normalize(`a > 10 AND a < 5 AND exists(b)`)).To(Equal(
+			`range(field: "a", gt: 10, lt: 5) AND NOT range(field: "a", gt: 10, lt: 5)`))
+		Expect(normalize(`a >= 5 AND a < 5`)).To(Equal(
+			`range(field: "a", gte: 5, lt: 5) AND NOT range(field: "a", gte: 5, lt: 5)`))
+		Expect(normalize(`a >= 5 AND a <= 5`)).To(Equal(
+			`range(field: "a", gte: 5, lte: 5)`))
+		Expect(normalize(`exists(b) OR (a > 10 AND a < 5)`)).To(Equal(
+			`exists(b)`))
+	})
+
+	It("should merge has queries of the same field", func() {
+		Expect(normalize(`a IN (2) OR a IN (3, 1) OR b = 1`)).To(Equal(
+			`b = 1 OR a IN (1, 2, 3)`))
+		Expect(normalize(`NOT (a IN (2) OR a IN (1))`)).To(Equal(
+			`a NOT IN (1, 2)`))
+	})
+
+	It("should not merge equals into has, as has differs between backends", func() {
+		Expect(normalize(`a = 2 OR a = 1`)).To(Equal(
+			`a = 1 OR a = 2`))
+		Expect(normalize(`a = 2 OR a IN (1)`)).To(Equal(
+			`a = 2 OR a IN (1)`))
+		Expect(normalize(`NOT (a = 2 OR a = 1)`)).To(Equal(
+			`a != 1 AND a != 2`))
+	})
+
+	It("should normalize equivalent expressions identically", func() {
+		a := normalize(`NOT (x != 1 AND y < 2) AND (z IN ("a") OR z IN ("b"))`)
+		b := normalize(`(z IN ("b", "a")) AND (NOT y < 2 OR x = 1)`)
+		Expect(a).To(Equal(b))
+	})
+
+})
@@ -0,0 +1,415 @@
+package filter
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Parse parses a filter expression into its AST. Comparisons compile into
+// the query types of the `query` package, while any registered query type
+// may be called as a function with named parameters. NOT binds tighter than
+// AND, which binds tighter than OR, and operators are case insensitive.
+//
+//     field = value               equals
+//     field != value              NOT equals
+//     field >= value              range, as do `>`, `<=` and `<`
+//     field IN (value, ...)       has
+//     field NOT IN (value, ...)   NOT has
+//     field ~ "pattern"           matchesString
+//     exists(field)               exists
+//     type(key: value, ...)       the query type registered under `type`
+//
+// Fields are identifiers such as `person.age`, or any string enclosed in
+// backticks. Values are JSON strings, numbers, booleans and null, along with
+// arrays and objects whose keys need not be quoted.
+//
+// Ex:
+//     name = "john" AND (age >= 18 OR NOT exists(email)) AND tag IN ("a", "b") AND text ~ "foo*"
+//
+func Parse(input string) (Node, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{
+		input:  input,
+		tokens: tokens,
+	}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	next := p.peek()
+	if next.typ != eofToken {
+		return nil, p.errorf(next, "expected AND, OR or end of input, found %s", next)
+	}
+	return node, nil
+}
+
+type parser struct {
+	input  string
+	tokens []token
+	index  int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.index]
+}
+
+func (p *parser) peekNext() token {
+	if p.index+1 < len(p.tokens) {
+		return p.tokens[p.index+1]
+	}
+	return p.tokens[len(p.tokens)-1]
+}
+
+func (p *parser) pop() token {
+	t := p.tokens[p.index]
+	if t.typ != eofToken {
+		p.index++
+	}
+	return t
+}
+
+func (p *parser) errorf(t token, format string, args ...interface{}) error {
+	return newSyntaxError(p.input, t.pos, format, args...)
+}
+
+// expect pops the next token, returning an error if it is not the provided
+// punctuation.
+func (p *parser) expect(punct string) error {
+	t := p.pop()
+	if !isPunct(t, punct) {
+		return p.errorf(t, "expected `%s`, found %s", punct, t)
+	}
+	return nil
+}
+
+func isPunct(t token, punct string) bool {
+	return t.typ == punctToken && t.val == punct
+}
+
+func isKeyword(t token, keyword string) bool {
+	return t.typ == identToken && strings.EqualFold(t.val, keyword)
+}
+
+func isReserved(t token) bool {
+	return isKeyword(t, And) || isKeyword(t, Or) || isKeyword(t, Not) || isKeyword(t, "IN")
+}
+
+func (p *parser) parseOr() (Node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for isKeyword(p.peek(), Or) {
+		p.pop()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Binary{
+			Left:  left,
+			Op:    Or,
+			Right: right,
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for isKeyword(p.peek(), And) {
+		p.pop()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &Binary{
+			Left:  left,
+			Op:    And,
+			Right: right,
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	if isKeyword(p.peek(), Not) {
+		p.pop()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Unary{
+			Op:      Not,
+			Operand: operand,
+		}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	t := p.peek()
+	switch {
+	case isPunct(t, "("):
+		p.pop()
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		err = p.expect(")")
+		if err != nil {
+			return nil, err
+		}
+		return node, nil
+	case t.typ == identToken && !isReserved(t) && isPunct(p.peekNext(), "("):
+		return p.parseCall()
+	case t.typ == fieldToken || (t.typ == identToken && !isReserved(t)):
+		return p.parseComparison()
+	}
+	return nil, p.errorf(t, "expected a comparison, a query or `(`, found %s", t)
+}
+
+// parseComparison parses a comparison of a field, such as `age >= 18`.
+func (p *parser) parseComparison() (Node, error) {
+	field := p.pop().val
+	t := p.pop()
+	switch {
+	case t.typ == operatorToken && t.val == "~":
+		match := p.pop()
+		if match.typ != stringToken {
+			return nil, p.errorf(match, "expected a string after `~`, found %s", match)
+		}
+		return &Query{
+			Type: MatchesStringType,
+			Params: map[string]interface{}{
+				"match":  match.val,
+				"fields": []interface{}{field},
+			},
+		}, nil
+	case t.typ == operatorToken:
+		val, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		switch t.val {
+		case "=":
+			return newEquals(field, val), nil
+		case "!=":
+			return &Unary{
+				Op:      Not,
+				Operand: newEquals(field, val),
+			}, nil
+		}
+		return &Query{
+			Type: RangeType,
+			Params: map[string]interface{}{
+				"field":         field,
+				rangeKeys[t.val]: val,
+			},
+		}, nil
+	case isKeyword(t, "IN"):
+		return p.parseIn(field)
+	case isKeyword(t, Not) && isKeyword(p.peek(), "IN"):
+		p.pop()
+		has, err := p.parseIn(field)
+		if err != nil {
+			return nil, err
+		}
+		return &Unary{
+			Op:      Not,
+			Operand: has,
+		}, nil
+	}
+	return nil, p.errorf(t, "expected a comparison operator after `%s`, found %s", field, t)
+}
+
+// rangeKeys maps the range comparison operators to their range parameter.
+var rangeKeys = map[string]string{
+	">=": "gte",
+	">":  "gt",
+	"<=": "lte",
+	"<":  "lt",
+}
+
+func newEquals(field string, val interface{}) *Query {
+	return &Query{
+		Type: EqualsType,
+		Params: map[string]interface{}{
+			"field": field,
+			"value": val,
+		},
+	}
+}
+
+// parseIn parses the parenthesized values of an IN comparison.
+func (p *parser) parseIn(field string) (Node, error) {
+	err := p.expect("(")
+	if err != nil {
+		return nil, err
+	}
+	values, err := p.parseValues(")")
+	if err != nil {
+		return nil, err
+	}
+	return &Query{
+		Type: HasType,
+		Params: map[string]interface{}{
+			"field":  field,
+			"values": values,
+		},
+	}, nil
+}
+
+// parseCall parses a query of a registered type, such as `exists(email)` or
+// `range(field: age, gte: 18, lt: 65)`. A single positional argument is the
+// `field` parameter, and parameters may be fields as well as values.
+func (p *parser) parseCall() (Node, error) {
+	typ := p.pop().val
+	p.pop() // (
+	params := make(map[string]interface{})
+	if isPunct(p.peek(), ")") {
+		p.pop()
+		return &Query{Type: typ, Params: params}, nil
+	}
+	first := p.peek()
+	if (first.typ == identToken || first.typ == fieldToken || first.typ == stringToken) &&
+		isPunct(p.peekNext(), ")") {
+		// single positional argument
+		p.pop()
+		p.pop()
+		params["field"] = first.val
+		return &Query{Type: typ, Params: params}, nil
+	}
+	for {
+		key := p.pop()
+		if key.typ != identToken && key.typ != fieldToken && key.typ != stringToken {
+			return nil, p.errorf(key, "expected a parameter name, found %s", key)
+		}
+		if _, ok := params[key.val]; ok {
+			return nil, p.errorf(key, "duplicate parameter `%s`", key.val)
+		}
+		err := p.expect(":")
+		if err != nil {
+			return nil, err
+		}
+		val, err := p.parseParam()
+		if err != nil {
+			return nil, err
+		}
+		params[key.val] = val
+		t := p.pop()
+		if isPunct(t, ")") {
+			return &Query{Type: typ, Params: params}, nil
+		}
+		if !isPunct(t, ",") {
+			return nil, p.errorf(t, "expected `,` or `)`, found %s", t)
+		}
+	}
+}
+
+// parseParam parses the value of a named parameter, which may also be a
+// field, such as in `range(field: age, gte: 18)`.
+func (p *parser) parseParam() (interface{}, error) {
+	t := p.peek()
+	if t.typ == fieldToken ||
+		(t.typ == identToken && !isReserved(t) && !isLiteral(t)) {
+		p.pop()
+		return t.val, nil
+	}
+	return p.parseValue()
+}
+
+func isLiteral(t token) bool {
+	return t.typ == identToken && (t.val == "true" || t.val == "false" || t.val == "null")
+}
+
+// parseValue parses a JSON value, allowing unquoted object keys.
+func (p *parser) parseValue() (interface{}, error) {
+	t := p.pop()
+	switch t.typ {
+	case stringToken:
+		return t.val, nil
+	case numberToken:
+		num, err := strconv.ParseFloat(t.val, 64)
+		if err != nil {
+			return nil, p.errorf(t, "invalid number %s", t)
+		}
+		return num, nil
+	case identToken:
+		switch t.val {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+	case punctToken:
+		switch t.val {
+		case "[":
+			return p.parseValues("]")
+		case "{":
+			return p.parseObject()
+		}
+	}
+	return nil, p.errorf(t, "expected a value, found %s", t)
+}
+
+// parseValues parses comma separated values up to the closing punctuation.
+func (p *parser) parseValues(end string) ([]interface{}, error) {
+	values := make([]interface{}, 0)
+	if isPunct(p.peek(), end) {
+		p.pop()
+		return values, nil
+	}
+	for {
+		val, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, val)
+		t := p.pop()
+		if isPunct(t, end) {
+			return values, nil
+		}
+		if !isPunct(t, ",") {
+			return nil, p.errorf(t, "expected `,` or `%s`, found %s", end, t)
+		}
+	}
+}
+
+// parseObject parses the members of an object up to the closing brace.
+func (p *parser) parseObject() (map[string]interface{}, error) {
+	obj := make(map[string]interface{})
+	if isPunct(p.peek(), "}") {
+		p.pop()
+		return obj, nil
+	}
+	for {
+		key := p.pop()
+		if key.typ != identToken && key.typ != fieldToken && key.typ != stringToken {
+			return nil, p.errorf(key, "expected an object key, found %s", key)
+		}
+		err := p.expect(":")
+		if err != nil {
+			return nil, err
+		}
+		val, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		obj[key.val] = val
+		t := p.pop()
+		if isPunct(t, "}") {
+			return obj, nil
+		}
+		if !isPunct(t, ",") {
+			return nil, p.errorf(t, "expected `,` or `}`, found %s", t)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package filter_test
+
+import (
+	"encoding/json"
+
+	"github.com/unchartedsoftware/veldt/filter"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func toJSON(arg interface{}) string {
+	bytes, err := json.Marshal(arg)
+	Expect(err).To(BeNil())
+	return string(bytes)
+}
+
+func compile(input string) string {
+	compiled, err := filter.Compile(input)
+	Expect(err).To(BeNil())
+	return toJSON(compiled)
+}
+
+func syntaxError(input string) *filter.SyntaxError {
+	_, err := filter.Parse(input)
+	Expect(err).To(HaveOccurred())
+	serr, ok := err.(*filter.SyntaxError)
+	Expect(ok).To(Equal(true))
+	return serr
+}
+
+var _ = Describe("Parse", func() {
+
+	It("should compile comparisons into queries", func() {
+		Expect(compile(`name = "john"`)).To(Equal(
+			`{"equals":{"field":"name","value":"john"}}`))
+		Expect(compile(`age >= 18`)).To(Equal(
+			`{"range":{"field":"age","gte":18}}`))
+		Expect(compile(`age < 65.5`)).To(Equal(
+			`{"range":{"field":"age","lt":65.5}}`))
+		Expect(compile(`tag IN ("a", "b")`)).To(Equal(
+			`{"has":{"field":"tag","values":["a","b"]}}`))
+		Expect(compile(`text ~ "foo*"`)).To(Equal(
+			`{"matchesString":{"fields":["text"],"match":"foo*"}}`))
+		Expect(compile(`exists(email)`)).To(Equal(
+			`{"exists":{"field":"email"}}`))
+	})
+
+	It("should compile negated comparisons", func() {
+		Expect(compile(`name != "john"`)).To(Equal(
+			`["NOT",{"equals":{"field":"name","value":"john"}}]`))
+		Expect(compile(`tag NOT IN (1, 2)`)).To(Equal(
+			`["NOT",{"has":{"field":"tag","values":[1,2]}}]`))
+	})
+
+	It("should compile calls with named parameters", func() {
+		Expect(compile(`range(field: age, gte: 18, lt: 65)`)).To(Equal(
+			`{"range":{"field":"age","gte":18,"lt":65}}`))
+		Expect(compile(`custom(a: [1, "b", true, null], b: {c: -1e3, "d": false})`)).To(Equal(
+			`{"custom":{"a":[1,"b",true,null],"b":{"c":-1000,"d":false}}}`))
+	})
+
+	It("should support quoted fields and escaped strings", func() {
+		Expect(compile("`first name` = \"jo\\\"hn\"")).To(Equal(
+			`{"equals":{"field":"first name","value":"jo\"hn"}}`))
+		Expect(compile("`and` = 1")).To(Equal(
+			`{"equals":{"field":"and","value":1}}`))
+		Expect(compile(`person.age = 1`)).To(Equal(
+			`{"equals":{"field":"person.age","value":1}}`))
+	})
+
+	It("should bind NOT tighter than AND, and AND tighter than OR", func() {
+		Expect(compile(`a = 1 OR b = 2 AND NOT c = 3`)).To(Equal(
+			`[{"equals":{"field":"a","value":1}},"OR",` +
+				`[{"equals":{"field":"b","value":2}},"AND","NOT",{"equals":{"field":"c","value":3}}]]`))
+	})
+
+	It("should flatten chains of the same operator and nest parentheses", func() {
+		Expect(compile(`a = 1 and (b = 2 or c = 3) and d = 4`)).To(Equal(
+			`[{"equals":{"field":"a","value":1}},"AND",` +
+				`[{"equals":{"field":"b","value":2}},"OR",{"equals":{"field":"c","value":3}}],"AND",` +
+				`{"equals":{"field":"d","value":4}}]`))
+		Expect(compile(`NOT (a = 1 OR b = 2)`)).To(Equal(
+			`["NOT",[{"equals":{"field":"a","value":1}},"OR",{"equals":{"field":"b","value":2}}]]`))
+	})
+
+	It("should report the position of syntax errors", func() {
+		err := syntaxError(`name = "john" AND`)
+		Expect(err.Offset).To(Equal(17))
+		Expect(err.Column).To(Equal(18))
+		Expect(err.Error()).To(Equal("expected a comparison, a query or `(`, found end of input at line 1, column 18"))
+
+		err = syntaxError("a = 1 AND\n  b >> 2")
+		Expect(err.Line).To(Equal(2))
+		Expect(err.Column).To(Equal(6))
+		Expect(err.Msg).To(Equal("expected a value, found `>`"))
+
+		err = syntaxError(`(a = 1 OR b = 2`)
+		Expect(err.Msg).To(Equal("expected `)`, found end of input"))
+
+		err = syntaxError(`a = 1 b = 2`)
+		Expect(err.Column).To(Equal(7))
+
+		err = syntaxError(`a = "john`)
+		Expect(err.Msg).To(Equal("unterminated string"))
+		Expect(err.Column).To(Equal(5))
+
+		err = syntaxError(`a # 1`)
+		Expect(err.Msg).To(Equal("unexpected character `#`"))
+
+		err = syntaxError(`a ~ 1`)
+		Expect(err.Msg).To(Equal("expected a string after `~`, found `1`"))
+
+		err = syntaxError(`range(field: a, field: b)`)
+		Expect(err.Msg).To(Equal("duplicate parameter `field`"))
+	})
+
+})
@@ -1,5 +1,11 @@
 package veldt
 
+import (
+	"context"
+
+	"github.com/unchartedsoftware/veldt/trace"
+)
+
 // GenerateTile generates a tile for the provided pipeline ID and JSON request.
 func GenerateTile(id string, args map[string]interface{}) error {
 	pipeline, err := GetPipeline(id)
@@ -13,6 +19,22 @@ func GenerateTile(id string, args map[string]interface{}) error {
 	return pipeline.Generate(req)
 }
 
+// GenerateTileContext generates a tile for the provided pipeline ID and JSON
+// request. The context is used to abandon and trace the request.
+func GenerateTileContext(ctx context.Context, id string, args map[string]interface{}) error {
+	ctx, span := startRequestSpan(ctx, id)
+	defer span.End()
+	pipeline, err := GetPipeline(id)
+	if err != nil {
+		return err
+	}
+	req, err := pipeline.NewTileRequestContext(ctx, args)
+	if err != nil {
+		return err
+	}
+	return pipeline.GenerateContext(ctx, req)
+}
+
 // GetTile retrieves a tile from the store for the provided pipeline ID
 // and JSON request.
 func GetTile(id string, args map[string]interface{}) ([]byte, error) {
@@ -27,6 +49,22 @@ func GetTile(id string, args map[string]interface{}) ([]byte, error) {
 	return pipeline.Get(req)
 }
 
+// GetTileContext retrieves a tile from the store for the provided pipeline ID
+// and JSON request. The context is used to abandon and trace the request.
+func GetTileContext(ctx context.Context, id string, args map[string]interface{}) ([]byte, error) {
+	ctx, span := startRequestSpan(ctx, id)
+	defer span.End()
+	pipeline, err := GetPipeline(id)
+	if err != nil {
+		return nil, err
+	}
+	req, err := pipeline.NewTileRequestContext(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return pipeline.GetContext(ctx, req)
+}
+
 // GenerateAndGetTile generates and retrieves a tile from the store
 // for the provided pipeline ID and JSON request.
 func GenerateAndGetTile(id string, args map[string]interface{}) ([]byte, error) {
@@ -41,6 +79,23 @@ func GenerateAndGetTile(id string, args map[string]interface{}) ([]byte, error)
 	return pipeline.GenerateAndGet(req)
 }
 
+// GenerateAndGetTileContext generates and retrieves a tile from the store for
+// the provided pipeline ID and JSON request. The context is used to abandon and
+// trace the request.
+func GenerateAndGetTileContext(ctx context.Context, id string, args map[string]interface{}) ([]byte, error) {
+	ctx, span := startRequestSpan(ctx, id)
+	defer span.End()
+	pipeline, err := GetPipeline(id)
+	if err != nil {
+		return nil, err
+	}
+	req, err := pipeline.NewTileRequestContext(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return pipeline.GenerateAndGetContext(ctx, req)
+}
+
 // GenerateMeta generates meta data for the provided pipeline ID and JSON
 // request.
 func GenerateMeta(id string, args map[string]interface{}) error {
@@ -55,6 +110,22 @@ func GenerateMeta(id string, args map[string]interface{}) error {
 	return pipeline.Generate(req)
 }
 
+// GenerateMetaContext generates meta data for the provided pipeline ID and JSON
+// request. The context is used to abandon and trace the request.
+func GenerateMetaContext(ctx context.Context, id string, args map[string]interface{}) error {
+	ctx, span := startRequestSpan(ctx, id)
+	defer span.End()
+	pipeline, err := GetPipeline(id)
+	if err != nil {
+		return err
+	}
+	req, err := pipeline.NewMetaRequestContext(ctx, args)
+	if err != nil {
+		return err
+	}
+	return pipeline.GenerateContext(ctx, req)
+}
+
 // GetMeta retrieves metadata from the store for the provided pipeline
 // ID and JSON request.
 func GetMeta(id string, args map[string]interface{}) ([]byte, error) {
@@ -69,6 +140,22 @@ func GetMeta(id string, args map[string]interface{}) ([]byte, error) {
 	return pipeline.Get(req)
 }
 
+// GetMetaContext retrieves metadata from the store for the provided pipeline ID
+// and JSON request. The context is used to abandon and trace the request.
+func GetMetaContext(ctx context.Context, id string, args map[string]interface{}) ([]byte, error) {
+	ctx, span := startRequestSpan(ctx, id)
+	defer span.End()
+	pipeline, err := GetPipeline(id)
+	if err != nil {
+		return nil, err
+	}
+	req, err := pipeline.NewMetaRequestContext(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return pipeline.GetContext(ctx, req)
+}
+
 // GenerateAndGetMeta generates and retrieves a metadata from the store
 // for the provided pipeline ID and JSON request.
 func GenerateAndGetMeta(id string, args map[string]interface{}) ([]byte, error) {
@@ -82,3 +169,37 @@ func GenerateAndGetMeta(id string, args map[string]interface{}) ([]byte, error)
 	}
 	return pipeline.GenerateAndGet(req)
 }
+
+// GenerateAndGetMetaContext generates and retrieves a metadata from the store
+// for the provided pipeline ID and JSON request. The context is used to abandon
+// and trace the request.
+func GenerateAndGetMetaContext(ctx context.Context, id string, args map[string]interface{}) ([]byte, error) {
+	ctx, span := startRequestSpan(ctx, id)
+	defer span.End()
+	pipeline, err := GetPipeline(id)
+	if err != nil {
+		return nil, err
+	}
+	req, err := pipeline.NewMetaRequestContext(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return pipeline.GenerateAndGetContext(ctx, req)
+}
+
+// Invalidate removes all tiles and metadata generated for the provided URI
+// from the store of the provided pipeline ID.
+func Invalidate(id string, uri string) error {
+	pipeline, err := GetPipeline(id)
+	if err != nil {
+		return err
+	}
+	return pipeline.Invalidate(uri)
+}
+
+// startRequestSpan starts the span of a request for the pipeline ID.
+func startRequestSpan(ctx context.Context, id string) (context.Context, *trace.Span) {
+	ctx, span := trace.Start(ctx, trace.RequestSpan)
+	span.SetAttribute(trace.PipelineAttribute, id)
+	return ctx, span
+}
@@ -16,25 +16,33 @@ type Bivariate struct {
 }
 
 // AddQuery adds the tiling query to the provided query object.
-func (b *Bivariate) AddQuery(coord *binning.TileCoord, query *Query) *Query {
+func (b *Bivariate) AddQuery(coord *binning.TileCoord, query *Query) (*Query, error) {
+	x, y, err := b.getColumns(query)
+	if err != nil {
+		return nil, err
+	}
 	// get tile bounds
 	bounds := b.TileBounds(coord)
 	// x
 	minXArg := query.AddParameter(int64(bounds.MinX()))
 	maxXArg := query.AddParameter(int64(bounds.MaxX()))
-	rangeQueryX := fmt.Sprintf("%s >= %s and %s < %s", b.XField, minXArg, b.XField, maxXArg)
+	rangeQueryX := fmt.Sprintf("%s >= %s and %s < %s", x, minXArg, x, maxXArg)
 	query.Where(rangeQueryX)
 	// y
 	minYArg := query.AddParameter(int64(bounds.MinY()))
 	maxYArg := query.AddParameter(int64(bounds.MaxY()))
-	rangeQueryY := fmt.Sprintf("%s >= %s and %s < %s", b.YField, minYArg, b.YField, maxYArg)
+	rangeQueryY := fmt.Sprintf("%s >= %s and %s < %s", y, minYArg, y, maxYArg)
 	query.Where(rangeQueryY)
 	// result
-	return query
+	return query, nil
 }
 
 // AddAggs adds the tiling aggregations to the provided query object.
-func (b *Bivariate) AddAggs(coord *binning.TileCoord, query *Query) *Query {
+func (b *Bivariate) AddAggs(coord *binning.TileCoord, query *Query) (*Query, error) {
+	x, y, err := b.getColumns(query)
+	if err != nil {
+		return nil, err
+	}
 	bounds := b.TileBounds(coord)
 	// bin
 	minX := int64(bounds.MinX())
@@ -44,17 +52,30 @@ func (b *Bivariate) AddAggs(coord *binning.TileCoord, query *Query) *Query {
 	// x
 	minXArg := query.AddParameter(minX)
 	intervalXArg := query.AddParameter(intervalX)
-	queryString := fmt.Sprintf("((%s - %s) / %s * %s)", b.XField, minXArg, intervalXArg, intervalXArg)
+	queryString := fmt.Sprintf("((%s - %s) / %s * %s)", x, minXArg, intervalXArg, intervalXArg)
 	query.GroupBy(queryString)
 	query.Select(fmt.Sprintf("%s + %s as x", minXArg, queryString))
 	// y
 	minYArg := query.AddParameter(minY)
 	intervalYArg := query.AddParameter(intervalY)
-	queryString = fmt.Sprintf("((%s - %s) / %s * %s)", b.YField, minYArg, intervalYArg, intervalYArg)
+	queryString = fmt.Sprintf("((%s - %s) / %s * %s)", y, minYArg, intervalYArg, intervalYArg)
 	query.GroupBy(queryString)
 	query.Select(fmt.Sprintf("%s + %s as y", minYArg, queryString))
 	// result
-	return query
+	return query, nil
+}
+
+// getColumns returns the quoted identifiers of the x and y fields.
+func (b *Bivariate) getColumns(query *Query) (string, string, error) {
+	x, err := query.Column(b.XField)
+	if err != nil {
+		return "", "", err
+	}
+	y, err := query.Column(b.YField)
+	if err != nil {
+		return "", "", err
+	}
+	return x, y, nil
 }
 
 // GetBins parses the resulting histograms into bins.
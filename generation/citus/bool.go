@@ -2,6 +2,7 @@ package citus
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/unchartedsoftware/veldt"
 )
@@ -17,38 +18,26 @@ func NewBinaryExpression() (veldt.Query, error) {
 }
 
 // Get adds the parameters to the query and returns the string representation.
+// Chains of the same operator are joined within a single set of parentheses.
 func (e *BinaryExpression) Get(query *Query) (string, error) {
-
-	left, ok := e.Left.(QueryString)
-	if !ok {
-		return "", fmt.Errorf("`Left` is not of type citus.Query")
-	}
-	right, ok := e.Right.(QueryString)
-	if !ok {
-		return "", fmt.Errorf("`Right` is not of type citus.Query")
-	}
-
-	queryStringLeft, err := left.Get(query)
-	if err != nil {
-		return "", err
-	}
-	queryStringRight, err := right.Get(query)
-	if err != nil {
-		return "", err
-	}
-
-	res := ""
-	switch e.Op {
-	case veldt.And:
-		// AND
-		res = fmt.Sprintf("((%s) AND (%s))", queryStringLeft, queryStringRight)
-	case veldt.Or:
-		// OR
-		res = fmt.Sprintf("((%s) OR (%s))", queryStringLeft, queryStringRight)
-	default:
+	if e.Op != veldt.And && e.Op != veldt.Or {
 		return "", fmt.Errorf("`%v` operator is not a valid binary operator", e.Op)
 	}
-	return res, nil
+	operands := e.GetOperands()
+	queryStrings := make([]string, len(operands))
+	for i, operand := range operands {
+		q, ok := operand.(QueryString)
+		if !ok {
+			return "", fmt.Errorf("operand is not of type citus.Query")
+		}
+		queryString, err := q.Get(query)
+		if err != nil {
+			return "", err
+		}
+		queryStrings[i] = fmt.Sprintf("(%s)", queryString)
+	}
+	// AND / OR
+	return fmt.Sprintf("(%s)", strings.Join(queryStrings, fmt.Sprintf(" %s ", e.Op))), nil
 }
 
 // UnaryExpression represents a must_not boolean query.
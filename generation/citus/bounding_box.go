@@ -0,0 +1,39 @@
+package citus
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// BoundingBox represents a citus bounding box query.
+type BoundingBox struct {
+	query.BoundingBox
+}
+
+// NewBoundingBox instantiates and returns a new query struct.
+func NewBoundingBox() (veldt.Query, error) {
+	return &BoundingBox{}, nil
+}
+
+// Get adds the parameters to the query and returns the string representation.
+func (q *BoundingBox) Get(query *Query) (string, error) {
+	if q.IsPair() {
+		x, y, err := getPairColumns(query, &q.Spatial)
+		if err != nil {
+			return "", err
+		}
+		return getBoundsClause(query, x, y, &q.Bounds), nil
+	}
+	field, err := query.Column(q.Field)
+	if err != nil {
+		return "", err
+	}
+	minXParam := query.AddParameter(q.MinX())
+	minYParam := query.AddParameter(q.MinY())
+	maxXParam := query.AddParameter(q.MaxX())
+	maxYParam := query.AddParameter(q.MaxY())
+	return fmt.Sprintf("ST_Intersects(%s, ST_MakeEnvelope(%s, %s, %s, %s, 4326))",
+		field, minXParam, minYParam, maxXParam, maxYParam), nil
+}
@@ -0,0 +1,150 @@
+package citus
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx"
+
+	"github.com/unchartedsoftware/veldt/resilience"
+)
+
+const (
+	defaultSchema = "public"
+	catalogExpiry = time.Minute
+	columnsQuery  = "SELECT column_name, data_type FROM information_schema.columns WHERE table_schema = $1 AND table_name = $2 ORDER BY ordinal_position;"
+)
+
+var (
+	catalogMutex = sync.Mutex{}
+	catalog      = make(map[string]*catalogEntry)
+)
+
+type catalogEntry struct {
+	table   *Table
+	expires time.Time
+}
+
+// IdentifierError represents a table or column of a request that does not
+// exist in the database.
+type IdentifierError struct {
+	Table  string
+	Column string
+}
+
+// Error returns the error message.
+func (e *IdentifierError) Error() string {
+	if e.Column != "" {
+		return fmt.Sprintf("column `%s` does not exist in table `%s`", e.Column, e.Table)
+	}
+	return fmt.Sprintf("table `%s` does not exist", e.Table)
+}
+
+// Table represents a table of the database and the types of its columns.
+type Table struct {
+	Schema  string
+	Name    string
+	Columns map[string]string
+}
+
+// String returns the `schema.table` name of the table.
+func (t *Table) String() string {
+	return t.Schema + "." + t.Name
+}
+
+// Quote returns the quoted `schema.table` identifier of the table.
+func (t *Table) Quote() string {
+	return QuoteIdentifier(t.Schema) + "." + QuoteIdentifier(t.Name)
+}
+
+// Column returns the quoted identifier of the column, or an error if the
+// table has no such column. As the error is caused by the request, it is
+// permanent.
+func (t *Table) Column(column string) (string, error) {
+	_, ok := t.Columns[column]
+	if !ok {
+		return "", resilience.Permanent(&IdentifierError{
+			Table:  t.String(),
+			Column: column,
+		})
+	}
+	return QuoteIdentifier(column), nil
+}
+
+// QuoteIdentifier returns the identifier enclosed in double quotes, with any
+// double quotes within it escaped, such that it is never interpreted as SQL.
+func QuoteIdentifier(identifier string) string {
+	return `"` + strings.Replace(identifier, `"`, `""`, -1) + `"`
+}
+
+// ParseTable returns the schema and table names of a `table` or
+// `schema.table` URI. The schema defaults to `public`.
+func ParseTable(uri string) (string, string, error) {
+	split := strings.Split(uri, ".")
+	if len(split) == 1 {
+		split = []string{defaultSchema, split[0]}
+	}
+	if len(split) != 2 || split[0] == "" || split[1] == "" {
+		return "", "", resilience.Permanent(
+			fmt.Errorf("incorrect format for table `%s`, expect 'table' or 'schema.table'", uri))
+	}
+	return split[0], split[1], nil
+}
+
+// GetTable returns the table of the URI from the catalog of the database,
+// looking up its columns in the information schema if they are not cached.
+// Tables are cached for a minute, such that schema changes are picked up.
+func GetTable(client *pgx.ConnPool, cfg *Config, uri string) (*Table, error) {
+	schema, name, err := ParseTable(uri)
+	if err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("%s/%s/%s.%s", cfg.GetEndpoint(), cfg.Database, schema, name)
+
+	catalogMutex.Lock()
+	entry, ok := catalog[key]
+	catalogMutex.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.table, nil
+	}
+
+	rows, err := client.Query(columnsQuery, schema, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	table := &Table{
+		Schema:  schema,
+		Name:    name,
+		Columns: make(map[string]string),
+	}
+	for rows.Next() {
+		var column string
+		var typ string
+		err := rows.Scan(&column, &typ)
+		if err != nil {
+			return nil, err
+		}
+		table.Columns[column] = typ
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	// missing tables are not cached, as they may yet be created
+	if len(table.Columns) == 0 {
+		return nil, resilience.Permanent(&IdentifierError{
+			Table: table.String(),
+		})
+	}
+
+	catalogMutex.Lock()
+	catalog[key] = &catalogEntry{
+		table:   table,
+		expires: time.Now().Add(catalogExpiry),
+	}
+	catalogMutex.Unlock()
+	return table, nil
+}
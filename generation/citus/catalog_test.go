@@ -0,0 +1,206 @@
+package citus_test
+
+import (
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/generation/citus"
+	"github.com/unchartedsoftware/veldt/query"
+	"github.com/unchartedsoftware/veldt/resilience"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+// newQuery returns a query of a table with the provided columns.
+func newQuery(columns ...string) *citus.Query {
+	q, err := citus.NewQuery()
+	Expect(err).To(BeNil())
+	q.Table = &citus.Table{
+		Schema:  "public",
+		Name:    "test",
+		Columns: make(map[string]string),
+	}
+	for _, column := range columns {
+		q.Table.Columns[column] = "integer"
+	}
+	return q
+}
+
+// expectIdentifierError expects the error to be a permanent error for the
+// missing column of the test table.
+func expectIdentifierError(err error, column string) {
+	Expect(resilience.IsPermanent(err)).To(BeTrue())
+	Expect(err.(*resilience.PermanentError).Err).To(Equal(&citus.IdentifierError{
+		Table:  "public.test",
+		Column: column,
+	}))
+}
+
+var _ = Describe("Catalog", func() {
+
+	Describe("QuoteIdentifier", func() {
+		It("should enclose the identifier in double quotes", func() {
+			Expect(citus.QuoteIdentifier("name")).To(Equal(`"name"`))
+		})
+
+		It("should escape double quotes within the identifier", func() {
+			Expect(citus.QuoteIdentifier(`a"; DROP TABLE test; --`)).
+				To(Equal(`"a""; DROP TABLE test; --"`))
+		})
+	})
+
+	Describe("ParseTable", func() {
+		It("should default the schema to public", func() {
+			schema, table, err := citus.ParseTable("t")
+			Expect(err).To(BeNil())
+			Expect(schema).To(Equal("public"))
+			Expect(table).To(Equal("t"))
+		})
+
+		It("should parse the schema and table", func() {
+			schema, table, err := citus.ParseTable("s.t")
+			Expect(err).To(BeNil())
+			Expect(schema).To(Equal("s"))
+			Expect(table).To(Equal("t"))
+		})
+
+		It("should return a permanent error for malformed URIs", func() {
+			for _, uri := range []string{"a.b.c", ".t", "s.", ""} {
+				_, _, err := citus.ParseTable(uri)
+				Expect(resilience.IsPermanent(err)).To(BeTrue())
+			}
+		})
+	})
+
+	Describe("Table", func() {
+		It("should return the quoted identifier of the table", func() {
+			table := &citus.Table{
+				Schema: "s",
+				Name:   `t"`,
+			}
+			Expect(table.Quote()).To(Equal(`"s"."t"""`))
+		})
+
+		It("should return the quoted identifier of a column", func() {
+			column, err := newQuery("age").Table.Column("age")
+			Expect(err).To(BeNil())
+			Expect(column).To(Equal(`"age"`))
+		})
+
+		It("should return a permanent identifier error for unknown columns", func() {
+			_, err := newQuery("age").Table.Column("missing")
+			expectIdentifierError(err, "missing")
+		})
+	})
+
+	Describe("Query", func() {
+		It("should quote fields without checking them if it has no table", func() {
+			q, err := citus.NewQuery()
+			Expect(err).To(BeNil())
+			column, err := q.Column(`my"field`)
+			Expect(err).To(BeNil())
+			Expect(column).To(Equal(`"my""field"`))
+		})
+
+		It("should check fields against the columns of its table", func() {
+			q := newQuery("age")
+			column, err := q.Column("age")
+			Expect(err).To(BeNil())
+			Expect(column).To(Equal(`"age"`))
+			_, err = q.Column("missing")
+			expectIdentifierError(err, "missing")
+		})
+	})
+
+	Describe("Range", func() {
+		It("should emit quoted identifiers", func() {
+			q := newQuery("age")
+			r := &citus.Range{
+				Range: query.Range{
+					Field: "age",
+					GTE:   10,
+					LT:    20,
+				},
+			}
+			clause, err := r.Get(q)
+			Expect(err).To(BeNil())
+			Expect(clause).To(Equal(`"age" >= $1 AND "age" < $2`))
+			Expect(q.QueryArgs).To(Equal([]interface{}{10, 20}))
+		})
+
+		It("should reject unknown fields", func() {
+			r := &citus.Range{
+				Range: query.Range{
+					Field: "age; DROP TABLE test",
+					GTE:   10,
+				},
+			}
+			_, err := r.Get(newQuery("age"))
+			expectIdentifierError(err, "age; DROP TABLE test")
+		})
+	})
+
+	Describe("TopHits", func() {
+		It("should emit quoted identifiers", func() {
+			t := &citus.TopHits{}
+			err := t.Parse(JSON(`{
+				"sortField": "age",
+				"sortOrder": "desc",
+				"hitsCount": 5,
+				"includeFields": ["name", "age"]
+			}`))
+			Expect(err).To(BeNil())
+			q, err := t.AddAggs(newQuery("name", "age"))
+			Expect(err).To(BeNil())
+			Expect(q.Fields).To(Equal([]string{`"name"`, `"age"`}))
+			Expect(q.OrderByClauses).To(Equal([]string{`"age" DESC`}))
+			Expect(q.RowLimit).To(Equal(uint32(5)))
+		})
+
+		It("should reject unknown fields", func() {
+			t := &citus.TopHits{}
+			err := t.Parse(JSON(`{
+				"sortField": "missing",
+				"hitsCount": 5,
+				"includeFields": ["name"]
+			}`))
+			Expect(err).To(BeNil())
+			_, err = t.AddAggs(newQuery("name"))
+			expectIdentifierError(err, "missing")
+		})
+	})
+
+	Describe("Bivariate", func() {
+		bivariate := func(xField string) *citus.Bivariate {
+			b := &citus.Bivariate{}
+			err := b.Parse(JSON(`{
+				"xField": "` + xField + `",
+				"yField": "y",
+				"left": 0,
+				"right": 256,
+				"bottom": 0,
+				"top": 256,
+				"resolution": 4
+			}`))
+			Expect(err).To(BeNil())
+			return b
+		}
+
+		It("should emit quoted identifiers", func() {
+			coord := &binning.TileCoord{Z: 0, X: 0, Y: 0}
+			q, err := bivariate("x").AddQuery(coord, newQuery("x", "y"))
+			Expect(err).To(BeNil())
+			Expect(q.WhereClauses).To(Equal([]string{
+				`"x" >= $1 and "x" < $2`,
+				`"y" >= $3 and "y" < $4`,
+			}))
+		})
+
+		It("should reject unknown fields", func() {
+			coord := &binning.TileCoord{Z: 0, X: 0, Y: 0}
+			_, err := bivariate("missing").AddQuery(coord, newQuery("x", "y"))
+			expectIdentifierError(err, "missing")
+		})
+	})
+
+})
@@ -4,15 +4,10 @@ import (
 	"fmt"
 	"runtime"
 	"sync"
-	"time"
 
 	"github.com/jackc/pgx"
 )
 
-const (
-	timeout = time.Second * 60
-)
-
 var (
 	mutex   = sync.Mutex{}
 	clients = make(map[string]*pgx.ConnPool)
@@ -27,9 +22,14 @@ type Config struct {
 	Password string
 }
 
+// GetEndpoint returns the host and port of the database.
+func (cfg *Config) GetEndpoint() string {
+	return fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
+}
+
 // NewClient return a citus client from the pool.
 func NewClient(cfg *Config) (*pgx.ConnPool, error) {
-	endpoint := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
+	endpoint := cfg.GetEndpoint()
 	mutex.Lock()
 	client, ok := clients[endpoint]
 	if !ok {
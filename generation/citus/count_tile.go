@@ -1,10 +1,12 @@
 package citus
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/unchartedsoftware/veldt"
 	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
 )
 
 // Count represents a citus implementation of the count tile.
@@ -27,9 +29,20 @@ func (t *Count) Parse(params map[string]interface{}) error {
 	return t.Bivariate.Parse(params)
 }
 
+// Schema returns the JSON Schema of the parameters of the tile.
+func (t *Count) Schema() *schema.Schema {
+	return t.Bivariate.Schema()
+}
+
 // Create generates a tile from the provided URI, tile coordinate and query
 // parameters.
 func (t *Count) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return t.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (t *Count) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
 	// Initialize the tile processing.
 	client, citusQuery, err := t.InitializeTile(uri, query)
 	if err != nil {
@@ -37,11 +50,14 @@ func (t *Count) Create(uri string, coord *binning.TileCoord, query veldt.Query)
 	}
 
 	// add tiling query
-	citusQuery = t.Bivariate.AddQuery(coord, citusQuery)
+	citusQuery, err = t.Bivariate.AddQuery(coord, citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	citusQuery.Select("CAST(COUNT(*) AS FLOAT) AS value")
 	// send query
-	res, err := client.Query(citusQuery.GetQuery(false), citusQuery.QueryArgs...)
+	res, err := citusQuery.Execute(ctx, client)
 	if err != nil {
 		return nil, err
 	}
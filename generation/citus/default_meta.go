@@ -1,15 +1,15 @@
 package citus
 
 import (
-	"errors"
+	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/jackc/pgx"
 
 	"github.com/unchartedsoftware/veldt"
 	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
@@ -42,19 +42,19 @@ func isTimestamp(typ string) bool {
 		typ == "interval"
 }
 
-func getPropertyMeta(connPool *pgx.ConnPool, schema string, table string, column string, typ string) (*PropertyMeta, error) {
+func getPropertyMeta(ctx context.Context, connPool *pgx.ConnPool, schema string, table string, column string, typ string) (*PropertyMeta, error) {
 	p := PropertyMeta{
 		Type: typ,
 	}
 	// if field is 'ordinal', get the extrema
 	if isNumeric(typ) {
-		extrema, err := GetNumericExtrema(connPool, schema, table, column)
+		extrema, err := GetNumericExtrema(ctx, connPool, schema, table, column)
 		if err != nil {
 			return nil, err
 		}
 		p.Extrema = extrema
 	} else if isTimestamp(typ) {
-		extrema, err := GetTimestampExtrema(connPool, schema, table, column)
+		extrema, err := GetTimestampExtrema(ctx, connPool, schema, table, column)
 		if err != nil {
 			return nil, err
 		}
@@ -63,11 +63,14 @@ func getPropertyMeta(connPool *pgx.ConnPool, schema string, table string, column
 	return &p, nil
 }
 
-// GetNumericExtrema returns the extrema of a numeric field for the provided table.
-func GetNumericExtrema(connPool *pgx.ConnPool, schema string, table string, column string) (*binning.Extrema, error) {
+// GetNumericExtrema returns the extrema of a numeric field for the provided
+// table, abandoning the query if the context is done.
+func GetNumericExtrema(ctx context.Context, connPool *pgx.ConnPool, schema string, table string, column string) (*binning.Extrema, error) {
 	// query
-	queryString := fmt.Sprintf("SELECT CAST(MIN(%s) AS FLOAT) as min, CAST(MAX(%s) AS FLOAT) as max FROM %s.%s;", column, column, schema, table)
-	row := connPool.QueryRow(queryString)
+	column = QuoteIdentifier(column)
+	queryString := fmt.Sprintf("SELECT CAST(MIN(%s) AS FLOAT) as min, CAST(MAX(%s) AS FLOAT) as max FROM %s.%s;",
+		column, column, QuoteIdentifier(schema), QuoteIdentifier(table))
+	row := connPool.QueryRowEx(ctx, queryString, nil)
 
 	// Parse min & max values.
 	var min *float64
@@ -89,11 +92,14 @@ func GetNumericExtrema(connPool *pgx.ConnPool, schema string, table string, colu
 	}, nil
 }
 
-// GetTimestampExtrema returns the extrema of a timestamp field for the provided table.
-func GetTimestampExtrema(connPool *pgx.ConnPool, schema string, table string, column string) (*binning.Extrema, error) {
+// GetTimestampExtrema returns the extrema of a timestamp field for the provided
+// table, abandoning the query if the context is done.
+func GetTimestampExtrema(ctx context.Context, connPool *pgx.ConnPool, schema string, table string, column string) (*binning.Extrema, error) {
 	// query
-	queryString := fmt.Sprintf("SELECT MIN(%s) as min, MAX(%s) as max FROM %s.%s;", column, column, schema, table)
-	row := connPool.QueryRow(queryString)
+	column = QuoteIdentifier(column)
+	queryString := fmt.Sprintf("SELECT MIN(%s) as min, MAX(%s) as max FROM %s.%s;",
+		column, column, QuoteIdentifier(schema), QuoteIdentifier(table))
+	row := connPool.QueryRowEx(ctx, queryString, nil)
 
 	// Parse min & max values.
 	var min *time.Time
@@ -130,44 +136,44 @@ func NewDefaultMeta(cfg *Config) veldt.MetaCtor {
 	}
 }
 
+// GetEndpoint returns the host and port of the database.
+func (g *DefaultMeta) GetEndpoint() string {
+	return g.Config.GetEndpoint()
+}
+
 // Parse parses the provided JSON object and populates the structs attributes.
 func (g *DefaultMeta) Parse(params map[string]interface{}) error {
 	return nil
 }
 
-// Create generates metadata from the provided URI.
+// Schema returns the JSON Schema of the parameters of the metadata, which
+// has none.
+func (g *DefaultMeta) Schema() *schema.Schema {
+	return schema.Object(nil)
+}
+
+// Create generates metadata from the provided URI, which is a `table` or a
+// `schema.table` of the database.
 func (g *DefaultMeta) Create(uri string) ([]byte, error) {
+	return g.CreateContext(context.Background(), uri)
+}
+
+// CreateContext generates metadata from the provided URI, abandoning the
+// queries for the extrema if the context is done.
+func (g *DefaultMeta) CreateContext(ctx context.Context, uri string) ([]byte, error) {
 	client, err := NewClient(g.Config)
 	if err != nil {
 		return nil, err
 	}
 
-	split := strings.Split(uri, ".")
-	if len(split) != 2 {
-		return nil, errors.New("incorrect format for table, expect 'schema.table'")
-	}
-	schemaInput := split[0]
-	tableInput := split[1]
-
-	schemaQuery := "select table_schema as schema, table_name as table, column_name as column, data_type as typ from information_schema.columns where table_schema = $1 and table_name = $2;"
-	rows, err := client.Query(schemaQuery, schemaInput, tableInput)
+	table, err := GetTable(client, g.Config, uri)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
 	meta := make(map[string]interface{})
-	for rows.Next() {
-		var table string
-		var schema string
-		var column string
-		var typ string
-		err := rows.Scan(&schema, &table, &column, &typ)
-		if err != nil {
-			return nil, err
-		}
-
-		metaColumn, err := getPropertyMeta(client, schema, table, column, typ)
+	for column, typ := range table.Columns {
+		metaColumn, err := getPropertyMeta(ctx, client, table.Schema, table.Name, column, typ)
 		if err != nil {
 			return nil, err
 		}
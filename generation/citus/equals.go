@@ -19,6 +19,10 @@ func NewEquals() (veldt.Query, error) {
 
 // Get adds the parameters to the query and returns the string representation.
 func (q *Equals) Get(query *Query) (string, error) {
+	field, err := query.Column(q.Field)
+	if err != nil {
+		return "", err
+	}
 	valueParam := query.AddParameter(q.Value)
-	return fmt.Sprintf("%s = %s", q.Field, valueParam), nil
+	return fmt.Sprintf("%s = %s", field, valueParam), nil
 }
@@ -0,0 +1,89 @@
+package citus
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+func init() {
+	veldt.RegisterBackend("citus", &veldt.Backend{
+		Binary: NewBinaryExpression,
+		Unary:  NewUnaryExpression,
+		Queries: map[string]veldt.QueryCtor{
+			"boundingBox": NewBoundingBox,
+			"equals":      NewEquals,
+			"exists":      NewExists,
+			"fuzzy":       NewFuzzy,
+			"has":         NewHas,
+			"phrase":      NewPhrase,
+			"polygon":     NewPolygon,
+			"prefix":      NewPrefix,
+			"radius":      NewRadius,
+			"range":       NewRange,
+			"regexp":      NewRegexp,
+			"wildcard":    NewWildcard,
+		},
+		Tiles: map[string]veldt.TileFactory{
+			"count":               newTileFactory(NewCountTile),
+			"frequency":           newTileFactory(NewFrequencyTile),
+			"heatmap":             newTileFactory(NewHeatmapTile),
+			"macro":               newTileFactory(NewMacroTile),
+			"micro":               newTileFactory(NewMicroTile),
+			"targetTermCount":     newTileFactory(NewTargetTermCountTile),
+			"targetTermFrequency": newTileFactory(NewTargetTermFrequencyTile),
+			"topTermCount":        newTileFactory(NewTopTermCountTile),
+			"topTermFrequency":    newTileFactory(NewTopTermFrequencyTile),
+		},
+		Metas: map[string]veldt.MetaFactory{
+			"default": func(conn map[string]interface{}) (veldt.MetaCtor, error) {
+				cfg, err := parseConfig(conn)
+				if err != nil {
+					return nil, err
+				}
+				return NewDefaultMeta(cfg), nil
+			},
+		},
+	})
+}
+
+func newTileFactory(ctor func(cfg *Config) veldt.TileCtor) veldt.TileFactory {
+	return func(conn map[string]interface{}) (veldt.TileCtor, error) {
+		cfg, err := parseConfig(conn)
+		if err != nil {
+			return nil, err
+		}
+		return ctor(cfg), nil
+	}
+}
+
+// parseConfig returns the database config of the connection parameters.
+func parseConfig(conn map[string]interface{}) (*Config, error) {
+	host, ok := json.GetString(conn, "host")
+	if !ok {
+		return nil, fmt.Errorf("`host` parameter missing from citus connection")
+	}
+	port, ok := json.GetInt(conn, "port")
+	if !ok {
+		return nil, fmt.Errorf("`port` parameter missing from citus connection")
+	}
+	if port <= 0 || port > 65535 {
+		return nil, fmt.Errorf("`port` parameter of citus connection is out of range")
+	}
+	database, ok := json.GetString(conn, "database")
+	if !ok {
+		return nil, fmt.Errorf("`database` parameter missing from citus connection")
+	}
+	user, ok := json.GetString(conn, "user")
+	if !ok {
+		return nil, fmt.Errorf("`user` parameter missing from citus connection")
+	}
+	return &Config{
+		Host:     host,
+		Port:     uint16(port),
+		Database: database,
+		User:     user,
+		Password: json.GetStringDefault(conn, "", "password"),
+	}, nil
+}
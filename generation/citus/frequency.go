@@ -22,7 +22,7 @@ type FrequencyResult struct {
 }
 
 // AddAggs adds the tiling aggregations to the provided query object.
-func (f *Frequency) AddAggs(query *Query) *Query {
+func (f *Frequency) AddAggs(query *Query) (*Query, error) {
 	//Bounds extension (empty buckets) will be done in the go code when parsing results
 	//Not 100% sure if we need to subtract the min value from the frequency field to
 	//set the initial bucket.
@@ -30,38 +30,47 @@ func (f *Frequency) AddAggs(query *Query) *Query {
 	//	data starts at 3, with intervals of 5.
 	//	Should the first bucket be 0-5 or 3-8?
 
+	field, err := query.Column(f.FrequencyField)
+	if err != nil {
+		return nil, err
+	}
+
 	//Ignoring potential error. Should really be done in some kind of setup function.
 	intervalNum, _ := strconv.ParseFloat(f.Interval, 64)
 	intervalArg := query.AddParameter(intervalNum)
-	queryString := fmt.Sprintf("(%s / %s * %s)", f.FrequencyField, intervalArg, intervalArg)
+	queryString := fmt.Sprintf("(%s / %s * %s)", field, intervalArg, intervalArg)
 	query.GroupBy(queryString)
 	query.Select(fmt.Sprintf("%s as bucket", queryString))
 	query.Select("COUNT(*) as frequency")
 
-	return query
+	return query, nil
 }
 
 // AddQuery adds the tiling query to the provided query object.
-func (f *Frequency) AddQuery(query *Query) *Query {
+func (f *Frequency) AddQuery(query *Query) (*Query, error) {
 	//TODO: Need to cast the frequency fields to a numeric value most likely.
+	field, err := query.Column(f.FrequencyField)
+	if err != nil {
+		return nil, err
+	}
 
 	if f.GTE != nil {
 		parameter := query.AddParameter(f.GTE)
-		query.Where(fmt.Sprintf("%s >= %s", f.FrequencyField, parameter))
+		query.Where(fmt.Sprintf("%s >= %s", field, parameter))
 	}
 	if f.GT != nil {
 		parameter := query.AddParameter(f.GT)
-		query.Where(fmt.Sprintf("%s > %s", f.FrequencyField, parameter))
+		query.Where(fmt.Sprintf("%s > %s", field, parameter))
 	}
 	if f.LTE != nil {
 		parameter := query.AddParameter(f.LTE)
-		query.Where(fmt.Sprintf("%s <= %s", f.FrequencyField, parameter))
+		query.Where(fmt.Sprintf("%s <= %s", field, parameter))
 	}
 	if f.LT != nil {
 		parameter := query.AddParameter(f.LT)
-		query.Where(fmt.Sprintf("%s < %s", f.FrequencyField, parameter))
+		query.Where(fmt.Sprintf("%s < %s", field, parameter))
 	}
-	return query
+	return query, nil
 }
 
 // GetBuckets returns the frequency buckets from the query results.
@@ -1,8 +1,10 @@
 package citus
 
 import (
+	"context"
 	"github.com/unchartedsoftware/veldt"
 	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
@@ -31,9 +33,20 @@ func (t *FrequencyTile) Parse(params map[string]interface{}) error {
 	return t.Frequency.Parse(params)
 }
 
+// Schema returns the JSON Schema of the parameters of the tile.
+func (t *FrequencyTile) Schema() *schema.Schema {
+	return schema.Merge(t.Bivariate.Schema(), t.Frequency.Schema())
+}
+
 // Create generates a tile from the provided URI, tile coordinate and query
 // parameters.
 func (t *FrequencyTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return t.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (t *FrequencyTile) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
 	// Initialize the tile processing.
 	client, citusQuery, err := t.InitializeTile(uri, query)
 	if err != nil {
@@ -41,16 +54,25 @@ func (t *FrequencyTile) Create(uri string, coord *binning.TileCoord, query veldt
 	}
 
 	// add tiling query
-	citusQuery = t.Bivariate.AddQuery(coord, citusQuery)
+	citusQuery, err = t.Bivariate.AddQuery(coord, citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	// add frequency query
-	citusQuery = t.Frequency.AddQuery(citusQuery)
+	citusQuery, err = t.Frequency.AddQuery(citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	// add aggs
-	citusQuery = t.Frequency.AddAggs(citusQuery)
+	citusQuery, err = t.Frequency.AddAggs(citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	// send query
-	res, err := client.Query(citusQuery.GetQuery(false), citusQuery.QueryArgs...)
+	res, err := citusQuery.Execute(ctx, client)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,48 @@
+package citus
+
+import (
+	"fmt"
+	"math"
+	"unicode/utf8"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Fuzzy represents a citus fuzzy query. The number of edits is approximated
+// by the trigram similarity of the `pg_trgm` extension, which must be
+// installed. Every value within the edits is matched, along with some values
+// that are similar but require more edits.
+type Fuzzy struct {
+	query.Fuzzy
+}
+
+// NewFuzzy instantiates and returns a new query struct.
+func NewFuzzy() (veldt.Query, error) {
+	return &Fuzzy{}, nil
+}
+
+// Get adds the parameters to the query and returns the string representation.
+func (q *Fuzzy) Get(query *Query) (string, error) {
+	field, err := query.Column(q.Field)
+	if err != nil {
+		return "", err
+	}
+	fuzziness := q.GetFuzziness()
+	if fuzziness == 0 {
+		valueParam := query.AddParameter(q.Value)
+		return fmt.Sprintf("%s = %s", field, valueParam), nil
+	}
+	// a value of n characters has n + 1 trigrams, and each edit changes at
+	// most three of them
+	trigrams := float64(utf8.RuneCountInString(q.Value) + 1)
+	edits := float64(fuzziness)
+	threshold := math.Max(0, (trigrams-3*edits)/(trigrams+4*edits))
+	lengthParam := query.AddParameter(utf8.RuneCountInString(q.Value))
+	fuzzinessParam := query.AddParameter(fuzziness)
+	valueParam := query.AddParameter(q.Value)
+	thresholdParam := query.AddParameter(threshold)
+	return fmt.Sprintf("abs(char_length(%s) - %s) <= %s AND similarity(%s, %s) >= %s",
+		field, lengthParam, fuzzinessParam,
+		field, valueParam, thresholdParam), nil
+}
@@ -19,6 +19,10 @@ func NewHas() (veldt.Query, error) {
 
 // Get adds the parameters to the query and returns the string representation.
 func (q *Has) Get(query *Query) (string, error) {
+	field, err := query.Column(q.Field)
+	if err != nil {
+		return "", err
+	}
 	// Check that the array contains the values.
 	// Use the column && ARRAY[value1, value2] notation.
 	clause := ""
@@ -30,6 +34,6 @@ func (q *Has) Get(query *Query) (string, error) {
 	}
 
 	//Remove the leading ", " from the array contents.
-	clause = fmt.Sprintf("%s && ARRAY[%s]", q.Field, clause[2:])
+	clause = fmt.Sprintf("%s && ARRAY[%s]", field, clause[2:])
 	return clause, nil
 }
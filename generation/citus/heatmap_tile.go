@@ -1,10 +1,12 @@
 package citus
 
 import (
+	"context"
 	"encoding/binary"
 
 	"github.com/unchartedsoftware/veldt"
 	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
 )
 
 // HeatmapTile represents a citus implementation of the heatmap tile.
@@ -27,9 +29,20 @@ func (h *HeatmapTile) Parse(params map[string]interface{}) error {
 	return h.Bivariate.Parse(params)
 }
 
+// Schema returns the JSON Schema of the parameters of the tile.
+func (h *HeatmapTile) Schema() *schema.Schema {
+	return h.Bivariate.Schema()
+}
+
 // Create generates a tile from the provided URI, tile coordinate and query
 // parameters.
 func (h *HeatmapTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return h.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (h *HeatmapTile) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
 	// Initialize the tile processing.
 	client, citusQuery, err := h.InitializeTile(uri, query)
 	if err != nil {
@@ -37,15 +50,21 @@ func (h *HeatmapTile) Create(uri string, coord *binning.TileCoord, query veldt.Q
 	}
 
 	// add tiling query
-	citusQuery = h.Bivariate.AddQuery(coord, citusQuery)
+	citusQuery, err = h.Bivariate.AddQuery(coord, citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	// add aggs
-	citusQuery = h.Bivariate.AddAggs(coord, citusQuery)
+	citusQuery, err = h.Bivariate.AddAggs(coord, citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	//May support AVG (& others) in the future. May as well make it a float for now.
 	citusQuery.Select("CAST(COUNT(*) AS FLOAT) AS value")
 	// send query
-	res, err := client.Query(citusQuery.GetQuery(false), citusQuery.QueryArgs...)
+	res, err := citusQuery.Execute(ctx, client)
 	if err != nil {
 		return nil, err
 	}
@@ -1,10 +1,12 @@
 package citus
 
 import (
+	"context"
 	"math"
 
 	"github.com/unchartedsoftware/veldt"
 	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
 	"github.com/unchartedsoftware/veldt/tile"
 )
 
@@ -33,9 +35,20 @@ func (m *MacroTile) Parse(params map[string]interface{}) error {
 	return m.Macro.Parse(params)
 }
 
+// Schema returns the JSON Schema of the parameters of the tile.
+func (m *MacroTile) Schema() *schema.Schema {
+	return schema.Merge(m.Bivariate.Schema(), m.Macro.Schema())
+}
+
 // Create generates a tile from the provided URI, tile coordinate and query
 // parameters.
 func (m *MacroTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return m.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (m *MacroTile) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
 	// Initialize the tile processing.
 	client, citusQuery, err := m.InitializeTile(uri, query)
 	if err != nil {
@@ -43,15 +56,21 @@ func (m *MacroTile) Create(uri string, coord *binning.TileCoord, query veldt.Que
 	}
 
 	// add tiling query
-	citusQuery = m.Bivariate.AddQuery(coord, citusQuery)
+	citusQuery, err = m.Bivariate.AddQuery(coord, citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	// add aggs
-	citusQuery = m.Bivariate.AddAggs(coord, citusQuery)
+	citusQuery, err = m.Bivariate.AddAggs(coord, citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	citusQuery.Select("CAST(COUNT(*) AS FLOAT) AS value")
 
 	// send query
-	res, err := client.Query(citusQuery.GetQuery(false), citusQuery.QueryArgs...)
+	res, err := citusQuery.Execute(ctx, client)
 	if err != nil {
 		return nil, err
 	}
@@ -1,10 +1,12 @@
 package citus
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/unchartedsoftware/veldt"
 	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
 	"github.com/unchartedsoftware/veldt/tile"
 )
 
@@ -47,9 +49,20 @@ func (m *MicroTile) Parse(params map[string]interface{}) error {
 	return nil
 }
 
+// Schema returns the JSON Schema of the parameters of the tile.
+func (m *MicroTile) Schema() *schema.Schema {
+	return schema.Merge(m.Bivariate.Schema(), m.TopHits.Schema(), m.Micro.Schema())
+}
+
 // Create generates a tile from the provided URI, tile coordinate and query
 // parameters.
 func (m *MicroTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return m.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (m *MicroTile) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
 	// Initialize the tile processing.
 	client, citusQuery, err := m.InitializeTile(uri, query)
 	if err != nil {
@@ -57,13 +70,19 @@ func (m *MicroTile) Create(uri string, coord *binning.TileCoord, query veldt.Que
 	}
 
 	// add tiling query
-	citusQuery = m.Bivariate.AddQuery(coord, citusQuery)
+	citusQuery, err = m.Bivariate.AddQuery(coord, citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	// get aggs
-	citusQuery = m.TopHits.AddAggs(citusQuery)
+	citusQuery, err = m.TopHits.AddAggs(citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	// send query
-	res, err := client.Query(citusQuery.GetQuery(false), citusQuery.QueryArgs...)
+	res, err := citusQuery.Execute(ctx, client)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,33 @@
+package citus
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Phrase represents a citus phrase query, using full text search with the
+// `simple` configuration, which lowercases words without stemming them. A
+// slop is not supported.
+type Phrase struct {
+	query.Phrase
+}
+
+// NewPhrase instantiates and returns a new query struct.
+func NewPhrase() (veldt.Query, error) {
+	return &Phrase{}, nil
+}
+
+// Get adds the parameters to the query and returns the string representation.
+func (q *Phrase) Get(query *Query) (string, error) {
+	if q.Slop != 0 {
+		return "", fmt.Errorf("`slop` parameter is not supported by citus")
+	}
+	field, err := query.Column(q.Field)
+	if err != nil {
+		return "", err
+	}
+	phraseParam := query.AddParameter(q.Phrase.Phrase)
+	return fmt.Sprintf("to_tsvector('simple', %s) @@ phraseto_tsquery('simple', %s)", field, phraseParam), nil
+}
@@ -0,0 +1,68 @@
+package citus
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Polygon represents a citus polygon query.
+type Polygon struct {
+	query.Polygon
+}
+
+// NewPolygon instantiates and returns a new query struct.
+func NewPolygon() (veldt.Query, error) {
+	return &Polygon{}, nil
+}
+
+// Get adds the parameters to the query and returns the string representation.
+// Points of a pair of fields are filtered by the bounds of the polygon, then
+// tested by counting the edges crossed by a ray cast from each point.
+func (q *Polygon) Get(query *Query) (string, error) {
+	if !q.IsPair() {
+		field, err := query.Column(q.Field)
+		if err != nil {
+			return "", err
+		}
+		geom, err := json.Marshal(q.Polygon.Polygon)
+		if err != nil {
+			return "", err
+		}
+		geomParam := query.AddParameter(string(geom))
+		return fmt.Sprintf("ST_Intersects(%s, ST_SetSRID(ST_GeomFromGeoJSON(%s), 4326))",
+			field, geomParam), nil
+	}
+	x, y, err := getPairColumns(query, &q.Spatial)
+	if err != nil {
+		return "", err
+	}
+	crossings := []string{}
+	for _, ring := range q.Polygon.Polygon.Rings {
+		for i := 1; i < len(ring); i++ {
+			a := ring[i-1]
+			b := ring[i]
+			if a.Y == b.Y {
+				// horizontal edges are never crossed
+				continue
+			}
+			yParam := query.AddParameter(a.Y)
+			otherYParam := query.AddParameter(b.Y)
+			slopeParam := query.AddParameter((b.X - a.X) / (b.Y - a.Y))
+			xParam := query.AddParameter(a.X)
+			crossings = append(crossings, fmt.Sprintf(
+				"CASE WHEN (%s > %s) <> (%s > %s) AND %s < %s * (%s - %s) + %s THEN 1 ELSE 0 END",
+				y, yParam,
+				y, otherYParam,
+				x, slopeParam, y, yParam, xParam))
+		}
+	}
+	clause := getBoundsClause(query, x, y, q.Polygon.Polygon.Bounds())
+	if len(crossings) == 0 {
+		return clause, nil
+	}
+	return fmt.Sprintf("%s AND (%s) %% 2 = 1", clause, strings.Join(crossings, " + ")), nil
+}
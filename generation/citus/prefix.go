@@ -0,0 +1,28 @@
+package citus
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Prefix represents a citus prefix query.
+type Prefix struct {
+	query.Prefix
+}
+
+// NewPrefix instantiates and returns a new query struct.
+func NewPrefix() (veldt.Query, error) {
+	return &Prefix{}, nil
+}
+
+// Get adds the parameters to the query and returns the string representation.
+func (q *Prefix) Get(query *Query) (string, error) {
+	field, err := query.Column(q.Field)
+	if err != nil {
+		return "", err
+	}
+	valueParam := query.AddParameter(escapeLike(q.Prefix.Prefix) + "%")
+	return fmt.Sprintf("%s %s %s", field, getLikeOperator(q.CaseInsensitive), valueParam), nil
+}
@@ -1,9 +1,18 @@
 package citus
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
+
+	"github.com/jackc/pgx"
+
+	"github.com/unchartedsoftware/veldt/trace"
+)
+
+const (
+	querySpan = "citus.query"
 )
 
 // QueryString represents a citus implementation of the veldt.Query interface.
@@ -11,8 +20,10 @@ type QueryString interface {
 	Get(*Query) (string, error)
 }
 
-// Query represents a citus query object.
+// Query represents a citus query object. Fields are checked against the
+// columns of the table of the query, if it has one.
 type Query struct {
+	Table          *Table
 	QueryArgs      []interface{}
 	WhereClauses   []string
 	GroupByClauses []string
@@ -35,6 +46,18 @@ func NewQuery() (*Query, error) {
 	}, nil
 }
 
+// Execute sends the query to the database, abandoning it if the context is
+// done. The round trip is traced as part of the context's trace.
+func (q *Query) Execute(ctx context.Context, client *pgx.ConnPool) (*pgx.Rows, error) {
+	ctx, span := trace.Start(ctx, querySpan)
+	defer span.End()
+	queryString := q.GetQuery(false)
+	span.SetAttribute("db.statement", queryString)
+	rows, err := client.QueryEx(ctx, queryString, nil, q.QueryArgs...)
+	span.SetError(err)
+	return rows, err
+}
+
 // GetQuery returns the query string.
 func (q *Query) GetQuery(nested bool) string {
 	queryString := fmt.Sprintf("SELECT %s", strings.Join(q.Fields, ", "))
@@ -70,6 +93,15 @@ func (q *Query) AddParameter(param interface{}) string {
 	return "$" + strconv.Itoa(len(q.QueryArgs))
 }
 
+// Column returns the quoted identifier of the field, or an error if the table
+// of the query has no such column.
+func (q *Query) Column(field string) (string, error) {
+	if q.Table == nil {
+		return QuoteIdentifier(field), nil
+	}
+	return q.Table.Column(field)
+}
+
 // Where adds a where clause to the query.
 func (q *Query) Where(clause string) {
 	q.WhereClauses = append(q.WhereClauses, clause)
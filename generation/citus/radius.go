@@ -0,0 +1,60 @@
+package citus
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/geometry"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Radius represents a citus radius query.
+type Radius struct {
+	query.Radius
+}
+
+// NewRadius instantiates and returns a new query struct.
+func NewRadius() (veldt.Query, error) {
+	return &Radius{}, nil
+}
+
+// Get adds the parameters to the query and returns the string representation.
+// Points of a pair of fields are filtered by the bounds of the radius before
+// their distance from the center is computed.
+func (q *Radius) Get(query *Query) (string, error) {
+	if !q.IsPair() {
+		field, err := query.Column(q.Field)
+		if err != nil {
+			return "", err
+		}
+		xParam := query.AddParameter(q.Center.X)
+		yParam := query.AddParameter(q.Center.Y)
+		distanceParam := query.AddParameter(q.Distance)
+		return fmt.Sprintf("ST_DWithin(%s::geography, ST_SetSRID(ST_MakePoint(%s, %s), 4326)::geography, %s)",
+			field, xParam, yParam, distanceParam), nil
+	}
+	x, y, err := getPairColumns(query, &q.Spatial)
+	if err != nil {
+		return "", err
+	}
+	clause := getBoundsClause(query, x, y, q.GetBounds())
+	xParam := query.AddParameter(q.Center.X)
+	yParam := query.AddParameter(q.Center.Y)
+	distanceParam := query.AddParameter(q.Distance)
+	if q.Geographic {
+		// haversine formula
+		radiusParam := query.AddParameter(geometry.EarthRadius)
+		return fmt.Sprintf("%s AND 2 * %s * asin(least(1, sqrt("+
+			"power(sin(radians(%s - %s) / 2), 2) + "+
+			"cos(radians(%s)) * cos(radians(%s)) * power(sin(radians(%s - %s) / 2), 2)))) <= %s",
+			clause, radiusParam,
+			y, yParam,
+			yParam, y, x, xParam,
+			distanceParam), nil
+	}
+	return fmt.Sprintf("%s AND (%s - %s) * (%s - %s) + (%s - %s) * (%s - %s) <= %s * %s",
+		clause,
+		x, xParam, x, xParam,
+		y, yParam, y, yParam,
+		distanceParam, distanceParam), nil
+}
@@ -19,23 +19,27 @@ func NewRange() (veldt.Query, error) {
 
 // Get adds the parameters to the query and returns the string representation.
 func (q *Range) Get(query *Query) (string, error) {
+	field, err := query.Column(q.Field)
+	if err != nil {
+		return "", err
+	}
 	clause := ""
 
 	if q.GTE != nil {
 		valueParam := query.AddParameter(q.GTE)
-		clause = clause + fmt.Sprintf(" AND %s >= %v", q.Field, valueParam)
+		clause = clause + fmt.Sprintf(" AND %s >= %v", field, valueParam)
 	}
 	if q.GT != nil {
 		valueParam := query.AddParameter(q.GT)
-		clause = clause + fmt.Sprintf(" AND %s > %v", q.Field, valueParam)
+		clause = clause + fmt.Sprintf(" AND %s > %v", field, valueParam)
 	}
 	if q.LTE != nil {
 		valueParam := query.AddParameter(q.LTE)
-		clause = clause + fmt.Sprintf(" AND %s <= %v", q.Field, valueParam)
+		clause = clause + fmt.Sprintf(" AND %s <= %v", field, valueParam)
 	}
 	if q.LT != nil {
 		valueParam := query.AddParameter(q.LT)
-		clause = clause + fmt.Sprintf(" AND %s < %v", q.Field, valueParam)
+		clause = clause + fmt.Sprintf(" AND %s < %v", field, valueParam)
 	}
 	//Remove leading " AND "
 	return clause[5:], nil
@@ -0,0 +1,34 @@
+package citus
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Regexp represents a citus regular expression query, using the PostgreSQL
+// regular expression syntax.
+type Regexp struct {
+	query.Regexp
+}
+
+// NewRegexp instantiates and returns a new query struct.
+func NewRegexp() (veldt.Query, error) {
+	return &Regexp{}, nil
+}
+
+// Get adds the parameters to the query and returns the string representation.
+func (q *Regexp) Get(query *Query) (string, error) {
+	field, err := query.Column(q.Field)
+	if err != nil {
+		return "", err
+	}
+	operator := "~"
+	if q.CaseInsensitive {
+		operator = "~*"
+	}
+	// anchor the pattern, as it must match the whole field
+	valueParam := query.AddParameter("^(?:" + q.Pattern + ")$")
+	return fmt.Sprintf("%s %s %s", field, operator, valueParam), nil
+}
@@ -0,0 +1,37 @@
+package citus
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt/geometry"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// getPairColumns returns the quoted identifiers of the x and y fields of the
+// location.
+func getPairColumns(query *Query, s *query.Spatial) (string, string, error) {
+	x, err := query.Column(s.XField)
+	if err != nil {
+		return "", "", err
+	}
+	y, err := query.Column(s.YField)
+	if err != nil {
+		return "", "", err
+	}
+	return x, y, nil
+}
+
+// getBoundsClause adds the parameters to the query and returns a clause
+// checking that the x and y columns of the location are within the bounds,
+// inclusive.
+func getBoundsClause(query *Query, x string, y string, bounds *geometry.Bounds) string {
+	minXParam := query.AddParameter(bounds.MinX())
+	maxXParam := query.AddParameter(bounds.MaxX())
+	minYParam := query.AddParameter(bounds.MinY())
+	maxYParam := query.AddParameter(bounds.MaxY())
+	return fmt.Sprintf("%s >= %s AND %s <= %s AND %s >= %s AND %s <= %s",
+		x, minXParam,
+		x, maxXParam,
+		y, minYParam,
+		y, maxYParam)
+}
@@ -1,8 +1,10 @@
 package citus
 
 import (
+	"context"
 	"github.com/unchartedsoftware/veldt"
 	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
@@ -32,9 +34,20 @@ func (t *TargetTermCountTile) Parse(params map[string]interface{}) error {
 	return t.TargetTerms.Parse(params)
 }
 
+// Schema returns the JSON Schema of the parameters of the tile.
+func (t *TargetTermCountTile) Schema() *schema.Schema {
+	return schema.Merge(t.Bivariate.Schema(), t.TargetTerms.Schema())
+}
+
 // Create generates a tile from the provided URI, tile coordinate and query
 // parameters.
 func (t *TargetTermCountTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return t.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (t *TargetTermCountTile) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
 	// Initialize the tile processing.
 	client, citusQuery, err := t.InitializeTile(uri, query)
 	if err != nil {
@@ -42,13 +55,19 @@ func (t *TargetTermCountTile) Create(uri string, coord *binning.TileCoord, query
 	}
 
 	// add tiling query
-	citusQuery = t.Bivariate.AddQuery(coord, citusQuery)
+	citusQuery, err = t.Bivariate.AddQuery(coord, citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	// get aggs
-	citusQuery = t.TargetTerms.AddAggs(citusQuery)
+	citusQuery, err = t.TargetTerms.AddAggs(citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	// send query
-	res, err := client.Query(citusQuery.GetQuery(false), citusQuery.QueryArgs...)
+	res, err := citusQuery.Execute(ctx, client)
 	if err != nil {
 		return nil, err
 	}
@@ -1,10 +1,12 @@
 package citus
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/unchartedsoftware/veldt"
 	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
@@ -32,12 +34,27 @@ func (t *TargetTermFrequencyTile) Parse(params map[string]interface{}) error {
 	if err != nil {
 		return err
 	}
-	return t.TargetTerms.Parse(params)
+	err = t.TargetTerms.Parse(params)
+	if err != nil {
+		return err
+	}
+	return t.Frequency.Parse(params)
+}
+
+// Schema returns the JSON Schema of the parameters of the tile.
+func (t *TargetTermFrequencyTile) Schema() *schema.Schema {
+	return schema.Merge(t.Bivariate.Schema(), t.TargetTerms.Schema(), t.Frequency.Schema())
 }
 
 // Create generates a tile from the provided URI, tile coordinate and query
 // parameters.
 func (t *TargetTermFrequencyTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return t.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (t *TargetTermFrequencyTile) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
 	// Initialize the tile processing.
 	client, citusQuery, err := t.InitializeTile(uri, query)
 	if err != nil {
@@ -45,15 +62,28 @@ func (t *TargetTermFrequencyTile) Create(uri string, coord *binning.TileCoord, q
 	}
 
 	// add tiling query
-	citusQuery = t.Bivariate.AddQuery(coord, citusQuery)
+	citusQuery, err = t.Bivariate.AddQuery(coord, citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	// get aggs
-	citusQuery.Select(t.Frequency.FrequencyField)
-	citusQuery = t.TargetTerms.AddAggs(citusQuery)
-	citusQuery = t.Frequency.AddAggs(citusQuery)
+	frequencyField, err := citusQuery.Column(t.Frequency.FrequencyField)
+	if err != nil {
+		return nil, err
+	}
+	citusQuery.Select(frequencyField)
+	citusQuery, err = t.TargetTerms.AddAggs(citusQuery)
+	if err != nil {
+		return nil, err
+	}
+	citusQuery, err = t.Frequency.AddAggs(citusQuery)
+	if err != nil {
+		return nil, err
+	}
 
 	// send query
-	res, err := client.Query(citusQuery.GetQuery(false), citusQuery.QueryArgs...)
+	res, err := citusQuery.Execute(ctx, client)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,21 @@
+package citus
+
+import (
+	"strings"
+)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike escapes the wildcards of a LIKE pattern, such that the string is
+// matched literally.
+func escapeLike(str string) string {
+	return likeEscaper.Replace(str)
+}
+
+// getLikeOperator returns the LIKE operator of the case sensitivity.
+func getLikeOperator(caseInsensitive bool) string {
+	if caseInsensitive {
+		return "ILIKE"
+	}
+	return "LIKE"
+}
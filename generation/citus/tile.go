@@ -5,20 +5,43 @@ import (
 
 	"github.com/jackc/pgx"
 	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/resilience"
 )
 
 // Tile represents an citus tile type.
 type Tile struct {
 	Config *Config
+	policy *resilience.Policy
 }
 
-// CreateQuery creates the underlying citus query object.
-func (t *Tile) CreateQuery(query veldt.Query) (*Query, error) {
+// SetPolicy sets the resilience policy of the queries made to the database.
+func (t *Tile) SetPolicy(policy *resilience.Policy) {
+	t.policy = policy
+}
+
+// GetPolicy returns the resilience policy of the queries made to the
+// database. Unless one has been set, the tile uses a default policy of its
+// own, which times queries out after 60 seconds.
+func (t *Tile) GetPolicy() *resilience.Policy {
+	if t.policy == nil {
+		t.policy = resilience.NewDefaultPolicy()
+	}
+	return t.policy
+}
+
+// GetEndpoint returns the host and port of the database.
+func (t *Tile) GetEndpoint() string {
+	return t.Config.GetEndpoint()
+}
+
+// CreateQuery creates the underlying citus query object on the table.
+func (t *Tile) CreateQuery(table *Table, query veldt.Query) (*Query, error) {
 	// create root query
 	root, err := NewQuery()
 	if err != nil {
 		return nil, err
 	}
+	root.Table = table
 
 	// add filter query
 	if query != nil {
@@ -39,18 +62,24 @@ func (t *Tile) CreateQuery(query veldt.Query) (*Query, error) {
 	return root, nil
 }
 
-// InitializeTile initializes the citus tile type.
+// InitializeTile initializes the citus tile type. The URI is a `table` or a
+// `schema.table` of the database.
 func (t *Tile) InitializeTile(uri string, query veldt.Query) (*pgx.ConnPool, *Query, error) {
 	// get client
 	client, err := NewClient(t.Config)
 	if err != nil {
 		return nil, nil, err
 	}
+	// get table
+	table, err := GetTable(client, t.Config, uri)
+	if err != nil {
+		return nil, nil, err
+	}
 	// create root query
-	citusQuery, err := t.CreateQuery(query)
+	citusQuery, err := t.CreateQuery(table, query)
 	if err != nil {
 		return nil, nil, err
 	}
-	citusQuery.From(uri)
+	citusQuery.From(table.Quote())
 	return client, citusQuery, nil
 }
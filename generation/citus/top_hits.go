@@ -14,21 +14,29 @@ type TopHits struct {
 }
 
 // AddAggs adds the tiling aggregations to the provided query object.
-func (t *TopHits) AddAggs(query *Query) *Query {
+func (t *TopHits) AddAggs(query *Query) (*Query, error) {
 	//Select the top N rows when sorted. Return only the specified fields.
 	for _, field := range t.IncludeFields {
-		query.Select(field)
+		column, err := query.Column(field)
+		if err != nil {
+			return nil, err
+		}
+		query.Select(column)
 	}
 	// sort
 	if t.SortField != "" {
+		column, err := query.Column(t.SortField)
+		if err != nil {
+			return nil, err
+		}
 		if t.SortOrder == "desc" {
-			query.OrderBy(fmt.Sprintf("%s DESC", t.SortField))
+			query.OrderBy(fmt.Sprintf("%s DESC", column))
 		} else {
-			query.OrderBy(t.SortField)
+			query.OrderBy(column)
 		}
 	}
 	query.Limit(uint32(t.HitsCount))
-	return query
+	return query, nil
 }
 
 // GetTopHits returns the individual hits from the provided rows.
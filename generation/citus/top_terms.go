@@ -14,16 +14,20 @@ type TopTerms struct {
 }
 
 // AddAggs adds the tiling aggregations to the provided query object.
-func (t *TopTerms) AddAggs(query *Query) *Query {
+func (t *TopTerms) AddAggs(query *Query) (*Query, error) {
+	field, err := query.Column(t.TermsField)
+	if err != nil {
+		return nil, err
+	}
 	//Assume the backing field is an array. Need to unpack that array and group by the terms.
-	query.Select(fmt.Sprintf("unnest(%s) AS term", t.TermsField))
+	query.Select(fmt.Sprintf("unnest(%s) AS term", field))
 
 	query.GroupBy("term")
 	query.Select("COUNT(*) as term_count")
 	query.OrderBy("term_count desc")
 	query.Limit(uint32(t.TermsCount))
 
-	return query
+	return query, nil
 }
 
 // GetTerms parses the result of the terms query into a map of term -> count.
@@ -0,0 +1,40 @@
+package citus
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Wildcard represents a citus wildcard query.
+type Wildcard struct {
+	query.Wildcard
+}
+
+// NewWildcard instantiates and returns a new query struct.
+func NewWildcard() (veldt.Query, error) {
+	return &Wildcard{}, nil
+}
+
+// Get adds the parameters to the query and returns the string representation.
+func (q *Wildcard) Get(query *Query) (string, error) {
+	field, err := query.Column(q.Field)
+	if err != nil {
+		return "", err
+	}
+	buffer := bytes.Buffer{}
+	for _, token := range q.GetTokens() {
+		switch token.Any {
+		case '*':
+			buffer.WriteString("%")
+		case '?':
+			buffer.WriteString("_")
+		default:
+			buffer.WriteString(escapeLike(string(token.Literal)))
+		}
+	}
+	valueParam := query.AddParameter(buffer.String())
+	return fmt.Sprintf("%s %s %s", field, getLikeOperator(q.CaseInsensitive), valueParam), nil
+}
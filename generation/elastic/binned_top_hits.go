@@ -1,10 +1,12 @@
 package elastic
 
 import (
+	"context"
 	"math"
 
 	"github.com/unchartedsoftware/veldt"
 	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
@@ -35,9 +37,20 @@ func (b *BinnedTopHits) Parse(params map[string]interface{}) error {
 	return b.Bivariate.Parse(params)
 }
 
+// Schema returns the JSON Schema of the parameters of the tile.
+func (b *BinnedTopHits) Schema() *schema.Schema {
+	return schema.Merge(b.TopHits.Schema(), b.Bivariate.Schema())
+}
+
 // Create generates a tile from the provided URI, tile coordinate and query
 // parameters.
 func (b *BinnedTopHits) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return b.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (b *BinnedTopHits) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
 	// create search service
 	search, err := b.CreateSearchService(uri)
 	if err != nil {
@@ -62,7 +75,7 @@ func (b *BinnedTopHits) Create(uri string, coord *binning.TileCoord, query veldt
 	search.Aggregation("x", aggs["x"])
 
 	// send query
-	res, err := search.Do()
+	res, err := doSearch(ctx, search)
 	if err != nil {
 		return nil, err
 	}
@@ -19,38 +19,44 @@ func NewBinaryExpression() (veldt.Query, error) {
 }
 
 // Get returns the appropriate elasticsearch query for the binary expression.
+// Chains of the same operator are translated into a single boolean query, and
+// negated operands of an AND are added to it as must_not clauses.
 func (e *BinaryExpression) Get() (elastic.Query, error) {
-
-	left, ok := e.Left.(Query)
-	if !ok {
-		return nil, fmt.Errorf("`Left` is not of type elastic.Query")
-	}
-	right, ok := e.Right.(Query)
-	if !ok {
-		return nil, fmt.Errorf("`Right` is not of type elastic.Query")
-	}
-
-	a, err := left.Get()
-	if err != nil {
-		return nil, err
-	}
-	b, err := right.Get()
-	if err != nil {
-		return nil, err
+	if e.Op != veldt.And && e.Op != veldt.Or {
+		return nil, fmt.Errorf("`%v` operator is not a valid binary operator", e.Op)
 	}
-
 	res := elastic.NewBoolQuery()
-	switch e.Op {
-	case veldt.And:
-		// AND
-		res.Must(a)
-		res.Must(b)
-	case veldt.Or:
-		// OR
-		res.Should(a)
-		res.Should(b)
-	default:
-		return nil, fmt.Errorf("`%v` operator is not a valid binary operator", e.Op)
+	for _, operand := range e.GetOperands() {
+		// NOT
+		unary, ok := operand.(*UnaryExpression)
+		if ok && unary.Op == veldt.Not && e.Op == veldt.And {
+			q, ok := unary.Query.(Query)
+			if !ok {
+				return nil, fmt.Errorf("`Query` is not of type elastic.Query")
+			}
+			a, err := q.Get()
+			if err != nil {
+				return nil, err
+			}
+			res.MustNot(a)
+			continue
+		}
+		q, ok := operand.(Query)
+		if !ok {
+			return nil, fmt.Errorf("operand is not of type elastic.Query")
+		}
+		a, err := q.Get()
+		if err != nil {
+			return nil, err
+		}
+		switch e.Op {
+		case veldt.And:
+			// AND
+			res.Must(a)
+		case veldt.Or:
+			// OR
+			res.Should(a)
+		}
 	}
 	return res, nil
 }
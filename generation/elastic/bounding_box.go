@@ -0,0 +1,28 @@
+package elastic
+
+import (
+	"gopkg.in/olivere/elastic.v3"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// BoundingBox represents an elasticsearch bounding box query.
+type BoundingBox struct {
+	query.BoundingBox
+}
+
+// NewBoundingBox instantiates and returns a new query struct.
+func NewBoundingBox() (veldt.Query, error) {
+	return &BoundingBox{}, nil
+}
+
+// Get returns the appropriate elasticsearch query for the query.
+func (q *BoundingBox) Get() (elastic.Query, error) {
+	if q.IsPair() {
+		return getBoundsQuery(&q.Spatial, &q.Bounds), nil
+	}
+	return elastic.NewGeoBoundingBoxQuery(q.Field).
+		TopLeft(q.MaxY(), q.MinX()).
+		BottomRight(q.MinY(), q.MaxX()), nil
+}
@@ -1,10 +1,12 @@
 package elastic
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/unchartedsoftware/veldt"
 	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
 )
 
 // Count represents an elasticsearch implementation of the count tile.
@@ -28,9 +30,20 @@ func (t *Count) Parse(params map[string]interface{}) error {
 	return t.Bivariate.Parse(params)
 }
 
+// Schema returns the JSON Schema of the parameters of the tile.
+func (t *Count) Schema() *schema.Schema {
+	return t.Bivariate.Schema()
+}
+
 // Create generates a tile from the provided URI, tile coordinate and query
 // parameters.
 func (t *Count) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return t.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (t *Count) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
 	// create search service
 	search, err := t.CreateSearchService(uri)
 	if err != nil {
@@ -48,7 +61,7 @@ func (t *Count) Create(uri string, coord *binning.TileCoord, query veldt.Query)
 	search.Query(q)
 
 	// send query
-	res, err := search.Do()
+	res, err := doSearch(ctx, search)
 	if err != nil {
 		return nil, err
 	}
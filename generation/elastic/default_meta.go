@@ -1,12 +1,14 @@
 package elastic
 
 import (
+	"context"
 	"fmt"
 
 	"gopkg.in/olivere/elastic.v3"
 
 	"github.com/unchartedsoftware/veldt"
 	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
@@ -31,15 +33,27 @@ func (m *DefaultMeta) Parse(params map[string]interface{}) error {
 	return nil
 }
 
+// Schema returns the JSON Schema of the parameters of the metadata, which
+// has none.
+func (m *DefaultMeta) Schema() *schema.Schema {
+	return schema.Object(nil)
+}
+
 // Create generates metadata from the provided URI.
 func (m *DefaultMeta) Create(uri string) ([]byte, error) {
+	return m.CreateContext(context.Background(), uri)
+}
+
+// CreateContext generates metadata from the provided URI, abandoning the
+// requests if the context is done.
+func (m *DefaultMeta) CreateContext(ctx context.Context, uri string) ([]byte, error) {
 	// get the raw mappings
 	service, err := m.CreateMappingService(uri)
 	if err != nil {
 		return nil, err
 	}
 	// get the raw mappings
-	mapping, err := service.Do()
+	mapping, err := service.DoC(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -62,7 +76,7 @@ func (m *DefaultMeta) Create(uri string) ([]byte, error) {
 	// for each type, parse the mapping
 	meta := make(map[string]interface{})
 	for key, typ := range mappings {
-		typeMeta, err := m.parseType(uri, typ)
+		typeMeta, err := m.parseType(ctx, uri, typ)
 		if err != nil {
 			return nil, err
 		}
@@ -88,19 +102,19 @@ func isOrdinal(typ string) bool {
 		typ == "date"
 }
 
-func (m *DefaultMeta) getExtrema(uri string, field string) (*binning.Extrema, error) {
+func (m *DefaultMeta) getExtrema(ctx context.Context, uri string, field string) (*binning.Extrema, error) {
 	// search
 	search, err := m.CreateSearchService(uri)
 	if err != nil {
 		return nil, err
 	}
-	result, err := search.Aggregation("min",
+	search.Aggregation("min",
 		elastic.NewMinAggregation().
 			Field(field)).
 		Aggregation("max",
 			elastic.NewMaxAggregation().
-				Field(field)).
-		Do()
+				Field(field))
+	result, err := doSearch(ctx, search)
 	if err != nil {
 		return nil, err
 	}
@@ -124,13 +138,13 @@ func (m *DefaultMeta) getExtrema(uri string, field string) (*binning.Extrema, er
 	}, nil
 }
 
-func (m *DefaultMeta) getPropertyMeta(uri string, field string, typ string) (*PropertyMeta, error) {
+func (m *DefaultMeta) getPropertyMeta(ctx context.Context, uri string, field string, typ string) (*PropertyMeta, error) {
 	prop := &PropertyMeta{
 		Type: typ,
 	}
 	// if field is ordinal, get the extrema
 	if isOrdinal(typ) {
-		extrema, err := m.getExtrema(uri, field)
+		extrema, err := m.getExtrema(ctx, uri, field)
 		if err != nil {
 			return nil, err
 		}
@@ -139,7 +153,7 @@ func (m *DefaultMeta) getPropertyMeta(uri string, field string, typ string) (*Pr
 	return prop, nil
 }
 
-func (m *DefaultMeta) parsePropertiesRecursive(meta map[string]PropertyMeta, uri string, p map[string]interface{}, path string) error {
+func (m *DefaultMeta) parsePropertiesRecursive(ctx context.Context, meta map[string]PropertyMeta, uri string, p map[string]interface{}, path string) error {
 	children, ok := json.GetChildMap(p)
 	if !ok {
 		return nil
@@ -153,7 +167,7 @@ func (m *DefaultMeta) parsePropertiesRecursive(meta map[string]PropertyMeta, uri
 		subprops, ok := json.GetChild(props, "properties")
 		if ok {
 			// recurse further
-			err := m.parsePropertiesRecursive(meta, uri, subprops, subpath)
+			err := m.parsePropertiesRecursive(ctx, meta, uri, subprops, subpath)
 			if err != nil {
 				return err
 			}
@@ -162,7 +176,7 @@ func (m *DefaultMeta) parsePropertiesRecursive(meta map[string]PropertyMeta, uri
 			// we don't support nested types
 			if ok && typ != "nested" {
 
-				prop, err := m.getPropertyMeta(uri, subpath, typ)
+				prop, err := m.getPropertyMeta(ctx, uri, subpath, typ)
 				if err != nil {
 					return err
 				}
@@ -173,7 +187,7 @@ func (m *DefaultMeta) parsePropertiesRecursive(meta map[string]PropertyMeta, uri
 				if hasFields {
 					for fieldName := range fields {
 						multiFieldPath := subpath + "." + fieldName
-						prop, err = m.getPropertyMeta(uri, multiFieldPath, typ)
+						prop, err = m.getPropertyMeta(ctx, uri, multiFieldPath, typ)
 						if err != nil {
 							return err
 						}
@@ -186,18 +200,18 @@ func (m *DefaultMeta) parsePropertiesRecursive(meta map[string]PropertyMeta, uri
 	return nil
 }
 
-func (m *DefaultMeta) parseProperties(uri string, props map[string]interface{}) (map[string]PropertyMeta, error) {
+func (m *DefaultMeta) parseProperties(ctx context.Context, uri string, props map[string]interface{}) (map[string]PropertyMeta, error) {
 	// create empty map
 	meta := make(map[string]PropertyMeta)
 	// parse recursively, appending to the map
-	err := m.parsePropertiesRecursive(meta, uri, props, "")
+	err := m.parsePropertiesRecursive(ctx, meta, uri, props, "")
 	if err != nil {
 		return nil, err
 	}
 	return meta, nil
 }
 
-func (m *DefaultMeta) parseType(uri string, typ map[string]interface{}) (map[string]PropertyMeta, error) {
+func (m *DefaultMeta) parseType(ctx context.Context, uri string, typ map[string]interface{}) (map[string]PropertyMeta, error) {
 	props, ok := json.GetChild(typ, "properties")
 	if !ok {
 		return nil, fmt.Errorf("Unable to parse `properties` from mappings response for type `%s` for %s",
@@ -205,5 +219,5 @@ func (m *DefaultMeta) parseType(uri string, typ map[string]interface{}) (map[str
 			uri)
 	}
 	// parse json mappings into the property map
-	return m.parseProperties(uri, props)
+	return m.parseProperties(ctx, uri, props)
 }
@@ -1,20 +1,22 @@
 package elastic
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"runtime"
 	"strings"
 	"sync"
-	"time"
 
 	"gopkg.in/olivere/elastic.v3"
 
 	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/resilience"
+	"github.com/unchartedsoftware/veldt/trace"
 )
 
 const (
-	timeout = time.Second * 60
+	searchSpan = "elastic.search"
 )
 
 var (
@@ -24,8 +26,30 @@ var (
 
 // Elastic represents an elasticsearch type.
 type Elastic struct {
-	Host string
-	Port string
+	Host   string
+	Port   string
+	policy *resilience.Policy
+}
+
+// SetPolicy sets the resilience policy of the requests made to
+// elasticsearch.
+func (e *Elastic) SetPolicy(policy *resilience.Policy) {
+	e.policy = policy
+}
+
+// GetPolicy returns the resilience policy of the requests made to
+// elasticsearch. If none has been set, a default policy is created for this
+// type alone.
+func (e *Elastic) GetPolicy() *resilience.Policy {
+	if e.policy == nil {
+		e.policy = resilience.NewDefaultPolicy()
+	}
+	return e.policy
+}
+
+// GetEndpoint returns the host and port of the elasticsearch cluster.
+func (e *Elastic) GetEndpoint() string {
+	return e.Host + ":" + e.Port
 }
 
 // CreateSearchService creates the elasticsearch search service from the provided uri.
@@ -91,14 +115,26 @@ func (e *Elastic) CreateMappingService(uri string) (*elastic.IndicesGetMappingSe
 		Type(typ), nil
 }
 
+// doSearch sends the search request, abandoning it if the context is done. The
+// round trip is traced as part of the context's trace, which is propagated to
+// elasticsearch in the `traceparent` header.
+func doSearch(ctx context.Context, search *elastic.SearchService) (*elastic.SearchResult, error) {
+	ctx, span := trace.Start(ctx, searchSpan)
+	defer span.End()
+	res, err := search.DoC(ctx)
+	span.SetError(err)
+	return res, err
+}
+
 func (e *Elastic) createClient() (*elastic.Client, error) {
-	endpoint := e.Host + ":" + e.Port
+	endpoint := e.GetEndpoint()
 	mutex.Lock()
 	client, ok := clients[endpoint]
 	if !ok {
 		c, err := elastic.NewClient(
+			// requests are timed out by the resilience policy
 			elastic.SetHttpClient(&http.Client{
-				Timeout: timeout,
+				Transport: trace.NewTransport(nil),
 			}),
 			elastic.SetURL(endpoint),
 			elastic.SetSniff(false),
@@ -0,0 +1,81 @@
+package elastic
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+func init() {
+	veldt.RegisterBackend("elastic", &veldt.Backend{
+		Binary: NewBinaryExpression,
+		Unary:  NewUnaryExpression,
+		Queries: map[string]veldt.QueryCtor{
+			"boundingBox":   NewBoundingBox,
+			"equals":        NewEquals,
+			"exists":        NewExists,
+			"fuzzy":         NewFuzzy,
+			"has":           NewHas,
+			"matchesString": NewMatchesString,
+			"phrase":        NewPhrase,
+			"polygon":       NewPolygon,
+			"prefix":        NewPrefix,
+			"radius":        NewRadius,
+			"range":         NewRange,
+			"regexp":        NewRegexp,
+			"wildcard":      NewWildcard,
+		},
+		Tiles: map[string]veldt.TileFactory{
+			"binnedTopHits":       newTileFactory(NewBinnedTopHits),
+			"count":               newTileFactory(NewCountTile),
+			"frequency":           newTileFactory(NewFrequencyTile),
+			"heatmap":             newTileFactory(NewHeatmapTile),
+			"macro":               newTileFactory(NewMacroTile),
+			"macroEdge":           newTileFactory(NewMacroEdgeTile),
+			"micro":               newTileFactory(NewMicroTile),
+			"targetTermCount":     newTileFactory(NewTargetTermCountTile),
+			"targetTermFrequency": newTileFactory(NewTargetTermFrequencyTile),
+			"topTermCount":        newTileFactory(NewTopTermCountTile),
+			"topTermFrequency":    newTileFactory(NewTopTermFrequencyTile),
+		},
+		Metas: map[string]veldt.MetaFactory{
+			"default": func(conn map[string]interface{}) (veldt.MetaCtor, error) {
+				host, port, err := parseConnection(conn)
+				if err != nil {
+					return nil, err
+				}
+				return NewDefaultMeta(host, port), nil
+			},
+		},
+	})
+}
+
+func newTileFactory(ctor func(host, port string) veldt.TileCtor) veldt.TileFactory {
+	return func(conn map[string]interface{}) (veldt.TileCtor, error) {
+		host, port, err := parseConnection(conn)
+		if err != nil {
+			return nil, err
+		}
+		return ctor(host, port), nil
+	}
+}
+
+// parseConnection returns the host and port of the connection parameters. The
+// port may be a string or a number.
+func parseConnection(conn map[string]interface{}) (string, string, error) {
+	host, ok := json.GetString(conn, "host")
+	if !ok {
+		return "", "", fmt.Errorf("`host` parameter missing from elastic connection")
+	}
+	port, ok := json.GetString(conn, "port")
+	if ok {
+		return host, port, nil
+	}
+	num, ok := json.GetInt(conn, "port")
+	if !ok {
+		return "", "", fmt.Errorf("`port` parameter missing from elastic connection")
+	}
+	return host, strconv.Itoa(num), nil
+}
@@ -1,8 +1,10 @@
 package elastic
 
 import (
+	"context"
 	"github.com/unchartedsoftware/veldt"
 	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
@@ -33,9 +35,20 @@ func (t *FrequencyTile) Parse(params map[string]interface{}) error {
 	return t.Frequency.Parse(params)
 }
 
+// Schema returns the JSON Schema of the parameters of the tile.
+func (t *FrequencyTile) Schema() *schema.Schema {
+	return schema.Merge(t.Bivariate.Schema(), t.Frequency.Schema())
+}
+
 // Create generates a tile from the provided URI, tile coordinate and query
 // parameters.
 func (t *FrequencyTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return t.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (t *FrequencyTile) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
 	// create search service
 	search, err := t.CreateSearchService(uri)
 	if err != nil {
@@ -60,7 +73,7 @@ func (t *FrequencyTile) Create(uri string, coord *binning.TileCoord, query veldt
 	search.Aggregation("frequency", aggs["frequency"])
 
 	// send query
-	res, err := search.Do()
+	res, err := doSearch(ctx, search)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,24 @@
+package elastic
+
+import (
+	"gopkg.in/olivere/elastic.v3"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Fuzzy represents an elasticsearch fuzzy query.
+type Fuzzy struct {
+	query.Fuzzy
+}
+
+// NewFuzzy instantiates and returns a new query struct.
+func NewFuzzy() (veldt.Query, error) {
+	return &Fuzzy{}, nil
+}
+
+// Get returns the appropriate elasticsearch query for the query.
+func (q *Fuzzy) Get() (elastic.Query, error) {
+	return elastic.NewFuzzyQuery(q.Field, q.Value).
+		Fuzziness(q.GetFuzziness()), nil
+}
@@ -1,10 +1,12 @@
 package elastic
 
 import (
+	"context"
 	"encoding/binary"
 
 	"github.com/unchartedsoftware/veldt"
 	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
 )
 
 // HeatmapTile represents an elasticsearch implementation of the heatmap tile.
@@ -28,9 +30,20 @@ func (h *HeatmapTile) Parse(params map[string]interface{}) error {
 	return h.Bivariate.Parse(params)
 }
 
+// Schema returns the JSON Schema of the parameters of the tile.
+func (h *HeatmapTile) Schema() *schema.Schema {
+	return h.Bivariate.Schema()
+}
+
 // Create generates a tile from the provided URI, tile coordinate and query
 // parameters.
 func (h *HeatmapTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return h.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (h *HeatmapTile) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
 	// create search service
 	search, err := h.CreateSearchService(uri)
 	if err != nil {
@@ -53,7 +66,7 @@ func (h *HeatmapTile) Create(uri string, coord *binning.TileCoord, query veldt.Q
 	search.Aggregation("x", aggs["x"])
 
 	// send query
-	res, err := search.Do()
+	res, err := doSearch(ctx, search)
 	if err != nil {
 		return nil, err
 	}
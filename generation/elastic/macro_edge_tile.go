@@ -1,10 +1,12 @@
 package elastic
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/unchartedsoftware/veldt"
 	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
 	"github.com/unchartedsoftware/veldt/tile"
 )
 
@@ -47,9 +49,20 @@ func (e *MacroEdgeTile) Parse(params map[string]interface{}) error {
 	return e.MacroEdge.Parse(params)
 }
 
+// Schema returns the JSON Schema of the parameters of the tile.
+func (e *MacroEdgeTile) Schema() *schema.Schema {
+	return schema.Merge(e.Edge.Schema(), e.TopHits.Schema(), e.MacroEdge.Schema())
+}
+
 // Create generates a tile from the provided URI, tile coordinate and query
 // parameters.
 func (e *MacroEdgeTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return e.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (e *MacroEdgeTile) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
 	// create search service
 	search, err := e.CreateSearchService(uri)
 	if err != nil {
@@ -73,7 +86,7 @@ func (e *MacroEdgeTile) Create(uri string, coord *binning.TileCoord, query veldt
 	search.Aggregation("top-hits", aggs["top-hits"])
 
 	// send query
-	res, err := search.Do()
+	res, err := doSearch(ctx, search)
 	if err != nil {
 		return nil, err
 	}
@@ -1,10 +1,12 @@
 package elastic
 
 import (
+	"context"
 	"math"
 
 	"github.com/unchartedsoftware/veldt"
 	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
 	"github.com/unchartedsoftware/veldt/tile"
 )
 
@@ -34,9 +36,20 @@ func (m *MacroTile) Parse(params map[string]interface{}) error {
 	return m.Macro.Parse(params)
 }
 
+// Schema returns the JSON Schema of the parameters of the tile.
+func (m *MacroTile) Schema() *schema.Schema {
+	return schema.Merge(m.Bivariate.Schema(), m.Macro.Schema())
+}
+
 // Create generates a tile from the provided URI, tile coordinate and query
 // parameters.
 func (m *MacroTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return m.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (m *MacroTile) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
 	// create search service
 	search, err := m.CreateSearchService(uri)
 	if err != nil {
@@ -59,7 +72,7 @@ func (m *MacroTile) Create(uri string, coord *binning.TileCoord, query veldt.Que
 	search.Aggregation("x", aggs["x"])
 
 	// send query
-	res, err := search.Do()
+	res, err := doSearch(ctx, search)
 	if err != nil {
 		return nil, err
 	}
@@ -1,10 +1,12 @@
 package elastic
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/unchartedsoftware/veldt"
 	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
 	"github.com/unchartedsoftware/veldt/tile"
 )
 
@@ -48,9 +50,20 @@ func (m *MicroTile) Parse(params map[string]interface{}) error {
 	return nil
 }
 
+// Schema returns the JSON Schema of the parameters of the tile.
+func (m *MicroTile) Schema() *schema.Schema {
+	return schema.Merge(m.Bivariate.Schema(), m.TopHits.Schema(), m.Micro.Schema())
+}
+
 // Create generates a tile from the provided URI, tile coordinate and query
 // parameters.
 func (m *MicroTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return m.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (m *MicroTile) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
 	// create search service
 	search, err := m.CreateSearchService(uri)
 	if err != nil {
@@ -73,7 +86,7 @@ func (m *MicroTile) Create(uri string, coord *binning.TileCoord, query veldt.Que
 	search.Aggregation("top-hits", aggs["top-hits"])
 
 	// send query
-	res, err := search.Do()
+	res, err := doSearch(ctx, search)
 	if err != nil {
 		return nil, err
 	}
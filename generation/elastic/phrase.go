@@ -0,0 +1,25 @@
+package elastic
+
+import (
+	"gopkg.in/olivere/elastic.v3"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Phrase represents an elasticsearch match phrase query. Words are those of
+// the analyzer of the field.
+type Phrase struct {
+	query.Phrase
+}
+
+// NewPhrase instantiates and returns a new query struct.
+func NewPhrase() (veldt.Query, error) {
+	return &Phrase{}, nil
+}
+
+// Get returns the appropriate elasticsearch query for the query.
+func (q *Phrase) Get() (elastic.Query, error) {
+	return elastic.NewMatchPhraseQuery(q.Field, q.Phrase.Phrase).
+		Slop(q.Slop), nil
+}
@@ -0,0 +1,47 @@
+package elastic
+
+import (
+	"fmt"
+
+	"gopkg.in/olivere/elastic.v3"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Polygon represents an elasticsearch polygon query.
+type Polygon struct {
+	query.Polygon
+}
+
+// NewPolygon instantiates and returns a new query struct.
+func NewPolygon() (veldt.Query, error) {
+	return &Polygon{}, nil
+}
+
+// Get returns the appropriate elasticsearch query for the query. Points of a
+// pair of fields are filtered by the bounds of the polygon before being tested
+// by script.
+func (q *Polygon) Get() (elastic.Query, error) {
+	if q.IsPair() {
+		rings := make([][][]float64, len(q.Polygon.Polygon.Rings))
+		for i, ring := range q.Polygon.Polygon.Rings {
+			rings[i] = make([][]float64, len(ring))
+			for j, coord := range ring {
+				rings[i][j] = []float64{coord.X, coord.Y}
+			}
+		}
+		script := getScriptQuery(&q.Spatial, polygonScript, map[string]interface{}{
+			"rings": rings,
+		})
+		return getBoundsQuery(&q.Spatial, q.Polygon.Polygon.Bounds()).Must(script), nil
+	}
+	if len(q.Polygon.Polygon.Rings) > 1 {
+		return nil, fmt.Errorf("polygons with holes are not supported for geo point field `%s`", q.Field)
+	}
+	polygon := elastic.NewGeoPolygonQuery(q.Field)
+	for _, coord := range q.Polygon.Polygon.Rings[0] {
+		polygon.AddPoint(coord.Y, coord.X)
+	}
+	return polygon, nil
+}
@@ -0,0 +1,29 @@
+package elastic
+
+import (
+	"fmt"
+
+	"gopkg.in/olivere/elastic.v3"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Prefix represents an elasticsearch prefix query. Case-insensitive matching
+// is not supported, as the prefix is compared against the indexed terms.
+type Prefix struct {
+	query.Prefix
+}
+
+// NewPrefix instantiates and returns a new query struct.
+func NewPrefix() (veldt.Query, error) {
+	return &Prefix{}, nil
+}
+
+// Get returns the appropriate elasticsearch query for the query.
+func (q *Prefix) Get() (elastic.Query, error) {
+	if q.CaseInsensitive {
+		return nil, fmt.Errorf("`caseInsensitive` parameter is not supported by elasticsearch")
+	}
+	return elastic.NewPrefixQuery(q.Field, q.Prefix.Prefix), nil
+}
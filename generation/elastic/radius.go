@@ -0,0 +1,44 @@
+package elastic
+
+import (
+	"fmt"
+
+	"gopkg.in/olivere/elastic.v3"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/geometry"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Radius represents an elasticsearch radius query.
+type Radius struct {
+	query.Radius
+}
+
+// NewRadius instantiates and returns a new query struct.
+func NewRadius() (veldt.Query, error) {
+	return &Radius{}, nil
+}
+
+// Get returns the appropriate elasticsearch query for the query. Points of a
+// pair of fields are filtered by the bounds of the radius before being tested
+// by script.
+func (q *Radius) Get() (elastic.Query, error) {
+	if q.IsPair() {
+		source := distanceScript
+		if q.Geographic {
+			source = greatCircleScript
+		}
+		script := getScriptQuery(&q.Spatial, source, map[string]interface{}{
+			"x":        q.Center.X,
+			"y":        q.Center.Y,
+			"distance": q.Distance,
+			"radius":   geometry.EarthRadius,
+		})
+		return getBoundsQuery(&q.Spatial, q.GetBounds()).Must(script), nil
+	}
+	return elastic.NewGeoDistanceQuery(q.Field).
+		Lat(q.Center.Y).
+		Lon(q.Center.X).
+		Distance(fmt.Sprintf("%vm", q.Distance)), nil
+}
@@ -0,0 +1,30 @@
+package elastic
+
+import (
+	"fmt"
+
+	"gopkg.in/olivere/elastic.v3"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Regexp represents an elasticsearch regexp query, using the Lucene regular
+// expression syntax. Case-insensitive matching is not supported, as the
+// pattern is compared against the indexed terms.
+type Regexp struct {
+	query.Regexp
+}
+
+// NewRegexp instantiates and returns a new query struct.
+func NewRegexp() (veldt.Query, error) {
+	return &Regexp{}, nil
+}
+
+// Get returns the appropriate elasticsearch query for the query.
+func (q *Regexp) Get() (elastic.Query, error) {
+	if q.CaseInsensitive {
+		return nil, fmt.Errorf("`caseInsensitive` parameter is not supported by elasticsearch")
+	}
+	return elastic.NewRegexpQuery(q.Field, q.Pattern), nil
+}
@@ -0,0 +1,79 @@
+package elastic
+
+import (
+	"gopkg.in/olivere/elastic.v3"
+
+	"github.com/unchartedsoftware/veldt/geometry"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+const (
+	// polygonScript tests whether the point is within the rings by the
+	// even-odd rule.
+	polygonScript = `
+		if (doc[xField].empty || doc[yField].empty) {
+			return false;
+		}
+		def x = doc[xField].value;
+		def y = doc[yField].value;
+		def inside = false;
+		for (ring in rings) {
+			def j = ring.size() - 1;
+			for (def i = 0; i < ring.size(); i++) {
+				def xi = ring[i][0];
+				def yi = ring[i][1];
+				def xj = ring[j][0];
+				def yj = ring[j][1];
+				if (((yi > y) != (yj > y)) && (x < (xj - xi) * (y - yi) / (yj - yi) + xi)) {
+					inside = !inside;
+				}
+				j = i;
+			}
+		}
+		return inside;`
+	// distanceScript tests whether the point is within the planar distance
+	// of the center.
+	distanceScript = `
+		if (doc[xField].empty || doc[yField].empty) {
+			return false;
+		}
+		def dx = doc[xField].value - x;
+		def dy = doc[yField].value - y;
+		return dx * dx + dy * dy <= distance * distance;`
+	// greatCircleScript tests whether the point is within the great-circle
+	// distance of the center, in meters, by the haversine formula.
+	greatCircleScript = `
+		if (doc[xField].empty || doc[yField].empty) {
+			return false;
+		}
+		def lat1 = Math.toRadians(y);
+		def lat2 = Math.toRadians(doc[yField].value);
+		def dlat = lat2 - lat1;
+		def dlon = Math.toRadians(doc[xField].value - x);
+		def a = Math.pow(Math.sin(dlat / 2), 2) +
+			Math.cos(lat1) * Math.cos(lat2) * Math.pow(Math.sin(dlon / 2), 2);
+		return 2 * radius * Math.asin(Math.min(1, Math.sqrt(a))) <= distance;`
+)
+
+// getBoundsQuery returns a query checking that the x and y fields of the
+// location are within the bounds, inclusive.
+func getBoundsQuery(s *query.Spatial, bounds *geometry.Bounds) *elastic.BoolQuery {
+	return elastic.NewBoolQuery().Must(
+		elastic.NewRangeQuery(s.XField).
+			Gte(bounds.MinX()).
+			Lte(bounds.MaxX()),
+		elastic.NewRangeQuery(s.YField).
+			Gte(bounds.MinY()).
+			Lte(bounds.MaxY()))
+}
+
+// getScriptQuery returns a groovy script query with the x and y fields of the
+// location passed as parameters.
+func getScriptQuery(s *query.Spatial, source string, params map[string]interface{}) *elastic.ScriptQuery {
+	params["xField"] = s.XField
+	params["yField"] = s.YField
+	script := elastic.NewScript(source).
+		Lang("groovy").
+		Params(params)
+	return elastic.NewScriptQuery(script)
+}
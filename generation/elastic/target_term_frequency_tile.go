@@ -1,8 +1,10 @@
 package elastic
 
 import (
+	"context"
 	"github.com/unchartedsoftware/veldt"
 	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
@@ -31,12 +33,27 @@ func (t *TargetTermFrequencyTile) Parse(params map[string]interface{}) error {
 	if err != nil {
 		return err
 	}
-	return t.TargetTerms.Parse(params)
+	err = t.TargetTerms.Parse(params)
+	if err != nil {
+		return err
+	}
+	return t.Frequency.Parse(params)
+}
+
+// Schema returns the JSON Schema of the parameters of the tile.
+func (t *TargetTermFrequencyTile) Schema() *schema.Schema {
+	return schema.Merge(t.Bivariate.Schema(), t.TargetTerms.Schema(), t.Frequency.Schema())
 }
 
 // Create generates a tile from the provided URI, tile coordinate and query
 // parameters.
 func (t *TargetTermFrequencyTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return t.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (t *TargetTermFrequencyTile) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
 	// create search service
 	search, err := t.CreateSearchService(uri)
 	if err != nil {
@@ -60,7 +77,7 @@ func (t *TargetTermFrequencyTile) Create(uri string, coord *binning.TileCoord, q
 		search.Aggregation(term, agg.SubAggregation("frequency", freqAggs["frequency"]))
 	}
 	// send query
-	res, err := search.Do()
+	res, err := doSearch(ctx, search)
 	if err != nil {
 		return nil, err
 	}
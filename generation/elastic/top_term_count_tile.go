@@ -1,8 +1,10 @@
 package elastic
 
 import (
+	"context"
 	"github.com/unchartedsoftware/veldt"
 	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
@@ -33,9 +35,20 @@ func (t *TopTermCountTile) Parse(params map[string]interface{}) error {
 	return t.TopTerms.Parse(params)
 }
 
+// Schema returns the JSON Schema of the parameters of the tile.
+func (t *TopTermCountTile) Schema() *schema.Schema {
+	return schema.Merge(t.Bivariate.Schema(), t.TopTerms.Schema())
+}
+
 // Create generates a tile from the provided URI, tile coordinate and query
 // parameters.
 func (t *TopTermCountTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return t.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (t *TopTermCountTile) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
 	// create search service
 	search, err := t.CreateSearchService(uri)
 	if err != nil {
@@ -55,7 +68,7 @@ func (t *TopTermCountTile) Create(uri string, coord *binning.TileCoord, query ve
 	// set the aggregation
 	search.Aggregation("top-terms", aggs["top-terms"])
 	// send query
-	res, err := search.Do()
+	res, err := doSearch(ctx, search)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,30 @@
+package elastic
+
+import (
+	"fmt"
+
+	"gopkg.in/olivere/elastic.v3"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Wildcard represents an elasticsearch wildcard query. Case-insensitive
+// matching is not supported, as the pattern is compared against the indexed
+// terms.
+type Wildcard struct {
+	query.Wildcard
+}
+
+// NewWildcard instantiates and returns a new query struct.
+func NewWildcard() (veldt.Query, error) {
+	return &Wildcard{}, nil
+}
+
+// Get returns the appropriate elasticsearch query for the query.
+func (q *Wildcard) Get() (elastic.Query, error) {
+	if q.CaseInsensitive {
+		return nil, fmt.Errorf("`caseInsensitive` parameter is not supported by elasticsearch")
+	}
+	return elastic.NewWildcardQuery(q.Field, q.Pattern), nil
+}
@@ -0,0 +1,15 @@
+package file
+
+import (
+	"github.com/unchartedsoftware/veldt"
+)
+
+func init() {
+	veldt.RegisterBackend("file", &veldt.Backend{
+		Tiles: map[string]veldt.TileFactory{
+			"tile": func(conn map[string]interface{}) (veldt.TileCtor, error) {
+				return NewTile(), nil
+			},
+		},
+	})
+}
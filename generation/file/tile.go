@@ -2,21 +2,27 @@ package file
 
 import (
 	"fmt"
+	"io/ioutil"
 	"math"
 	"os"
 	"strconv"
 
 	"github.com/unchartedsoftware/veldt"
 	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/codec"
+	"github.com/unchartedsoftware/veldt/schema"
 	"github.com/unchartedsoftware/veldt/tile"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
-// Tile represents a filesystem tile type.
+// Tile represents a filesystem tile type. Images are decoded into RGBA
+// pixels, unless `decode` is false, in which case the files are served as is
+// and are not compressed again by the pipeline.
 type Tile struct {
 	path      string
 	ext       string
 	padCoords bool
+	decode    bool
 }
 
 // NewTile instantiates and returns a new filesystem tile.
@@ -40,13 +46,35 @@ func (t *Tile) Parse(params map[string]interface{}) error {
 	}
 	// do we pad the coords?
 	padcoords := json.GetBoolDefault(params, true, "padcoords")
+	// do we decode the files?
+	decode := json.GetBoolDefault(params, true, "decode")
 	// set attributes
 	t.path = path
 	t.ext = ext
 	t.padCoords = padcoords
+	t.decode = decode
 	return nil
 }
 
+// Schema returns the JSON Schema of the parameters of the tile.
+func (t *Tile) Schema() *schema.Schema {
+	return schema.Object(map[string]*schema.Schema{
+		"path":      schema.String("The directory of the tile files."),
+		"ext":       schema.String("The extension of the tile files."),
+		"padcoords": schema.Boolean("Whether the coordinates in file names are zero padded.").WithDefault(true),
+		"decode":    schema.Boolean("Whether images are decoded into RGBA pixels, rather than served as is.").WithDefault(true),
+	}, "path", "ext")
+}
+
+// Compression returns the `identity` codec for undecoded images, which are
+// already compressed, deferring to the pipeline otherwise.
+func (t *Tile) Compression() string {
+	if !t.decode && tile.IsImage(t.ext) {
+		return codec.Identity
+	}
+	return ""
+}
+
 // Create generates a tile from the provided URI, tile coordinate and query
 // parameters.
 func (t *Tile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
@@ -70,6 +98,9 @@ func (t *Tile) Create(uri string, coord *binning.TileCoord, query veldt.Query) (
 		return nil, err
 	}
 	defer file.Close()
+	if !t.decode {
+		return ioutil.ReadAll(file)
+	}
 	// decode file
 	return tile.Decode(t.ext, file)
 }
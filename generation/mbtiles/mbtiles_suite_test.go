@@ -0,0 +1,13 @@
+package mbtiles_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestMBTiles(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "MBTiles Suite")
+}
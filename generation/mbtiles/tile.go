@@ -0,0 +1,84 @@
+package mbtiles
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/archive"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+var (
+	mutex   = sync.Mutex{}
+	readers = make(map[string]archive.Reader)
+)
+
+func getReader(path string) (archive.Reader, error) {
+	mutex.Lock()
+	defer runtime.Gosched()
+	defer mutex.Unlock()
+	reader, ok := readers[path]
+	if !ok {
+		var err error
+		reader, err = archive.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		Infof("Opened archive `%s`", path)
+		readers[path] = reader
+	}
+	return reader, nil
+}
+
+// Tile represents an archive tile type, serving the tiles of an MBTiles or
+// PMTiles archive. Archives are opened once and shared across tiles.
+type Tile struct {
+	path string
+}
+
+// NewTile instantiates and returns a new archive tile.
+func NewTile() veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		return &Tile{}, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (t *Tile) Parse(params map[string]interface{}) error {
+	// get path
+	path, ok := json.GetString(params, "path")
+	if !ok {
+		return fmt.Errorf("`path` parameter missing from tile")
+	}
+	t.path = path
+	return nil
+}
+
+// Schema returns the JSON Schema of the parameters of the tile.
+func (t *Tile) Schema() *schema.Schema {
+	return schema.Object(map[string]*schema.Schema{
+		"path": schema.String("The path of the MBTiles archive."),
+	}, "path")
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (t *Tile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	reader, err := getReader(t.path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := reader.ReadTile(coord)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		// don't return an error if the tile doesn't exist
+		return []byte{}, nil
+	}
+	return data, nil
+}
@@ -0,0 +1,64 @@
+package mbtiles_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/unchartedsoftware/veldt/archive"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/generation/mbtiles"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("Tile", func() {
+
+	var dir string
+	var path string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "veldt-mbtiles")
+		Expect(err).To(BeNil())
+		path = filepath.Join(dir, "test.mbtiles")
+		writer, err := archive.NewWriter(path, &archive.Metadata{
+			Name:    "test",
+			Format:  "bin",
+			MaxZoom: 2,
+		})
+		Expect(err).To(BeNil())
+		Expect(writer.WriteTile(&binning.TileCoord{Z: 2, X: 1, Y: 3}, []byte("tile"))).To(BeNil())
+		Expect(writer.Close()).To(BeNil())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	create := func(coord *binning.TileCoord) []byte {
+		t, err := mbtiles.NewTile()()
+		Expect(err).To(BeNil())
+		err = t.Parse(JSON(`{"path": "` + path + `"}`))
+		Expect(err).To(BeNil())
+		data, err := t.Create("test", coord, nil)
+		Expect(err).To(BeNil())
+		return data
+	}
+
+	It("should serve the tiles of the archive", func() {
+		Expect(create(&binning.TileCoord{Z: 2, X: 1, Y: 3})).To(Equal([]byte("tile")))
+	})
+
+	It("should serve missing tiles as empty", func() {
+		Expect(create(&binning.TileCoord{Z: 2, X: 0, Y: 0})).To(Equal([]byte{}))
+	})
+
+	It("should return an error if the path is missing", func() {
+		t, err := mbtiles.NewTile()()
+		Expect(err).To(BeNil())
+		Expect(t.Parse(JSON(`{}`))).NotTo(BeNil())
+	})
+})
@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"math"
+
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+// Bivariate represents an in-memory implementation of the bivariate tile.
+type Bivariate struct {
+	tile.Bivariate
+}
+
+// GetQuery returns the tiling filter.
+func (b *Bivariate) GetQuery(coord *binning.TileCoord) Filter {
+	// get tile bounds
+	bounds := b.TileBounds(coord)
+	minX, maxX := bounds.MinX(), bounds.MaxX()
+	minY, maxY := bounds.MinY(), bounds.MaxY()
+	return func(doc map[string]interface{}) bool {
+		x, ok := getFloat(doc, b.XField)
+		if !ok || x < minX || x >= maxX {
+			return false
+		}
+		y, ok := getFloat(doc, b.YField)
+		if !ok || y < minY || y >= maxY {
+			return false
+		}
+		return true
+	}
+}
+
+// GetBins groups the provided hits into bins. Empty bins are nil.
+func (b *Bivariate) GetBins(coord *binning.TileCoord, hits []map[string]interface{}) [][]map[string]interface{} {
+	// allocate bins
+	bins := make([][]map[string]interface{}, b.Resolution*b.Resolution)
+	// fill bins
+	for _, hit := range hits {
+		x, _ := getFloat(hit, b.XField)
+		y, _ := getFloat(hit, b.YField)
+		xBin := b.GetXBin(coord, x)
+		yBin := b.GetYBin(coord, y)
+		index := xBin + b.Resolution*yBin
+		bins[index] = append(bins[index], hit)
+	}
+	return bins
+}
+
+// GetLevelExtrema returns the extrema of the non-empty bin counts across every
+// tile of the provided zoom level, where each tile has the provided
+// resolution. Hits outside of the global bounds are ignored. If no bins are
+// non-empty, nil is returned.
+func (b *Bivariate) GetLevelExtrema(z uint32, resolution int, hits []map[string]interface{}) *binning.Extrema {
+	bounds := b.GlobalBounds()
+	numBins := math.Pow(2, float64(z)) * float64(resolution)
+	binSizeX := (bounds.Right - bounds.Left) / numBins
+	binSizeY := (bounds.Top - bounds.Bottom) / numBins
+	// count the hits of each bin
+	counts := make(map[[2]int64]float64)
+	for _, hit := range hits {
+		x, ok := getFloat(hit, b.XField)
+		if !ok || x < bounds.MinX() || x >= bounds.MaxX() {
+			continue
+		}
+		y, ok := getFloat(hit, b.YField)
+		if !ok || y < bounds.MinY() || y >= bounds.MaxY() {
+			continue
+		}
+		bin := [2]int64{
+			int64(math.Floor((x - bounds.Left) / binSizeX)),
+			int64(math.Floor((y - bounds.Bottom) / binSizeY)),
+		}
+		counts[bin]++
+	}
+	var extrema *binning.Extrema
+	for _, count := range counts {
+		if extrema == nil {
+			extrema = &binning.Extrema{
+				Min: count,
+				Max: count,
+			}
+			continue
+		}
+		extrema.Min = math.Min(extrema.Min, count)
+		extrema.Max = math.Max(extrema.Max, count)
+	}
+	return extrema
+}
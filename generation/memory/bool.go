@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+)
+
+// BinaryExpression represents an AND / OR filter.
+type BinaryExpression struct {
+	veldt.BinaryExpression
+}
+
+// NewBinaryExpression instantiates and returns a new binary expression.
+func NewBinaryExpression() (veldt.Query, error) {
+	return &BinaryExpression{}, nil
+}
+
+// Get returns the appropriate filter for the binary expression. Chains of the
+// same operator are evaluated as a single filter.
+func (e *BinaryExpression) Get() (Filter, error) {
+	operands := e.GetOperands()
+	filters := make([]Filter, len(operands))
+	for i, operand := range operands {
+		q, ok := operand.(Query)
+		if !ok {
+			return nil, fmt.Errorf("operand is not of type memory.Query")
+		}
+		filter, err := q.Get()
+		if err != nil {
+			return nil, err
+		}
+		filters[i] = filter
+	}
+	switch e.Op {
+	case veldt.And:
+		// AND
+		return func(doc map[string]interface{}) bool {
+			for _, filter := range filters {
+				if !filter(doc) {
+					return false
+				}
+			}
+			return true
+		}, nil
+	case veldt.Or:
+		// OR
+		return func(doc map[string]interface{}) bool {
+			for _, filter := range filters {
+				if filter(doc) {
+					return true
+				}
+			}
+			return false
+		}, nil
+	}
+	return nil, fmt.Errorf("`%v` operator is not a valid binary operator", e.Op)
+}
+
+// UnaryExpression represents a NOT filter.
+type UnaryExpression struct {
+	veldt.UnaryExpression
+}
+
+// NewUnaryExpression instantiates and returns a new unary expression.
+func NewUnaryExpression() (veldt.Query, error) {
+	return &UnaryExpression{}, nil
+}
+
+// Get returns the appropriate filter for the unary expression.
+func (e *UnaryExpression) Get() (Filter, error) {
+	q, ok := e.Query.(Query)
+	if !ok {
+		return nil, fmt.Errorf("`Query` is not of type memory.Query")
+	}
+	a, err := q.Get()
+	if err != nil {
+		return nil, err
+	}
+	switch e.Op {
+	case veldt.Not:
+		// NOT
+		return func(doc map[string]interface{}) bool {
+			return !a(doc)
+		}, nil
+	}
+	return nil, fmt.Errorf("`%v` operator is not a valid unary operator", e.Op)
+}
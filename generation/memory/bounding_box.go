@@ -0,0 +1,24 @@
+package memory
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// BoundingBox represents an in-memory bounding box query.
+type BoundingBox struct {
+	query.BoundingBox
+}
+
+// NewBoundingBox instantiates and returns a new query struct.
+func NewBoundingBox() (veldt.Query, error) {
+	return &BoundingBox{}, nil
+}
+
+// Get returns the appropriate filter for the query.
+func (q *BoundingBox) Get() (Filter, error) {
+	return func(doc map[string]interface{}) bool {
+		x, y, ok := getPoint(doc, &q.Spatial)
+		return ok && contains(&q.Bounds, x, y)
+	}, nil
+}
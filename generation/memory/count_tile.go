@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
+)
+
+// Count represents an in-memory implementation of the count tile.
+type Count struct {
+	Memory
+	Bivariate
+}
+
+// NewCountTile instantiates and returns a new tile struct.
+func NewCountTile() veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		return &Count{}, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (t *Count) Parse(params map[string]interface{}) error {
+	return t.Bivariate.Parse(params)
+}
+
+// Schema returns the JSON Schema of the parameters of the tile.
+func (t *Count) Schema() *schema.Schema {
+	return t.Bivariate.Schema()
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (t *Count) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return t.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (t *Count) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// create root query
+	q, err := t.CreateQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	// search with tiling query
+	hits, err := t.Search(ctx, uri, q, t.Bivariate.GetQuery(coord))
+	if err != nil {
+		return nil, err
+	}
+	return []byte(fmt.Sprintf(`{"count":%d}`, len(hits))), nil
+}
+
+// LevelExtrema returns the extrema of the non-empty tile counts across the
+// provided zoom level.
+func (t *Count) LevelExtrema(uri string, z uint32, query veldt.Query) (*binning.Extrema, error) {
+	// create root query
+	q, err := t.CreateQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	hits, err := t.Search(context.Background(), uri, q)
+	if err != nil {
+		return nil, err
+	}
+	return t.Bivariate.GetLevelExtrema(z, 1, hits), nil
+}
@@ -0,0 +1,138 @@
+package memory
+
+import (
+	"math"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// DefaultMeta represents a meta data generator that produces default
+// metadata with property types and extrema.
+type DefaultMeta struct {
+	Memory
+}
+
+// NewDefaultMeta instantiates and returns a pointer to a new generator.
+func NewDefaultMeta() veldt.MetaCtor {
+	return func() (veldt.Meta, error) {
+		return &DefaultMeta{}, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the structs attributes.
+func (m *DefaultMeta) Parse(params map[string]interface{}) error {
+	return nil
+}
+
+// Schema returns the JSON Schema of the parameters of the metadata, which
+// has none.
+func (m *DefaultMeta) Schema() *schema.Schema {
+	return schema.Object(nil)
+}
+
+// PropertyMeta represents the meta data for a single property.
+type PropertyMeta struct {
+	Type    string           `json:"type"`
+	Extrema *binning.Extrema `json:"extrema,omitempty"`
+}
+
+// Create generates metadata from the provided URI. Property types are inferred
+// from the loaded values as one of `long`, `double`, `boolean` or `string`.
+// Nested attributes are keyed using dot notation.
+func (m *DefaultMeta) Create(uri string) ([]byte, error) {
+	docs, err := getDocs(uri)
+	if err != nil {
+		return nil, err
+	}
+	meta := make(map[string]*PropertyMeta)
+	for _, doc := range docs {
+		parsePropertiesRecursive(meta, doc, "")
+	}
+	return json.Marshal(meta)
+}
+
+func parsePropertiesRecursive(meta map[string]*PropertyMeta, doc map[string]interface{}, path string) {
+	for key, val := range doc {
+		subpath := key
+		if path != "" {
+			subpath = path + "." + key
+		}
+		child, ok := val.(map[string]interface{})
+		if ok {
+			// recurse further
+			parsePropertiesRecursive(meta, child, subpath)
+			continue
+		}
+		arr, ok := val.([]interface{})
+		if !ok {
+			arr = []interface{}{val}
+		}
+		for _, elem := range arr {
+			addValue(meta, subpath, elem)
+		}
+	}
+}
+
+func addValue(meta map[string]*PropertyMeta, field string, val interface{}) {
+	typ := getType(val)
+	if typ == "" {
+		return
+	}
+	prop, ok := meta[field]
+	if !ok {
+		prop = &PropertyMeta{
+			Type: typ,
+		}
+		meta[field] = prop
+	}
+	// resolve conflicting types
+	if prop.Type != typ {
+		if isNumeric(prop.Type) && isNumeric(typ) {
+			prop.Type = "double"
+		} else {
+			prop.Type = "string"
+			prop.Extrema = nil
+		}
+	}
+	// if field is numeric, update the extrema
+	if !isNumeric(prop.Type) {
+		return
+	}
+	num, _ := toFloat(val)
+	if prop.Extrema == nil {
+		prop.Extrema = &binning.Extrema{
+			Min: num,
+			Max: num,
+		}
+		return
+	}
+	prop.Extrema.Min = math.Min(prop.Extrema.Min, num)
+	prop.Extrema.Max = math.Max(prop.Extrema.Max, num)
+}
+
+func isNumeric(typ string) bool {
+	return typ == "long" || typ == "double"
+}
+
+func getType(val interface{}) string {
+	switch v := val.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case nil:
+		return ""
+	default:
+		num, ok := toFloat(v)
+		if !ok {
+			return ""
+		}
+		if num == math.Trunc(num) {
+			return "long"
+		}
+		return "double"
+	}
+}
@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+// Edge represents an in-memory implementation of the edge tile.
+type Edge struct {
+	tile.Edge
+}
+
+// GetQuery returns the tiling filter.
+func (e *Edge) GetQuery(coord *binning.TileCoord) Filter {
+	// get tile bounds
+	bounds := e.TileBounds(coord)
+	minX, maxX := bounds.MinX(), bounds.MaxX()
+	minY, maxY := bounds.MinY(), bounds.MaxY()
+	contains := func(doc map[string]interface{}, xField string, yField string) bool {
+		x, ok := getFloat(doc, xField)
+		if !ok || x < minX || x >= maxX {
+			return false
+		}
+		y, ok := getFloat(doc, yField)
+		if !ok || y < minY || y >= maxY {
+			return false
+		}
+		return true
+	}
+	return func(doc map[string]interface{}) bool {
+		// Require at least 1 of the points, possibly both.
+		if e.Edge.RequireSrc || !e.Edge.RequireDst {
+			if !contains(doc, e.Edge.SrcXField, e.Edge.SrcYField) {
+				return false
+			}
+		}
+		if e.Edge.RequireDst {
+			if !contains(doc, e.Edge.DstXField, e.Edge.DstYField) {
+				return false
+			}
+		}
+		return true
+	}
+}
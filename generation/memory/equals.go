@@ -0,0 +1,29 @@
+package memory
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Equals represents an in-memory term query. Array fields match if any of
+// their elements equal the value.
+type Equals struct {
+	query.Equals
+}
+
+// NewEquals instantiates and returns a new query struct.
+func NewEquals() (veldt.Query, error) {
+	return &Equals{}, nil
+}
+
+// Get returns the appropriate filter for the query.
+func (q *Equals) Get() (Filter, error) {
+	return func(doc map[string]interface{}) bool {
+		for _, val := range getValues(doc, q.Field) {
+			if equals(val, q.Value) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
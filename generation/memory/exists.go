@@ -0,0 +1,24 @@
+package memory
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Exists represents an in-memory exists query.
+type Exists struct {
+	query.Exists
+}
+
+// NewExists instantiates and returns a new query struct.
+func NewExists() (veldt.Query, error) {
+	return &Exists{}, nil
+}
+
+// Get returns the appropriate filter for the query.
+func (q *Exists) Get() (Filter, error) {
+	return func(doc map[string]interface{}) bool {
+		_, ok := getField(doc, q.Field)
+		return ok
+	}, nil
+}
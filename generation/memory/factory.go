@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"github.com/unchartedsoftware/veldt"
+)
+
+func init() {
+	veldt.RegisterBackend("memory", &veldt.Backend{
+		Binary: NewBinaryExpression,
+		Unary:  NewUnaryExpression,
+		Queries: map[string]veldt.QueryCtor{
+			"boundingBox":   NewBoundingBox,
+			"equals":        NewEquals,
+			"exists":        NewExists,
+			"fuzzy":         NewFuzzy,
+			"has":           NewHas,
+			"matchesString": NewMatchesString,
+			"phrase":        NewPhrase,
+			"polygon":       NewPolygon,
+			"prefix":        NewPrefix,
+			"radius":        NewRadius,
+			"range":         NewRange,
+			"regexp":        NewRegexp,
+			"wildcard":      NewWildcard,
+		},
+		Tiles: map[string]veldt.TileFactory{
+			"binnedTopHits":       newTileFactory(NewBinnedTopHits),
+			"count":               newTileFactory(NewCountTile),
+			"frequency":           newTileFactory(NewFrequencyTile),
+			"heatmap":             newTileFactory(NewHeatmapTile),
+			"macro":               newTileFactory(NewMacroTile),
+			"macroEdge":           newTileFactory(NewMacroEdgeTile),
+			"micro":               newTileFactory(NewMicroTile),
+			"targetTermCount":     newTileFactory(NewTargetTermCountTile),
+			"targetTermFrequency": newTileFactory(NewTargetTermFrequencyTile),
+			"topTermCount":        newTileFactory(NewTopTermCountTile),
+			"topTermFrequency":    newTileFactory(NewTopTermFrequencyTile),
+		},
+		Metas: map[string]veldt.MetaFactory{
+			"default": func(conn map[string]interface{}) (veldt.MetaCtor, error) {
+				return NewDefaultMeta(), nil
+			},
+		},
+	})
+}
+
+// newTileFactory returns a factory for the tile constructor. The in-memory
+// backend has no connection parameters.
+func newTileFactory(ctor func() veldt.TileCtor) veldt.TileFactory {
+	return func(conn map[string]interface{}) (veldt.TileCtor, error) {
+		return ctor(), nil
+	}
+}
@@ -0,0 +1,200 @@
+package memory
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+const (
+	// maxBuckets is the maximum number of buckets of a frequency, as empty
+	// buckets are included across the whole range.
+	maxBuckets = 10000
+)
+
+var (
+	units = map[string]float64{
+		"ms":     1,
+		"s":      1000,
+		"m":      1000 * 60,
+		"h":      1000 * 60 * 60,
+		"d":      1000 * 60 * 60 * 24,
+		"w":      1000 * 60 * 60 * 24 * 7,
+		"second": 1000,
+		"minute": 1000 * 60,
+		"hour":   1000 * 60 * 60,
+		"day":    1000 * 60 * 60 * 24,
+		"week":   1000 * 60 * 60 * 24 * 7,
+	}
+	layouts = []string{
+		time.RFC3339Nano,
+		"2006-01-02T15:04:05",
+		"2006-01-02",
+	}
+)
+
+// Frequency represents an in-memory implementation of the frequency tile.
+// Intervals must be of a fixed duration, such as `1d` or `hour`, as calendar
+// intervals like `month` are not supported. Times are either milliseconds
+// since the epoch, or RFC3339 / date strings.
+type Frequency struct {
+	tile.Frequency
+}
+
+// GetQuery returns the appropriate filter for the tile.
+func (f *Frequency) GetQuery() (Filter, error) {
+	gte, gt, lte, lt, err := f.getBounds()
+	if err != nil {
+		return nil, err
+	}
+	return func(doc map[string]interface{}) bool {
+		val, ok := getField(doc, f.FrequencyField)
+		if !ok {
+			return false
+		}
+		t, ok := castTime(val)
+		if !ok {
+			return false
+		}
+		return (gte == nil || t >= *gte) &&
+			(gt == nil || t > *gt) &&
+			(lte == nil || t <= *lte) &&
+			(lt == nil || t < *lt)
+	}, nil
+}
+
+// GetBuckets returns the frequency buckets for the provided hits. Buckets
+// begin at the lower bound of the range and empty buckets are included up to
+// the upper bound of the range. An error is returned if the range spans more
+// than 10000 intervals.
+func (f *Frequency) GetBuckets(hits []map[string]interface{}) ([]map[string]interface{}, error) {
+	interval, err := parseInterval(f.Interval)
+	if err != nil {
+		return nil, err
+	}
+	gte, gt, lte, lt, err := f.getBounds()
+	if err != nil {
+		return nil, err
+	}
+	// buckets are offset by the lower bound
+	var min, max *float64
+	offset := 0.0
+	if gte != nil {
+		min = gte
+		offset = *gte
+	}
+	if gt != nil {
+		min = gt
+		offset = *gt
+	}
+	if lte != nil {
+		max = lte
+	}
+	if lt != nil {
+		max = lt
+	}
+	index := func(t float64) int64 {
+		return int64(math.Floor((t - offset) / interval))
+	}
+	// count hits
+	counts := make(map[int64]int64)
+	for _, hit := range hits {
+		val, ok := getField(hit, f.FrequencyField)
+		if !ok {
+			continue
+		}
+		t, ok := castTime(val)
+		if !ok {
+			continue
+		}
+		counts[index(t)]++
+		if min == nil || t < *min {
+			min = &t
+		}
+		if max == nil || t > *max {
+			max = &t
+		}
+	}
+	if min == nil || max == nil {
+		return []map[string]interface{}{}, nil
+	}
+	// check the number of buckets before filling them
+	first, last := index(*min), index(*max)
+	if float64(last)-float64(first)+1 > maxBuckets {
+		return nil, fmt.Errorf("frequency range of `%s` intervals exceeds the maximum of %d buckets", f.Interval, maxBuckets)
+	}
+	// fill buckets
+	buckets := make([]map[string]interface{}, 0, last-first+1)
+	for i := first; i <= last; i++ {
+		buckets = append(buckets, map[string]interface{}{
+			"timestamp": int64(offset + float64(i)*interval),
+			"count":     counts[i],
+		})
+	}
+	return buckets, nil
+}
+
+func (f *Frequency) getBounds() (*float64, *float64, *float64, *float64, error) {
+	bounds := make([]*float64, 4)
+	for i, val := range []interface{}{f.GTE, f.GT, f.LTE, f.LT} {
+		if val == nil {
+			continue
+		}
+		t, ok := castTime(val)
+		if !ok {
+			return nil, nil, nil, nil, fmt.Errorf("`%v` is not a valid time", val)
+		}
+		bounds[i] = &t
+	}
+	return bounds[0], bounds[1], bounds[2], bounds[3], nil
+}
+
+func parseInterval(interval string) (float64, error) {
+	// named unit
+	unit, ok := units[interval]
+	if ok {
+		return unit, nil
+	}
+	// number followed by a unit
+	index := strings.IndexFunc(interval, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if index > 0 {
+		unit, ok := units[interval[index:]]
+		if ok {
+			num, err := strconv.ParseFloat(interval[:index], 64)
+			if err == nil && num > 0 {
+				return num * unit, nil
+			}
+		}
+	}
+	// plain milliseconds
+	num, err := strconv.ParseFloat(interval, 64)
+	if err == nil && num > 0 {
+		return num, nil
+	}
+	return 0, fmt.Errorf("`%s` is not a supported fixed interval", interval)
+}
+
+func castTime(val interface{}) (float64, bool) {
+	num, ok := toFloat(val)
+	if ok {
+		// assume milliseconds
+		return num, true
+	}
+	str, ok := val.(string)
+	if !ok {
+		return 0, false
+	}
+	for _, layout := range layouts {
+		t, err := time.Parse(layout, str)
+		if err == nil {
+			return float64(t.UnixNano() / int64(time.Millisecond)), true
+		}
+	}
+	return 0, false
+}
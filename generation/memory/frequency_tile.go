@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// FrequencyTile represents an in-memory implementation of the frequency
+// tile.
+type FrequencyTile struct {
+	Memory
+	Bivariate
+	Frequency
+}
+
+// NewFrequencyTile instantiates and returns a new tile struct.
+func NewFrequencyTile() veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		return &FrequencyTile{}, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (t *FrequencyTile) Parse(params map[string]interface{}) error {
+	err := t.Bivariate.Parse(params)
+	if err != nil {
+		return err
+	}
+	return t.Frequency.Parse(params)
+}
+
+// Schema returns the JSON Schema of the parameters of the tile.
+func (t *FrequencyTile) Schema() *schema.Schema {
+	return schema.Merge(t.Bivariate.Schema(), t.Frequency.Schema())
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (t *FrequencyTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return t.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (t *FrequencyTile) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// create root query
+	q, err := t.CreateQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	// create frequency query
+	freq, err := t.Frequency.GetQuery()
+	if err != nil {
+		return nil, err
+	}
+	// search with tiling and frequency query
+	hits, err := t.Search(ctx, uri, q, t.Bivariate.GetQuery(coord), freq)
+	if err != nil {
+		return nil, err
+	}
+	// get buckets
+	buckets, err := t.Frequency.GetBuckets(hits)
+	if err != nil {
+		return nil, err
+	}
+	// marshal results
+	return json.Marshal(buckets)
+}
@@ -0,0 +1,24 @@
+package memory
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Fuzzy represents an in-memory fuzzy query.
+type Fuzzy struct {
+	query.Fuzzy
+}
+
+// NewFuzzy instantiates and returns a new query struct.
+func NewFuzzy() (veldt.Query, error) {
+	return &Fuzzy{}, nil
+}
+
+// Get returns the appropriate filter for the query.
+func (q *Fuzzy) Get() (Filter, error) {
+	fuzziness := q.GetFuzziness()
+	return matchStrings(q.Field, func(str string) bool {
+		return editDistance(str, q.Value) <= fuzziness
+	}), nil
+}
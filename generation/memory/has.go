@@ -0,0 +1,31 @@
+package memory
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Has represents an in-memory terms query. Array fields match if any of their
+// elements equal any of the values.
+type Has struct {
+	query.Has
+}
+
+// NewHas instantiates and returns a new query struct.
+func NewHas() (veldt.Query, error) {
+	return &Has{}, nil
+}
+
+// Get returns the appropriate filter for the query.
+func (q *Has) Get() (Filter, error) {
+	return func(doc map[string]interface{}) bool {
+		for _, val := range getValues(doc, q.Field) {
+			for _, value := range q.Values {
+				if equals(val, value) {
+					return true
+				}
+			}
+		}
+		return false
+	}, nil
+}
@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
+)
+
+// HeatmapTile represents an in-memory implementation of the heatmap tile.
+type HeatmapTile struct {
+	Memory
+	Bivariate
+}
+
+// NewHeatmapTile instantiates and returns a new tile struct.
+func NewHeatmapTile() veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		return &HeatmapTile{}, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (h *HeatmapTile) Parse(params map[string]interface{}) error {
+	return h.Bivariate.Parse(params)
+}
+
+// Schema returns the JSON Schema of the parameters of the tile.
+func (h *HeatmapTile) Schema() *schema.Schema {
+	return h.Bivariate.Schema()
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (h *HeatmapTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return h.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (h *HeatmapTile) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// create root query
+	q, err := h.CreateQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	// search with tiling query
+	hits, err := h.Search(ctx, uri, q, h.Bivariate.GetQuery(coord))
+	if err != nil {
+		return nil, err
+	}
+	// get bins
+	bins := h.Bivariate.GetBins(coord, hits)
+	// convert to byte array
+	bits := make([]byte, len(bins)*4)
+	for i, bin := range bins {
+		if bin != nil {
+			binary.LittleEndian.PutUint32(
+				bits[i*4:i*4+4],
+				uint32(len(bin)))
+		}
+	}
+	return bits, nil
+}
+
+// LevelExtrema returns the extrema of the non-empty bin counts across every
+// tile of the provided zoom level.
+func (h *HeatmapTile) LevelExtrema(uri string, z uint32, query veldt.Query) (*binning.Extrema, error) {
+	// create root query
+	q, err := h.CreateQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	hits, err := h.Search(context.Background(), uri, q)
+	if err != nil {
+		return nil, err
+	}
+	return h.Bivariate.GetLevelExtrema(z, h.Resolution, hits), nil
+}
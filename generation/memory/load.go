@@ -0,0 +1,139 @@
+package memory
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxLineSize = 1024 * 1024 * 16
+)
+
+// LoadFile loads the documents of a CSV or JSON-lines file under the provided
+// URI. The format is determined by the file extension: `.csv` for CSV, and
+// `.json`, `.jsonl` or `.ndjson` for JSON-lines.
+func LoadFile(uri string, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return LoadCSV(uri, file)
+	case ".json", ".jsonl", ".ndjson":
+		return LoadJSONLines(uri, file)
+	}
+	return fmt.Errorf("unrecognized file extension for `%s`", path)
+}
+
+// LoadCSV loads the documents of a CSV stream under the provided URI. The
+// first record is the header. Header names using dot notation produce nested
+// attributes, numeric and boolean values are parsed, and empty values are
+// omitted.
+func LoadCSV(uri string, reader io.Reader) error {
+	r := csv.NewReader(reader)
+	header, err := r.Read()
+	if err != nil {
+		return fmt.Errorf("unable to read CSV header: %v", err)
+	}
+	var docs []map[string]interface{}
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		doc := make(map[string]interface{})
+		for i, str := range record {
+			if str == "" {
+				continue
+			}
+			setField(doc, header[i], parseValue(str))
+		}
+		docs = append(docs, doc)
+	}
+	Load(uri, docs)
+	return nil
+}
+
+// LoadJSONLines loads the documents of a JSON-lines stream, one JSON object per
+// line, under the provided URI.
+func LoadJSONLines(uri string, reader io.Reader) error {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	var docs []map[string]interface{}
+	line := 0
+	for scanner.Scan() {
+		line++
+		bytes := scanner.Bytes()
+		if len(strings.TrimSpace(string(bytes))) == 0 {
+			continue
+		}
+		doc := make(map[string]interface{})
+		err := json.Unmarshal(bytes, &doc)
+		if err != nil {
+			return fmt.Errorf("unable to parse line %d: %v", line, err)
+		}
+		docs = append(docs, doc)
+	}
+	err := scanner.Err()
+	if err != nil {
+		return err
+	}
+	Load(uri, docs)
+	return nil
+}
+
+// LoadColumns loads columnar data under the provided URI. Each column holds
+// the values of one field, and all columns must have the same length. Nil
+// values are omitted.
+func LoadColumns(uri string, columns map[string][]interface{}) error {
+	length := -1
+	for field, values := range columns {
+		if length >= 0 && len(values) != length {
+			return fmt.Errorf("column `%s` has length %d, expected %d",
+				field, len(values), length)
+		}
+		length = len(values)
+	}
+	if length < 0 {
+		length = 0
+	}
+	docs := make([]map[string]interface{}, length)
+	for i := range docs {
+		docs[i] = make(map[string]interface{})
+	}
+	for field, values := range columns {
+		for i, val := range values {
+			if val != nil {
+				setField(docs[i], field, val)
+			}
+		}
+	}
+	Load(uri, docs)
+	return nil
+}
+
+func parseValue(str string) interface{} {
+	num, err := strconv.ParseFloat(str, 64)
+	if err == nil {
+		return num
+	}
+	switch strings.ToLower(str) {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	return str
+}
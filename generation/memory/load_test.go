@@ -0,0 +1,117 @@
+package memory_test
+
+import (
+	"context"
+	"strings"
+
+	"github.com/unchartedsoftware/veldt/generation/memory"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Load", func() {
+
+	var m *memory.Memory
+
+	BeforeEach(func() {
+		m = &memory.Memory{}
+	})
+
+	AfterEach(func() {
+		memory.Unload("test")
+	})
+
+	Describe("LoadCSV", func() {
+		It("should parse numbers, booleans and nested attributes", func() {
+			err := memory.LoadCSV("test", strings.NewReader(
+				"name,pixel.x,pixel.y,active\n"+
+					"a,1,2,true\n"+
+					"b,3,,FALSE\n"))
+			Expect(err).To(BeNil())
+			docs, err := m.Search(context.Background(), "test")
+			Expect(err).To(BeNil())
+			Expect(docs).To(Equal([]map[string]interface{}{
+				{
+					"name":   "a",
+					"pixel":  map[string]interface{}{"x": 1.0, "y": 2.0},
+					"active": true,
+				},
+				{
+					"name":   "b",
+					"pixel":  map[string]interface{}{"x": 3.0},
+					"active": false,
+				},
+			}))
+		})
+
+		It("should return an error if the header is missing", func() {
+			err := memory.LoadCSV("test", strings.NewReader(""))
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("LoadJSONLines", func() {
+		It("should parse one document per line, skipping blank lines", func() {
+			err := memory.LoadJSONLines("test", strings.NewReader(
+				`{"name":"a","tags":["x","y"]}`+"\n\n"+
+					`{"name":"b"}`+"\n"))
+			Expect(err).To(BeNil())
+			docs, err := m.Search(context.Background(), "test")
+			Expect(err).To(BeNil())
+			Expect(docs).To(HaveLen(2))
+			Expect(docs[0]["tags"]).To(Equal([]interface{}{"x", "y"}))
+		})
+
+		It("should return an error identifying the invalid line", func() {
+			err := memory.LoadJSONLines("test", strings.NewReader(
+				`{"name":"a"}`+"\n"+`{"name":`+"\n"))
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("line 2"))
+		})
+	})
+
+	Describe("LoadColumns", func() {
+		It("should transpose the columns into documents", func() {
+			err := memory.LoadColumns("test", map[string][]interface{}{
+				"name":    {"a", "b"},
+				"pixel.x": {1.0, nil},
+			})
+			Expect(err).To(BeNil())
+			docs, err := m.Search(context.Background(), "test")
+			Expect(err).To(BeNil())
+			Expect(docs).To(Equal([]map[string]interface{}{
+				{
+					"name":  "a",
+					"pixel": map[string]interface{}{"x": 1.0},
+				},
+				{
+					"name": "b",
+				},
+			}))
+		})
+
+		It("should return an error if the columns differ in length", func() {
+			err := memory.LoadColumns("test", map[string][]interface{}{
+				"a": {1.0, 2.0},
+				"b": {1.0},
+			})
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("Search", func() {
+		It("should return an error if no dataset is loaded under the URI", func() {
+			_, err := m.Search(context.Background(), "missing")
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should return an error if the context is done", func() {
+			memory.Load("test", []map[string]interface{}{{}})
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			_, err := m.Search(ctx, "test")
+			Expect(err).To(Equal(context.Canceled))
+		})
+	})
+})
@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+// MacroEdgeTile represents an in-memory implementation of the edge tile.
+type MacroEdgeTile struct {
+	Memory
+	TopHits
+	Edge
+	tile.MacroEdge
+}
+
+// NewMacroEdgeTile instantiates and returns a new tile struct.
+func NewMacroEdgeTile() veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		return &MacroEdgeTile{}, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (e *MacroEdgeTile) Parse(params map[string]interface{}) error {
+	err := e.Edge.Parse(params)
+	if err != nil {
+		return err
+	}
+	err = e.TopHits.Parse(params)
+	if err != nil {
+		return err
+	}
+	// parse includes
+	e.TopHits.IncludeFields = e.MacroEdge.ParseIncludes(
+		e.TopHits.IncludeFields,
+		e.Edge.SrcXField,
+		e.Edge.SrcYField,
+		e.Edge.DstXField,
+		e.Edge.DstYField,
+		e.Edge.WeightField)
+	return e.MacroEdge.Parse(params)
+}
+
+// Schema returns the JSON Schema of the parameters of the tile.
+func (e *MacroEdgeTile) Schema() *schema.Schema {
+	return schema.Merge(e.Edge.Schema(), e.TopHits.Schema(), e.MacroEdge.Schema())
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (e *MacroEdgeTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return e.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (e *MacroEdgeTile) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// create root query
+	q, err := e.CreateQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	// search with tiling query
+	hits, err := e.Search(ctx, uri, q, e.Edge.GetQuery(coord))
+	if err != nil {
+		return nil, err
+	}
+
+	// get top hits
+	hits = e.TopHits.GetTopHits(hits)
+
+	// convert to point array
+	points := make([]float32, len(hits)*6)
+	// get hit x/y in tile coords
+	for i, hit := range hits {
+		srcX, srcY, ok := e.Edge.GetSrcXY(coord, hit)
+		if !ok {
+			return nil, fmt.Errorf("could not parse edge source position from hit: %v", hit)
+		}
+		dstX, dstY, ok := e.Edge.GetDstXY(coord, hit)
+		if !ok {
+			return nil, fmt.Errorf("could not parse edge destination position from hit: %v", hit)
+		}
+		weight, ok := e.Edge.GetWeight(hit)
+		if !ok {
+			return nil, fmt.Errorf("could not parse edge weight from hit: %v", hit)
+		}
+		// add to point array
+		points[i*6] = float32(srcX)
+		points[i*6+1] = float32(srcY)
+		points[i*6+2] = float32(weight)
+		points[i*6+3] = float32(dstX)
+		points[i*6+4] = float32(dstY)
+		points[i*6+5] = float32(weight)
+	}
+
+	// encode and return results
+	return e.MacroEdge.Encode(points)
+}
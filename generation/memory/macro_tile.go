@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"context"
+	"math"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+// MacroTile represents an in-memory implementation of the macro tile.
+type MacroTile struct {
+	Memory
+	Bivariate
+	tile.Macro
+}
+
+// NewMacroTile instantiates and returns a new tile struct.
+func NewMacroTile() veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		return &MacroTile{}, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (m *MacroTile) Parse(params map[string]interface{}) error {
+	err := m.Bivariate.Parse(params)
+	if err != nil {
+		return err
+	}
+	return m.Macro.Parse(params)
+}
+
+// Schema returns the JSON Schema of the parameters of the tile.
+func (m *MacroTile) Schema() *schema.Schema {
+	return schema.Merge(m.Bivariate.Schema(), m.Macro.Schema())
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (m *MacroTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return m.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (m *MacroTile) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// create root query
+	q, err := m.CreateQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	// search with tiling query
+	hits, err := m.Search(ctx, uri, q, m.Bivariate.GetQuery(coord))
+	if err != nil {
+		return nil, err
+	}
+	// get bins
+	bins := m.Bivariate.GetBins(coord, hits)
+
+	// bin width
+	binSize := binning.MaxTileResolution / float64(m.Resolution)
+	halfSize := float64(binSize / 2)
+
+	// convert to point array
+	points := make([]float32, len(bins)*2)
+	numPoints := 0
+	for i, bin := range bins {
+		if bin != nil {
+			x := float32(float64(i%m.Resolution)*binSize + halfSize)
+			y := float32(math.Floor(float64(i/m.Resolution))*binSize + halfSize)
+			points[numPoints*2] = x
+			points[numPoints*2+1] = y
+			numPoints++
+		}
+	}
+
+	// encode the result
+	return m.Macro.Encode(points[0 : numPoints*2])
+}
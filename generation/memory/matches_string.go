@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// MatchesString represents an in-memory string query. Unlike a full query
+// string syntax, the match is a case-insensitive substring search, where `*`
+// matches any sequence of characters and `?` matches any single character.
+type MatchesString struct {
+	query.MatchesString
+}
+
+// NewMatchesString instantiates and returns a new struct.
+func NewMatchesString() (veldt.Query, error) {
+	return &MatchesString{}, nil
+}
+
+// Get returns the appropriate filter for the query.
+func (q *MatchesString) Get() (Filter, error) {
+	// escape everything but the wildcards
+	pattern := regexp.QuoteMeta(q.Match)
+	pattern = strings.Replace(pattern, `\*`, ".*", -1)
+	pattern = strings.Replace(pattern, `\?`, ".", -1)
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compile match `%s`: %v", q.Match, err)
+	}
+	return func(doc map[string]interface{}) bool {
+		for _, field := range q.Fields {
+			for _, val := range getValues(doc, field) {
+				str, ok := val.(string)
+				if ok && re.MatchString(str) {
+					return true
+				}
+			}
+		}
+		return false
+	}, nil
+}
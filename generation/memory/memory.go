@@ -0,0 +1,259 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/unchartedsoftware/veldt"
+)
+
+const (
+	checkInterval = 4096
+)
+
+var (
+	mutex    = sync.RWMutex{}
+	datasets = make(map[string][]map[string]interface{})
+)
+
+// Load stores the provided documents in memory under the provided URI,
+// replacing any documents previously loaded under it. Nested attributes are
+// referenced by tiles and queries using dot notation, ex. `pixel.x`.
+func Load(uri string, docs []map[string]interface{}) {
+	mutex.Lock()
+	datasets[uri] = docs
+	mutex.Unlock()
+	runtime.Gosched()
+}
+
+// Unload removes the documents stored under the provided URI.
+func Unload(uri string) {
+	mutex.Lock()
+	delete(datasets, uri)
+	mutex.Unlock()
+	runtime.Gosched()
+}
+
+func getDocs(uri string) ([]map[string]interface{}, error) {
+	mutex.RLock()
+	docs, ok := datasets[uri]
+	mutex.RUnlock()
+	runtime.Gosched()
+	if !ok {
+		return nil, fmt.Errorf("no dataset has been loaded under `%s`", uri)
+	}
+	return docs, nil
+}
+
+// Memory represents an in-memory backend type.
+type Memory struct {
+}
+
+// CreateQuery creates the filter from the query struct.
+func (m *Memory) CreateQuery(query veldt.Query) (Filter, error) {
+	if query == nil {
+		return matchAll, nil
+	}
+	// type assert
+	memquery, ok := query.(Query)
+	if !ok {
+		return nil, fmt.Errorf("query is not memory.Query")
+	}
+	// get underlying filter
+	return memquery.Get()
+}
+
+// Search returns all documents loaded under the provided URI that pass every
+// provided filter, abandoning the scan if the context is done.
+func (m *Memory) Search(ctx context.Context, uri string, filters ...Filter) ([]map[string]interface{}, error) {
+	docs, err := getDocs(uri)
+	if err != nil {
+		return nil, err
+	}
+	var hits []map[string]interface{}
+	for i, doc := range docs {
+		// periodically check for cancellation
+		if i%checkInterval == 0 && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if all(doc, filters) {
+			hits = append(hits, doc)
+		}
+	}
+	return hits, nil
+}
+
+func all(doc map[string]interface{}, filters []Filter) bool {
+	for _, filter := range filters {
+		if !filter(doc) {
+			return false
+		}
+	}
+	return true
+}
+
+// getField returns the value of the field, supporting dot notation for nested
+// attributes.
+func getField(doc map[string]interface{}, field string) (interface{}, bool) {
+	path := strings.Split(field, ".")
+	last := len(path) - 1
+	child := doc
+	for index, key := range path {
+		v, ok := child[key]
+		if !ok {
+			return nil, false
+		}
+		if index == last {
+			return v, v != nil
+		}
+		c, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		child = c
+	}
+	return nil, false
+}
+
+// setField sets the value of the field, creating any missing nested
+// attributes along the way.
+func setField(doc map[string]interface{}, field string, val interface{}) {
+	path := strings.Split(field, ".")
+	last := len(path) - 1
+	child := doc
+	for index, key := range path {
+		if index == last {
+			child[key] = val
+			return
+		}
+		c, ok := child[key].(map[string]interface{})
+		if !ok {
+			c = make(map[string]interface{})
+			child[key] = c
+		}
+		child = c
+	}
+}
+
+// getValues returns the values of the field, flattening array fields into
+// their individual elements.
+func getValues(doc map[string]interface{}, field string) []interface{} {
+	val, ok := getField(doc, field)
+	if !ok {
+		return nil
+	}
+	arr, ok := val.([]interface{})
+	if ok {
+		return arr
+	}
+	return []interface{}{val}
+}
+
+// getFloat returns the numeric value of the field.
+func getFloat(doc map[string]interface{}, field string) (float64, bool) {
+	val, ok := getField(doc, field)
+	if !ok {
+		return 0, false
+	}
+	return toFloat(val)
+}
+
+func toFloat(val interface{}) (float64, bool) {
+	switch v := val.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// compare returns -1, 0 or 1 if a is less than, equal to or greater than b.
+// Numbers are compared numerically, strings and booleans lexicographically.
+// The second return value is false if the values are not comparable.
+func compare(a interface{}, b interface{}) (int, bool) {
+	fa, aOk := toFloat(a)
+	fb, bOk := toFloat(b)
+	if aOk && bOk {
+		switch {
+		case fa < fb:
+			return -1, true
+		case fa > fb:
+			return 1, true
+		}
+		return 0, true
+	}
+	sa, aOk := a.(string)
+	sb, bOk := b.(string)
+	if aOk && bOk {
+		return strings.Compare(sa, sb), true
+	}
+	ba, aOk := a.(bool)
+	bb, bOk := b.(bool)
+	if aOk && bOk {
+		switch {
+		case ba == bb:
+			return 0, true
+		case bb:
+			return -1, true
+		}
+		return 1, true
+	}
+	return 0, false
+}
+
+func equals(a interface{}, b interface{}) bool {
+	c, ok := compare(a, b)
+	return ok && c == 0
+}
+
+// copyFields returns a deep copy of the document restricted to the provided
+// fields. If no fields are provided, the entire document is copied.
+func copyFields(doc map[string]interface{}, fields []string) map[string]interface{} {
+	if fields == nil {
+		return copyMap(doc)
+	}
+	res := make(map[string]interface{})
+	for _, field := range fields {
+		val, ok := getField(doc, field)
+		if ok {
+			setField(res, field, copyValue(val))
+		}
+	}
+	return res
+}
+
+func copyMap(doc map[string]interface{}) map[string]interface{} {
+	res := make(map[string]interface{}, len(doc))
+	for key, val := range doc {
+		res[key] = copyValue(val)
+	}
+	return res
+}
+
+func copyValue(val interface{}) interface{} {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		return copyMap(v)
+	case []interface{}:
+		arr := make([]interface{}, len(v))
+		for i, elem := range v {
+			arr[i] = copyValue(elem)
+		}
+		return arr
+	}
+	return val
+}
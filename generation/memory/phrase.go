@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Phrase represents an in-memory phrase query. Words are runs of letters and
+// digits, and a slop is not supported.
+type Phrase struct {
+	query.Phrase
+}
+
+// NewPhrase instantiates and returns a new query struct.
+func NewPhrase() (veldt.Query, error) {
+	return &Phrase{}, nil
+}
+
+// Get returns the appropriate filter for the query.
+func (q *Phrase) Get() (Filter, error) {
+	if q.Slop != 0 {
+		return nil, fmt.Errorf("`slop` parameter is not supported by the memory backend")
+	}
+	phrase := tokenize(q.Phrase.Phrase)
+	return matchStrings(q.Field, func(str string) bool {
+		words := tokenize(str)
+		for i := 0; i+len(phrase) <= len(words); i++ {
+			match := true
+			for j, word := range phrase {
+				if words[i+j] != word {
+					match = false
+					break
+				}
+			}
+			if match {
+				return true
+			}
+		}
+		return false
+	}), nil
+}
@@ -0,0 +1,25 @@
+package memory
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Polygon represents an in-memory polygon query.
+type Polygon struct {
+	query.Polygon
+}
+
+// NewPolygon instantiates and returns a new query struct.
+func NewPolygon() (veldt.Query, error) {
+	return &Polygon{}, nil
+}
+
+// Get returns the appropriate filter for the query.
+func (q *Polygon) Get() (Filter, error) {
+	bounds := q.Polygon.Polygon.Bounds()
+	return func(doc map[string]interface{}) bool {
+		x, y, ok := getPoint(doc, &q.Spatial)
+		return ok && contains(bounds, x, y) && q.Polygon.Polygon.Contains(x, y)
+	}, nil
+}
@@ -0,0 +1,31 @@
+package memory
+
+import (
+	"strings"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Prefix represents an in-memory prefix query.
+type Prefix struct {
+	query.Prefix
+}
+
+// NewPrefix instantiates and returns a new query struct.
+func NewPrefix() (veldt.Query, error) {
+	return &Prefix{}, nil
+}
+
+// Get returns the appropriate filter for the query.
+func (q *Prefix) Get() (Filter, error) {
+	if q.CaseInsensitive {
+		prefix := strings.ToLower(q.Prefix.Prefix)
+		return matchStrings(q.Field, func(str string) bool {
+			return strings.HasPrefix(strings.ToLower(str), prefix)
+		}), nil
+	}
+	return matchStrings(q.Field, func(str string) bool {
+		return strings.HasPrefix(str, q.Prefix.Prefix)
+	}), nil
+}
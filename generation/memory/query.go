@@ -0,0 +1,13 @@
+package memory
+
+// Filter represents a predicate over a single document.
+type Filter func(map[string]interface{}) bool
+
+// Query represents an in-memory implementation of the veldt.Query interface.
+type Query interface {
+	Get() (Filter, error)
+}
+
+func matchAll(doc map[string]interface{}) bool {
+	return true
+}
@@ -0,0 +1,153 @@
+package memory_test
+
+import (
+	"context"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/generation/memory"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+func newQuery(ctor veldt.QueryCtor, params string) veldt.Query {
+	q, err := ctor()
+	Expect(err).To(BeNil())
+	err = q.Parse(JSON(params))
+	Expect(err).To(BeNil())
+	return q
+}
+
+var _ = Describe("Query", func() {
+
+	var m *memory.Memory
+
+	search := func(query veldt.Query) []string {
+		filter, err := m.CreateQuery(query)
+		Expect(err).To(BeNil())
+		docs, err := m.Search(context.Background(), "test", filter)
+		Expect(err).To(BeNil())
+		names := make([]string, len(docs))
+		for i, doc := range docs {
+			names[i] = doc["name"].(string)
+		}
+		return names
+	}
+
+	BeforeEach(func() {
+		m = &memory.Memory{}
+		memory.Load("test", []map[string]interface{}{
+			{"name": "Alpha", "age": 10.0, "tags": []interface{}{"a", "b"}, "x": 2.0, "y": 2.0, "location": []interface{}{-75.70, 45.42}},
+			{"name": "Beta", "age": 20.0, "tags": []interface{}{"b"}, "x": 5.0, "y": 5.0, "location": map[string]interface{}{"lon": -75.69, "lat": 45.42}},
+			{"name": "Gamma", "age": 30.0, "x": 12.0, "y": 5.0, "location": []interface{}{-73.57, 45.50}},
+		})
+	})
+
+	AfterEach(func() {
+		memory.Unload("test")
+	})
+
+	It("should match all documents when no query is provided", func() {
+		Expect(search(nil)).To(Equal([]string{"Alpha", "Beta", "Gamma"}))
+	})
+
+	It("should match documents equal to a value, including array elements", func() {
+		q := newQuery(memory.NewEquals, `{"field": "tags", "value": "a"}`)
+		Expect(search(q)).To(Equal([]string{"Alpha"}))
+	})
+
+	It("should match documents containing any of the values", func() {
+		q := newQuery(memory.NewHas, `{"field": "age", "values": [10, 30]}`)
+		Expect(search(q)).To(Equal([]string{"Alpha", "Gamma"}))
+	})
+
+	It("should match documents where the field exists", func() {
+		q := newQuery(memory.NewExists, `{"field": "tags"}`)
+		Expect(search(q)).To(Equal([]string{"Alpha", "Beta"}))
+	})
+
+	It("should match documents within the range", func() {
+		q := newQuery(memory.NewRange, `{"field": "age", "gt": 10, "lte": 30}`)
+		Expect(search(q)).To(Equal([]string{"Beta", "Gamma"}))
+	})
+
+	It("should match strings case-insensitively with wildcards", func() {
+		q := newQuery(memory.NewMatchesString, `{"fields": ["name"], "match": "?E*a"}`)
+		Expect(search(q)).To(Equal([]string{"Beta"}))
+	})
+
+	It("should combine queries with binary and unary expressions", func() {
+		has := newQuery(memory.NewExists, `{"field": "tags"}`)
+		rng := newQuery(memory.NewRange, `{"field": "age", "gte": 20}`)
+		not, _ := memory.NewUnaryExpression()
+		not.(*memory.UnaryExpression).Query = has
+		not.(*memory.UnaryExpression).Op = veldt.Not
+		or, _ := memory.NewBinaryExpression()
+		or.(*memory.BinaryExpression).Left = not
+		or.(*memory.BinaryExpression).Op = veldt.Or
+		or.(*memory.BinaryExpression).Right = newQuery(memory.NewEquals, `{"field": "name", "value": "Alpha"}`)
+		and, _ := memory.NewBinaryExpression()
+		and.(*memory.BinaryExpression).Left = or
+		and.(*memory.BinaryExpression).Op = veldt.And
+		and.(*memory.BinaryExpression).Right = rng
+		Expect(search(and)).To(Equal([]string{"Gamma"}))
+	})
+	It("should match documents within the bounding box", func() {
+		q := newQuery(memory.NewBoundingBox, `{"xField": "x", "yField": "y", "left": 0, "right": 10, "bottom": 0, "top": 5}`)
+		Expect(search(q)).To(Equal([]string{"Alpha", "Beta"}))
+	})
+
+	It("should match documents within the polygon and outside its holes", func() {
+		q := newQuery(memory.NewPolygon, `{
+			"xField": "x",
+			"yField": "y",
+			"polygon": {
+				"type": "Polygon",
+				"coordinates": [
+					[[0, 0], [20, 0], [20, 10], [0, 10], [0, 0]],
+					[[4, 4], [6, 4], [6, 6], [4, 6], [4, 4]]
+				]
+			}
+		}`)
+		Expect(search(q)).To(Equal([]string{"Alpha", "Gamma"}))
+	})
+
+	It("should match documents within the planar distance of the center", func() {
+		q := newQuery(memory.NewRadius, `{"xField": "x", "yField": "y", "center": [0, 0], "distance": 3}`)
+		Expect(search(q)).To(Equal([]string{"Alpha"}))
+	})
+
+	It("should match geo points within the distance of the center in meters", func() {
+		q := newQuery(memory.NewRadius, `{"field": "location", "center": [-75.70, 45.42], "distance": 1000}`)
+		Expect(search(q)).To(Equal([]string{"Alpha", "Beta"}))
+	})
+	It("should match documents starting with the prefix", func() {
+		q := newQuery(memory.NewPrefix, `{"field": "name", "prefix": "al", "caseInsensitive": true}`)
+		Expect(search(q)).To(Equal([]string{"Alpha"}))
+	})
+
+	It("should match documents matching the wildcard pattern", func() {
+		q := newQuery(memory.NewWildcard, `{"field": "name", "pattern": "?e*a"}`)
+		Expect(search(q)).To(Equal([]string{"Beta"}))
+	})
+
+	It("should match documents matching the whole regular expression", func() {
+		q := newQuery(memory.NewRegexp, `{"field": "name", "pattern": "[A-Z][a-z]+(ta|ma)"}`)
+		Expect(search(q)).To(Equal([]string{"Beta", "Gamma"}))
+	})
+
+	It("should match documents within the edits of the value", func() {
+		q := newQuery(memory.NewFuzzy, `{"field": "name", "value": "Gama", "fuzziness": 1}`)
+		Expect(search(q)).To(Equal([]string{"Gamma"}))
+	})
+
+	It("should match documents containing the phrase", func() {
+		memory.Load("test", []map[string]interface{}{
+			{"name": "Alpha", "text": "The quick brown fox."},
+			{"name": "Beta", "text": "The brown, quick fox."},
+		})
+		q := newQuery(memory.NewPhrase, `{"field": "text", "phrase": "Quick Brown"}`)
+		Expect(search(q)).To(Equal([]string{"Alpha"}))
+	})
+})
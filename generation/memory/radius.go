@@ -0,0 +1,34 @@
+package memory
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/geometry"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Radius represents an in-memory radius query.
+type Radius struct {
+	query.Radius
+}
+
+// NewRadius instantiates and returns a new query struct.
+func NewRadius() (veldt.Query, error) {
+	return &Radius{}, nil
+}
+
+// Get returns the appropriate filter for the query.
+func (q *Radius) Get() (Filter, error) {
+	return func(doc map[string]interface{}) bool {
+		x, y, ok := getPoint(doc, &q.Spatial)
+		if !ok {
+			return false
+		}
+		var distance float64
+		if q.Geographic {
+			distance = geometry.GreatCircleDistance(q.Center.X, q.Center.Y, x, y)
+		} else {
+			distance = geometry.Distance(q.Center.X, q.Center.Y, x, y)
+		}
+		return distance <= q.Distance
+	}, nil
+}
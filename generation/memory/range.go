@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Range represents an in-memory range query. Array fields match if any of
+// their elements are within the range.
+type Range struct {
+	query.Range
+}
+
+// NewRange instantiates and returns a new query struct.
+func NewRange() (veldt.Query, error) {
+	return &Range{}, nil
+}
+
+// Get returns the appropriate filter for the query.
+func (q *Range) Get() (Filter, error) {
+	return func(doc map[string]interface{}) bool {
+		for _, val := range getValues(doc, q.Field) {
+			if q.contains(val) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+func (q *Range) contains(val interface{}) bool {
+	if q.GTE != nil {
+		c, ok := compare(val, q.GTE)
+		if !ok || c < 0 {
+			return false
+		}
+	}
+	if q.GT != nil {
+		c, ok := compare(val, q.GT)
+		if !ok || c <= 0 {
+			return false
+		}
+	}
+	if q.LTE != nil {
+		c, ok := compare(val, q.LTE)
+		if !ok || c > 0 {
+			return false
+		}
+	}
+	if q.LT != nil {
+		c, ok := compare(val, q.LT)
+		if !ok || c >= 0 {
+			return false
+		}
+	}
+	return true
+}
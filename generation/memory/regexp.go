@@ -0,0 +1,33 @@
+package memory
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Regexp represents an in-memory regular expression query, using the syntax
+// of the Go regexp package.
+type Regexp struct {
+	query.Regexp
+}
+
+// NewRegexp instantiates and returns a new query struct.
+func NewRegexp() (veldt.Query, error) {
+	return &Regexp{}, nil
+}
+
+// Get returns the appropriate filter for the query.
+func (q *Regexp) Get() (Filter, error) {
+	flags := ""
+	if q.CaseInsensitive {
+		flags = "(?i)"
+	}
+	re, err := regexp.Compile(flags + "^(?:" + q.Pattern + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("unable to compile pattern `%s`: %v", q.Pattern, err)
+	}
+	return matchStrings(q.Field, re.MatchString), nil
+}
@@ -0,0 +1,40 @@
+package memory
+
+import (
+	"github.com/unchartedsoftware/veldt/geometry"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// getPoint returns the x and y, or longitude and latitude, of the location of
+// the document. A geo point field holds either an array of longitude and
+// latitude, as in GeoJSON, or an object with `lon` and `lat` attributes.
+func getPoint(doc map[string]interface{}, s *query.Spatial) (float64, float64, bool) {
+	if s.IsPair() {
+		x, xOk := getFloat(doc, s.XField)
+		y, yOk := getFloat(doc, s.YField)
+		return x, y, xOk && yOk
+	}
+	val, ok := getField(doc, s.Field)
+	if !ok {
+		return 0, 0, false
+	}
+	switch v := val.(type) {
+	case []interface{}:
+		if len(v) == 2 {
+			lon, lonOk := toFloat(v[0])
+			lat, latOk := toFloat(v[1])
+			return lon, lat, lonOk && latOk
+		}
+	case map[string]interface{}:
+		lon, lonOk := toFloat(v["lon"])
+		lat, latOk := toFloat(v["lat"])
+		return lon, lat, lonOk && latOk
+	}
+	return 0, 0, false
+}
+
+// contains returns whether the point is within the bounds, inclusive.
+func contains(bounds *geometry.Bounds, x, y float64) bool {
+	return x >= bounds.MinX() && x <= bounds.MaxX() &&
+		y >= bounds.MinY() && y <= bounds.MaxY()
+}
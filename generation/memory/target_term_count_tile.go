@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// TargetTermCountTile represents an in-memory implementation of the
+// target term count tile.
+type TargetTermCountTile struct {
+	Memory
+	Bivariate
+	TargetTerms
+}
+
+// NewTargetTermCountTile instantiates and returns a new tile struct.
+func NewTargetTermCountTile() veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		return &TargetTermCountTile{}, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (t *TargetTermCountTile) Parse(params map[string]interface{}) error {
+	err := t.Bivariate.Parse(params)
+	if err != nil {
+		return err
+	}
+	return t.TargetTerms.Parse(params)
+}
+
+// Schema returns the JSON Schema of the parameters of the tile.
+func (t *TargetTermCountTile) Schema() *schema.Schema {
+	return schema.Merge(t.Bivariate.Schema(), t.TargetTerms.Schema())
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (t *TargetTermCountTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return t.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (t *TargetTermCountTile) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// create root query
+	q, err := t.CreateQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	// search with tiling query
+	hits, err := t.Search(ctx, uri, q, t.Bivariate.GetQuery(coord))
+	if err != nil {
+		return nil, err
+	}
+	// get terms
+	terms := t.TargetTerms.GetTerms(hits)
+	// encode
+	counts := make(map[string]uint32)
+	for term, termHits := range terms {
+		counts[term] = uint32(len(termHits))
+	}
+	// marshal results
+	return json.Marshal(counts)
+}
@@ -0,0 +1,32 @@
+package memory
+
+import (
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+// TargetTerms represents an in-memory implementation of the target terms
+// tile.
+type TargetTerms struct {
+	tile.TargetTerms
+}
+
+// GetTerms groups the provided hits by target term. Every target term is
+// present in the result, even if no hits contain it.
+func (t *TargetTerms) GetTerms(hits []map[string]interface{}) map[string][]map[string]interface{} {
+	res := make(map[string][]map[string]interface{}, len(t.Terms))
+	for _, term := range t.Terms {
+		res[term] = nil
+	}
+	for _, hit := range hits {
+		seen := make(map[string]bool)
+		for _, val := range getValues(hit, t.TermsField) {
+			term := toTerm(val)
+			_, ok := res[term]
+			if ok && !seen[term] {
+				res[term] = append(res[term], hit)
+				seen[term] = true
+			}
+		}
+	}
+	return res
+}
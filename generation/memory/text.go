@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"strings"
+	"unicode"
+)
+
+// matchStrings returns a filter matching documents where any string value of
+// the field satisfies the predicate.
+func matchStrings(field string, match func(string) bool) Filter {
+	return func(doc map[string]interface{}) bool {
+		for _, val := range getValues(doc, field) {
+			str, ok := val.(string)
+			if ok && match(str) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// tokenize splits the text into lowercase words of letters and digits.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// editDistance returns the number of single character insertions, deletions
+// or substitutions between the strings.
+func editDistance(a, b string) int {
+	ra := []rune(a)
+	rb := []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = prev[j-1] + cost
+			if prev[j]+1 < curr[j] {
+				curr[j] = prev[j] + 1
+			}
+			if curr[j-1]+1 < curr[j] {
+				curr[j] = curr[j-1] + 1
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,182 @@
+package memory_test
+
+import (
+	"encoding/binary"
+	"encoding/json"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/generation/memory"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+const (
+	bivariate = `
+		"xField": "x",
+		"yField": "y",
+		"left": 0,
+		"right": 256,
+		"bottom": 0,
+		"top": 256,
+		"resolution": 4`
+)
+
+func createTile(ctor veldt.TileCtor, params string) []byte {
+	t, err := ctor()
+	Expect(err).To(BeNil())
+	err = t.Parse(JSON(params))
+	Expect(err).To(BeNil())
+	data, err := t.Create("test", &binning.TileCoord{Z: 0, X: 0, Y: 0}, nil)
+	Expect(err).To(BeNil())
+	return data
+}
+
+func unmarshal(data []byte) interface{} {
+	var res interface{}
+	err := json.Unmarshal(data, &res)
+	Expect(err).To(BeNil())
+	return res
+}
+
+var _ = Describe("Tile", func() {
+
+	BeforeEach(func() {
+		memory.Load("test", []map[string]interface{}{
+			{"x": 10.0, "y": 10.0, "term": "a", "time": 0.0, "rank": 1.0},
+			{"x": 20.0, "y": 20.0, "term": "a", "time": 1000.0, "rank": 3.0},
+			{"x": 100.0, "y": 10.0, "term": "b", "time": 2500.0, "rank": 2.0},
+			{"x": 300.0, "y": 10.0, "term": "c", "time": 0.0, "rank": 4.0},
+		})
+	})
+
+	AfterEach(func() {
+		memory.Unload("test")
+	})
+
+	Describe("HeatmapTile", func() {
+		It("should count the documents of each bin", func() {
+			data := createTile(memory.NewHeatmapTile(), `{`+bivariate+`}`)
+			Expect(data).To(HaveLen(16 * 4))
+			Expect(binary.LittleEndian.Uint32(data[0:4])).To(Equal(uint32(2)))
+			Expect(binary.LittleEndian.Uint32(data[4:8])).To(Equal(uint32(1)))
+		})
+
+		It("should compute the bin extrema across the zoom level", func() {
+			t, err := memory.NewHeatmapTile()()
+			Expect(err).To(BeNil())
+			err = t.Parse(JSON(`{` + bivariate + `}`))
+			Expect(err).To(BeNil())
+			extrema, err := t.(veldt.ExtremaTile).LevelExtrema("test", 0, nil)
+			Expect(err).To(BeNil())
+			Expect(extrema).To(Equal(&binning.Extrema{Min: 1, Max: 2}))
+		})
+	})
+
+	Describe("Count", func() {
+		It("should count the documents within the tile", func() {
+			data := createTile(memory.NewCountTile(), `{`+bivariate+`}`)
+			Expect(string(data)).To(Equal(`{"count":3}`))
+		})
+	})
+
+	Describe("FrequencyTile", func() {
+		It("should bucket the documents, including empty buckets", func() {
+			data := createTile(memory.NewFrequencyTile(), `{`+bivariate+`,
+				"frequencyField": "time",
+				"gte": 0,
+				"lt": 4000,
+				"interval": "1s"
+			}`)
+			Expect(unmarshal(data)).To(Equal([]interface{}{
+				map[string]interface{}{"timestamp": 0.0, "count": 1.0},
+				map[string]interface{}{"timestamp": 1000.0, "count": 1.0},
+				map[string]interface{}{"timestamp": 2000.0, "count": 1.0},
+				map[string]interface{}{"timestamp": 3000.0, "count": 0.0},
+				map[string]interface{}{"timestamp": 4000.0, "count": 0.0},
+			}))
+		})
+
+		It("should return an error if the range spans too many intervals", func() {
+			t, err := memory.NewFrequencyTile()()
+			Expect(err).To(BeNil())
+			err = t.Parse(JSON(`{` + bivariate + `,
+				"frequencyField": "time",
+				"gte": 0,
+				"lt": 1e15,
+				"interval": "1ms"
+			}`))
+			Expect(err).To(BeNil())
+			_, err = t.Create("test", &binning.TileCoord{Z: 0, X: 0, Y: 0}, nil)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("TopTermCountTile", func() {
+		It("should count the most frequent terms", func() {
+			data := createTile(memory.NewTopTermCountTile(), `{`+bivariate+`,
+				"termsField": "term",
+				"termsCount": 1
+			}`)
+			Expect(unmarshal(data)).To(Equal(map[string]interface{}{
+				"a": 2.0,
+			}))
+		})
+	})
+
+	Describe("TargetTermCountTile", func() {
+		It("should count every target term", func() {
+			data := createTile(memory.NewTargetTermCountTile(), `{`+bivariate+`,
+				"termsField": "term",
+				"terms": ["b", "c"]
+			}`)
+			Expect(unmarshal(data)).To(Equal(map[string]interface{}{
+				"b": 1.0,
+				"c": 0.0,
+			}))
+		})
+	})
+
+	Describe("BinnedTopHits", func() {
+		It("should return the sorted top hits of each bin", func() {
+			data := createTile(memory.NewBinnedTopHits(), `{`+bivariate+`,
+				"sortField": "rank",
+				"sortOrder": "desc",
+				"hitsCount": 1,
+				"includeFields": ["rank"]
+			}`)
+			res := unmarshal(data).(map[string]interface{})
+			Expect(res["points"]).To(Equal([]interface{}{32.0, 32.0, 96.0, 32.0}))
+			hits := res["hits"].([]interface{})
+			Expect(hits[0]).To(Equal([]interface{}{
+				map[string]interface{}{"rank": 3.0},
+			}))
+			Expect(hits[1]).To(Equal([]interface{}{
+				map[string]interface{}{"rank": 2.0},
+			}))
+			Expect(hits[2]).To(BeNil())
+		})
+	})
+
+	Describe("DefaultMeta", func() {
+		It("should infer the type and extrema of each property", func() {
+			m, err := memory.NewDefaultMeta()()
+			Expect(err).To(BeNil())
+			data, err := m.Create("test")
+			Expect(err).To(BeNil())
+			res := unmarshal(data).(map[string]interface{})
+			Expect(res["x"]).To(Equal(map[string]interface{}{
+				"type": "long",
+				"extrema": map[string]interface{}{
+					"min": 10.0,
+					"max": 300.0,
+				},
+			}))
+			Expect(res["term"]).To(Equal(map[string]interface{}{
+				"type": "string",
+			}))
+		})
+	})
+})
@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"sort"
+
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+// TopHits represents an in-memory implementation of the top hits tile.
+type TopHits struct {
+	tile.TopHits
+}
+
+// GetTopHits sorts the provided hits and returns copies of the top hits,
+// restricted to the included fields. Hits missing the sort field are sorted
+// last.
+func (t *TopHits) GetTopHits(hits []map[string]interface{}) []map[string]interface{} {
+	sorted := make([]map[string]interface{}, len(hits))
+	copy(sorted, hits)
+	// sort
+	if t.SortField != "" {
+		sort.Stable(&hitsArray{
+			hits:  sorted,
+			field: t.SortField,
+			desc:  t.SortOrder == "desc",
+		})
+	}
+	// limit
+	if len(sorted) > t.HitsCount {
+		sorted = sorted[:t.HitsCount]
+	}
+	// add includes
+	res := make([]map[string]interface{}, len(sorted))
+	for i, hit := range sorted {
+		res[i] = copyFields(hit, t.IncludeFields)
+	}
+	return res
+}
+
+type hitsArray struct {
+	hits  []map[string]interface{}
+	field string
+	desc  bool
+}
+
+func (h *hitsArray) Len() int {
+	return len(h.hits)
+}
+func (h *hitsArray) Swap(i, j int) {
+	h.hits[i], h.hits[j] = h.hits[j], h.hits[i]
+}
+func (h *hitsArray) Less(i, j int) bool {
+	a, aOk := getField(h.hits[i], h.field)
+	b, bOk := getField(h.hits[j], h.field)
+	if !aOk || !bOk {
+		return aOk && !bOk
+	}
+	c, _ := compare(a, b)
+	if h.desc {
+		return c > 0
+	}
+	return c < 0
+}
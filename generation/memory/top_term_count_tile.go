@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// TopTermCountTile represents an in-memory implementation of the
+// top term count tile.
+type TopTermCountTile struct {
+	Memory
+	Bivariate
+	TopTerms
+}
+
+// NewTopTermCountTile instantiates and returns a new tile struct.
+func NewTopTermCountTile() veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		return &TopTermCountTile{}, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (t *TopTermCountTile) Parse(params map[string]interface{}) error {
+	err := t.Bivariate.Parse(params)
+	if err != nil {
+		return err
+	}
+	return t.TopTerms.Parse(params)
+}
+
+// Schema returns the JSON Schema of the parameters of the tile.
+func (t *TopTermCountTile) Schema() *schema.Schema {
+	return schema.Merge(t.Bivariate.Schema(), t.TopTerms.Schema())
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (t *TopTermCountTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return t.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (t *TopTermCountTile) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// create root query
+	q, err := t.CreateQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	// search with tiling query
+	hits, err := t.Search(ctx, uri, q, t.Bivariate.GetQuery(coord))
+	if err != nil {
+		return nil, err
+	}
+	// get terms
+	terms := t.TopTerms.GetTerms(hits)
+	// encode
+	counts := make(map[string]uint32)
+	for term, termHits := range terms {
+		counts[term] = uint32(len(termHits))
+	}
+	// marshal results
+	return json.Marshal(counts)
+}
@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// TopTermFrequencyTile represents an in-memory implementation of the
+// top term frequency tile.
+type TopTermFrequencyTile struct {
+	Memory
+	Bivariate
+	TopTerms
+	Frequency
+}
+
+// NewTopTermFrequencyTile instantiates and returns a new tile struct.
+func NewTopTermFrequencyTile() veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		return &TopTermFrequencyTile{}, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (t *TopTermFrequencyTile) Parse(params map[string]interface{}) error {
+	err := t.Bivariate.Parse(params)
+	if err != nil {
+		return err
+	}
+	err = t.TopTerms.Parse(params)
+	if err != nil {
+		return err
+	}
+	return t.Frequency.Parse(params)
+}
+
+// Schema returns the JSON Schema of the parameters of the tile.
+func (t *TopTermFrequencyTile) Schema() *schema.Schema {
+	return schema.Merge(t.Bivariate.Schema(), t.TopTerms.Schema(), t.Frequency.Schema())
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (t *TopTermFrequencyTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return t.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (t *TopTermFrequencyTile) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// create root query
+	q, err := t.CreateQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	// create frequency query
+	freq, err := t.Frequency.GetQuery()
+	if err != nil {
+		return nil, err
+	}
+	// search with tiling and frequency query
+	hits, err := t.Search(ctx, uri, q, t.Bivariate.GetQuery(coord), freq)
+	if err != nil {
+		return nil, err
+	}
+	// get terms
+	terms := t.TopTerms.GetTerms(hits)
+	// encode
+	result := make(map[string][]map[string]interface{})
+	for term, termHits := range terms {
+		// get buckets
+		buckets, err := t.Frequency.GetBuckets(termHits)
+		if err != nil {
+			return nil, err
+		}
+		result[term] = buckets
+	}
+	// marshal results
+	return json.Marshal(result)
+}
@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+// TopTerms represents an in-memory implementation of the top terms tile.
+type TopTerms struct {
+	tile.TopTerms
+}
+
+// GetTerms groups the provided hits by term and returns the hits of the most
+// frequent terms. Ties are broken by term order. Hits with array fields are
+// counted once for each distinct term.
+func (t *TopTerms) GetTerms(hits []map[string]interface{}) map[string][]map[string]interface{} {
+	// group hits by term
+	groups := make(map[string][]map[string]interface{})
+	for _, hit := range hits {
+		seen := make(map[string]bool)
+		for _, val := range getValues(hit, t.TermsField) {
+			term := toTerm(val)
+			if !seen[term] {
+				groups[term] = append(groups[term], hit)
+				seen[term] = true
+			}
+		}
+	}
+	// sort terms by count
+	terms := make([]string, 0, len(groups))
+	for term := range groups {
+		terms = append(terms, term)
+	}
+	sort.Sort(&termsArray{
+		terms:  terms,
+		groups: groups,
+	})
+	// limit
+	if len(terms) > t.TermsCount {
+		terms = terms[:t.TermsCount]
+	}
+	res := make(map[string][]map[string]interface{}, len(terms))
+	for _, term := range terms {
+		res[term] = groups[term]
+	}
+	return res
+}
+
+func toTerm(val interface{}) string {
+	str, ok := val.(string)
+	if ok {
+		return str
+	}
+	return fmt.Sprintf("%v", val)
+}
+
+type termsArray struct {
+	terms  []string
+	groups map[string][]map[string]interface{}
+}
+
+func (t *termsArray) Len() int {
+	return len(t.terms)
+}
+func (t *termsArray) Swap(i, j int) {
+	t.terms[i], t.terms[j] = t.terms[j], t.terms[i]
+}
+func (t *termsArray) Less(i, j int) bool {
+	a := len(t.groups[t.terms[i]])
+	b := len(t.groups[t.terms[j]])
+	if a != b {
+		return a > b
+	}
+	return t.terms[i] < t.terms[j]
+}
@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Wildcard represents an in-memory wildcard query.
+type Wildcard struct {
+	query.Wildcard
+}
+
+// NewWildcard instantiates and returns a new query struct.
+func NewWildcard() (veldt.Query, error) {
+	return &Wildcard{}, nil
+}
+
+// Get returns the appropriate filter for the query.
+func (q *Wildcard) Get() (Filter, error) {
+	buffer := bytes.Buffer{}
+	// wildcards match newlines
+	if q.CaseInsensitive {
+		buffer.WriteString("(?is)^")
+	} else {
+		buffer.WriteString("(?s)^")
+	}
+	for _, token := range q.GetTokens() {
+		switch token.Any {
+		case '*':
+			buffer.WriteString(".*")
+		case '?':
+			buffer.WriteString(".")
+		default:
+			buffer.WriteString(regexp.QuoteMeta(string(token.Literal)))
+		}
+	}
+	buffer.WriteString("$")
+	re, err := regexp.Compile(buffer.String())
+	if err != nil {
+		return nil, fmt.Errorf("unable to compile pattern `%s`: %v", q.Pattern, err)
+	}
+	return matchStrings(q.Field, re.MatchString), nil
+}
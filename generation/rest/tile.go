@@ -1,18 +1,25 @@
 package rest
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strings"
 
 	"github.com/unchartedsoftware/veldt"
 	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/resilience"
+	"github.com/unchartedsoftware/veldt/schema"
 	"github.com/unchartedsoftware/veldt/tile"
+	"github.com/unchartedsoftware/veldt/trace"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
 const (
 	maxErrLength = 1024
+	requestSpan  = "rest.request"
 )
 
 // Tile represents a REST tile type.
@@ -20,6 +27,7 @@ type Tile struct {
 	ext      string
 	endpoint string
 	scheme   string
+	policy   *resilience.Policy
 }
 
 // NewTile instantiates and returns a new REST tile.
@@ -53,9 +61,53 @@ func (t *Tile) Parse(params map[string]interface{}) error {
 	return nil
 }
 
+// Schema returns the JSON Schema of the parameters of the tile.
+func (t *Tile) Schema() *schema.Schema {
+	return schema.Object(map[string]*schema.Schema{
+		"endpoint": schema.String("The host and path of the tile server."),
+		"scheme":   schema.String("The scheme of the tile server, such as `http`."),
+		"ext":      schema.String("The extension of the tiles."),
+	}, "endpoint", "scheme", "ext")
+}
+
+// GetEndpoint returns the host of the endpoint.
+func (t *Tile) GetEndpoint() string {
+	return strings.SplitN(t.endpoint, "/", 2)[0]
+}
+
+// SetPolicy sets the resilience policy of the requests made to the endpoint.
+func (t *Tile) SetPolicy(policy *resilience.Policy) {
+	t.policy = policy
+}
+
+// GetPolicy returns the resilience policy of the requests made to the
+// endpoint. A tile without a policy is given its own default policy, which
+// times each request out after a minute.
+func (t *Tile) GetPolicy() *resilience.Policy {
+	if t.policy == nil {
+		t.policy = resilience.NewDefaultPolicy()
+	}
+	return t.policy
+}
+
 // Create generates a tile from the provided URI, tile coordinate and query
 // parameters.
 func (t *Tile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return t.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done. The trace
+// context is propagated to the endpoint in the `traceparent` header.
+func (t *Tile) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	ctx, span := trace.Start(ctx, requestSpan)
+	defer span.End()
+	res, err := t.request(ctx, uri, coord)
+	span.SetError(err)
+	return res, err
+}
+
+func (t *Tile) request(ctx context.Context, uri string, coord *binning.TileCoord) ([]byte, error) {
 	// create URL
 	format := "%s://%s/%s/%d/%d/%d.%s"
 	url := fmt.Sprintf(format,
@@ -71,8 +123,11 @@ func (t *Tile) Create(uri string, coord *binning.TileCoord, query veldt.Query) (
 	if err != nil {
 		return nil, err
 	}
+	req = req.WithContext(ctx)
 	// set appropriate headers based on extension
 	handleExt(t.ext, req)
+	// propagate the trace context
+	trace.Inject(ctx, req.Header)
 	// build http request
 	client := &http.Client{}
 	res, err := client.Do(req)
@@ -91,7 +146,11 @@ func (t *Tile) Create(uri string, coord *binning.TileCoord, query veldt.Query) (
 		if len(str) > maxErrLength {
 			str = str[0:maxErrLength] + "..."
 		}
-		return nil, fmt.Errorf(str)
+		if res.StatusCode < 500 {
+			// client errors are not resolved by retrying
+			return nil, resilience.Permanent(errors.New(str))
+		}
+		return nil, errors.New(str)
 	}
 	return tile.Decode(t.ext, res.Body)
 }
@@ -0,0 +1,15 @@
+package s3
+
+import (
+	"github.com/unchartedsoftware/veldt"
+)
+
+func init() {
+	veldt.RegisterBackend("s3", &veldt.Backend{
+		Tiles: map[string]veldt.TileFactory{
+			"tile": func(conn map[string]interface{}) (veldt.TileCtor, error) {
+				return NewTile(), nil
+			},
+		},
+	})
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/unchartedsoftware/veldt"
 	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/codec"
+	"github.com/unchartedsoftware/veldt/schema"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
@@ -44,7 +46,17 @@ func (t *Tile) Parse(params map[string]interface{}) error {
 	return nil
 }
 
+// Schema returns the JSON Schema of the parameters of the tile.
+func (t *Tile) Schema() *schema.Schema {
+	return schema.Object(map[string]*schema.Schema{
+		"ext":       schema.String("The extension of the tile objects.").WithDefault(defaultExt),
+		"padCoords": schema.Boolean("Whether the coordinates in object keys are zero padded.").WithDefault(defaultPadCoords),
+	})
+}
+
 // Create generates a tile from the provided URI, tile coordinate and query parameters.
+// Objects whose content encoding names a codec, such as those written by the
+// `store/s3` store, are decoded by it.
 func (t *Tile) Create(s3uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
 	// create s3 client
 	s3Client, err := NewS3Client()
@@ -76,7 +88,7 @@ func (t *Tile) Create(s3uri string, coord *binning.TileCoord, query veldt.Query)
 		Key:    aws.String(key),
 	}
 	// Fetch tile from s3
-	res, err := s3Client.GetObject(params)
+	res, err := GetObject(s3Client, params)
 	// Handle response
 	if err != nil {
 		// don't return an error if the tile doesn't exist
@@ -91,5 +103,10 @@ func (t *Tile) Create(s3uri string, coord *binning.TileCoord, query veldt.Query)
 	if err != nil {
 		return nil, err
 	}
+	// decode tiles stored compressed, such as by the `store/s3` store
+	encoding := aws.StringValue(res.ContentEncoding)
+	if encoding != "" && encoding != codec.Identity {
+		return codec.Decode(encoding, body)
+	}
 	return body, nil
 }
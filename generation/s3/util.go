@@ -34,3 +34,24 @@ func NewS3Client() (*s3.S3, error) {
 	runtime.Gosched()
 	return s3.New(awsSession), nil
 }
+
+// SetSession sets the AWS session of the S3 clients, such as to connect to an
+// S3 compatible endpoint.
+func SetSession(sess *session.Session) {
+	mutex.Lock()
+	awsSession = sess
+	mutex.Unlock()
+}
+
+// GetObject fetches an object without the HTTP client transparently
+// decompressing it, as its content encoding names the codec of the data
+// rather than a transfer encoding.
+func GetObject(client *s3.S3, input *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+	req, res := client.GetObjectRequest(input)
+	req.HTTPRequest.Header.Set("Accept-Encoding", "identity")
+	err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -43,6 +43,11 @@ func tOrF(b bool) string {
 	return "F"
 }
 
+// GetEndpoint returns the host and port of the RabbitMQ server.
+func (c *Config) GetEndpoint() string {
+	return fmt.Sprintf("%s:%d", c.host, c.port)
+}
+
 // Key returns a unique key that completely describes this configuration
 func (c *Config) Key() string {
 	qcs := make([]string, len(c.queueConfigs))
@@ -0,0 +1,67 @@
+package salt
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+func init() {
+	veldt.RegisterBackend("salt", &veldt.Backend{
+		Binary:  NewBinaryExpression,
+		Unary:   NewUnaryExpression,
+		Queries: map[string]veldt.QueryCtor{},
+		Tiles: map[string]veldt.TileFactory{
+			"count":     newTileFactory(NewCountTile),
+			"heatmap":   newTileFactory(NewHeatmapTile),
+			"macro":     newTileFactory(NewMacroTile),
+			"macroEdge": newTileFactory(NewMacroEdgeTile),
+			"micro":     newTileFactory(NewMicroTile),
+		},
+		Metas: map[string]veldt.MetaFactory{
+			"default": func(conn map[string]interface{}) (veldt.MetaCtor, error) {
+				config, _, err := parseConnection(conn)
+				if err != nil {
+					return nil, err
+				}
+				return NewMeta(config), nil
+			},
+		},
+	})
+}
+
+func newTileFactory(ctor func(*Config, ...[]byte) veldt.TileCtor) veldt.TileFactory {
+	return func(conn map[string]interface{}) (veldt.TileCtor, error) {
+		config, datasets, err := parseConnection(conn)
+		if err != nil {
+			return nil, err
+		}
+		return ctor(config, datasets...), nil
+	}
+}
+
+// parseConnection reads the salt configuration file and the dataset
+// configuration files of the connection parameters.
+func parseConnection(conn map[string]interface{}) (*Config, [][]byte, error) {
+	filename, ok := json.GetString(conn, "config")
+	if !ok {
+		return nil, nil, fmt.Errorf("`config` parameter missing from salt connection")
+	}
+	config, err := ReadConfig(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	filenames, ok := json.GetStringArray(conn, "datasets")
+	if !ok && json.Exists(conn, "datasets") {
+		return nil, nil, fmt.Errorf("`datasets` parameter of salt connection is not an array of strings")
+	}
+	datasets := make([][]byte, len(filenames))
+	for i, filename := range filenames {
+		datasets[i], err = ReadDatasetConfig(filename)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return config, datasets, nil
+}
@@ -1,11 +1,20 @@
 package salt
 
 import (
+	"context"
 	"fmt"
 	"runtime"
 	"sync"
+	"time"
 
 	"github.com/streadway/amqp"
+
+	"github.com/unchartedsoftware/veldt/trace"
+)
+
+const (
+	requestSpan = "salt.request"
+	timeout     = time.Second * 60
 )
 
 // This file contains the basic facilities for connecting to and communicating
@@ -77,8 +86,15 @@ func NewConnection(config *Config) (*RabbitMQConnection, error) {
 		go func() {
 			for response := range responses {
 				msgID := response.MessageId
-				responseChannel := responseChannels[msgID]
+				mutex.Lock()
+				responseChannel, ok := responseChannels[msgID]
 				delete(responseChannels, msgID)
+				mutex.Unlock()
+				if !ok {
+					// the requester has abandoned the message
+					Debugf("Discarding response to abandoned message %s", msgID)
+					continue
+				}
 				responseChannel <- response
 			}
 		}()
@@ -136,24 +152,37 @@ func nextMessageID() string {
 	return msgID
 }
 
-// Dataset sets up a dataset on the Salt server for future use
+// Dataset sets up a dataset on the Salt server for future use, giving up on
+// the response after the default timeout
 func (rmq *RabbitMQConnection) Dataset(message []byte) ([]byte, error) {
-	return rmq.sendServerMessage("dataset", message)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return rmq.sendServerMessage(ctx, "dataset", message)
 }
 
 // QueryTiles queries the salt server for a tile
 func (rmq *RabbitMQConnection) QueryTiles(message []byte) ([]byte, error) {
-	return rmq.sendServerMessage("tiles", message)
+	return rmq.QueryTilesContext(context.Background(), message)
+}
+
+// QueryTilesContext queries the salt server for a tile, abandoning the
+// response if the context is done before it arrives
+func (rmq *RabbitMQConnection) QueryTilesContext(ctx context.Context, message []byte) ([]byte, error) {
+	return rmq.sendServerMessage(ctx, "tiles", message)
 }
 
-// QueryMetadata queries the salt server for metadata on a dataset
+// QueryMetadata queries the salt server for metadata on a dataset, giving up
+// on the response after the default timeout
 func (rmq *RabbitMQConnection) QueryMetadata(message []byte) ([]byte, error) {
-	return rmq.sendServerMessage("metadata", message)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return rmq.sendServerMessage(ctx, "metadata", message)
 }
 
 // sendServerMessage is a low-level generic function to do exactly what it says.  It is used by
-// Query and Dataset
-func (rmq *RabbitMQConnection) sendServerMessage(messageType string, message []byte) ([]byte, error) {
+// Query and Dataset.  If the context is done before a response arrives, the
+// message is abandoned and any later response to it is discarded.
+func (rmq *RabbitMQConnection) sendServerMessage(ctx context.Context, messageType string, message []byte) ([]byte, error) {
 	queryQ, err := rmq.GetQueue(rmq.serverQueue)
 	if err != nil {
 		return emptyResponse, err
@@ -164,23 +193,47 @@ func (rmq *RabbitMQConnection) sendServerMessage(messageType string, message []b
 	}
 
 	msgID := nextMessageID()
-	responseChannel := make(chan amqp.Delivery)
+	// buffered so that the consumer never blocks on an abandoned message
+	responseChannel := make(chan amqp.Delivery, 1)
+	mutex.Lock()
 	responseChannels[msgID] = responseChannel
+	mutex.Unlock()
 
 	Debugf("Publishing message \"%s\"\n\t(query queue: %s(=%s))\n\t(response queue: %s(=%s))\n\t(type: %s)",
 		string(message), rmq.serverQueue, queryQ.Name, "response", responseQ.Name, messageType)
 
+	// the round trip is traced, with the trace context propagated in the
+	// message headers
+	ctx, span := trace.Start(ctx, requestSpan)
+	span.SetAttribute("salt.type", messageType)
+	defer span.End()
+	headers := amqp.Table{}
+	trace.InjectTable(ctx, headers)
+
 	rmq.channel.Publish("", queryQ.Name, false, false,
 		amqp.Publishing{
+			Headers:   headers,
 			Type:      messageType,
 			Body:      message,
 			ReplyTo:   responseQ.Name,
 			MessageId: msgID})
 
-	response := <-responseChannel
+	var response amqp.Delivery
+	select {
+	case response = <-responseChannel:
+	case <-ctx.Done():
+		mutex.Lock()
+		delete(responseChannels, msgID)
+		mutex.Unlock()
+		Debugf("Abandoning message %s: %v", msgID, ctx.Err())
+		span.SetError(ctx.Err())
+		return nil, ctx.Err()
+	}
 	Debugf("Response received: \"%s\"", string(response.Body))
 	if "error" == response.Type {
-		return nil, fmt.Errorf(string(response.Body))
+		err := fmt.Errorf(string(response.Body))
+		span.SetError(err)
+		return nil, err
 	}
 
 	return response.Body, nil
@@ -1,6 +1,7 @@
 package salt
 
 import (
+	"context"
 	"encoding/binary"
 	"fmt"
 	"math"
@@ -11,6 +12,8 @@ import (
 	"github.com/unchartedsoftware/veldt"
 	"github.com/unchartedsoftware/veldt/binning"
 	"github.com/unchartedsoftware/veldt/generation/batch"
+	"github.com/unchartedsoftware/veldt/resilience"
+	"github.com/unchartedsoftware/veldt/schema"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
@@ -52,6 +55,8 @@ type TileData struct {
 	// A function to construct a default tile, for when Salt tells us there
 	// is no information in the requested tile
 	buildDefault DefaultTileConstructor
+	// The resilience policy of single tile requests
+	policy *resilience.Policy
 }
 
 type tileResult struct {
@@ -104,16 +109,50 @@ func setupConnection(rmqConfig *Config, datasetConfigs ...[]byte) {
 	}
 }
 
+// SetPolicy sets the resilience policy of single tile requests made to the
+// salt server.
+func (t *TileData) SetPolicy(policy *resilience.Policy) {
+	t.policy = policy
+}
+
+// GetPolicy returns the resilience policy of single tile requests made to the
+// salt server, which is a default policy of the tile's own unless one has been
+// set.
+func (t *TileData) GetPolicy() *resilience.Policy {
+	if t.policy == nil {
+		t.policy = resilience.NewDefaultPolicy()
+	}
+	return t.policy
+}
+
+// GetEndpoint returns the host and port of the RabbitMQ server.
+func (t *TileData) GetEndpoint() string {
+	return t.rmqConfig.GetEndpoint()
+}
+
 // Parse parses the parameters for a heatmap tile
 func (t *TileData) Parse(params map[string]interface{}) error {
 	t.parameters = &params
 	return nil
 }
 
+// Schema returns nil, as the parameters are passed through to the Salt
+// server, which validates them. Declaring it also prevents the schemas of
+// embedded tile types from being promoted in its place.
+func (t *TileData) Schema() *schema.Schema {
+	return nil
+}
+
 // Create generates a single tile from the provided URI, tile coordinate, and
 // query parameters.  It does this by wrapping the information as a multi-tile
 // request with a single tile in it, and calling CreateTiles.
 func (t *TileData) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return t.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a single tile in the same manner as Create,
+// abandoning the request to the salt server if the context is done.
+func (t *TileData) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
 	responseChan := make(chan batch.TileResponse, 1)
 	request := &batch.TileRequest{
 		Params:        *t.parameters,
@@ -122,7 +161,7 @@ func (t *TileData) Create(uri string, coord *binning.TileCoord, query veldt.Quer
 		Query:         query,
 		ResultChannel: responseChan,
 	}
-	t.CreateTiles([]*batch.TileRequest{request})
+	t.createTiles(ctx, []*batch.TileRequest{request})
 	response := <-responseChan
 	if response.Tile != nil {
 		Debugf("Create: Got response tile of length %d", len(response.Tile))
@@ -139,6 +178,10 @@ func (t *TileData) Create(uri string, coord *binning.TileCoord, query veldt.Quer
 
 // CreateTiles generates multiple tiles from the provided information
 func (t *TileData) CreateTiles(requests []*batch.TileRequest) {
+	t.createTiles(context.Background(), requests)
+}
+
+func (t *TileData) createTiles(ctx context.Context, requests []*batch.TileRequest) {
 	Infof("CreateTiles: Processing %d requests of type %s", len(requests), t.tileType)
 	// Create our connection
 	connection, err := NewConnection(t.rmqConfig)
@@ -222,7 +265,7 @@ func (t *TileData) CreateTiles(requests []*batch.TileRequest) {
 				}
 			} else {
 				// Send the marshalled request to Salt, and await a response
-				result, err := connection.QueryTiles(requestBytes)
+				result, err := connection.QueryTilesContext(ctx, requestBytes)
 				if err != nil {
 					for _, channel := range responseChannels {
 						channel <- batch.TileResponse{
@@ -0,0 +1,156 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// BinnedTopHits represents a SQL implementation of the binned top hits tile.
+// The hits of each bin are ranked by a window function, which requires
+// PostgreSQL 8.4, SQLite 3.25 or MySQL 8.
+type BinnedTopHits struct {
+	Bivariate
+	TopHits
+	Tile
+}
+
+// NewBinnedTopHits instantiates and returns a new tile struct.
+func NewBinnedTopHits(cfg *Config) veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		b := &BinnedTopHits{}
+		b.Config = cfg
+		return b, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (b *BinnedTopHits) Parse(params map[string]interface{}) error {
+	err := b.TopHits.Parse(params)
+	if err != nil {
+		return err
+	}
+	return b.Bivariate.Parse(params)
+}
+
+// Schema returns the JSON Schema of the parameters of the tile.
+func (b *BinnedTopHits) Schema() *schema.Schema {
+	return schema.Merge(b.TopHits.Schema(), b.Bivariate.Schema())
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (b *BinnedTopHits) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return b.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (b *BinnedTopHits) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// Initialize the tile processing.
+	client, sqlQuery, err := b.InitializeTile(uri, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// add tiling query
+	sqlQuery = b.Bivariate.AddQuery(coord, sqlQuery)
+
+	// rank the hits of each bin
+	xBin, yBin := b.Bivariate.GetBinExpressions(coord, sqlQuery)
+	window := fmt.Sprintf("PARTITION BY %s, %s", xBin, yBin)
+	if b.SortField != "" {
+		window += fmt.Sprintf(" ORDER BY %s", b.TopHits.GetOrderBy(sqlQuery))
+	}
+	sqlQuery = b.TopHits.AddFields(sqlQuery)
+	sqlQuery.Select(fmt.Sprintf("%s AS x_bin", xBin))
+	sqlQuery.Select(fmt.Sprintf("%s AS y_bin", yBin))
+	sqlQuery.Select(fmt.Sprintf("ROW_NUMBER() OVER (%s) AS hit_rank", window))
+
+	// keep the top hits of each bin
+	ranked, err := NewQuery(sqlQuery.Dialect)
+	if err != nil {
+		return nil, err
+	}
+	ranked.QueryArgs = sqlQuery.QueryArgs
+	ranked.From(fmt.Sprintf("(%s) AS hits", sqlQuery.GetQuery(true)))
+	ranked.Select("*")
+	ranked.Where(fmt.Sprintf("hit_rank <= %d", b.HitsCount))
+	ranked.OrderBy("x_bin")
+	ranked.OrderBy("y_bin")
+	ranked.OrderBy("hit_rank")
+
+	// send query
+	res, err := ranked.Execute(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	// convert hit bins
+	bins := make([][]map[string]interface{}, b.Resolution*b.Resolution)
+	for res.Next() {
+		hit, err := scanHit(res)
+		if err != nil {
+			return nil, err
+		}
+		x, xOk := toInt(hit["x_bin"])
+		y, yOk := toInt(hit["y_bin"])
+		if !xOk || !yOk {
+			return nil, fmt.Errorf("error parsing bin of hit: %v", hit)
+		}
+		delete(hit, "x_bin")
+		delete(hit, "y_bin")
+		delete(hit, "hit_rank")
+		index := b.Bivariate.GetBinIndex(coord, x, y)
+		bins[index] = append(bins[index], hit)
+	}
+	err = res.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	// bin width
+	binSize := binning.MaxTileResolution / float64(b.Resolution)
+	halfSize := float64(binSize / 2)
+
+	// convert to point array
+	points := make([]float32, len(bins)*2)
+	numPoints := 0
+	for i, bin := range bins {
+		if bin != nil {
+			x := float32(float64(i%b.Resolution)*binSize + halfSize)
+			y := float32(math.Floor(float64(i/b.Resolution))*binSize + halfSize)
+			points[numPoints*2] = x
+			points[numPoints*2+1] = y
+			numPoints++
+		}
+	}
+
+	//encode
+	return json.Marshal(map[string]interface{}{
+		"points": points[0 : numPoints*2],
+		"hits":   bins,
+	})
+}
+
+// toInt returns the integer of a scanned value, which some drivers return as
+// text.
+func toInt(val interface{}) (int64, bool) {
+	switch v := val.(type) {
+	case int64:
+		return v, true
+	case float64:
+		return int64(v), true
+	case string:
+		num, err := strconv.ParseInt(v, 10, 64)
+		return num, err == nil
+	}
+	return 0, false
+}
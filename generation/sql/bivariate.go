@@ -0,0 +1,98 @@
+package sql
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+// Bivariate represents a bivariate tile generator.
+type Bivariate struct {
+	tile.Bivariate
+}
+
+// AddQuery adds the tiling query to the provided query object.
+func (b *Bivariate) AddQuery(coord *binning.TileCoord, query *Query) *Query {
+	// get tile bounds
+	bounds := b.TileBounds(coord)
+	xField := query.Quote(b.XField)
+	yField := query.Quote(b.YField)
+	// x
+	minXArg := query.AddParameter(bounds.MinX())
+	maxXArg := query.AddParameter(bounds.MaxX())
+	query.Where(fmt.Sprintf("%s >= %s AND %s < %s", xField, minXArg, xField, maxXArg))
+	// y
+	minYArg := query.AddParameter(bounds.MinY())
+	maxYArg := query.AddParameter(bounds.MaxY())
+	query.Where(fmt.Sprintf("%s >= %s AND %s < %s", yField, minYArg, yField, maxYArg))
+	// result
+	return query
+}
+
+// AddAggs adds the tiling aggregations to the provided query object, selecting
+// the `x_bin` and `y_bin` of each group, counted from the minimum of the tile
+// bounds.
+func (b *Bivariate) AddAggs(coord *binning.TileCoord, query *Query) *Query {
+	xBin, yBin := b.GetBinExpressions(coord, query)
+	query.Select(fmt.Sprintf("%s AS x_bin", xBin))
+	query.Select(fmt.Sprintf("%s AS y_bin", yBin))
+	query.GroupBy(xBin)
+	query.GroupBy(yBin)
+	return query
+}
+
+// GetBinExpressions returns the expressions of the x and y bins of each row,
+// counted from the minimum of the tile bounds.
+func (b *Bivariate) GetBinExpressions(coord *binning.TileCoord, query *Query) (string, string) {
+	bounds := b.TileBounds(coord)
+	xBin := query.Dialect.Div(
+		fmt.Sprintf("(%s - %s)", query.Quote(b.XField), literal(bounds.MinX())),
+		literal(b.BinSizeX(coord)))
+	yBin := query.Dialect.Div(
+		fmt.Sprintf("(%s - %s)", query.Quote(b.YField), literal(bounds.MinY())),
+		literal(b.BinSizeY(coord)))
+	return xBin, yBin
+}
+
+// GetBinIndex returns the index of the bin of the provided x and y bins,
+// counted from the minimum of the tile bounds.
+func (b *Bivariate) GetBinIndex(coord *binning.TileCoord, xBin int64, yBin int64) int {
+	bounds := b.TileBounds(coord)
+	if bounds.Left > bounds.Right {
+		xBin = int64(b.Resolution) - 1 - xBin
+	}
+	if bounds.Bottom > bounds.Top {
+		yBin = int64(b.Resolution) - 1 - yBin
+	}
+	return clampBin(xBin, b.Resolution) + b.Resolution*clampBin(yBin, b.Resolution)
+}
+
+// GetBins parses the resulting histograms into bins.
+func (b *Bivariate) GetBins(coord *binning.TileCoord, rows *sql.Rows) ([]float64, error) {
+	defer rows.Close()
+	// allocate bins buffer
+	bins := make([]float64, b.Resolution*b.Resolution)
+	// fill bins buffer
+	for rows.Next() {
+		var x, y int64
+		var value float64
+		err := rows.Scan(&x, &y, &value)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing histogram aggregation: %v", err)
+		}
+		bins[b.GetBinIndex(coord, x, y)] += value
+	}
+	return bins, rows.Err()
+}
+
+func clampBin(bin int64, resolution int) int {
+	if bin > int64(resolution)-1 {
+		return resolution - 1
+	}
+	if bin < 0 {
+		return 0
+	}
+	return int(bin)
+}
@@ -0,0 +1,75 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/unchartedsoftware/veldt"
+)
+
+// BinaryExpression represents an and/or boolean query.
+type BinaryExpression struct {
+	veldt.BinaryExpression
+}
+
+// NewBinaryExpression instantiates and returns a new binary expression.
+func NewBinaryExpression() (veldt.Query, error) {
+	return &BinaryExpression{}, nil
+}
+
+// Get adds the parameters to the query and returns the string representation.
+// Chains of the same operator are joined within a single set of parentheses.
+func (e *BinaryExpression) Get(query *Query) (string, error) {
+	if e.Op != veldt.And && e.Op != veldt.Or {
+		return "", fmt.Errorf("`%v` operator is not a valid binary operator", e.Op)
+	}
+	operands := e.GetOperands()
+	queryStrings := make([]string, len(operands))
+	for i, operand := range operands {
+		q, ok := operand.(QueryString)
+		if !ok {
+			return "", fmt.Errorf("operand is not of type sql.Query")
+		}
+		queryString, err := q.Get(query)
+		if err != nil {
+			return "", err
+		}
+		queryStrings[i] = fmt.Sprintf("(%s)", queryString)
+	}
+	// AND / OR
+	return fmt.Sprintf("(%s)", strings.Join(queryStrings, fmt.Sprintf(" %s ", e.Op))), nil
+}
+
+// UnaryExpression represents a must_not boolean query.
+type UnaryExpression struct {
+	veldt.UnaryExpression
+}
+
+// NewUnaryExpression instantiates and returns a new unary expression.
+func NewUnaryExpression() (veldt.Query, error) {
+	return &UnaryExpression{}, nil
+}
+
+// Get adds the parameters to the query and returns the string representation.
+func (e *UnaryExpression) Get(query *Query) (string, error) {
+
+	q, ok := e.Query.(QueryString)
+	if !ok {
+		return "", fmt.Errorf("`Query` is not of type sql.Query")
+	}
+
+	a, err := q.Get(query)
+	if err != nil {
+		return "", err
+	}
+
+	res := ""
+	switch e.Op {
+	case veldt.Not:
+		// NOT
+		res = res + fmt.Sprintf("NOT (%s)", a)
+	default:
+		return "", fmt.Errorf("`%v` operator is not a valid unary operator", e.Op)
+	}
+	return res, nil
+}
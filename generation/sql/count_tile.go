@@ -0,0 +1,76 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
+)
+
+// Count represents a SQL implementation of the count tile.
+type Count struct {
+	Bivariate
+	Tile
+}
+
+// NewCountTile instantiates and returns a new tile struct.
+func NewCountTile(cfg *Config) veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		t := &Count{}
+		t.Config = cfg
+		return t, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (t *Count) Parse(params map[string]interface{}) error {
+	return t.Bivariate.Parse(params)
+}
+
+// Schema returns the JSON Schema of the parameters of the tile.
+func (t *Count) Schema() *schema.Schema {
+	return t.Bivariate.Schema()
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (t *Count) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return t.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (t *Count) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// Initialize the tile processing.
+	client, sqlQuery, err := t.InitializeTile(uri, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// add tiling query
+	sqlQuery = t.Bivariate.AddQuery(coord, sqlQuery)
+
+	sqlQuery.Select("COUNT(*) AS value")
+	// send query
+	res, err := sqlQuery.Execute(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	value := int64(0)
+	for res.Next() {
+		err = res.Scan(&value)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing count: %v", err)
+		}
+	}
+	err = res.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(fmt.Sprintf(`{"count":%d}`, value)), nil
+}
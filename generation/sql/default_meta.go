@@ -0,0 +1,170 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// PropertyMeta represents the meta data for a single property.
+type PropertyMeta struct {
+	Type    string           `json:"type"`
+	Extrema *binning.Extrema `json:"extrema,omitempty"`
+}
+
+// isNumeric returns whether the column type is numeric. As the type names
+// differ between databases, it matches the names by their contents.
+func isNumeric(typ string) bool {
+	typ = strings.ToLower(typ)
+	return strings.Contains(typ, "int") ||
+		strings.Contains(typ, "real") ||
+		strings.Contains(typ, "double") ||
+		strings.Contains(typ, "float") ||
+		strings.Contains(typ, "numeric") ||
+		strings.Contains(typ, "decimal") ||
+		strings.Contains(typ, "serial")
+}
+
+func getPropertyMeta(ctx context.Context, client *sql.DB, dialect Dialect, table string, column string, typ string) (*PropertyMeta, error) {
+	p := PropertyMeta{
+		Type: typ,
+	}
+	// if field is 'ordinal', get the extrema
+	if isNumeric(typ) {
+		extrema, err := GetNumericExtrema(ctx, client, dialect, table, column)
+		if err != nil {
+			return nil, err
+		}
+		p.Extrema = extrema
+	}
+	return &p, nil
+}
+
+// GetNumericExtrema returns the extrema of a numeric field for the provided
+// table, abandoning the query if the context is done.
+func GetNumericExtrema(ctx context.Context, client *sql.DB, dialect Dialect, table string, column string) (*binning.Extrema, error) {
+	// query
+	query, err := NewQuery(dialect)
+	if err != nil {
+		return nil, err
+	}
+	query.Select(fmt.Sprintf("MIN(%s) AS min", dialect.Quote(column)))
+	query.Select(fmt.Sprintf("MAX(%s) AS max", dialect.Quote(column)))
+	query.From(query.Quote(table))
+	rows, err := query.Execute(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// if no rows have the column, the min / max are null
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	var min sql.NullFloat64
+	var max sql.NullFloat64
+	err = rows.Scan(&min, &max)
+	if err != nil {
+		return nil, err
+	}
+	if !min.Valid || !max.Valid {
+		return nil, nil
+	}
+
+	return &binning.Extrema{
+		Min: min.Float64,
+		Max: max.Float64,
+	}, nil
+}
+
+// DefaultMeta represents a meta data generator that produces default
+// metadata with property types and extrema.
+type DefaultMeta struct {
+	Config *Config
+}
+
+// NewDefaultMeta instantiates and returns a pointer to a new generator.
+func NewDefaultMeta(cfg *Config) veldt.MetaCtor {
+	return func() (veldt.Meta, error) {
+		return &DefaultMeta{
+			Config: cfg,
+		}, nil
+	}
+}
+
+// GetEndpoint returns the endpoint of the database.
+func (g *DefaultMeta) GetEndpoint() string {
+	return g.Config.GetEndpoint()
+}
+
+// Parse parses the provided JSON object and populates the structs attributes.
+func (g *DefaultMeta) Parse(params map[string]interface{}) error {
+	return nil
+}
+
+// Schema returns the JSON Schema of the parameters of the metadata, which
+// has none.
+func (g *DefaultMeta) Schema() *schema.Schema {
+	return schema.Object(nil)
+}
+
+// Create generates metadata from the provided URI, which is either a `table`
+// or a `schema.table`.
+func (g *DefaultMeta) Create(uri string) ([]byte, error) {
+	return g.CreateContext(context.Background(), uri)
+}
+
+// CreateContext generates metadata from the provided URI, abandoning the
+// queries for the extrema if the context is done.
+func (g *DefaultMeta) CreateContext(ctx context.Context, uri string) ([]byte, error) {
+	client, err := NewClient(g.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	columnsQuery, args := g.Config.Dialect.Columns(uri)
+	rows, err := client.Query(columnsQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// read the columns before querying their extrema, as a database may
+	// only allow a single open connection
+	var columns []string
+	var types []string
+	for rows.Next() {
+		var column string
+		var typ string
+		err := rows.Scan(&column, &typ)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+		types = append(types, typ)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table `%s` does not exist or has no columns", uri)
+	}
+
+	meta := make(map[string]interface{})
+	for i, column := range columns {
+		metaColumn, err := getPropertyMeta(ctx, client, g.Config.Dialect, uri, column, types[i])
+		if err != nil {
+			return nil, err
+		}
+		meta[column] = metaColumn
+	}
+
+	return json.Marshal(meta)
+}
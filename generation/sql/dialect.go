@@ -0,0 +1,205 @@
+package sql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Postgres is the name of the PostgreSQL dialect.
+	Postgres = "postgres"
+	// SQLite is the name of the SQLite dialect.
+	SQLite = "sqlite"
+	// MySQL is the name of the MySQL dialect.
+	MySQL = "mysql"
+)
+
+var (
+	// the dialects of the common driver names
+	driverDialects = map[string]string{
+		"postgres": Postgres,
+		"pgx":      Postgres,
+		"sqlite3":  SQLite,
+		"sqlite":   SQLite,
+		"mysql":    MySQL,
+	}
+)
+
+// Dialect represents the syntax of a SQL database that differs between
+// implementations.
+type Dialect interface {
+	// Quote returns the identifier quoted, such that it is neither folded
+	// nor interpreted as a keyword.
+	Quote(identifier string) string
+	// Placeholder returns the placeholder of the nth parameter, counting
+	// from 1.
+	Placeholder(n int) string
+	// Div returns the integer quotient of a non-negative numerator and a
+	// positive denominator.
+	Div(numerator string, denominator string) string
+	// Unnest returns a FROM item expanding the array field of each row into
+	// a row per element, and the expression of the element.
+	Unnest(field string, alias string) (string, string)
+	// Limit returns the clause limiting the number of rows.
+	Limit(n uint32) string
+	// Columns returns a query of the name and type of each column of the
+	// table, along with its parameters.
+	Columns(table string) (string, []interface{})
+}
+
+// GetDialect returns the dialect of the provided name.
+func GetDialect(name string) (Dialect, error) {
+	switch name {
+	case Postgres:
+		return &PostgresDialect{}, nil
+	case SQLite:
+		return &SQLiteDialect{}, nil
+	case MySQL:
+		return &MySQLDialect{}, nil
+	}
+	return nil, fmt.Errorf("`%s` is not a supported SQL dialect", name)
+}
+
+// GetDriverDialect returns the dialect of the provided driver name.
+func GetDriverDialect(driver string) (Dialect, error) {
+	name, ok := driverDialects[driver]
+	if !ok {
+		return nil, fmt.Errorf("no SQL dialect is known for driver `%s`", driver)
+	}
+	return GetDialect(name)
+}
+
+// splitTable returns the schema and name of a `schema.table` or `table`
+// identifier.
+func splitTable(table string) (string, string) {
+	index := strings.LastIndex(table, ".")
+	if index == -1 {
+		return "", table
+	}
+	return table[:index], table[index+1:]
+}
+
+// PostgresDialect represents the PostgreSQL dialect.
+type PostgresDialect struct{}
+
+// Quote returns the identifier quoted with double quotes.
+func (d *PostgresDialect) Quote(identifier string) string {
+	return `"` + strings.Replace(identifier, `"`, `""`, -1) + `"`
+}
+
+// Placeholder returns the numbered placeholder of the parameter.
+func (d *PostgresDialect) Placeholder(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+// Div returns the floored quotient, as division of integers truncates while
+// division of floats does not.
+func (d *PostgresDialect) Div(numerator string, denominator string) string {
+	return fmt.Sprintf("CAST(FLOOR(CAST(%s AS DOUBLE PRECISION) / %s) AS BIGINT)", numerator, denominator)
+}
+
+// Unnest returns the array field expanded by `unnest`.
+func (d *PostgresDialect) Unnest(field string, alias string) (string, string) {
+	return fmt.Sprintf("unnest(%s) AS %s(term)", field, alias), alias + ".term"
+}
+
+// Limit returns a LIMIT clause.
+func (d *PostgresDialect) Limit(n uint32) string {
+	return fmt.Sprintf("LIMIT %d", n)
+}
+
+// Columns returns a query of the information schema. Tables without a schema
+// are of the current schema.
+func (d *PostgresDialect) Columns(table string) (string, []interface{}) {
+	schema, name := splitTable(table)
+	if schema == "" {
+		return "SELECT column_name, data_type FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = $1 ORDER BY ordinal_position",
+			[]interface{}{name}
+	}
+	return "SELECT column_name, data_type FROM information_schema.columns WHERE table_schema = $1 AND table_name = $2 ORDER BY ordinal_position",
+		[]interface{}{schema, name}
+}
+
+// SQLiteDialect represents the SQLite dialect. Arrays are stored as JSON
+// arrays, which requires the JSON1 extension.
+type SQLiteDialect struct{}
+
+// Quote returns the identifier quoted with double quotes.
+func (d *SQLiteDialect) Quote(identifier string) string {
+	return `"` + strings.Replace(identifier, `"`, `""`, -1) + `"`
+}
+
+// Placeholder returns a positional placeholder.
+func (d *SQLiteDialect) Placeholder(n int) string {
+	return "?"
+}
+
+// Div returns the truncated quotient, which is the floored quotient of a
+// non-negative numerator.
+func (d *SQLiteDialect) Div(numerator string, denominator string) string {
+	return fmt.Sprintf("CAST(%s / %s AS INTEGER)", numerator, denominator)
+}
+
+// Unnest returns the JSON array field expanded by `json_each`.
+func (d *SQLiteDialect) Unnest(field string, alias string) (string, string) {
+	return fmt.Sprintf("json_each(%s) AS %s", field, alias), alias + ".value"
+}
+
+// Limit returns a LIMIT clause.
+func (d *SQLiteDialect) Limit(n uint32) string {
+	return fmt.Sprintf("LIMIT %d", n)
+}
+
+// Columns returns a query of the table info pragma.
+func (d *SQLiteDialect) Columns(table string) (string, []interface{}) {
+	schema, name := splitTable(table)
+	if schema == "" {
+		return "SELECT name, type FROM pragma_table_info(?) ORDER BY cid",
+			[]interface{}{name}
+	}
+	return "SELECT name, type FROM pragma_table_info(?, ?) ORDER BY cid",
+		[]interface{}{name, schema}
+}
+
+// MySQLDialect represents the MySQL dialect. Arrays are stored as JSON
+// arrays, which requires MySQL 8.
+type MySQLDialect struct{}
+
+// Quote returns the identifier quoted with backticks.
+func (d *MySQLDialect) Quote(identifier string) string {
+	return "`" + strings.Replace(identifier, "`", "``", -1) + "`"
+}
+
+// Placeholder returns a positional placeholder.
+func (d *MySQLDialect) Placeholder(n int) string {
+	return "?"
+}
+
+// Div returns the quotient of `DIV`, which truncates.
+func (d *MySQLDialect) Div(numerator string, denominator string) string {
+	return fmt.Sprintf("(%s DIV %s)", numerator, denominator)
+}
+
+// Unnest returns the JSON array field expanded by `JSON_TABLE`.
+func (d *MySQLDialect) Unnest(field string, alias string) (string, string) {
+	return fmt.Sprintf("JSON_TABLE(%s, '$[*]' COLUMNS (term VARCHAR(255) PATH '$')) AS %s", field, alias),
+		alias + ".term"
+}
+
+// Limit returns a LIMIT clause.
+func (d *MySQLDialect) Limit(n uint32) string {
+	return fmt.Sprintf("LIMIT %d", n)
+}
+
+// Columns returns a query of the information schema. Tables without a schema
+// are of the current database.
+func (d *MySQLDialect) Columns(table string) (string, []interface{}) {
+	schema, name := splitTable(table)
+	if schema == "" {
+		return "SELECT column_name, data_type FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = ? ORDER BY ordinal_position",
+			[]interface{}{name}
+	}
+	return "SELECT column_name, data_type FROM information_schema.columns WHERE table_schema = ? AND table_name = ? ORDER BY ordinal_position",
+		[]interface{}{schema, name}
+}
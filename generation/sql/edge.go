@@ -0,0 +1,38 @@
+package sql
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+// Edge represents a SQL implementation of the edge tile.
+type Edge struct {
+	tile.Edge
+}
+
+// AddQuery adds the tiling query to the provided query object, requiring the
+// source, the destination, or both, to be within the tile.
+func (e *Edge) AddQuery(coord *binning.TileCoord, query *Query) *Query {
+	// Require at least 1 of the points, possibly both.
+	if e.RequireSrc || !e.RequireDst {
+		e.addRange(coord, query, e.SrcXField, e.SrcYField)
+	}
+	if e.RequireDst {
+		e.addRange(coord, query, e.DstXField, e.DstYField)
+	}
+	return query
+}
+
+func (e *Edge) addRange(coord *binning.TileCoord, query *Query, xField string, yField string) {
+	bounds := e.TileBounds(coord)
+	x := query.Quote(xField)
+	y := query.Quote(yField)
+	minXArg := query.AddParameter(bounds.MinX())
+	maxXArg := query.AddParameter(bounds.MaxX())
+	query.Where(fmt.Sprintf("%s >= %s AND %s < %s", x, minXArg, x, maxXArg))
+	minYArg := query.AddParameter(bounds.MinY())
+	maxYArg := query.AddParameter(bounds.MaxY())
+	query.Where(fmt.Sprintf("%s >= %s AND %s < %s", y, minYArg, y, maxYArg))
+}
@@ -0,0 +1,24 @@
+package sql
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Equals represents a SQL equality query.
+type Equals struct {
+	query.Equals
+}
+
+// NewEquals instantiates and returns a new query struct.
+func NewEquals() (veldt.Query, error) {
+	return &Equals{}, nil
+}
+
+// Get adds the parameters to the query and returns the string representation.
+func (q *Equals) Get(query *Query) (string, error) {
+	valueParam := query.AddParameter(q.Value)
+	return fmt.Sprintf("%s = %s", query.Quote(q.Field), valueParam), nil
+}
@@ -0,0 +1,23 @@
+package sql
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Exists represents a SQL exists query.
+type Exists struct {
+	query.Exists
+}
+
+// NewExists instantiates and returns a new query struct.
+func NewExists() (veldt.Query, error) {
+	return &Exists{}, nil
+}
+
+// Get adds the parameters to the query and returns the string representation.
+func (q *Exists) Get(query *Query) (string, error) {
+	return fmt.Sprintf("%s IS NOT NULL", query.Quote(q.Field)), nil
+}
@@ -0,0 +1,83 @@
+package sql
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+func init() {
+	veldt.RegisterBackend("sql", &veldt.Backend{
+		Binary: NewBinaryExpression,
+		Unary:  NewUnaryExpression,
+		Queries: map[string]veldt.QueryCtor{
+			"equals": NewEquals,
+			"exists": NewExists,
+			"has":    NewHas,
+			"range":  NewRange,
+		},
+		Tiles: map[string]veldt.TileFactory{
+			"binnedTopHits":       newTileFactory(NewBinnedTopHits),
+			"count":               newTileFactory(NewCountTile),
+			"frequency":           newTileFactory(NewFrequencyTile),
+			"heatmap":             newTileFactory(NewHeatmapTile),
+			"macro":               newTileFactory(NewMacroTile),
+			"macroEdge":           newTileFactory(NewMacroEdgeTile),
+			"micro":               newTileFactory(NewMicroTile),
+			"targetTermCount":     newTileFactory(NewTargetTermCountTile),
+			"targetTermFrequency": newTileFactory(NewTargetTermFrequencyTile),
+			"topTermCount":        newTileFactory(NewTopTermCountTile),
+			"topTermFrequency":    newTileFactory(NewTopTermFrequencyTile),
+		},
+		Metas: map[string]veldt.MetaFactory{
+			"default": func(conn map[string]interface{}) (veldt.MetaCtor, error) {
+				cfg, err := parseConfig(conn)
+				if err != nil {
+					return nil, err
+				}
+				return NewDefaultMeta(cfg), nil
+			},
+		},
+	})
+}
+
+func newTileFactory(ctor func(cfg *Config) veldt.TileCtor) veldt.TileFactory {
+	return func(conn map[string]interface{}) (veldt.TileCtor, error) {
+		cfg, err := parseConfig(conn)
+		if err != nil {
+			return nil, err
+		}
+		return ctor(cfg), nil
+	}
+}
+
+// parseConfig returns the database config of the connection parameters. The
+// dialect defaults to that of the driver.
+func parseConfig(conn map[string]interface{}) (*Config, error) {
+	driver, ok := json.GetString(conn, "driver")
+	if !ok {
+		return nil, fmt.Errorf("`driver` parameter missing from sql connection")
+	}
+	dataSource, ok := json.GetString(conn, "dataSource")
+	if !ok {
+		return nil, fmt.Errorf("`dataSource` parameter missing from sql connection")
+	}
+	var dialect Dialect
+	var err error
+	name, ok := json.GetString(conn, "dialect")
+	if ok {
+		dialect, err = GetDialect(name)
+	} else {
+		dialect, err = GetDriverDialect(driver)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Config{
+		Driver:     driver,
+		DataSource: dataSource,
+		Endpoint:   json.GetStringDefault(conn, "", "endpoint"),
+		Dialect:    dialect,
+	}, nil
+}
@@ -0,0 +1,224 @@
+package sql
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+const (
+	// maxBuckets is the maximum number of buckets of a frequency, as empty
+	// buckets are included across the whole range.
+	maxBuckets = 10000
+)
+
+var (
+	units = map[string]float64{
+		"ms":     1,
+		"s":      1000,
+		"m":      1000 * 60,
+		"h":      1000 * 60 * 60,
+		"d":      1000 * 60 * 60 * 24,
+		"w":      1000 * 60 * 60 * 24 * 7,
+		"second": 1000,
+		"minute": 1000 * 60,
+		"hour":   1000 * 60 * 60,
+		"day":    1000 * 60 * 60 * 24,
+		"week":   1000 * 60 * 60 * 24 * 7,
+	}
+)
+
+// Frequency represents a SQL implementation of the frequency tile. The
+// frequency field holds milliseconds since the epoch, and intervals must be
+// of a fixed duration, such as `1d` or `hour`. Buckets begin at the lower
+// bound of the range, or the epoch if there is none, and earlier times are
+// not supported.
+type Frequency struct {
+	tile.Frequency
+	interval float64
+	bounds   []*float64
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (f *Frequency) Parse(params map[string]interface{}) error {
+	err := f.Frequency.Parse(params)
+	if err != nil {
+		return err
+	}
+	interval, err := parseInterval(f.Interval)
+	if err != nil {
+		return err
+	}
+	bounds := make([]*float64, 4)
+	for i, val := range []interface{}{f.GTE, f.GT, f.LTE, f.LT} {
+		if val == nil {
+			continue
+		}
+		num, ok := val.(float64)
+		if !ok {
+			return fmt.Errorf("`%v` is not a valid time in milliseconds", val)
+		}
+		bounds[i] = &num
+	}
+	f.interval = interval
+	f.bounds = bounds
+	return nil
+}
+
+// AddQuery adds the tiling query to the provided query object.
+func (f *Frequency) AddQuery(query *Query) *Query {
+	field := query.Quote(f.FrequencyField)
+	for i, op := range []string{">=", ">", "<=", "<"} {
+		if f.bounds[i] != nil {
+			parameter := query.AddParameter(*f.bounds[i])
+			query.Where(fmt.Sprintf("%s %s %s", field, op, parameter))
+		}
+	}
+	return query
+}
+
+// AddAggs adds the tiling aggregations to the provided query object,
+// selecting the `bucket` and `frequency` of each group.
+func (f *Frequency) AddAggs(query *Query) *Query {
+	bucket := query.Dialect.Div(
+		fmt.Sprintf("(%s - %s)", query.Quote(f.FrequencyField), literal(f.getOffset())),
+		literal(f.interval))
+	query.Select(fmt.Sprintf("%s AS bucket", bucket))
+	query.Select("COUNT(*) AS frequency")
+	query.GroupBy(bucket)
+	return query
+}
+
+// GetBuckets returns the frequency buckets from the query results.
+func (f *Frequency) GetBuckets(rows *sql.Rows) ([]map[string]interface{}, error) {
+	defer rows.Close()
+	counts := make(map[int64]int64)
+	for rows.Next() {
+		var bucket int64
+		var frequency int64
+		err := rows.Scan(&bucket, &frequency)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing frequency: %v", err)
+		}
+		counts[bucket] = frequency
+	}
+	err := rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return f.CreateBuckets(counts)
+}
+
+// CreateBuckets creates the frequency buckets from the counts of each bucket.
+// Empty buckets are included from the lower bound of the range, or the first
+// non-empty bucket, to the upper bound of the range, or the last non-empty
+// bucket. An error is returned if the range spans more than 10000 intervals.
+func (f *Frequency) CreateBuckets(counts map[int64]int64) ([]map[string]interface{}, error) {
+	offset := f.getOffset()
+	var min, max *int64
+	for bucket := range counts {
+		b := bucket
+		if min == nil || b < *min {
+			min = &b
+		}
+		if max == nil || b > *max {
+			max = &b
+		}
+	}
+	if f.bounds[0] != nil || f.bounds[1] != nil {
+		first := int64(0)
+		min = &first
+	}
+	for _, upper := range f.bounds[2:] {
+		if upper != nil {
+			last := int64((*upper - offset) / f.interval)
+			max = &last
+		}
+	}
+	if min == nil || max == nil {
+		return []map[string]interface{}{}, nil
+	}
+	if float64(*max)-float64(*min)+1 > maxBuckets {
+		return nil, fmt.Errorf("frequency range of `%s` intervals exceeds the maximum of %d buckets", f.Interval, maxBuckets)
+	}
+	buckets := make([]map[string]interface{}, 0, *max-*min+1)
+	for i := *min; i <= *max; i++ {
+		buckets = append(buckets, map[string]interface{}{
+			"timestamp": int64(offset + float64(i)*f.interval),
+			"count":     counts[i],
+		})
+	}
+	return buckets, nil
+}
+
+func (f *Frequency) getOffset() float64 {
+	if f.bounds[0] != nil {
+		return *f.bounds[0]
+	}
+	if f.bounds[1] != nil {
+		return *f.bounds[1]
+	}
+	return 0
+}
+
+func parseInterval(interval string) (float64, error) {
+	// named unit
+	unit, ok := units[interval]
+	if ok {
+		return unit, nil
+	}
+	// number followed by a unit
+	index := strings.IndexFunc(interval, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if index > 0 {
+		unit, ok := units[interval[index:]]
+		if ok {
+			num, err := strconv.ParseFloat(interval[:index], 64)
+			if err == nil && num > 0 {
+				return num * unit, nil
+			}
+		}
+	}
+	// plain milliseconds
+	num, err := strconv.ParseFloat(interval, 64)
+	if err == nil && num > 0 {
+		return num, nil
+	}
+	return 0, fmt.Errorf("`%s` is not a supported fixed interval", interval)
+}
+
+// GetTermBuckets returns the frequency buckets of each term from the query
+// results of rows of terms, buckets and frequencies.
+func (f *Frequency) GetTermBuckets(rows *sql.Rows) (map[string][]map[string]interface{}, error) {
+	defer rows.Close()
+	counts := make(map[string]map[int64]int64)
+	for rows.Next() {
+		var term string
+		var bucket int64
+		var frequency int64
+		err := rows.Scan(&term, &bucket, &frequency)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing term frequency: %v", err)
+		}
+		if counts[term] == nil {
+			counts[term] = make(map[int64]int64)
+		}
+		counts[term][bucket] = frequency
+	}
+	err := rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string][]map[string]interface{}, len(counts))
+	for term, termCounts := range counts {
+		result[term], err = f.CreateBuckets(termCounts)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,80 @@
+package sql
+
+import (
+	"context"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// FrequencyTile represents a SQL implementation of the frequency tile.
+type FrequencyTile struct {
+	Bivariate
+	Frequency
+	Tile
+}
+
+// NewFrequencyTile instantiates and returns a new tile struct.
+func NewFrequencyTile(cfg *Config) veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		t := &FrequencyTile{}
+		t.Config = cfg
+		return t, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (t *FrequencyTile) Parse(params map[string]interface{}) error {
+	err := t.Bivariate.Parse(params)
+	if err != nil {
+		return err
+	}
+	return t.Frequency.Parse(params)
+}
+
+// Schema returns the JSON Schema of the parameters of the tile.
+func (t *FrequencyTile) Schema() *schema.Schema {
+	return schema.Merge(t.Bivariate.Schema(), t.Frequency.Schema())
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (t *FrequencyTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return t.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (t *FrequencyTile) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// Initialize the tile processing.
+	client, sqlQuery, err := t.InitializeTile(uri, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// add tiling query
+	sqlQuery = t.Bivariate.AddQuery(coord, sqlQuery)
+
+	// add frequency query
+	sqlQuery = t.Frequency.AddQuery(sqlQuery)
+
+	// add aggs
+	sqlQuery = t.Frequency.AddAggs(sqlQuery)
+
+	// send query
+	res, err := sqlQuery.Execute(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	// get buckets
+	buckets, err := t.Frequency.GetBuckets(res)
+	if err != nil {
+		return nil, err
+	}
+
+	// marshal results
+	return json.Marshal(buckets)
+}
@@ -0,0 +1,29 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Has represents a SQL has query. Unlike the citus query, the field holds a
+// single value rather than an array.
+type Has struct {
+	query.Has
+}
+
+// NewHas instantiates and returns a new query struct.
+func NewHas() (veldt.Query, error) {
+	return &Has{}, nil
+}
+
+// Get adds the parameters to the query and returns the string representation.
+func (q *Has) Get(query *Query) (string, error) {
+	valueParams := make([]string, len(q.Values))
+	for i, value := range q.Values {
+		valueParams[i] = query.AddParameter(value)
+	}
+	return fmt.Sprintf("%s IN (%s)", query.Quote(q.Field), strings.Join(valueParams, ", ")), nil
+}
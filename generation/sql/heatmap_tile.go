@@ -0,0 +1,79 @@
+package sql
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
+)
+
+// HeatmapTile represents a SQL implementation of the heatmap tile.
+type HeatmapTile struct {
+	Bivariate
+	Tile
+}
+
+// NewHeatmapTile instantiates and returns a new tile struct.
+func NewHeatmapTile(cfg *Config) veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		h := &HeatmapTile{}
+		h.Config = cfg
+		return h, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (h *HeatmapTile) Parse(params map[string]interface{}) error {
+	return h.Bivariate.Parse(params)
+}
+
+// Schema returns the JSON Schema of the parameters of the tile.
+func (h *HeatmapTile) Schema() *schema.Schema {
+	return h.Bivariate.Schema()
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (h *HeatmapTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return h.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (h *HeatmapTile) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// Initialize the tile processing.
+	client, sqlQuery, err := h.InitializeTile(uri, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// add tiling query
+	sqlQuery = h.Bivariate.AddQuery(coord, sqlQuery)
+
+	// add aggs
+	sqlQuery = h.Bivariate.AddAggs(coord, sqlQuery)
+
+	sqlQuery.Select("COUNT(*) AS value")
+	// send query
+	res, err := sqlQuery.Execute(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	// get bins
+	bins, err := h.Bivariate.GetBins(coord, res)
+	if err != nil {
+		return nil, err
+	}
+
+	// convert to byte array
+	bits := make([]byte, len(bins)*4)
+	for i, bin := range bins {
+		binary.LittleEndian.PutUint32(
+			bits[i*4:i*4+4],
+			uint32(bin))
+	}
+	return bits, nil
+}
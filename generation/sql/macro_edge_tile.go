@@ -0,0 +1,116 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+// MacroEdgeTile represents a SQL implementation of the edge tile.
+type MacroEdgeTile struct {
+	Edge
+	TopHits
+	Tile
+	tile.MacroEdge
+}
+
+// NewMacroEdgeTile instantiates and returns a new tile struct.
+func NewMacroEdgeTile(cfg *Config) veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		e := &MacroEdgeTile{}
+		e.Config = cfg
+		return e, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (e *MacroEdgeTile) Parse(params map[string]interface{}) error {
+	err := e.Edge.Parse(params)
+	if err != nil {
+		return err
+	}
+	err = e.TopHits.Parse(params)
+	if err != nil {
+		return err
+	}
+	// parse includes
+	e.TopHits.IncludeFields = e.MacroEdge.ParseIncludes(
+		e.TopHits.IncludeFields,
+		e.Edge.SrcXField,
+		e.Edge.SrcYField,
+		e.Edge.DstXField,
+		e.Edge.DstYField,
+		e.Edge.WeightField)
+	return e.MacroEdge.Parse(params)
+}
+
+// Schema returns the JSON Schema of the parameters of the tile.
+func (e *MacroEdgeTile) Schema() *schema.Schema {
+	return schema.Merge(e.Edge.Schema(), e.TopHits.Schema(), e.MacroEdge.Schema())
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (e *MacroEdgeTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return e.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (e *MacroEdgeTile) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// Initialize the tile processing.
+	client, sqlQuery, err := e.InitializeTile(uri, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// add tiling query
+	sqlQuery = e.Edge.AddQuery(coord, sqlQuery)
+
+	// get aggs
+	sqlQuery = e.TopHits.AddAggs(sqlQuery)
+
+	// send query
+	res, err := sqlQuery.Execute(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	// get top hits
+	hits, err := e.TopHits.GetTopHits(res)
+	if err != nil {
+		return nil, err
+	}
+
+	// convert to point array
+	points := make([]float32, len(hits)*6)
+	// get hit x/y in tile coords
+	for i, hit := range hits {
+		srcX, srcY, ok := e.Edge.GetSrcXY(coord, hit)
+		if !ok {
+			return nil, fmt.Errorf("could not parse edge source position from hit: %v", hit)
+		}
+		dstX, dstY, ok := e.Edge.GetDstXY(coord, hit)
+		if !ok {
+			return nil, fmt.Errorf("could not parse edge destination position from hit: %v", hit)
+		}
+		weight, ok := e.Edge.GetWeight(hit)
+		if !ok {
+			return nil, fmt.Errorf("could not parse edge weight from hit: %v", hit)
+		}
+		// add to point array
+		points[i*6] = float32(srcX)
+		points[i*6+1] = float32(srcY)
+		points[i*6+2] = float32(weight)
+		points[i*6+3] = float32(dstX)
+		points[i*6+4] = float32(dstY)
+		points[i*6+5] = float32(weight)
+	}
+
+	// encode and return results
+	return e.MacroEdge.Encode(points)
+}
@@ -0,0 +1,105 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+// MicroTile represents a SQL implementation of the micro tile.
+type MicroTile struct {
+	Bivariate
+	Tile
+	TopHits
+	tile.Micro
+}
+
+// NewMicroTile instantiates and returns a new tile struct.
+func NewMicroTile(cfg *Config) veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		m := &MicroTile{}
+		m.Config = cfg
+		return m, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (m *MicroTile) Parse(params map[string]interface{}) error {
+	err := m.Bivariate.Parse(params)
+	if err != nil {
+		return err
+	}
+	err = m.TopHits.Parse(params)
+	if err != nil {
+		return err
+	}
+	err = m.Micro.Parse(params)
+	if err != nil {
+		return err
+	}
+	// parse includes
+	m.TopHits.IncludeFields = m.Micro.ParseIncludes(
+		m.TopHits.IncludeFields,
+		m.Bivariate.XField,
+		m.Bivariate.YField)
+	return nil
+}
+
+// Schema returns the JSON Schema of the parameters of the tile.
+func (m *MicroTile) Schema() *schema.Schema {
+	return schema.Merge(m.Bivariate.Schema(), m.TopHits.Schema(), m.Micro.Schema())
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (m *MicroTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return m.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (m *MicroTile) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// Initialize the tile processing.
+	client, sqlQuery, err := m.InitializeTile(uri, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// add tiling query
+	sqlQuery = m.Bivariate.AddQuery(coord, sqlQuery)
+
+	// get aggs
+	sqlQuery = m.TopHits.AddAggs(sqlQuery)
+
+	// send query
+	res, err := sqlQuery.Execute(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	// get top hits
+	hits, err := m.TopHits.GetTopHits(res)
+	if err != nil {
+		return nil, err
+	}
+
+	// convert to point array
+	points := make([]float32, len(hits)*2)
+	for i, hit := range hits {
+		// get hit x/y in tile coords
+		x, y, ok := m.Bivariate.GetXY(coord, hit)
+		if !ok {
+			return nil, fmt.Errorf("could not parse position from hit: %v", hit)
+		}
+		// add to point array
+		points[i*2] = float32(x)
+		points[i*2+1] = float32(y)
+	}
+
+	// encode and return results
+	return m.Micro.Encode(hits, points)
+}
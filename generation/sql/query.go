@@ -0,0 +1,141 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/unchartedsoftware/veldt/trace"
+)
+
+const (
+	querySpan = "sql.query"
+)
+
+// QueryString represents a SQL implementation of the veldt.Query interface.
+type QueryString interface {
+	Get(*Query) (string, error)
+}
+
+// Query represents a SQL query object. As positional placeholders are bound
+// in the order they appear, parameters are only added by WHERE clauses, which
+// are rendered in the order they are added. Other clauses inline the numeric
+// values they compute.
+type Query struct {
+	Dialect        Dialect
+	QueryArgs      []interface{}
+	WhereClauses   []string
+	GroupByClauses []string
+	Fields         []string
+	Tables         []string
+	OrderByClauses []string
+	RowLimit       uint32
+}
+
+// NewQuery instantiates and returns a new query object.
+func NewQuery(dialect Dialect) (*Query, error) {
+	return &Query{
+		Dialect:        dialect,
+		WhereClauses:   []string{},
+		GroupByClauses: []string{},
+		Fields:         []string{},
+		Tables:         []string{},
+		OrderByClauses: []string{},
+		RowLimit:       0,
+		QueryArgs:      make([]interface{}, 0),
+	}, nil
+}
+
+// Execute sends the query to the database, abandoning it if the context is
+// done, from Go 1.8. The round trip is traced as part of the context's trace.
+func (q *Query) Execute(ctx context.Context, client *sql.DB) (*sql.Rows, error) {
+	ctx, span := trace.Start(ctx, querySpan)
+	defer span.End()
+	queryString := q.GetQuery(false)
+	span.SetAttribute("db.statement", queryString)
+	rows, err := queryRows(ctx, client, queryString, q.QueryArgs)
+	span.SetError(err)
+	return rows, err
+}
+
+// GetQuery returns the query string.
+func (q *Query) GetQuery(nested bool) string {
+	queryString := fmt.Sprintf("SELECT %s", strings.Join(q.Fields, ", "))
+
+	queryString += fmt.Sprintf(" FROM %s", strings.Join(q.Tables, ", "))
+
+	if len(q.WhereClauses) > 0 {
+		queryString += fmt.Sprintf(" WHERE %s", strings.Join(q.WhereClauses, " AND "))
+	}
+
+	if len(q.GroupByClauses) > 0 {
+		queryString += fmt.Sprintf(" GROUP BY %s", strings.Join(q.GroupByClauses, ", "))
+	}
+
+	if len(q.OrderByClauses) > 0 {
+		queryString += fmt.Sprintf(" ORDER BY %s", strings.Join(q.OrderByClauses, ", "))
+	}
+
+	if q.RowLimit > 0 {
+		queryString += " " + q.Dialect.Limit(q.RowLimit)
+	}
+
+	if !nested {
+		queryString = queryString + ";"
+	}
+
+	return queryString
+}
+
+// AddParameter adds a parameter to the query and returns its placeholder.
+func (q *Query) AddParameter(param interface{}) string {
+	q.QueryArgs = append(q.QueryArgs, param)
+	return q.Dialect.Placeholder(len(q.QueryArgs))
+}
+
+// Quote returns the quoted identifier of a `column` or `table.column` field.
+func (q *Query) Quote(field string) string {
+	parts := strings.Split(field, ".")
+	for i, part := range parts {
+		parts[i] = q.Dialect.Quote(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+// Where adds a where clause to the query.
+func (q *Query) Where(clause string) {
+	q.WhereClauses = append(q.WhereClauses, clause)
+}
+
+// GroupBy adds a groupby to the query.
+func (q *Query) GroupBy(clause string) {
+	q.GroupByClauses = append(q.GroupByClauses, clause)
+}
+
+// Select adds a field to the query.
+func (q *Query) Select(field string) {
+	q.Fields = append(q.Fields, field)
+}
+
+// From adds a table to the query.
+func (q *Query) From(table string) {
+	q.Tables = append(q.Tables, table)
+}
+
+// OrderBy adds an order by clause to the query.
+func (q *Query) OrderBy(clause string) {
+	q.OrderByClauses = append(q.OrderByClauses, clause)
+}
+
+// Limit sets the limit to the query.
+func (q *Query) Limit(limit uint32) {
+	q.RowLimit = limit
+}
+
+// literal returns the SQL literal of a computed number, parenthesized such
+// that negative numbers may follow an operator.
+func literal(num float64) string {
+	return "(" + strconv.FormatFloat(num, 'g', -1, 64) + ")"
+}
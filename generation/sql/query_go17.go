@@ -0,0 +1,15 @@
+// +build !go1.8
+
+package sql
+
+import (
+	"context"
+	"database/sql"
+)
+
+// queryRows sends the query string to the database. As database/sql does not
+// accept a context before Go 1.8, the query is not abandoned if the context
+// is done.
+func queryRows(ctx context.Context, client *sql.DB, queryString string, args []interface{}) (*sql.Rows, error) {
+	return client.Query(queryString, args...)
+}
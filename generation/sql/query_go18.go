@@ -0,0 +1,14 @@
+// +build go1.8
+
+package sql
+
+import (
+	"context"
+	"database/sql"
+)
+
+// queryRows sends the query string to the database, abandoning it if the context
+// is done.
+func queryRows(ctx context.Context, client *sql.DB, queryString string, args []interface{}) (*sql.Rows, error) {
+	return client.QueryContext(ctx, queryString, args...)
+}
@@ -0,0 +1,107 @@
+package sql_test
+
+import (
+	"context"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/generation/sql"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+func newQuery(ctor veldt.QueryCtor, params string) veldt.Query {
+	q, err := ctor()
+	Expect(err).To(BeNil())
+	err = q.Parse(JSON(params))
+	Expect(err).To(BeNil())
+	return q
+}
+
+var _ = Describe("Query", func() {
+
+	search := func(query veldt.Query) []string {
+		t := &sql.Tile{Config: cfg}
+		client, sqlQuery, err := t.InitializeTile("test", query)
+		Expect(err).To(BeNil())
+		sqlQuery.Select(sqlQuery.Quote("name"))
+		sqlQuery.OrderBy(sqlQuery.Quote("name"))
+		rows, err := sqlQuery.Execute(context.Background(), client)
+		Expect(err).To(BeNil())
+		defer rows.Close()
+		names := []string{}
+		for rows.Next() {
+			var name string
+			err := rows.Scan(&name)
+			Expect(err).To(BeNil())
+			names = append(names, name)
+		}
+		Expect(rows.Err()).To(BeNil())
+		return names
+	}
+
+	BeforeEach(func() {
+		load(
+			"CREATE TABLE test (name TEXT, age INTEGER, tag TEXT)",
+			`INSERT INTO test VALUES
+				('Alpha', 10, 'a'),
+				('Beta', 20, 'b'),
+				('Gamma', 30, NULL)`,
+		)
+	})
+
+	AfterEach(func() {
+		unload()
+	})
+
+	It("should match all rows when no query is provided", func() {
+		Expect(search(nil)).To(Equal([]string{"Alpha", "Beta", "Gamma"}))
+	})
+
+	It("should match rows equal to a value", func() {
+		q := newQuery(sql.NewEquals, `{"field": "tag", "value": "a"}`)
+		Expect(search(q)).To(Equal([]string{"Alpha"}))
+	})
+
+	It("should match rows containing any of the values", func() {
+		q := newQuery(sql.NewHas, `{"field": "age", "values": [10, 30]}`)
+		Expect(search(q)).To(Equal([]string{"Alpha", "Gamma"}))
+	})
+
+	It("should match rows where the field exists", func() {
+		q := newQuery(sql.NewExists, `{"field": "tag"}`)
+		Expect(search(q)).To(Equal([]string{"Alpha", "Beta"}))
+	})
+
+	It("should match rows within the range", func() {
+		q := newQuery(sql.NewRange, `{"field": "age", "gt": 10, "lte": 30}`)
+		Expect(search(q)).To(Equal([]string{"Beta", "Gamma"}))
+	})
+
+	It("should combine queries with binary and unary expressions", func() {
+		exists := newQuery(sql.NewExists, `{"field": "tag"}`)
+		rng := newQuery(sql.NewRange, `{"field": "age", "gte": 20}`)
+		not, _ := sql.NewUnaryExpression()
+		not.(*sql.UnaryExpression).Query = exists
+		not.(*sql.UnaryExpression).Op = veldt.Not
+		or, _ := sql.NewBinaryExpression()
+		or.(*sql.BinaryExpression).Left = not
+		or.(*sql.BinaryExpression).Op = veldt.Or
+		or.(*sql.BinaryExpression).Right = newQuery(sql.NewEquals, `{"field": "name", "value": "Alpha"}`)
+		and, _ := sql.NewBinaryExpression()
+		and.(*sql.BinaryExpression).Left = or
+		and.(*sql.BinaryExpression).Op = veldt.And
+		and.(*sql.BinaryExpression).Right = rng
+		Expect(search(and)).To(Equal([]string{"Gamma"}))
+	})
+
+	It("should number the parameters of the dialect", func() {
+		t := &sql.Tile{Config: &sql.Config{Dialect: &sql.PostgresDialect{}}}
+		q, err := t.CreateQuery(newQuery(sql.NewRange, `{"field": "age", "gt": 10, "lte": 30}`))
+		Expect(err).To(BeNil())
+		q.From(q.Quote("public.test"))
+		Expect(q.GetQuery(false)).To(ContainSubstring(`FROM "public"."test" WHERE "age" > $1 AND "age" <= $2`))
+		Expect(q.QueryArgs).To(HaveLen(2))
+	})
+})
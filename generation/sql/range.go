@@ -0,0 +1,42 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/query"
+)
+
+// Range represents a SQL range query.
+type Range struct {
+	query.Range
+}
+
+// NewRange instantiates and returns a new query struct.
+func NewRange() (veldt.Query, error) {
+	return &Range{}, nil
+}
+
+// Get adds the parameters to the query and returns the string representation.
+func (q *Range) Get(query *Query) (string, error) {
+	field := query.Quote(q.Field)
+	clauses := []string{}
+	if q.GTE != nil {
+		valueParam := query.AddParameter(q.GTE)
+		clauses = append(clauses, fmt.Sprintf("%s >= %s", field, valueParam))
+	}
+	if q.GT != nil {
+		valueParam := query.AddParameter(q.GT)
+		clauses = append(clauses, fmt.Sprintf("%s > %s", field, valueParam))
+	}
+	if q.LTE != nil {
+		valueParam := query.AddParameter(q.LTE)
+		clauses = append(clauses, fmt.Sprintf("%s <= %s", field, valueParam))
+	}
+	if q.LT != nil {
+		valueParam := query.AddParameter(q.LT)
+		clauses = append(clauses, fmt.Sprintf("%s < %s", field, valueParam))
+	}
+	return strings.Join(clauses, " AND "), nil
+}
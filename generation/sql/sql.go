@@ -0,0 +1,53 @@
+package sql
+
+import (
+	"database/sql"
+	"runtime"
+	"sync"
+)
+
+var (
+	mutex   = sync.Mutex{}
+	clients = make(map[string]*sql.DB)
+)
+
+// Config defines the database details required to establish a connection.
+// The driver must be registered with the database/sql package by importing
+// it, such as `github.com/mattn/go-sqlite3` or `github.com/lib/pq`.
+type Config struct {
+	Driver     string
+	DataSource string
+	Endpoint   string
+	Dialect    Dialect
+}
+
+// GetEndpoint returns the endpoint of the database. As the data source may
+// hold credentials, it defaults to the name of the driver.
+func (cfg *Config) GetEndpoint() string {
+	if cfg.Endpoint != "" {
+		return cfg.Endpoint
+	}
+	return cfg.Driver
+}
+
+// NewClient returns a database handle from the pool. Each handle maintains
+// its own pool of connections.
+func NewClient(cfg *Config) (*sql.DB, error) {
+	key := cfg.Driver + ":" + cfg.DataSource
+	mutex.Lock()
+	client, ok := clients[key]
+	if !ok {
+		db, err := sql.Open(cfg.Driver, cfg.DataSource)
+		if err != nil {
+			mutex.Unlock()
+			runtime.Gosched()
+			return nil, err
+		}
+		db.SetMaxOpenConns(16)
+		clients[key] = db
+		client = db
+	}
+	mutex.Unlock()
+	runtime.Gosched()
+	return client, nil
+}
@@ -0,0 +1,13 @@
+package sql_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestSQL(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "SQL Suite")
+}
@@ -0,0 +1,78 @@
+package sql
+
+import (
+	"context"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// TargetTermCountTile represents a SQL implementation of the target term
+// count tile.
+type TargetTermCountTile struct {
+	Bivariate
+	TargetTerms
+	Tile
+}
+
+// NewTargetTermCountTile instantiates and returns a new tile struct.
+func NewTargetTermCountTile(cfg *Config) veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		t := &TargetTermCountTile{}
+		t.Config = cfg
+		return t, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (t *TargetTermCountTile) Parse(params map[string]interface{}) error {
+	err := t.Bivariate.Parse(params)
+	if err != nil {
+		return err
+	}
+	return t.TargetTerms.Parse(params)
+}
+
+// Schema returns the JSON Schema of the parameters of the tile.
+func (t *TargetTermCountTile) Schema() *schema.Schema {
+	return schema.Merge(t.Bivariate.Schema(), t.TargetTerms.Schema())
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (t *TargetTermCountTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return t.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (t *TargetTermCountTile) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// Initialize the tile processing.
+	client, sqlQuery, err := t.InitializeTile(uri, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// add tiling query
+	sqlQuery = t.Bivariate.AddQuery(coord, sqlQuery)
+
+	// get aggs
+	sqlQuery = t.TargetTerms.AddAggs(sqlQuery)
+
+	// send query
+	res, err := sqlQuery.Execute(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	// get terms
+	terms, err := t.TargetTerms.GetTerms(res)
+	if err != nil {
+		return nil, err
+	}
+
+	// marshal results
+	return json.Marshal(terms)
+}
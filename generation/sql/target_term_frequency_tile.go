@@ -0,0 +1,100 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// TargetTermFrequencyTile represents a SQL implementation of the target term
+// frequency tile.
+type TargetTermFrequencyTile struct {
+	Bivariate
+	TargetTerms
+	Frequency
+	Tile
+}
+
+// NewTargetTermFrequencyTile instantiates and returns a new tile struct.
+func NewTargetTermFrequencyTile(cfg *Config) veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		t := &TargetTermFrequencyTile{}
+		t.Config = cfg
+		return t, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (t *TargetTermFrequencyTile) Parse(params map[string]interface{}) error {
+	err := t.Bivariate.Parse(params)
+	if err != nil {
+		return err
+	}
+	err = t.TargetTerms.Parse(params)
+	if err != nil {
+		return err
+	}
+	return t.Frequency.Parse(params)
+}
+
+// Schema returns the JSON Schema of the parameters of the tile.
+func (t *TargetTermFrequencyTile) Schema() *schema.Schema {
+	return schema.Merge(t.Bivariate.Schema(), t.TargetTerms.Schema(), t.Frequency.Schema())
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (t *TargetTermFrequencyTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return t.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (t *TargetTermFrequencyTile) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// Initialize the tile processing.
+	client, sqlQuery, err := t.InitializeTile(uri, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// add tiling and frequency query
+	sqlQuery = t.Bivariate.AddQuery(coord, sqlQuery)
+	sqlQuery = t.Frequency.AddQuery(sqlQuery)
+
+	// keep only the target terms
+	term := t.TargetTerms.AddQuery(sqlQuery)
+
+	// get aggs
+	sqlQuery.Select(fmt.Sprintf("%s AS term", term))
+	sqlQuery.GroupBy(term)
+	sqlQuery = t.Frequency.AddAggs(sqlQuery)
+
+	// send query
+	res, err := sqlQuery.Execute(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	// get buckets
+	result, err := t.Frequency.GetTermBuckets(res)
+	if err != nil {
+		return nil, err
+	}
+	// every target term is present in the result
+	for _, term := range t.Terms {
+		_, ok := result[term]
+		if !ok {
+			result[term], err = t.Frequency.CreateBuckets(nil)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// marshal results
+	return json.Marshal(result)
+}
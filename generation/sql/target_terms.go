@@ -0,0 +1,53 @@
+package sql
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+// TargetTerms represents a SQL implementation of the target terms tile. The
+// terms field holds an array, which is a JSON array for SQLite and MySQL.
+type TargetTerms struct {
+	tile.TargetTerms
+}
+
+// AddQuery expands the terms of each row, keeping only the target terms, and
+// returns the expression of the term.
+func (t *TargetTerms) AddQuery(query *Query) string {
+	term := addTerms(query, t.TermsField)
+	termParams := make([]string, len(t.Terms))
+	for i, value := range t.Terms {
+		termParams[i] = query.AddParameter(value)
+	}
+	query.Where(fmt.Sprintf("%s IN (%s)", term, strings.Join(termParams, ", ")))
+	return term
+}
+
+// AddAggs adds the tiling aggregations to the provided query object,
+// selecting the `term` and `term_count` of each target term.
+func (t *TargetTerms) AddAggs(query *Query) *Query {
+	term := t.AddQuery(query)
+	query.Select(fmt.Sprintf("%s AS term", term))
+	query.Select("COUNT(*) AS term_count")
+	query.GroupBy(term)
+	return query
+}
+
+// GetTerms parses the result of the terms query into a map of term -> count.
+// Every target term is present in the result, even if no rows contain it.
+func (t *TargetTerms) GetTerms(rows *sql.Rows) (map[string]uint32, error) {
+	counts, err := getTermCounts(rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, term := range t.Terms {
+		_, ok := counts[term]
+		if !ok {
+			counts[term] = 0
+		}
+	}
+	return counts, nil
+}
@@ -0,0 +1,77 @@
+package sql
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/resilience"
+)
+
+// Tile represents a SQL tile type.
+type Tile struct {
+	Config *Config
+	policy *resilience.Policy
+}
+
+// SetPolicy sets the resilience policy of the queries made to the database.
+func (t *Tile) SetPolicy(policy *resilience.Policy) {
+	t.policy = policy
+}
+
+// GetPolicy returns the resilience policy of the queries made to the
+// database, creating a default policy for the tile if none has been set.
+func (t *Tile) GetPolicy() *resilience.Policy {
+	if t.policy == nil {
+		t.policy = resilience.NewDefaultPolicy()
+	}
+	return t.policy
+}
+
+// GetEndpoint returns the endpoint of the database.
+func (t *Tile) GetEndpoint() string {
+	return t.Config.GetEndpoint()
+}
+
+// CreateQuery creates the underlying SQL query object.
+func (t *Tile) CreateQuery(query veldt.Query) (*Query, error) {
+	// create root query
+	root, err := NewQuery(t.Config.Dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	// add filter query
+	if query != nil {
+		// type assert
+		sqlQuery, ok := query.(QueryString)
+		if !ok {
+			return nil, fmt.Errorf("query is not sql.Query")
+		}
+		// get underlying query
+		q, err := sqlQuery.Get(root)
+		if err != nil {
+			return nil, err
+		}
+
+		root.Where(q)
+	}
+
+	return root, nil
+}
+
+// InitializeTile initializes the SQL tile type.
+func (t *Tile) InitializeTile(uri string, query veldt.Query) (*sql.DB, *Query, error) {
+	// get client
+	client, err := NewClient(t.Config)
+	if err != nil {
+		return nil, nil, err
+	}
+	// create root query
+	sqlQuery, err := t.CreateQuery(query)
+	if err != nil {
+		return nil, nil, err
+	}
+	sqlQuery.From(sqlQuery.Quote(uri))
+	return client, sqlQuery, nil
+}
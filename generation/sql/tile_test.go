@@ -0,0 +1,254 @@
+package sql_test
+
+import (
+	"encoding/binary"
+	"encoding/json"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/generation/sql"
+	"github.com/unchartedsoftware/veldt/resilience"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+const (
+	bivariate = `
+		"xField": "x",
+		"yField": "y",
+		"left": 0,
+		"right": 256,
+		"bottom": 0,
+		"top": 256,
+		"resolution": 4`
+)
+
+var cfg = &sql.Config{
+	Driver:     "sqlite3",
+	DataSource: "file:veldt?mode=memory&cache=shared",
+	Dialect:    &sql.SQLiteDialect{},
+}
+
+// load creates the test table from the provided statements.
+func load(statements ...string) {
+	client, err := sql.NewClient(cfg)
+	Expect(err).To(BeNil())
+	for _, statement := range statements {
+		_, err = client.Exec(statement)
+		Expect(err).To(BeNil())
+	}
+}
+
+func unload() {
+	load("DROP TABLE IF EXISTS test")
+}
+
+func createTile(ctor veldt.TileCtor, params string) []byte {
+	t, err := ctor()
+	Expect(err).To(BeNil())
+	err = t.Parse(JSON(params))
+	Expect(err).To(BeNil())
+	data, err := t.Create("test", &binning.TileCoord{Z: 0, X: 0, Y: 0}, nil)
+	Expect(err).To(BeNil())
+	return data
+}
+
+func unmarshal(data []byte) interface{} {
+	var res interface{}
+	err := json.Unmarshal(data, &res)
+	Expect(err).To(BeNil())
+	return res
+}
+
+var _ = Describe("Tile", func() {
+
+	BeforeEach(func() {
+		load(
+			"CREATE TABLE test (x REAL, y REAL, term TEXT, time INTEGER, rank REAL)",
+			`INSERT INTO test VALUES
+				(10, 10, '["a"]', 0, 1),
+				(20, 20, '["a", "b"]', 1000, 3),
+				(100, 10, '["b"]', 2500, 2),
+				(300, 10, '["c"]', 0, 4)`,
+		)
+	})
+
+	AfterEach(func() {
+		unload()
+	})
+
+	Describe("HeatmapTile", func() {
+		It("should count the rows of each bin", func() {
+			data := createTile(sql.NewHeatmapTile(cfg), `{`+bivariate+`}`)
+			Expect(data).To(HaveLen(16 * 4))
+			Expect(binary.LittleEndian.Uint32(data[0:4])).To(Equal(uint32(2)))
+			Expect(binary.LittleEndian.Uint32(data[4:8])).To(Equal(uint32(1)))
+		})
+	})
+
+	Describe("CountTile", func() {
+		It("should count the rows within the tile", func() {
+			data := createTile(sql.NewCountTile(cfg), `{`+bivariate+`}`)
+			Expect(string(data)).To(Equal(`{"count":3}`))
+		})
+	})
+
+	Describe("FrequencyTile", func() {
+		It("should bucket the rows, including empty buckets", func() {
+			data := createTile(sql.NewFrequencyTile(cfg), `{`+bivariate+`,
+				"frequencyField": "time",
+				"gte": 0,
+				"lt": 4000,
+				"interval": "1s"
+			}`)
+			Expect(unmarshal(data)).To(Equal([]interface{}{
+				map[string]interface{}{"timestamp": 0.0, "count": 1.0},
+				map[string]interface{}{"timestamp": 1000.0, "count": 1.0},
+				map[string]interface{}{"timestamp": 2000.0, "count": 1.0},
+				map[string]interface{}{"timestamp": 3000.0, "count": 0.0},
+				map[string]interface{}{"timestamp": 4000.0, "count": 0.0},
+			}))
+		})
+
+		It("should return an error if the range spans too many intervals", func() {
+			t, err := sql.NewFrequencyTile(cfg)()
+			Expect(err).To(BeNil())
+			err = t.Parse(JSON(`{` + bivariate + `,
+				"frequencyField": "time",
+				"gte": 0,
+				"lt": 1e15,
+				"interval": "1ms"
+			}`))
+			Expect(err).To(BeNil())
+			_, err = t.Create("test", &binning.TileCoord{Z: 0, X: 0, Y: 0}, nil)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("TopTermCountTile", func() {
+		It("should count the most frequent terms, breaking ties by term", func() {
+			data := createTile(sql.NewTopTermCountTile(cfg), `{`+bivariate+`,
+				"termsField": "term",
+				"termsCount": 1
+			}`)
+			Expect(unmarshal(data)).To(Equal(map[string]interface{}{
+				"a": 2.0,
+			}))
+		})
+	})
+
+	Describe("TargetTermCountTile", func() {
+		It("should count every target term", func() {
+			data := createTile(sql.NewTargetTermCountTile(cfg), `{`+bivariate+`,
+				"termsField": "term",
+				"terms": ["a", "c"]
+			}`)
+			Expect(unmarshal(data)).To(Equal(map[string]interface{}{
+				"a": 2.0,
+				"c": 0.0,
+			}))
+		})
+	})
+
+	Describe("TopTermFrequencyTile", func() {
+		It("should bucket the rows of the most frequent terms", func() {
+			data := createTile(sql.NewTopTermFrequencyTile(cfg), `{`+bivariate+`,
+				"termsField": "term",
+				"termsCount": 1,
+				"frequencyField": "time",
+				"gte": 0,
+				"lt": 3000,
+				"interval": "1s"
+			}`)
+			Expect(unmarshal(data)).To(Equal(map[string]interface{}{
+				"a": []interface{}{
+					map[string]interface{}{"timestamp": 0.0, "count": 1.0},
+					map[string]interface{}{"timestamp": 1000.0, "count": 1.0},
+					map[string]interface{}{"timestamp": 2000.0, "count": 0.0},
+					map[string]interface{}{"timestamp": 3000.0, "count": 0.0},
+				},
+			}))
+		})
+	})
+
+	Describe("TargetTermFrequencyTile", func() {
+		It("should bucket the rows of every target term", func() {
+			data := createTile(sql.NewTargetTermFrequencyTile(cfg), `{`+bivariate+`,
+				"termsField": "term",
+				"terms": ["a", "c"],
+				"frequencyField": "time",
+				"gte": 0,
+				"lt": 1000,
+				"interval": "1s"
+			}`)
+			Expect(unmarshal(data)).To(Equal(map[string]interface{}{
+				"a": []interface{}{
+					map[string]interface{}{"timestamp": 0.0, "count": 1.0},
+					map[string]interface{}{"timestamp": 1000.0, "count": 0.0},
+				},
+				"c": []interface{}{
+					map[string]interface{}{"timestamp": 0.0, "count": 0.0},
+					map[string]interface{}{"timestamp": 1000.0, "count": 0.0},
+				},
+			}))
+		})
+	})
+
+	Describe("BinnedTopHits", func() {
+		It("should return the sorted top hits of each bin", func() {
+			data := createTile(sql.NewBinnedTopHits(cfg), `{`+bivariate+`,
+				"sortField": "rank",
+				"sortOrder": "desc",
+				"hitsCount": 1,
+				"includeFields": ["rank"]
+			}`)
+			res := unmarshal(data).(map[string]interface{})
+			Expect(res["points"]).To(Equal([]interface{}{32.0, 32.0, 96.0, 32.0}))
+			hits := res["hits"].([]interface{})
+			Expect(hits[0]).To(Equal([]interface{}{
+				map[string]interface{}{"rank": 3.0},
+			}))
+			Expect(hits[1]).To(Equal([]interface{}{
+				map[string]interface{}{"rank": 2.0},
+			}))
+			Expect(hits[2]).To(BeNil())
+		})
+	})
+
+	Describe("GetPolicy", func() {
+		It("should not share the default policy between tiles", func() {
+			a, err := sql.NewCountTile(cfg)()
+			Expect(err).To(BeNil())
+			b, err := sql.NewCountTile(cfg)()
+			Expect(err).To(BeNil())
+			policy := a.(resilience.Resilient).GetPolicy()
+			Expect(policy).NotTo(BeNil())
+			Expect(a.(resilience.Resilient).GetPolicy() == policy).To(BeTrue())
+			Expect(b.(resilience.Resilient).GetPolicy() == policy).To(BeFalse())
+		})
+	})
+
+	Describe("DefaultMeta", func() {
+		It("should infer the type and extrema of each column", func() {
+			m, err := sql.NewDefaultMeta(cfg)()
+			Expect(err).To(BeNil())
+			data, err := m.Create("test")
+			Expect(err).To(BeNil())
+			res := unmarshal(data).(map[string]interface{})
+			Expect(res["x"]).To(Equal(map[string]interface{}{
+				"type": "REAL",
+				"extrema": map[string]interface{}{
+					"min": 10.0,
+					"max": 300.0,
+				},
+			}))
+			Expect(res["term"]).To(Equal(map[string]interface{}{
+				"type": "TEXT",
+			}))
+		})
+	})
+})
@@ -0,0 +1,89 @@
+package sql
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+// TopHits represents a SQL implementation of the top hits tile.
+type TopHits struct {
+	tile.TopHits
+}
+
+// AddAggs adds the tiling aggregations to the provided query object.
+func (t *TopHits) AddAggs(query *Query) *Query {
+	// select the top N rows when sorted, returning only the included fields
+	t.AddFields(query)
+	// sort
+	if t.SortField != "" {
+		query.OrderBy(t.GetOrderBy(query))
+	}
+	query.Limit(uint32(t.HitsCount))
+	return query
+}
+
+// AddFields selects the included fields, or every field of the table if none
+// are included.
+func (t *TopHits) AddFields(query *Query) *Query {
+	if len(t.IncludeFields) == 0 {
+		// qualify the wildcard, as it may be followed by other fields
+		query.Select(fmt.Sprintf("%s.*", query.Tables[0]))
+		return query
+	}
+	for _, field := range t.IncludeFields {
+		query.Select(query.Quote(field))
+	}
+	return query
+}
+
+// GetOrderBy returns the ordering of the hits.
+func (t *TopHits) GetOrderBy(query *Query) string {
+	if t.SortOrder == "desc" {
+		return fmt.Sprintf("%s DESC", query.Quote(t.SortField))
+	}
+	return query.Quote(t.SortField)
+}
+
+// GetTopHits returns the individual hits from the provided rows.
+func (t *TopHits) GetTopHits(rows *sql.Rows) ([]map[string]interface{}, error) {
+	defer rows.Close()
+	hits := make([]map[string]interface{}, 0)
+	for rows.Next() {
+		hit, err := scanHit(rows)
+		if err != nil {
+			return nil, err
+		}
+		hits = append(hits, hit)
+	}
+	return hits, rows.Err()
+}
+
+// scanHit returns the current row keyed by column name. Text returned as
+// bytes is converted to strings.
+func scanHit(rows *sql.Rows) (map[string]interface{}, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	err = rows.Scan(pointers...)
+	if err != nil {
+		return nil, err
+	}
+	hit := make(map[string]interface{}, len(columns))
+	for i, column := range columns {
+		bytes, ok := values[i].([]byte)
+		if ok {
+			hit[column] = string(bytes)
+		} else {
+			hit[column] = values[i]
+		}
+	}
+	return hit, nil
+}
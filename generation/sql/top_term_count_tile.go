@@ -0,0 +1,76 @@
+package sql
+
+import (
+	"context"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// TopTermCountTile represents a SQL implementation of the top term count
+// tile.
+type TopTermCountTile struct {
+	Bivariate
+	TopTerms
+	Tile
+}
+
+// NewTopTermCountTile instantiates and returns a new tile struct.
+func NewTopTermCountTile(cfg *Config) veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		t := &TopTermCountTile{}
+		t.Config = cfg
+		return t, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (t *TopTermCountTile) Parse(params map[string]interface{}) error {
+	err := t.Bivariate.Parse(params)
+	if err != nil {
+		return err
+	}
+	return t.TopTerms.Parse(params)
+}
+
+// Schema returns the JSON Schema of the parameters of the tile.
+func (t *TopTermCountTile) Schema() *schema.Schema {
+	return schema.Merge(t.Bivariate.Schema(), t.TopTerms.Schema())
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (t *TopTermCountTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return t.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (t *TopTermCountTile) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// Initialize the tile processing.
+	client, sqlQuery, err := t.InitializeTile(uri, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// add tiling query
+	sqlQuery = t.Bivariate.AddQuery(coord, sqlQuery)
+
+	// get agg
+	sqlQuery = t.TopTerms.AddAggs(sqlQuery)
+
+	// send query
+	res, err := sqlQuery.Execute(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	// marshal results
+	counts, err := t.TopTerms.GetTerms(res)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(counts)
+}
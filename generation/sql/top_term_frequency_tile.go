@@ -0,0 +1,121 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// TopTermFrequencyTile represents a SQL implementation of the top term
+// frequency tile. The most frequent terms are queried before their
+// frequencies.
+type TopTermFrequencyTile struct {
+	Bivariate
+	TopTerms
+	Frequency
+	Tile
+}
+
+// NewTopTermFrequencyTile instantiates and returns a new tile struct.
+func NewTopTermFrequencyTile(cfg *Config) veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		t := &TopTermFrequencyTile{}
+		t.Config = cfg
+		return t, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the tiles attributes.
+func (t *TopTermFrequencyTile) Parse(params map[string]interface{}) error {
+	err := t.Bivariate.Parse(params)
+	if err != nil {
+		return err
+	}
+	err = t.TopTerms.Parse(params)
+	if err != nil {
+		return err
+	}
+	return t.Frequency.Parse(params)
+}
+
+// Schema returns the JSON Schema of the parameters of the tile.
+func (t *TopTermFrequencyTile) Schema() *schema.Schema {
+	return schema.Merge(t.Bivariate.Schema(), t.TopTerms.Schema(), t.Frequency.Schema())
+}
+
+// Create generates a tile from the provided URI, tile coordinate and query
+// parameters.
+func (t *TopTermFrequencyTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return t.CreateContext(context.Background(), uri, coord, query)
+}
+
+// CreateContext generates a tile from the provided URI, tile coordinate and
+// query parameters, abandoning the request if the context is done.
+func (t *TopTermFrequencyTile) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	// Initialize the terms query.
+	client, termsQuery, err := t.InitializeTile(uri, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// add tiling and frequency query
+	termsQuery = t.Bivariate.AddQuery(coord, termsQuery)
+	termsQuery = t.Frequency.AddQuery(termsQuery)
+
+	// get terms
+	termsQuery = t.TopTerms.AddAggs(termsQuery)
+	res, err := termsQuery.Execute(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := t.TopTerms.GetTerms(res)
+	if err != nil {
+		return nil, err
+	}
+	if len(counts) == 0 {
+		return json.Marshal(map[string]interface{}{})
+	}
+
+	// Initialize the frequency query.
+	_, sqlQuery, err := t.InitializeTile(uri, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// add tiling and frequency query
+	sqlQuery = t.Bivariate.AddQuery(coord, sqlQuery)
+	sqlQuery = t.Frequency.AddQuery(sqlQuery)
+
+	// keep only the top terms
+	term := addTerms(sqlQuery, t.TermsField)
+	termParams := make([]string, 0, len(counts))
+	for value := range counts {
+		termParams = append(termParams, sqlQuery.AddParameter(value))
+	}
+	sqlQuery.Where(fmt.Sprintf("%s IN (%s)", term, strings.Join(termParams, ", ")))
+
+	// get aggs
+	sqlQuery.Select(fmt.Sprintf("%s AS term", term))
+	sqlQuery.GroupBy(term)
+	sqlQuery = t.Frequency.AddAggs(sqlQuery)
+
+	// send query
+	res, err = sqlQuery.Execute(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	// get buckets
+	result, err := t.Frequency.GetTermBuckets(res)
+	if err != nil {
+		return nil, err
+	}
+
+	// marshal results
+	return json.Marshal(result)
+}
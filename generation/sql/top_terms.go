@@ -0,0 +1,59 @@
+package sql
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt/tile"
+)
+
+// TopTerms represents a SQL implementation of the top terms tile. The terms
+// field holds an array, which is a JSON array for SQLite and MySQL.
+type TopTerms struct {
+	tile.TopTerms
+}
+
+// AddAggs adds the tiling aggregations to the provided query object,
+// selecting the `term` and `term_count` of the most frequent terms. Ties are
+// broken by term order.
+func (t *TopTerms) AddAggs(query *Query) *Query {
+	term := addTerms(query, t.TermsField)
+	query.Select(fmt.Sprintf("%s AS term", term))
+	query.Select("COUNT(*) AS term_count")
+	query.GroupBy(term)
+	query.OrderBy("term_count DESC")
+	query.OrderBy(term)
+	query.Limit(uint32(t.TermsCount))
+	return query
+}
+
+// GetTerms parses the result of the terms query into a map of term -> count.
+func (t *TopTerms) GetTerms(rows *sql.Rows) (map[string]uint32, error) {
+	return getTermCounts(rows)
+}
+
+// addTerms expands the array field of each row of the table into a row per
+// term, and returns the expression of the term.
+func addTerms(query *Query, field string) string {
+	from, term := query.Dialect.Unnest(
+		fmt.Sprintf("%s.%s", query.Tables[0], query.Quote(field)),
+		"terms")
+	query.From(from)
+	return term
+}
+
+// getTermCounts parses rows of terms and counts into a map of term -> count.
+func getTermCounts(rows *sql.Rows) (map[string]uint32, error) {
+	defer rows.Close()
+	counts := make(map[string]uint32)
+	for rows.Next() {
+		var term string
+		var count uint32
+		err := rows.Scan(&term, &count)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing terms: %v", err)
+		}
+		counts[term] = count
+	}
+	return counts, rows.Err()
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 
+	"github.com/unchartedsoftware/veldt/schema"
 	jsonUtil "github.com/unchartedsoftware/veldt/util/json"
 )
 
@@ -51,6 +52,16 @@ func (b *Bounds) Parse(params map[string]interface{}) error {
 	return nil
 }
 
+// Schema returns the JSON Schema of the parameters of the bounds.
+func (b *Bounds) Schema() *schema.Schema {
+	return schema.Object(map[string]*schema.Schema{
+		"left":   schema.Number("The x value of the left edge of the bounds."),
+		"right":  schema.Number("The x value of the right edge of the bounds."),
+		"bottom": schema.Number("The y value of the bottom edge of the bounds."),
+		"top":    schema.Number("The y value of the top edge of the bounds."),
+	}, "left", "right", "bottom", "top")
+}
+
 // MinX returns the minimum x value for the bounds.
 func (b Bounds) MinX() float64 {
 	return math.Min(b.Left, b.Right)
@@ -0,0 +1,27 @@
+package geometry
+
+import (
+	"math"
+)
+
+const (
+	// EarthRadius is the mean radius of the earth in meters.
+	EarthRadius = 6371008.8
+)
+
+// Distance returns the euclidean distance between two points.
+func Distance(x1, y1, x2, y2 float64) float64 {
+	return math.Hypot(x2-x1, y2-y1)
+}
+
+// GreatCircleDistance returns the distance in meters between two points of
+// longitude and latitude, by the haversine formula.
+func GreatCircleDistance(lon1, lat1, lon2, lat2 float64) float64 {
+	phi1 := lat1 * math.Pi / 180
+	phi2 := lat2 * math.Pi / 180
+	dPhi := phi2 - phi1
+	dLambda := (lon2 - lon1) * math.Pi / 180
+	a := math.Pow(math.Sin(dPhi/2), 2) +
+		math.Cos(phi1)*math.Cos(phi2)*math.Pow(math.Sin(dLambda/2), 2)
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
@@ -0,0 +1,134 @@
+package geometry
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/unchartedsoftware/veldt/schema"
+)
+
+// Polygon represents a polygon of an exterior ring and any number of interior
+// rings, or holes, as specified by a GeoJSON Polygon geometry. Each ring is
+// closed, such that its first and last coordinates are equal.
+type Polygon struct {
+	Rings [][]Coord
+}
+
+// Parse parses the provided GeoJSON Polygon geometry and populates the
+// polygon attributes.
+//
+// Ex:
+//     {
+//         "type": "Polygon",
+//         "coordinates": [
+//             [[0, 0], [10, 0], [10, 10], [0, 10], [0, 0]]
+//         ]
+//     }
+//
+func (p *Polygon) Parse(params map[string]interface{}) error {
+	typ, ok := params["type"].(string)
+	if !ok || typ != "Polygon" {
+		return fmt.Errorf("`type` of polygon is not `Polygon`")
+	}
+	coords, ok := params["coordinates"].([]interface{})
+	if !ok || len(coords) == 0 {
+		return fmt.Errorf("`coordinates` of polygon are not an array of rings")
+	}
+	rings := make([][]Coord, len(coords))
+	for i, c := range coords {
+		ring, err := parseRing(c)
+		if err != nil {
+			return fmt.Errorf("ring %d of polygon %v", i, err)
+		}
+		rings[i] = ring
+	}
+	p.Rings = rings
+	return nil
+}
+
+func parseRing(arg interface{}) ([]Coord, error) {
+	positions, ok := arg.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("is not an array of positions")
+	}
+	if len(positions) < 4 {
+		return nil, fmt.Errorf("has fewer than 4 positions")
+	}
+	ring := make([]Coord, len(positions))
+	for i, arg := range positions {
+		position, ok := arg.([]interface{})
+		if !ok || len(position) < 2 {
+			return nil, fmt.Errorf("position %d is not an array of x and y", i)
+		}
+		x, xOk := position[0].(float64)
+		y, yOk := position[1].(float64)
+		if !xOk || !yOk {
+			return nil, fmt.Errorf("position %d is not an array of x and y", i)
+		}
+		ring[i] = Coord{
+			X: x,
+			Y: y,
+		}
+	}
+	if ring[0] != ring[len(ring)-1] {
+		return nil, fmt.Errorf("is not closed")
+	}
+	return ring, nil
+}
+
+// Schema returns the JSON Schema of the GeoJSON Polygon geometry.
+func (p *Polygon) Schema() *schema.Schema {
+	position := schema.Array(schema.Number(""), "A position of x and y, or longitude and latitude.").WithMinItems(2)
+	ring := schema.Array(position, "A closed ring of positions.").WithMinItems(4)
+	return schema.Object(map[string]*schema.Schema{
+		"type":        schema.String("The GeoJSON geometry type.").WithEnum("Polygon"),
+		"coordinates": schema.Array(ring, "The exterior ring, followed by any interior rings.").WithMinItems(1),
+	}, "type", "coordinates")
+}
+
+// Contains returns whether the point lies within the polygon, and outside of
+// its holes, by the even-odd rule.
+func (p *Polygon) Contains(x, y float64) bool {
+	inside := false
+	for _, ring := range p.Rings {
+		for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+			a, b := ring[i], ring[j]
+			if (a.Y > y) != (b.Y > y) &&
+				x < (b.X-a.X)*(y-a.Y)/(b.Y-a.Y)+a.X {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// Bounds returns the bounds of the exterior ring of the polygon.
+func (p *Polygon) Bounds() *Bounds {
+	bounds := NewBounds(math.Inf(1), math.Inf(-1), math.Inf(1), math.Inf(-1))
+	if len(p.Rings) == 0 {
+		return bounds
+	}
+	for _, coord := range p.Rings[0] {
+		bounds.Left = math.Min(bounds.Left, coord.X)
+		bounds.Right = math.Max(bounds.Right, coord.X)
+		bounds.Bottom = math.Min(bounds.Bottom, coord.Y)
+		bounds.Top = math.Max(bounds.Top, coord.Y)
+	}
+	return bounds
+}
+
+// MarshalJSON returns the polygon as a GeoJSON Polygon geometry.
+func (p *Polygon) MarshalJSON() ([]byte, error) {
+	coords := make([][][]float64, len(p.Rings))
+	for i, ring := range p.Rings {
+		coords[i] = make([][]float64, len(ring))
+		for j, coord := range ring {
+			coords[i][j] = []float64{coord.X, coord.Y}
+		}
+	}
+	return json.Marshal(map[string]interface{}{
+		"type":        "Polygon",
+		"coordinates": coords,
+	})
+}
@@ -0,0 +1,108 @@
+package geometry_test
+
+import (
+	"github.com/unchartedsoftware/veldt/geometry"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("Polygon", func() {
+
+	var polygon *geometry.Polygon
+
+	BeforeEach(func() {
+		polygon = &geometry.Polygon{}
+	})
+
+	Describe("Parse", func() {
+		It("should parse properties from the params argument", func() {
+			params := JSON(
+				`{
+					"type": "Polygon",
+					"coordinates": [
+						[[0, 0], [10, 0], [10, 10], [0, 10], [0, 0]],
+						[[4, 4], [6, 4], [6, 6], [4, 6], [4, 4]]
+					]
+				}`)
+			err := polygon.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(len(polygon.Rings)).To(Equal(2))
+			Expect(len(polygon.Rings[0])).To(Equal(5))
+			Expect(polygon.Rings[0][1]).To(Equal(geometry.Coord{X: 10, Y: 0}))
+		})
+
+		It("should return an error if `type` property is not `Polygon`", func() {
+			params := JSON(
+				`{
+					"type": "Point",
+					"coordinates": [0, 0]
+				}`)
+			err := polygon.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should return an error if a ring is not closed", func() {
+			params := JSON(
+				`{
+					"type": "Polygon",
+					"coordinates": [
+						[[0, 0], [10, 0], [10, 10], [0, 10], [0, 1]]
+					]
+				}`)
+			err := polygon.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should return an error if a ring has fewer than four positions", func() {
+			params := JSON(
+				`{
+					"type": "Polygon",
+					"coordinates": [
+						[[0, 0], [10, 0], [0, 0]]
+					]
+				}`)
+			err := polygon.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("Contains", func() {
+		It("should return whether the point is within the polygon and outside its holes", func() {
+			params := JSON(
+				`{
+					"type": "Polygon",
+					"coordinates": [
+						[[0, 0], [10, 0], [10, 10], [0, 10], [0, 0]],
+						[[4, 4], [6, 4], [6, 6], [4, 6], [4, 4]]
+					]
+				}`)
+			err := polygon.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(polygon.Contains(2, 2)).To(Equal(true))
+			Expect(polygon.Contains(5, 5)).To(Equal(false))
+			Expect(polygon.Contains(12, 5)).To(Equal(false))
+		})
+	})
+
+	Describe("Bounds", func() {
+		It("should return the bounds of the exterior ring", func() {
+			params := JSON(
+				`{
+					"type": "Polygon",
+					"coordinates": [
+						[[-2, 1], [3, 1], [3, 8], [-2, 1]]
+					]
+				}`)
+			err := polygon.Parse(params)
+			Expect(err).To(BeNil())
+			bounds := polygon.Bounds()
+			Expect(bounds.MinX()).To(Equal(-2.0))
+			Expect(bounds.MaxX()).To(Equal(3.0))
+			Expect(bounds.MinY()).To(Equal(1.0))
+			Expect(bounds.MaxY()).To(Equal(8.0))
+		})
+	})
+
+})
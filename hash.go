@@ -0,0 +1,206 @@
+package veldt
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+)
+
+const (
+	// hashVersion is mixed into every hash, and must be incremented whenever
+	// the canonical form of requests or pipelines changes, so that data
+	// stored under the previous form is no longer retrieved.
+	hashVersion = 2
+	// maxCanonicalDepth limits the depth of the canonical form of structs,
+	// guarding against cyclic references.
+	maxCanonicalDepth = 32
+)
+
+// The kinds of registered types, used to key their identities.
+const (
+	tileKind   = "tile"
+	metaKind   = "meta"
+	queryKind  = "query"
+	renderKind = "render"
+	binaryKind = "binary"
+	unaryKind  = "unary"
+	storeKind  = "store"
+)
+
+// Normalizer represents a tile, query, metadata or renderer type with
+// parameters that have more than one equivalent JSON representation, such as
+// arrays whose order is insignificant. Normalize is called with the JSON
+// parameters after they have been parsed, and rewrites them into a single
+// representation, such that equivalent requests share a hash.
+type Normalizer interface {
+	Normalize(map[string]interface{})
+}
+
+// normalize normalizes the parameters if the parsed type supports it.
+func normalize(parsed interface{}, params interface{}) {
+	normalizer, ok := parsed.(Normalizer)
+	if !ok {
+		return
+	}
+	p, ok := params.(map[string]interface{})
+	if ok {
+		normalizer.Normalize(p)
+	}
+}
+
+// getDigest returns the versioned hex SHA-256 digest of the provided parts.
+func getDigest(parts ...string) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "v%d", hashVersion)
+	for _, part := range parts {
+		// length prefix each part so that no two lists of parts collide
+		fmt.Fprintf(hash, ":%d:%s", len(part), part)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// getIdentityKey returns the key of the identity of the type registered
+// under the kind and ID.
+func getIdentityKey(kind string, id string) string {
+	return kind + ":" + id
+}
+
+// getCtorIdentity returns the identity of a registered constructor, formed
+// from the name of the constructor function and the canonical form of an
+// unparsed instance, which includes any configuration it was registered
+// with, such as the host of a database.
+func getCtorIdentity(ctor interface{}) string {
+	fn := reflect.ValueOf(ctor)
+	if fn.Kind() != reflect.Func || fn.IsNil() {
+		return ""
+	}
+	return getFuncName(ctor) + getCanonical(getInstance(ctor))
+}
+
+// getInstance returns an unparsed instance of the type of a registered
+// constructor, or nil if it cannot be instantiated.
+func getInstance(ctor interface{}) interface{} {
+	fn := reflect.ValueOf(ctor)
+	if fn.Kind() != reflect.Func || fn.IsNil() {
+		return nil
+	}
+	if fn.Type().NumIn() != 0 || fn.Type().NumOut() == 0 {
+		return nil
+	}
+	return fn.Call(nil)[0].Interface()
+}
+
+// getFuncName returns the name of the function, such as
+// `github.com/unchartedsoftware/veldt/generation/elastic.NewHeatmapTile.func1`.
+func getFuncName(fn interface{}) string {
+	val := reflect.ValueOf(fn)
+	if val.Kind() != reflect.Func || val.IsNil() {
+		return ""
+	}
+	f := runtime.FuncForPC(val.Pointer())
+	if f == nil {
+		return ""
+	}
+	return f.Name()
+}
+
+// getCanonical returns the canonical form of the exported fields of a value,
+// including the type names of any structs. Map keys are sorted, while channels
+// and functions are represented by their type only.
+func getCanonical(val interface{}) string {
+	buffer := &bytes.Buffer{}
+	writeCanonical(buffer, reflect.ValueOf(val), 0)
+	return buffer.String()
+}
+
+func writeCanonical(buffer *bytes.Buffer, val reflect.Value, depth int) {
+	if depth > maxCanonicalDepth {
+		buffer.WriteString("...")
+		return
+	}
+	if !val.IsValid() {
+		buffer.WriteString("null")
+		return
+	}
+	switch val.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if val.IsNil() {
+			buffer.WriteString("null")
+			return
+		}
+		writeCanonical(buffer, val.Elem(), depth+1)
+	case reflect.Struct:
+		typ := val.Type()
+		buffer.WriteString(typ.PkgPath() + "." + typ.Name() + "{")
+		first := true
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if field.PkgPath != "" && !field.Anonymous {
+				// unexported fields hold derived or cached state
+				continue
+			}
+			if !first {
+				buffer.WriteString(",")
+			}
+			first = false
+			buffer.WriteString(field.Name + ":")
+			writeCanonical(buffer, val.Field(i), depth+1)
+		}
+		buffer.WriteString("}")
+	case reflect.Map:
+		keys := make([]string, 0, val.Len())
+		values := make(map[string]reflect.Value, val.Len())
+		for _, key := range val.MapKeys() {
+			k := getCanonicalValue(key, depth+1)
+			keys = append(keys, k)
+			values[k] = val.MapIndex(key)
+		}
+		sort.Strings(keys)
+		buffer.WriteString("{")
+		for i, key := range keys {
+			if i > 0 {
+				buffer.WriteString(",")
+			}
+			buffer.WriteString(key + ":")
+			writeCanonical(buffer, values[key], depth+1)
+		}
+		buffer.WriteString("}")
+	case reflect.Slice, reflect.Array:
+		if val.Kind() == reflect.Slice && val.IsNil() {
+			buffer.WriteString("null")
+			return
+		}
+		buffer.WriteString("[")
+		for i := 0; i < val.Len(); i++ {
+			if i > 0 {
+				buffer.WriteString(",")
+			}
+			writeCanonical(buffer, val.Index(i), depth+1)
+		}
+		buffer.WriteString("]")
+	case reflect.String:
+		buffer.WriteString(strconv.Quote(val.String()))
+	case reflect.Bool:
+		buffer.WriteString(strconv.FormatBool(val.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		buffer.WriteString(strconv.FormatInt(val.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		buffer.WriteString(strconv.FormatUint(val.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		buffer.WriteString(strconv.FormatFloat(val.Float(), 'g', -1, 64))
+	default:
+		// channels, functions and unsafe pointers
+		buffer.WriteString(val.Type().String())
+	}
+}
+
+func getCanonicalValue(val reflect.Value, depth int) string {
+	buffer := &bytes.Buffer{}
+	writeCanonical(buffer, val, depth)
+	return buffer.String()
+}
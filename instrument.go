@@ -0,0 +1,115 @@
+package veldt
+
+import (
+	"context"
+	"path"
+	"reflect"
+	"time"
+
+	"github.com/unchartedsoftware/veldt/metrics"
+	"github.com/unchartedsoftware/veldt/resilience"
+	"github.com/unchartedsoftware/veldt/trace"
+	"github.com/unchartedsoftware/veldt/util/queue"
+)
+
+// instrumentedRequest wraps a request to record the latency and errors of its
+// creation, labelled by the type and backend of its tile or metadata, and to
+// trace it. The scheduling of the wrapped request is passed through to the
+// queue. Requests for tiles or metadata with a resilience policy are created
+// under it.
+type instrumentedRequest struct {
+	req      Request
+	labels   metrics.Labels
+	policy   *resilience.Policy
+	endpoint string
+}
+
+// GetPriority returns the scheduling priority of the wrapped request.
+func (r *instrumentedRequest) GetPriority() queue.Priority {
+	sreq, ok := r.req.(queue.ScheduledRequest)
+	if !ok {
+		return queue.Interactive
+	}
+	return sreq.GetPriority()
+}
+
+// GetClient returns the client the wrapped request is made on behalf of.
+func (r *instrumentedRequest) GetClient() string {
+	sreq, ok := r.req.(queue.ScheduledRequest)
+	if !ok {
+		return ""
+	}
+	return sreq.GetClient()
+}
+
+// newInstrumentedRequest wraps the request, recording its metrics under the
+// provided labels of its type and backend.
+func newInstrumentedRequest(req Request, labels metrics.Labels) *instrumentedRequest {
+	generator := getGenerator(req)
+	r := &instrumentedRequest{
+		req:    req,
+		labels: labels,
+		policy: getPolicy(generator),
+	}
+	if r.policy != nil {
+		r.endpoint = getEndpoint(generator)
+	}
+	return r
+}
+
+// Create creates the wrapped request.
+func (r *instrumentedRequest) Create() ([]byte, error) {
+	return r.CreateContext(context.Background())
+}
+
+// CreateContext creates the wrapped request, passing through the context if
+// the request supports cancellation. Cancelled requests are not recorded as
+// errors.
+func (r *instrumentedRequest) CreateContext(ctx context.Context) ([]byte, error) {
+	ctx, span := trace.Start(ctx, trace.CreateSpan)
+	span.SetAttribute(trace.PipelineAttribute, r.labels[metrics.PipelineLabel])
+	span.SetAttribute(trace.TypeAttribute, r.labels[metrics.TypeLabel])
+	span.SetAttribute(trace.BackendAttribute, r.labels[metrics.BackendLabel])
+	defer span.End()
+	start := time.Now()
+	res, err := r.policy.Do(ctx, r.endpoint, r.create)
+	metrics.ObserveSince(metrics.CreateLatency, r.labels, start)
+	if err != nil && ctx.Err() == nil {
+		metrics.Inc(metrics.CreateErrors, r.labels)
+	}
+	span.SetError(err)
+	return res, err
+}
+
+func (r *instrumentedRequest) create(ctx context.Context) ([]byte, error) {
+	creq, ok := r.req.(queue.ContextRequest)
+	if ok {
+		return creq.CreateContext(ctx)
+	}
+	return r.req.Create()
+}
+
+// getGenerator returns the tile or metadata generator of the request.
+func getGenerator(req Request) interface{} {
+	switch r := req.(type) {
+	case *TileRequest:
+		return r.Tile
+	case *MetaRequest:
+		return r.Meta
+	}
+	return nil
+}
+
+// getTypeAndBackend returns the type name of the tile or metadata generator,
+// and the name of the package implementing it, such as `HeatmapTile` and
+// `elastic`.
+func getTypeAndBackend(generator interface{}) (string, string) {
+	if generator == nil {
+		return "", ""
+	}
+	typ := reflect.TypeOf(generator)
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ.Name(), path.Base(typ.PkgPath())
+}
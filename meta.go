@@ -1,11 +1,26 @@
 package veldt
 
+import (
+	"context"
+)
+
 // Meta represents an interface for generating meta data.
 type Meta interface {
 	Create(string) ([]byte, error)
 	Parse(map[string]interface{}) error
 }
 
+// ContextMeta represents an interface for generating meta data that can be
+// cancelled through a context.
+type ContextMeta interface {
+	Meta
+	// CreateContext creates the meta data, abandoning the work if the context
+	// is done.
+	// parameter 1 (context.Context): the context of the request
+	// parameter 2 (string): A dataset ID (typically called uri)
+	CreateContext(context.Context, string) ([]byte, error)
+}
+
 // MetaCtor represents a function that instantiates and returns a new meta
 // data type.
 type MetaCtor func() (Meta, error)
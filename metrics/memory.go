@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"sync"
+)
+
+// MemorySink represents a sink that holds metrics in memory, useful for
+// testing.
+type MemorySink struct {
+	mutex      sync.Mutex
+	counters   map[string]float64
+	gauges     map[string]float64
+	histograms map[string][]float64
+}
+
+// NewMemorySink instantiates and returns a new in-memory sink.
+func NewMemorySink() *MemorySink {
+	return &MemorySink{
+		counters:   make(map[string]float64),
+		gauges:     make(map[string]float64),
+		histograms: make(map[string][]float64),
+	}
+}
+
+// AddCounter adds the delta to the counter.
+func (s *MemorySink) AddCounter(name string, labels Labels, delta float64) {
+	s.mutex.Lock()
+	s.counters[getKey(name, labels)] += delta
+	s.mutex.Unlock()
+}
+
+// SetGauge sets the gauge to the value.
+func (s *MemorySink) SetGauge(name string, labels Labels, value float64) {
+	s.mutex.Lock()
+	s.gauges[getKey(name, labels)] = value
+	s.mutex.Unlock()
+}
+
+// ObserveHistogram records the value in the histogram.
+func (s *MemorySink) ObserveHistogram(name string, labels Labels, value float64) {
+	s.mutex.Lock()
+	key := getKey(name, labels)
+	s.histograms[key] = append(s.histograms[key], value)
+	s.mutex.Unlock()
+}
+
+// GetCounter returns the value of the counter.
+func (s *MemorySink) GetCounter(name string, labels Labels) float64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.counters[getKey(name, labels)]
+}
+
+// GetGauge returns the value of the gauge.
+func (s *MemorySink) GetGauge(name string, labels Labels) float64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.gauges[getKey(name, labels)]
+}
+
+// GetHistogram returns the values recorded in the histogram.
+func (s *MemorySink) GetHistogram(name string, labels Labels) []float64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	values := s.histograms[getKey(name, labels)]
+	res := make([]float64, len(values))
+	copy(res, values)
+	return res
+}
+
+func getKey(name string, labels Labels) string {
+	return name + "{" + labels.key() + "}"
+}
@@ -0,0 +1,163 @@
+package metrics
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The metrics recorded by veldt.
+const (
+	// QueueDepth is a gauge of the requests pending in a pipeline queue,
+	// including those being generated.
+	QueueDepth = "veldt_queue_depth"
+	// QueueRejections is a counter of the requests rejected by a full
+	// pipeline queue.
+	QueueRejections = "veldt_queue_rejections_total"
+	// QueueExpirations is a counter of the requests that expired while
+	// waiting in a pipeline queue.
+	QueueExpirations = "veldt_queue_expirations_total"
+	// ConcurrencyLimit is a gauge of the adaptive concurrency limit of a
+	// pipeline queue, labelled by endpoint.
+	ConcurrencyLimit = "veldt_concurrency_limit"
+	// BackendRetries is a counter of the requests retried under a
+	// resilience policy, labelled by endpoint.
+	BackendRetries = "veldt_backend_retries_total"
+	// BreakerRejections is a counter of the requests failed fast by an open
+	// circuit breaker, labelled by endpoint.
+	BreakerRejections = "veldt_breaker_rejections_total"
+	// PromiseHits is a counter of the requests that joined the in-flight
+	// generation of an identical request, labelled by type and backend.
+	PromiseHits = "veldt_promise_hits_total"
+	// StoreHits is a counter of the requests found in the store, labelled by
+	// type and backend.
+	StoreHits = "veldt_store_hits_total"
+	// StoreMisses is a counter of the requests not found in the store,
+	// labelled by type and backend.
+	StoreMisses = "veldt_store_misses_total"
+	// StoreLatency is a histogram of the seconds taken by store operations,
+	// labelled by operation, type and backend.
+	StoreLatency = "veldt_store_latency_seconds"
+	// CreateLatency is a histogram of the seconds taken to create tiles and
+	// metadata, labelled by type and backend.
+	CreateLatency = "veldt_create_latency_seconds"
+	// CreateErrors is a counter of the tiles and metadata that failed to be
+	// created, labelled by type and backend.
+	CreateErrors = "veldt_create_errors_total"
+)
+
+// The labels recorded by veldt.
+const (
+	// PipelineLabel is the ID the pipeline is registered under.
+	PipelineLabel = "pipeline"
+	// TypeLabel is the type of the tile or metadata.
+	TypeLabel = "type"
+	// BackendLabel is the generation backend of the tile or metadata, such
+	// as `elastic`.
+	BackendLabel = "backend"
+	// OperationLabel is the store operation.
+	OperationLabel = "operation"
+	// EndpointLabel is the backend endpoint of a pipeline queue or
+	// resilience policy, such as the host and port of a database.
+	EndpointLabel = "endpoint"
+)
+
+var (
+	mutex = sync.RWMutex{}
+	sink  Sink
+)
+
+// Labels represents the labels of a metric.
+type Labels map[string]string
+
+// Sink represents an interface for recording metrics.
+type Sink interface {
+	AddCounter(name string, labels Labels, delta float64)
+	SetGauge(name string, labels Labels, value float64)
+	ObserveHistogram(name string, labels Labels, value float64)
+}
+
+// SetSink sets the sink metrics are recorded to. A nil sink disables
+// recording.
+func SetSink(s Sink) {
+	mutex.Lock()
+	sink = s
+	mutex.Unlock()
+}
+
+func getSink() Sink {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return sink
+}
+
+// Inc increments the counter.
+func Inc(name string, labels Labels) {
+	Add(name, labels, 1)
+}
+
+// Add adds the delta to the counter.
+func Add(name string, labels Labels, delta float64) {
+	s := getSink()
+	if s != nil {
+		s.AddCounter(name, labels, delta)
+	}
+}
+
+// Set sets the gauge to the value.
+func Set(name string, labels Labels, value float64) {
+	s := getSink()
+	if s != nil {
+		s.SetGauge(name, labels, value)
+	}
+}
+
+// Observe records the value in the histogram.
+func Observe(name string, labels Labels, value float64) {
+	s := getSink()
+	if s != nil {
+		s.ObserveHistogram(name, labels, value)
+	}
+}
+
+// ObserveSince records the seconds elapsed since the start time in the
+// histogram.
+func ObserveSince(name string, labels Labels, start time.Time) {
+	Observe(name, labels, time.Since(start).Seconds())
+}
+
+// With returns a copy of the labels with the additional label.
+func (l Labels) With(name string, value string) Labels {
+	labels := l.copy()
+	labels[name] = value
+	return labels
+}
+
+func (l Labels) copy() Labels {
+	labels := make(Labels, len(l)+1)
+	for name, value := range l {
+		labels[name] = value
+	}
+	return labels
+}
+
+// key returns a string uniquely identifying the labels.
+func (l Labels) key() string {
+	names := l.names()
+	parts := make([]string, len(names))
+	for i, name := range names {
+		// separate with control characters to avoid collisions
+		parts[i] = name + "\x00" + l[name]
+	}
+	return strings.Join(parts, "\x01")
+}
+
+func (l Labels) names() []string {
+	names := make([]string, 0, len(l))
+	for name := range l {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,71 @@
+package metrics_test
+
+import (
+	"net/http/httptest"
+
+	"github.com/unchartedsoftware/veldt/metrics"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("metrics", func() {
+
+	labels := metrics.Labels{
+		"pipeline": "test",
+	}
+
+	AfterEach(func() {
+		metrics.SetSink(nil)
+	})
+
+	Describe("MemorySink", func() {
+		It("should record counters, gauges and histograms", func() {
+			sink := metrics.NewMemorySink()
+			metrics.SetSink(sink)
+			metrics.Inc("counter", labels)
+			metrics.Add("counter", labels, 2)
+			metrics.Set("gauge", labels, 4)
+			metrics.Set("gauge", labels, 5)
+			metrics.Observe("histogram", labels, 0.5)
+			metrics.Observe("histogram", labels, 1.5)
+			Expect(sink.GetCounter("counter", labels)).To(Equal(3.0))
+			Expect(sink.GetCounter("counter", nil)).To(Equal(0.0))
+			Expect(sink.GetGauge("gauge", labels)).To(Equal(5.0))
+			Expect(sink.GetHistogram("histogram", labels)).To(Equal([]float64{0.5, 1.5}))
+		})
+	})
+
+	Describe("PrometheusSink", func() {
+		It("should expose metrics in the text format", func() {
+			sink := metrics.NewPrometheusSink(0.1, 1)
+			metrics.SetSink(sink)
+			metrics.Inc("requests_total", labels)
+			metrics.Inc("requests_total", labels.With("type", `a"b`))
+			metrics.Set("depth", nil, 2)
+			metrics.Observe("latency_seconds", labels, 0.5)
+			metrics.Observe("latency_seconds", labels, 2)
+			Expect(string(sink.Expose())).To(Equal(`# TYPE depth gauge
+depth 2
+# TYPE latency_seconds histogram
+latency_seconds_bucket{le="0.1",pipeline="test"} 0
+latency_seconds_bucket{le="1",pipeline="test"} 1
+latency_seconds_bucket{le="+Inf",pipeline="test"} 2
+latency_seconds_sum{pipeline="test"} 2.5
+latency_seconds_count{pipeline="test"} 2
+# TYPE requests_total counter
+requests_total{pipeline="test"} 1
+requests_total{pipeline="test",type="a\"b"} 1
+`))
+		})
+
+		It("should serve metrics over HTTP", func() {
+			sink := metrics.NewPrometheusSink()
+			sink.AddCounter("requests_total", nil, 1)
+			res := httptest.NewRecorder()
+			sink.ServeHTTP(res, httptest.NewRequest("GET", "/metrics", nil))
+			Expect(res.Code).To(Equal(200))
+			Expect(res.Body.String()).To(Equal("# TYPE requests_total counter\nrequests_total 1\n"))
+		})
+	})
+})
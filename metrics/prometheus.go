@@ -0,0 +1,170 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	// DefaultBuckets are the default histogram buckets, in seconds.
+	DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+)
+
+type series struct {
+	labels  Labels
+	value   float64
+	buckets []uint64
+	sum     float64
+	count   uint64
+}
+
+type family struct {
+	typ    string
+	series map[string]*series
+}
+
+// PrometheusSink represents a sink that aggregates metrics and serves them in
+// the Prometheus text exposition format.
+//
+// Ex:
+//     sink := metrics.NewPrometheusSink()
+//     metrics.SetSink(sink)
+//     http.Handle("/metrics", sink)
+//
+type PrometheusSink struct {
+	mutex    sync.Mutex
+	buckets  []float64
+	families map[string]*family
+}
+
+// NewPrometheusSink instantiates and returns a new Prometheus sink. If no
+// histogram buckets are provided, the DefaultBuckets are used.
+func NewPrometheusSink(buckets ...float64) *PrometheusSink {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	sorted := make([]float64, len(buckets))
+	copy(sorted, buckets)
+	sort.Float64s(sorted)
+	return &PrometheusSink{
+		buckets:  sorted,
+		families: make(map[string]*family),
+	}
+}
+
+// AddCounter adds the delta to the counter.
+func (s *PrometheusSink) AddCounter(name string, labels Labels, delta float64) {
+	s.mutex.Lock()
+	s.getSeries(name, "counter", labels).value += delta
+	s.mutex.Unlock()
+}
+
+// SetGauge sets the gauge to the value.
+func (s *PrometheusSink) SetGauge(name string, labels Labels, value float64) {
+	s.mutex.Lock()
+	s.getSeries(name, "gauge", labels).value = value
+	s.mutex.Unlock()
+}
+
+// ObserveHistogram records the value in the histogram.
+func (s *PrometheusSink) ObserveHistogram(name string, labels Labels, value float64) {
+	s.mutex.Lock()
+	series := s.getSeries(name, "histogram", labels)
+	if series.buckets == nil {
+		series.buckets = make([]uint64, len(s.buckets))
+	}
+	for i, bound := range s.buckets {
+		if value <= bound {
+			series.buckets[i]++
+		}
+	}
+	series.sum += value
+	series.count++
+	s.mutex.Unlock()
+}
+
+func (s *PrometheusSink) getSeries(name string, typ string, labels Labels) *series {
+	f, ok := s.families[name]
+	if !ok {
+		f = &family{
+			typ:    typ,
+			series: make(map[string]*series),
+		}
+		s.families[name] = f
+	}
+	key := labels.key()
+	ser, ok := f.series[key]
+	if !ok {
+		ser = &series{
+			labels: labels.copy(),
+		}
+		f.series[key] = ser
+	}
+	return ser
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (s *PrometheusSink) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.Write(s.Expose())
+}
+
+// Expose returns the metrics in the Prometheus text exposition format.
+func (s *PrometheusSink) Expose() []byte {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	buffer := &bytes.Buffer{}
+	names := make([]string, 0, len(s.families))
+	for name := range s.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f := s.families[name]
+		fmt.Fprintf(buffer, "# TYPE %s %s\n", name, f.typ)
+		keys := make([]string, 0, len(f.series))
+		for key := range f.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			ser := f.series[key]
+			if f.typ != "histogram" {
+				writeSample(buffer, name, ser.labels, ser.value)
+				continue
+			}
+			for i, bound := range s.buckets {
+				labels := ser.labels.With("le", formatFloat(bound))
+				writeSample(buffer, name+"_bucket", labels, float64(ser.buckets[i]))
+			}
+			writeSample(buffer, name+"_bucket", ser.labels.With("le", "+Inf"), float64(ser.count))
+			writeSample(buffer, name+"_sum", ser.labels, ser.sum)
+			writeSample(buffer, name+"_count", ser.labels, float64(ser.count))
+		}
+	}
+	return buffer.Bytes()
+}
+
+func writeSample(buffer *bytes.Buffer, name string, labels Labels, value float64) {
+	buffer.WriteString(name)
+	if len(labels) > 0 {
+		names := labels.names()
+		pairs := make([]string, len(names))
+		for i, label := range names {
+			pairs[i] = fmt.Sprintf(`%s="%s"`, label, labelEscaper.Replace(labels[label]))
+		}
+		buffer.WriteString("{" + strings.Join(pairs, ",") + "}")
+	}
+	buffer.WriteString(" " + formatFloat(value) + "\n")
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -1,13 +1,16 @@
 package veldt
 
 import (
-	"bytes"
-	"compress/gzip"
-	"compress/zlib"
+	"context"
 	"fmt"
-	"io"
-	"io/ioutil"
+	"net/url"
+	"sync"
+	"time"
 
+	"github.com/unchartedsoftware/veldt/codec"
+	"github.com/unchartedsoftware/veldt/metrics"
+	"github.com/unchartedsoftware/veldt/schema"
+	"github.com/unchartedsoftware/veldt/trace"
 	"github.com/unchartedsoftware/veldt/util/json"
 	"github.com/unchartedsoftware/veldt/util/promise"
 	"github.com/unchartedsoftware/veldt/util/queue"
@@ -15,13 +18,21 @@ import (
 
 // Pipeline represents a cohesive tile and meta generation unit.
 type Pipeline struct {
+	id          string
 	queue       *queue.Queue
+	endpoints   map[string]*queue.Queue
+	options     []func(*queue.Queue)
+	adaptive    *adaptiveBounds
+	queueMu     *sync.Mutex
 	queries     map[string]QueryCtor
 	binary      QueryCtor
 	unary       QueryCtor
 	tiles       map[string]TileCtor
 	metas       map[string]MetaCtor
+	renderers   map[string]RendererCtor
 	store       StoreCtor
+	identities  map[string]string
+	schemas     map[string]*schema.Schema
 	promises    *promise.Map
 	compression string
 }
@@ -30,52 +41,163 @@ type Pipeline struct {
 func NewPipeline() *Pipeline {
 	return &Pipeline{
 		queue:       queue.NewQueue(),
+		endpoints:   make(map[string]*queue.Queue),
+		queueMu:     &sync.Mutex{},
 		queries:     make(map[string]QueryCtor),
 		tiles:       make(map[string]TileCtor),
 		metas:       make(map[string]MetaCtor),
+		renderers:   make(map[string]RendererCtor),
+		identities:  make(map[string]string),
+		schemas:     make(map[string]*schema.Schema),
 		promises:    promise.NewMap(),
-		compression: "gzip",
+		compression: codec.Gzip,
 	}
 }
 
-// SetMaxConcurrent sets the maximum concurrent tile requests allowed.
+// SetMaxConcurrent sets the maximum concurrent tile requests allowed. It has
+// no effect on the queues of endpoints with adaptive concurrency.
 func (p *Pipeline) SetMaxConcurrent(max int) {
 	p.queue.SetMaxConcurrent(max)
 }
 
+// SetAdaptiveConcurrency enables adaptive concurrency, giving each backend
+// endpoint its own queue, with a concurrency limit adapted to the latency and
+// errors of the endpoint within the provided bounds. Tiles and metadata
+// implementing Endpoint are grouped by their endpoint, and all others by
+// their backend package.
+func (p *Pipeline) SetAdaptiveConcurrency(min int, max int) {
+	p.queueMu.Lock()
+	defer p.queueMu.Unlock()
+	p.adaptive = &adaptiveBounds{
+		min: min,
+		max: max,
+	}
+	for _, q := range p.endpoints {
+		q.SetLimiter(p.adaptive.newLimiter())
+	}
+}
+
+// GetConcurrencyLimits returns the current concurrency limit of each backend
+// endpoint with adaptive concurrency.
+func (p *Pipeline) GetConcurrencyLimits() map[string]int {
+	p.queueMu.Lock()
+	defer p.queueMu.Unlock()
+	limits := make(map[string]int, len(p.endpoints))
+	for endpoint, q := range p.endpoints {
+		limits[endpoint] = q.GetMaxConcurrent()
+	}
+	return limits
+}
+
 // SetQueueLength sets the queue length for tiles to hold in the queue.
 func (p *Pipeline) SetQueueLength(length int) {
-	p.queue.SetLength(length)
+	p.configureQueues(func(q *queue.Queue) {
+		q.SetLength(length)
+	})
+}
+
+// SetQueueMaxWait sets the maximum duration a request may wait in the queue
+// before it expires. A duration of zero disables expiry.
+func (p *Pipeline) SetQueueMaxWait(wait time.Duration) {
+	p.configureQueues(func(q *queue.Queue) {
+		q.SetMaxWait(wait)
+	})
+}
+
+// SetClientWeight sets the share of the queue given to the client relative
+// to the other clients of the same priority, which have a weight of one by
+// default.
+func (p *Pipeline) SetClientWeight(client string, weight int) {
+	p.configureQueues(func(q *queue.Queue) {
+		q.SetWeight(client, weight)
+	})
+}
+
+// SetCompression sets the name of the codec used to compress generated data
+// in the store, unless selected by the tile, metadata or renderer type. The
+// codec must be registered with the codec package. Defaults to `gzip`.
+func (p *Pipeline) SetCompression(name string) {
+	p.compression = name
 }
 
 // Query registers a query type under the provided ID string.
 func (p *Pipeline) Query(id string, ctor QueryCtor) {
 	p.queries[id] = ctor
+	p.identities[getIdentityKey(queryKind, id)] = getCtorIdentity(ctor)
+	p.schemas[getIdentityKey(queryKind, id)] = getSchema(getInstance(ctor))
+}
+
+// setID sets the ID the pipeline is registered under, used to label its
+// metrics.
+func (p *Pipeline) setID(id string) {
+	p.id = id
+	p.queue.SetLabels(p.labels())
+	p.queueMu.Lock()
+	for endpoint, q := range p.endpoints {
+		q.SetLabels(p.labels().With(metrics.EndpointLabel, endpoint))
+	}
+	p.queueMu.Unlock()
+}
+
+// labels returns the labels of the metrics recorded by the pipeline.
+func (p *Pipeline) labels() metrics.Labels {
+	return metrics.Labels{
+		metrics.PipelineLabel: p.id,
+	}
+}
+
+// requestLabels returns the labels of the metrics recorded by the pipeline for
+// the request, along with the type and backend of its tile or metadata.
+func (p *Pipeline) requestLabels(req Request) metrics.Labels {
+	typ, backend := getTypeAndBackend(getGenerator(req))
+	return p.labels().With(metrics.TypeLabel, typ).With(metrics.BackendLabel, backend)
+}
+
+// startSpan starts a span labelled by the pipeline.
+func (p *Pipeline) startSpan(ctx context.Context, name string) (context.Context, *trace.Span) {
+	ctx, span := trace.Start(ctx, name)
+	span.SetAttribute(trace.PipelineAttribute, p.id)
+	return ctx, span
 }
 
 // Binary registers a binary operator type under the provided ID string.
 func (p *Pipeline) Binary(ctor QueryCtor) {
 	p.binary = ctor
+	p.identities[getIdentityKey(binaryKind, "")] = getCtorIdentity(ctor)
 }
 
 // Unary registers a unary operator type under the provided ID string.
 func (p *Pipeline) Unary(ctor QueryCtor) {
 	p.unary = ctor
+	p.identities[getIdentityKey(unaryKind, "")] = getCtorIdentity(ctor)
 }
 
 // Tile registers a tile generation type under the provided ID string.
 func (p *Pipeline) Tile(id string, ctor TileCtor) {
 	p.tiles[id] = ctor
+	p.identities[getIdentityKey(tileKind, id)] = getCtorIdentity(ctor)
+	p.schemas[getIdentityKey(tileKind, id)] = getSchema(getInstance(ctor))
 }
 
 // Meta registers a metadata generation type under the provided ID string.
 func (p *Pipeline) Meta(id string, ctor MetaCtor) {
 	p.metas[id] = ctor
+	p.identities[getIdentityKey(metaKind, id)] = getCtorIdentity(ctor)
+	p.schemas[getIdentityKey(metaKind, id)] = getSchema(getInstance(ctor))
+}
+
+// Render registers a tile rendering type under the provided ID string.
+func (p *Pipeline) Render(id string, ctor RendererCtor) {
+	p.renderers[id] = ctor
+	p.identities[getIdentityKey(renderKind, id)] = getCtorIdentity(ctor)
+	p.schemas[getIdentityKey(renderKind, id)] = getSchema(getInstance(ctor))
 }
 
 // Store registers the storage system used to cache generated data.
 func (p *Pipeline) Store(ctor StoreCtor) {
 	p.store = ctor
+	// the store is identified by its constructor only, to avoid connecting
+	p.identities[getIdentityKey(storeKind, "")] = getFuncName(ctor)
 }
 
 // GetQuery returns the instantiated query struct from the provided ID and JSON.
@@ -161,6 +283,28 @@ func (p *Pipeline) GetMeta(id string, args interface{}) (Meta, error) {
 	return meta, nil
 }
 
+// GetRenderer returns the instantiated renderer struct from the provided ID
+// and JSON.
+func (p *Pipeline) GetRenderer(id string, args interface{}) (Renderer, error) {
+	params, ok := args.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("`%s` is not of correct type", id)
+	}
+	ctor, ok := p.renderers[id]
+	if !ok {
+		return nil, fmt.Errorf("unrecognized render type `%v`", id)
+	}
+	renderer, err := ctor()
+	if err != nil {
+		return nil, err
+	}
+	err = renderer.Parse(params)
+	if err != nil {
+		return nil, err
+	}
+	return renderer, nil
+}
+
 // GetStore returns the instantiated store struct from the provided ID and JSON.
 func (p *Pipeline) GetStore() (Store, error) {
 	if p.store == nil {
@@ -169,45 +313,115 @@ func (p *Pipeline) GetStore() (Store, error) {
 	return p.store()
 }
 
-// GetHash returns a unique hash for the state of the pipeline.
+// GetHash returns a unique hash for the state of the pipeline, formed from
+// the registered store. The types used by each request and the compression of
+// its data are part of the request hash.
 func (p *Pipeline) GetHash() string {
+	return getDigest(p.identities[getIdentityKey(storeKind, "")])
+}
+
+// GetRequestHash returns the unique hash under which the data for the
+// provided request is stored.
+func (p *Pipeline) GetRequestHash(req Request) string {
+	return p.getHash(req)
+}
+
+// GetRequestCompression returns the name of the codec the data for the
+// provided request is compressed with in the store. A renderer's selection
+// takes precedence over the tile's, followed by that of the pipeline.
+func (p *Pipeline) GetRequestCompression(req Request) string {
+	switch r := req.(type) {
+	case *TileRequest:
+		if name, ok := getCompression(r.Render); ok {
+			return name
+		}
+		if name, ok := getCompression(r.Tile); ok {
+			return name
+		}
+	case *MetaRequest:
+		if name, ok := getCompression(r.Meta); ok {
+			return name
+		}
+	}
 	return p.compression
 }
 
 // NewTileRequest instantiates and returns a tile request struct from the
 // provided JSON.
 func (p *Pipeline) NewTileRequest(args map[string]interface{}) (*TileRequest, error) {
+	return p.NewTileRequestContext(context.Background(), args)
+}
+
+// NewTileRequestContext instantiates and returns a tile request struct from
+// the provided JSON. The validation is traced as part of the context's trace.
+func (p *Pipeline) NewTileRequestContext(ctx context.Context, args map[string]interface{}) (*TileRequest, error) {
+	_, span := p.startSpan(ctx, trace.ValidateSpan)
+	defer span.End()
 	// params are modified in place during validation, so create a copy
 	copy, err := json.Copy(args)
 	if err != nil {
+		span.SetError(err)
 		return nil, err
 	}
 	// validate request
 	req, err := newValidator(p).validateTileRequest(copy)
 	if err != nil {
-		return nil, fmt.Errorf("invalid tile request:\n%s", err)
+		err = fmt.Errorf("invalid tile request:\n%s", err)
+		span.SetError(err)
+		return nil, err
 	}
+	span.SetAttribute(trace.URIAttribute, req.URI)
 	return req, nil
 }
 
 // NewMetaRequest instantiates and returns a metadata request struct from the
 // provided JSON.
 func (p *Pipeline) NewMetaRequest(args map[string]interface{}) (*MetaRequest, error) {
+	return p.NewMetaRequestContext(context.Background(), args)
+}
+
+// NewMetaRequestContext instantiates and returns a metadata request struct
+// from the provided JSON. The validation is traced as part of the context's
+// trace.
+func (p *Pipeline) NewMetaRequestContext(ctx context.Context, args map[string]interface{}) (*MetaRequest, error) {
+	_, span := p.startSpan(ctx, trace.ValidateSpan)
+	defer span.End()
 	// params are modified in place during validation, so create a copy
 	copy, err := json.Copy(args)
 	if err != nil {
+		span.SetError(err)
 		return nil, err
 	}
 	// validate request
 	req, err := newValidator(p).validateMetaRequest(copy)
 	if err != nil {
-		return nil, fmt.Errorf("invalid meta request:\n%s", err)
+		err = fmt.Errorf("invalid meta request:\n%s", err)
+		span.SetError(err)
+		return nil, err
 	}
+	span.SetAttribute(trace.URIAttribute, req.URI)
 	return req, nil
 }
 
 // Generate generates data for the provided request.
 func (p *Pipeline) Generate(req Request) error {
+	return p.GenerateContext(context.Background(), req)
+}
+
+// GenerateContext generates data for the provided request. If the context is
+// done before the data is generated, the context error is returned. Any
+// in-flight generation is cancelled once every request waiting on it has
+// been abandoned.
+func (p *Pipeline) GenerateContext(ctx context.Context, req Request) error {
+	ctx, span := p.startSpan(ctx, trace.GenerateSpan)
+	span.SetAttribute(trace.URIAttribute, req.GetURI())
+	defer span.End()
+	err := p.generate(ctx, req)
+	span.SetError(err)
+	return err
+}
+
+func (p *Pipeline) generate(ctx context.Context, req Request) error {
 	// get hash
 	hash := p.getHash(req)
 	// get store
@@ -216,8 +430,9 @@ func (p *Pipeline) Generate(req Request) error {
 		return err
 	}
 	defer store.Close()
+	labels := p.requestLabels(req)
 	// check if already exists in store
-	exists, err := store.Exists(hash)
+	exists, err := p.exists(ctx, store, hash, labels)
 	if err != nil {
 		return err
 	}
@@ -226,82 +441,215 @@ func (p *Pipeline) Generate(req Request) error {
 		return nil
 	}
 	// otherwise, initiate the generation task and return error
-	return p.getPromise(hash, req)
+	return p.getPromise(ctx, hash, req, labels)
+}
+
+// Invalidate removes all tiles and metadata generated for the provided URI
+// from the store. The store must implement the InvalidatingStore interface.
+// Generation already in progress for the URI is not cancelled, and may store
+// its data after the invalidation.
+func (p *Pipeline) Invalidate(uri string) error {
+	// get store
+	store, err := p.GetStore()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	invalidating, ok := store.(InvalidatingStore)
+	if !ok {
+		return fmt.Errorf("store does not support invalidation")
+	}
+	return invalidating.DeleteByPrefix(getURIPrefix(uri))
 }
 
 // Get retrieves the generated data from the store.
 func (p *Pipeline) Get(req Request) ([]byte, error) {
+	return p.GetContext(context.Background(), req)
+}
+
+// GetContext retrieves the generated data from the store. If the context is
+// already done, the context error is returned.
+func (p *Pipeline) GetContext(ctx context.Context, req Request) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	ctx, span := p.startSpan(ctx, trace.GetSpan)
+	span.SetAttribute(trace.URIAttribute, req.GetURI())
+	defer span.End()
+	res, name, err := p.getEncoded(ctx, req)
+	if err == nil {
+		res, err = codec.Decode(name, res)
+	}
+	span.SetError(err)
+	return res, err
+}
+
+// GetEncoded retrieves the generated data from the store as stored, without
+// decompressing it, along with the name of the codec it is compressed with.
+func (p *Pipeline) GetEncoded(req Request) ([]byte, string, error) {
+	return p.GetEncodedContext(context.Background(), req)
+}
+
+// GetEncodedContext retrieves the generated data from the store as stored,
+// without decompressing it, along with the name of the codec it is compressed
+// with. If the context is already done, the context error is returned.
+func (p *Pipeline) GetEncodedContext(ctx context.Context, req Request) ([]byte, string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
+	}
+	ctx, span := p.startSpan(ctx, trace.GetSpan)
+	span.SetAttribute(trace.URIAttribute, req.GetURI())
+	defer span.End()
+	res, name, err := p.getEncoded(ctx, req)
+	span.SetError(err)
+	return res, name, err
+}
+
+func (p *Pipeline) getEncoded(ctx context.Context, req Request) ([]byte, string, error) {
 	// get hash
 	hash := p.getHash(req)
 	// get store
 	store, err := p.GetStore()
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer store.Close()
 	// get data from store
-	res, err := store.Get(hash)
+	res, err := p.get(ctx, store, hash, p.requestLabels(req))
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
-	return p.decompress(res)
+	return res, p.GetRequestCompression(req), nil
 }
 
 // GenerateAndGet retrieves the generated data from the store, if it
 // does not exist, generate it before retrieval.
 func (p *Pipeline) GenerateAndGet(req Request) ([]byte, error) {
+	return p.GenerateAndGetContext(context.Background(), req)
+}
+
+// GenerateAndGetContext retrieves the generated data from the store, if it
+// does not exist, generate it before retrieval. If the context is done before
+// the data is generated, the context error is returned.
+func (p *Pipeline) GenerateAndGetContext(ctx context.Context, req Request) ([]byte, error) {
+	ctx, span := p.startSpan(ctx, trace.GenerateSpan)
+	span.SetAttribute(trace.URIAttribute, req.GetURI())
+	defer span.End()
+	res, name, err := p.generateAndGet(ctx, req)
+	if err == nil {
+		res, err = codec.Decode(name, res)
+	}
+	span.SetError(err)
+	return res, err
+}
+
+// GenerateAndGetEncoded retrieves the generated data from the store as
+// stored, without decompressing it, along with the name of the codec it is
+// compressed with. If it does not exist, it is generated before retrieval.
+func (p *Pipeline) GenerateAndGetEncoded(req Request) ([]byte, string, error) {
+	return p.GenerateAndGetEncodedContext(context.Background(), req)
+}
+
+// GenerateAndGetEncodedContext retrieves the generated data from the store as
+// stored, without decompressing it, along with the name of the codec it is
+// compressed with. If it does not exist, it is generated before retrieval. If
+// the context is done before the data is generated, the context error is
+// returned.
+func (p *Pipeline) GenerateAndGetEncodedContext(ctx context.Context, req Request) ([]byte, string, error) {
+	ctx, span := p.startSpan(ctx, trace.GenerateSpan)
+	span.SetAttribute(trace.URIAttribute, req.GetURI())
+	defer span.End()
+	res, name, err := p.generateAndGet(ctx, req)
+	span.SetError(err)
+	return res, name, err
+}
+
+func (p *Pipeline) generateAndGet(ctx context.Context, req Request) ([]byte, string, error) {
 	// get hash
 	hash := p.getHash(req)
 	// get store
 	store, err := p.GetStore()
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer store.Close()
+	labels := p.requestLabels(req)
 	// check if already exists in store
-	exists, err := store.Exists(hash)
+	exists, err := p.exists(ctx, store, hash, labels)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	// check if it exists
 	if !exists {
 		// if not, initiate the tiling job
-		err = p.getPromise(hash, req)
+		err = p.getPromise(ctx, hash, req, labels)
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 	}
 	// get data from store
-	res, err := store.Get(hash)
+	res, err := p.get(ctx, store, hash, labels)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
-	return p.decompress(res)
+	return res, p.GetRequestCompression(req), nil
 }
 
-func (p *Pipeline) getPromise(hash string, req Request) error {
-	promise, exists := p.promises.GetOrCreate(hash)
+func (p *Pipeline) getPromise(ctx context.Context, hash string, req Request, labels metrics.Labels) error {
+	prom, exists := p.promises.GetOrCreate(hash)
+	var err error
 	if exists {
-		// promise already existed, return it
-		return promise.Wait()
-	}
-	// promise had to be created, generate data
+		// promise already existed, wait on it
+		metrics.Inc(metrics.PromiseHits, labels)
+		_, span := p.startSpan(ctx, trace.PromiseSpan)
+		err = prom.WaitContext(ctx)
+		span.SetError(err)
+		span.End()
+	} else {
+		// promise had to be created, generate data
+		err = p.generatePromise(ctx, prom, hash, req, labels)
+	}
+	if err == promise.ErrAbandoned {
+		// every other caller has gone and the generation is cancelled, so
+		// ensure the promise is removed and start anew
+		p.promises.CompareAndRemove(hash, prom)
+		return p.getPromise(ctx, hash, req, labels)
+	}
+	return err
+}
+
+func (p *Pipeline) generatePromise(ctx context.Context, prom *promise.Promise, hash string, req Request, labels metrics.Labels) error {
+	// generation is detached from any single caller, and is only cancelled
+	// once all callers waiting on it have gone. It is traced as part of the
+	// trace of the caller that initiated it.
+	genCtx, cancel := context.WithCancel(trace.Detach(ctx))
+	prom.OnAbandon(func() {
+		// remove the promise before cancelling, such that subsequent requests
+		// do not join the cancelled generation
+		p.promises.CompareAndRemove(hash, prom)
+		cancel()
+	})
 	go func() {
-		err := p.generateAndStore(hash, req)
-		promise.Resolve(err)
-		p.promises.Remove(hash)
+		err := p.generateAndStore(genCtx, hash, req, labels)
+		cancel()
+		prom.Resolve(err)
+		p.promises.CompareAndRemove(hash, prom)
 	}()
-	return promise.Wait()
+	return prom.WaitContext(ctx)
 }
 
-func (p *Pipeline) generateAndStore(hash string, req Request) error {
+func (p *Pipeline) generateAndStore(ctx context.Context, hash string, req Request, labels metrics.Labels) error {
 	// queue the tile to be generated
-	res, err := p.queue.Send(req)
+	res, err := p.getQueue(req).SendContext(ctx, newInstrumentedRequest(req, labels))
 	if err != nil {
 		return err
 	}
 	// compress tile payload
-	res, err = p.compress(res)
+	compression := p.GetRequestCompression(req)
+	_, span := p.startSpan(ctx, trace.CompressSpan)
+	res, err = codec.Encode(compression, res)
+	span.SetError(err)
+	span.End()
 	if err != nil {
 		return err
 	}
@@ -312,73 +660,83 @@ func (p *Pipeline) generateAndStore(hash string, req Request) error {
 	}
 	defer store.Close()
 	// add tile to store
-	return store.Set(hash, res)
+	return p.set(ctx, store, hash, res, compression, labels)
 }
 
-func (p *Pipeline) getHash(req Request) string {
-	return fmt.Sprintf("%s:%s", req.GetHash(), p.GetHash())
+// startStoreSpan starts a span for the store operation.
+func (p *Pipeline) startStoreSpan(ctx context.Context, operation string) *trace.Span {
+	_, span := p.startSpan(ctx, trace.StoreSpan)
+	span.SetAttribute(trace.OperationAttribute, operation)
+	return span
 }
 
-func (p *Pipeline) compress(data []byte) ([]byte, error) {
-	var buffer bytes.Buffer
-	writer, ok := p.getWriter(&buffer)
-	if ok {
-		// compress
-		_, err := writer.Write(data)
-		if err != nil {
-			return nil, err
-		}
-		err = writer.Close()
-		if err != nil {
-			return nil, err
-		}
+// exists checks whether the data exists in the store, recording it as a hit
+// or miss.
+func (p *Pipeline) exists(ctx context.Context, store Store, hash string, labels metrics.Labels) (bool, error) {
+	span := p.startStoreSpan(ctx, "exists")
+	defer span.End()
+	start := time.Now()
+	exists, err := store.Exists(hash)
+	metrics.ObserveSince(metrics.StoreLatency, labels.With(metrics.OperationLabel, "exists"), start)
+	if err != nil {
+		span.SetError(err)
+		return false, err
 	}
-	return buffer.Bytes(), nil
+	span.SetAttribute(trace.HitAttribute, exists)
+	if exists {
+		metrics.Inc(metrics.StoreHits, labels)
+	} else {
+		metrics.Inc(metrics.StoreMisses, labels)
+	}
+	return exists, nil
 }
 
-func (p *Pipeline) decompress(data []byte) ([]byte, error) {
-	buffer := bytes.NewBuffer(data)
-	reader, ok, err := p.getReader(buffer)
-	if err != nil {
-		return nil, err
-	}
+// get retrieves the data from the store.
+func (p *Pipeline) get(ctx context.Context, store Store, hash string, labels metrics.Labels) ([]byte, error) {
+	span := p.startStoreSpan(ctx, "get")
+	defer span.End()
+	start := time.Now()
+	res, err := store.Get(hash)
+	metrics.ObserveSince(metrics.StoreLatency, labels.With(metrics.OperationLabel, "get"), start)
+	span.SetError(err)
+	return res, err
+}
+
+// set adds the data to the store, along with the name of the codec it is
+// compressed with if the store records it.
+func (p *Pipeline) set(ctx context.Context, store Store, hash string, data []byte, compression string, labels metrics.Labels) error {
+	span := p.startStoreSpan(ctx, "set")
+	defer span.End()
+	start := time.Now()
+	var err error
+	encoded, ok := store.(EncodedStore)
 	if ok {
-		// decompress
-		var err error
-		data, err = ioutil.ReadAll(reader)
-		if err != nil {
-			return nil, err
-		}
-		err = reader.Close()
-		if err != nil {
-			return nil, err
-		}
+		err = encoded.SetEncoded(hash, data, compression)
+	} else {
+		err = store.Set(hash, data)
 	}
-	return data, nil
+	metrics.ObserveSince(metrics.StoreLatency, labels.With(metrics.OperationLabel, "set"), start)
+	span.SetError(err)
+	return err
 }
 
-func (p *Pipeline) getReader(buffer io.Reader) (io.ReadCloser, bool, error) {
-	// use compression based reader if specified
-	switch p.compression {
-	case "gzip":
-		reader, err := gzip.NewReader(buffer)
-		return reader, true, err
-	case "zlib":
-		reader, err := zlib.NewReader(buffer)
-		return reader, true, err
-	default:
-		return nil, false, nil
-	}
+// getHash returns the key of the data for the request, in the form
+// `{uri}/{z}/{x}/{y}/{digest}` for tiles and `{uri}/meta/{digest}` for
+// metadata.
+func (p *Pipeline) getHash(req Request) string {
+	prefix := getURIPrefix(req.GetURI())
+	tile, ok := req.(*TileRequest)
+	if ok && tile.Coord != nil {
+		// tile keys include the coordinate so stores may lay them out by it
+		prefix += getCoordSegment(tile.Coord) + keySeparator
+	} else {
+		prefix += metaSegment + keySeparator
+	}
+	return prefix + getDigest(req.GetHash(), p.GetHash(), p.GetRequestCompression(req))
 }
 
-func (p *Pipeline) getWriter(buffer io.Writer) (io.WriteCloser, bool) {
-	// use compression based reader if specified
-	switch p.compression {
-	case "gzip":
-		return gzip.NewWriter(buffer), true
-	case "zlib":
-		return zlib.NewWriter(buffer), true
-	default:
-		return nil, false
-	}
+// getURIPrefix returns the prefix shared by the keys of all data generated for
+// the URI. The URI is escaped so that no prefix is shared across URIs.
+func getURIPrefix(uri string) string {
+	return url.QueryEscape(uri) + keySeparator
 }
@@ -0,0 +1,1022 @@
+package veldt_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/codec"
+	"github.com/unchartedsoftware/veldt/metrics"
+	"github.com/unchartedsoftware/veldt/query"
+	"github.com/unchartedsoftware/veldt/resilience"
+	"github.com/unchartedsoftware/veldt/tile"
+	"github.com/unchartedsoftware/veldt/trace"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+// basicStore hides the invalidation methods of the underlying store.
+type basicStore struct {
+	veldt.Store
+}
+
+type blockingTile struct {
+	release   chan bool
+	cancelled chan bool
+}
+
+func newBlockingTile() *blockingTile {
+	return &blockingTile{
+		release:   make(chan bool),
+		cancelled: make(chan bool, 1),
+	}
+}
+
+func (t *blockingTile) Parse(params map[string]interface{}) error {
+	return nil
+}
+
+func (t *blockingTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return t.CreateContext(context.Background(), uri, coord, query)
+}
+
+func (t *blockingTile) CreateContext(ctx context.Context, uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	select {
+	case <-t.release:
+		return []byte("tile"), nil
+	case <-ctx.Done():
+		t.cancelled <- true
+		return nil, ctx.Err()
+	}
+}
+
+type blockingMeta struct {
+	cancelled chan bool
+}
+
+func (m *blockingMeta) Parse(params map[string]interface{}) error {
+	return nil
+}
+
+func (m *blockingMeta) Create(uri string) ([]byte, error) {
+	return m.CreateContext(context.Background(), uri)
+}
+
+func (m *blockingMeta) CreateContext(ctx context.Context, uri string) ([]byte, error) {
+	<-ctx.Done()
+	m.cancelled <- true
+	return nil, ctx.Err()
+}
+
+type staticTile struct{}
+
+func (t *staticTile) Parse(params map[string]interface{}) error {
+	return nil
+}
+
+func (t *staticTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return []byte("tile"), nil
+}
+
+type topHitsTile struct {
+	tile.TopHits
+}
+
+func newTopHitsTile(sortOrder string) veldt.TileCtor {
+	return func() (veldt.Tile, error) {
+		t := &topHitsTile{}
+		t.SortOrder = sortOrder
+		return t, nil
+	}
+}
+
+func (t *topHitsTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return []byte("tile"), nil
+}
+
+type imageTile struct {
+	staticTile
+}
+
+func (t *imageTile) Compression() string {
+	return codec.Identity
+}
+
+type bivariateTile struct {
+	tile.Bivariate
+}
+
+func (t *bivariateTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return []byte("tile"), nil
+}
+
+type failingTile struct{}
+
+func (t *failingTile) Parse(params map[string]interface{}) error {
+	return nil
+}
+
+func (t *failingTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return nil, fmt.Errorf("failed")
+}
+
+// scriptedTile returns its error, if any, from its endpoint.
+type scriptedTile struct {
+	endpoint string
+	err      error
+}
+
+func (t *scriptedTile) Parse(params map[string]interface{}) error {
+	return nil
+}
+
+func (t *scriptedTile) GetEndpoint() string {
+	return t.endpoint
+}
+
+func (t *scriptedTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+	return []byte("tile"), nil
+}
+
+// flakyTile fails the provided number of times before succeeding.
+type flakyTile struct {
+	staticTile
+	failures int
+	attempts int
+	policy   *resilience.Policy
+}
+
+func (t *flakyTile) SetPolicy(policy *resilience.Policy) {
+	t.policy = policy
+}
+
+func (t *flakyTile) GetPolicy() *resilience.Policy {
+	return t.policy
+}
+
+func (t *flakyTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	t.attempts++
+	if t.attempts <= t.failures {
+		return nil, fmt.Errorf("failed")
+	}
+	return []byte("tile"), nil
+}
+
+type suffixRenderer struct {
+	Suffix string
+}
+
+func (r *suffixRenderer) Parse(params map[string]interface{}) error {
+	suffix, ok := params["suffix"].(string)
+	if !ok {
+		return fmt.Errorf("`suffix` parameter missing from renderer")
+	}
+	r.Suffix = suffix
+	return nil
+}
+
+func (r *suffixRenderer) Render(req *veldt.TileRequest, data []byte) ([]byte, error) {
+	return append(data, []byte(r.Suffix)...), nil
+}
+
+var _ = Describe("Pipeline", func() {
+
+	var pipeline *veldt.Pipeline
+	var tile *blockingTile
+	var req *veldt.TileRequest
+
+	BeforeEach(func() {
+		pipeline = veldt.NewPipeline()
+		pipeline.Store(NewMapStore().Ctor())
+		tile = newBlockingTile()
+		req = &veldt.TileRequest{
+			URI:   "test",
+			Coord: &binning.TileCoord{},
+			Tile:  tile,
+		}
+	})
+
+	Describe("GenerateContext", func() {
+
+		It("should cancel generation once the only waiter has gone", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			go func() {
+				time.Sleep(time.Millisecond * 100)
+				cancel()
+			}()
+			err := pipeline.GenerateContext(ctx, req)
+			Expect(err).To(Equal(context.Canceled))
+			Eventually(tile.cancelled).Should(Receive())
+		})
+
+		It("should start a new generation for requests after the only waiter has gone", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			Expect(pipeline.GenerateContext(ctx, req)).To(Equal(context.Canceled))
+			go func() {
+				tile.release <- true
+			}()
+			Expect(pipeline.Generate(req)).To(BeNil())
+		})
+
+		It("should cancel meta data generation once the only waiter has gone", func() {
+			meta := &blockingMeta{
+				cancelled: make(chan bool, 1),
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			go func() {
+				time.Sleep(time.Millisecond * 100)
+				cancel()
+			}()
+			err := pipeline.GenerateContext(ctx, &veldt.MetaRequest{
+				URI:  "test",
+				Meta: meta,
+			})
+			Expect(err).To(Equal(context.Canceled))
+			Eventually(meta.cancelled).Should(Receive())
+		})
+
+		It("should not cancel generation while other waiters remain", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			wg := sync.WaitGroup{}
+			wg.Add(2)
+			go func() {
+				Expect(pipeline.GenerateContext(ctx, req)).To(Equal(context.Canceled))
+				wg.Done()
+			}()
+			go func() {
+				Expect(pipeline.Generate(req)).To(BeNil())
+				wg.Done()
+			}()
+			time.Sleep(time.Millisecond * 100)
+			cancel()
+			time.Sleep(time.Millisecond * 100)
+			tile.release <- true
+			wg.Wait()
+			Expect(len(tile.cancelled)).To(Equal(0))
+		})
+
+	})
+
+	Describe("GenerateAndGetContext", func() {
+
+		It("should return the generated data", func() {
+			go func() {
+				tile.release <- true
+			}()
+			res, err := pipeline.GenerateAndGetContext(context.Background(), req)
+			Expect(err).To(BeNil())
+			Expect(res).To(Equal([]byte("tile")))
+		})
+
+	})
+
+	Describe("NewTileRequest", func() {
+
+		BeforeEach(func() {
+			pipeline.Tile("static", func() (veldt.Tile, error) {
+				return &staticTile{}, nil
+			})
+			pipeline.Render("suffix", func() (veldt.Renderer, error) {
+				return &suffixRenderer{}, nil
+			})
+		})
+
+		It("should render the generated data if a renderer is requested", func() {
+			req, err := pipeline.NewTileRequest(map[string]interface{}{
+				"uri":   "test",
+				"coord": map[string]interface{}{"z": 0.0, "x": 0.0, "y": 0.0},
+				"tile":  map[string]interface{}{"static": map[string]interface{}{}},
+				"render": map[string]interface{}{
+					"suffix": map[string]interface{}{"suffix": "-rendered"},
+				},
+			})
+			Expect(err).To(BeNil())
+			res, err := pipeline.GenerateAndGet(req)
+			Expect(err).To(BeNil())
+			Expect(res).To(Equal([]byte("tile-rendered")))
+		})
+
+		It("should store rendered and unrendered data under different hashes", func() {
+			args := map[string]interface{}{
+				"uri":   "test",
+				"coord": map[string]interface{}{"z": 0.0, "x": 0.0, "y": 0.0},
+				"tile":  map[string]interface{}{"static": map[string]interface{}{}},
+			}
+			plain, err := pipeline.NewTileRequest(args)
+			Expect(err).To(BeNil())
+			args["render"] = map[string]interface{}{
+				"suffix": map[string]interface{}{"suffix": "-rendered"},
+			}
+			rendered, err := pipeline.NewTileRequest(args)
+			Expect(err).To(BeNil())
+			Expect(pipeline.GetRequestHash(plain)).NotTo(Equal(pipeline.GetRequestHash(rendered)))
+		})
+
+		It("should return an error for an unrecognized renderer", func() {
+			_, err := pipeline.NewTileRequest(map[string]interface{}{
+				"uri":    "test",
+				"coord":  map[string]interface{}{"z": 0.0, "x": 0.0, "y": 0.0},
+				"tile":   map[string]interface{}{"static": map[string]interface{}{}},
+				"render": map[string]interface{}{"missing": map[string]interface{}{}},
+			})
+			Expect(err).NotTo(BeNil())
+		})
+
+	})
+
+	Describe("Invalidate", func() {
+
+		newRequest := func(uri string) *veldt.TileRequest {
+			return &veldt.TileRequest{
+				URI:   uri,
+				Coord: &binning.TileCoord{},
+				Tile:  &staticTile{},
+			}
+		}
+
+		exists := func(req veldt.Request) bool {
+			store, err := pipeline.GetStore()
+			Expect(err).To(BeNil())
+			exists, err := store.Exists(pipeline.GetRequestHash(req))
+			Expect(err).To(BeNil())
+			return exists
+		}
+
+		It("should remove the data generated for the URI only", func() {
+			a := newRequest("a")
+			ab := newRequest("a:b")
+			Expect(pipeline.Generate(a)).To(BeNil())
+			Expect(pipeline.Generate(ab)).To(BeNil())
+			err := pipeline.Invalidate("a")
+			Expect(err).To(BeNil())
+			Expect(exists(a)).To(BeFalse())
+			Expect(exists(ab)).To(BeTrue())
+		})
+
+		It("should return an error if the store does not support invalidation", func() {
+			store := NewMapStore()
+			pipeline.Store(func() (veldt.Store, error) {
+				return &basicStore{store}, nil
+			})
+			err := pipeline.Invalidate("a")
+			Expect(err).NotTo(BeNil())
+		})
+
+	})
+
+	Describe("ParseKey", func() {
+
+		It("should parse the URI and coordinate of a tile key", func() {
+			req := &veldt.TileRequest{
+				URI:   "a:b/c",
+				Coord: &binning.TileCoord{Z: 4, X: 12, Y: 8},
+				Tile:  &staticTile{},
+			}
+			uri, coord, err := veldt.ParseKey(pipeline.GetRequestHash(req))
+			Expect(err).To(BeNil())
+			Expect(uri).To(Equal("a:b/c"))
+			Expect(coord).To(Equal(&binning.TileCoord{Z: 4, X: 12, Y: 8}))
+		})
+
+		It("should parse the URI of a meta key", func() {
+			req := &veldt.MetaRequest{
+				URI: "a",
+			}
+			uri, coord, err := veldt.ParseKey(pipeline.GetRequestHash(req))
+			Expect(err).To(BeNil())
+			Expect(uri).To(Equal("a"))
+			Expect(coord).To(BeNil())
+		})
+
+		It("should return an error for a key not generated by a pipeline", func() {
+			_, _, err := veldt.ParseKey("key")
+			Expect(err).NotTo(BeNil())
+		})
+
+	})
+
+	Describe("GetRequestHash", func() {
+
+		args := func(fields []interface{}, values []interface{}) map[string]interface{} {
+			return map[string]interface{}{
+				"uri":   "test",
+				"coord": map[string]interface{}{"z": 0.0, "x": 0.0, "y": 0.0},
+				"tile": map[string]interface{}{
+					"top": map[string]interface{}{
+						"hitsCount":     10.0,
+						"includeFields": fields,
+					},
+				},
+				"query": map[string]interface{}{
+					"has": map[string]interface{}{
+						"field":  "name",
+						"values": values,
+					},
+				},
+			}
+		}
+
+		hash := func(args map[string]interface{}) string {
+			req, err := pipeline.NewTileRequest(args)
+			Expect(err).To(BeNil())
+			return pipeline.GetRequestHash(req)
+		}
+
+		BeforeEach(func() {
+			pipeline.Tile("top", newTopHitsTile("desc"))
+			pipeline.Query("has", func() (veldt.Query, error) {
+				return &query.Has{}, nil
+			})
+		})
+
+		It("should return a readable key of the URI, coordinate and digest", func() {
+			key := hash(args([]interface{}{"a"}, []interface{}{"b"}))
+			Expect(key).To(MatchRegexp("^test/0/0/0/[0-9a-f]{64}$"))
+		})
+
+		It("should return the same hash for equivalent requests", func() {
+			a := hash(args([]interface{}{"a", "b", "c"}, []interface{}{"x", 1.0, true}))
+			b := hash(args([]interface{}{"c", "a", "b"}, []interface{}{true, "x", 1.0}))
+			Expect(a).To(Equal(b))
+		})
+
+		It("should return different hashes for different requests", func() {
+			a := hash(args([]interface{}{"a", "b"}, []interface{}{"x"}))
+			b := hash(args([]interface{}{"a", "c"}, []interface{}{"x"}))
+			Expect(a).NotTo(Equal(b))
+		})
+
+		It("should return different hashes for differently configured types", func() {
+			a := hash(args([]interface{}{"a"}, []interface{}{"x"}))
+			pipeline.Tile("top", newTopHitsTile("asc"))
+			b := hash(args([]interface{}{"a"}, []interface{}{"x"}))
+			Expect(a).NotTo(Equal(b))
+		})
+
+		It("should return different hashes for different stores", func() {
+			a := hash(args([]interface{}{"a"}, []interface{}{"x"}))
+			pipeline.Store(func() (veldt.Store, error) {
+				return &basicStore{}, nil
+			})
+			b := hash(args([]interface{}{"a"}, []interface{}{"x"}))
+			Expect(a).NotTo(Equal(b))
+		})
+
+	})
+
+	Describe("SetCompression", func() {
+
+		newRequest := func(t veldt.Tile) *veldt.TileRequest {
+			return &veldt.TileRequest{
+				URI:   "test",
+				Coord: &binning.TileCoord{},
+				Tile:  t,
+			}
+		}
+
+		It("should compress stored data with the codec", func() {
+			pipeline.SetCompression(codec.Zlib)
+			req := newRequest(&staticTile{})
+			data, name, err := pipeline.GenerateAndGetEncoded(req)
+			Expect(err).To(BeNil())
+			Expect(name).To(Equal(codec.Zlib))
+			decoded, err := codec.Decode(codec.Zlib, data)
+			Expect(err).To(BeNil())
+			Expect(decoded).To(Equal([]byte("tile")))
+			res, err := pipeline.Get(req)
+			Expect(err).To(BeNil())
+			Expect(res).To(Equal([]byte("tile")))
+		})
+
+		It("should use the codec selected by the tile", func() {
+			pipeline.SetCompression(codec.Zlib)
+			req := newRequest(&imageTile{})
+			data, name, err := pipeline.GenerateAndGetEncoded(req)
+			Expect(err).To(BeNil())
+			Expect(name).To(Equal(codec.Identity))
+			Expect(data).To(Equal([]byte("tile")))
+		})
+
+		It("should store data compressed with different codecs under different hashes", func() {
+			req := newRequest(&staticTile{})
+			a := pipeline.GetRequestHash(req)
+			pipeline.SetCompression(codec.Zlib)
+			b := pipeline.GetRequestHash(req)
+			Expect(a).NotTo(Equal(b))
+		})
+
+		It("should return an error for an unrecognized codec", func() {
+			pipeline.SetCompression("missing")
+			err := pipeline.Generate(newRequest(&staticTile{}))
+			Expect(err).NotTo(BeNil())
+		})
+
+	})
+
+	Describe("Schematic", func() {
+
+		BeforeEach(func() {
+			pipeline.Tile("heatmap", func() (veldt.Tile, error) {
+				return &bivariateTile{}, nil
+			})
+			pipeline.Query("range", func() (veldt.Query, error) {
+				return &query.Range{}, nil
+			})
+		})
+
+		newArgs := func(tile map[string]interface{}, query map[string]interface{}) map[string]interface{} {
+			return map[string]interface{}{
+				"uri":   "test",
+				"coord": map[string]interface{}{"z": 0.0, "x": 0.0, "y": 0.0},
+				"tile":  map[string]interface{}{"heatmap": tile},
+				"query": map[string]interface{}{"range": query},
+			}
+		}
+
+		bounds := map[string]interface{}{
+			"xField": "x",
+			"yField": "y",
+			"left":   0.0,
+			"right":  256.0,
+			"bottom": 0.0,
+			"top":    256.0,
+		}
+
+		rang := map[string]interface{}{
+			"field": "age",
+			"gte":   19.0,
+		}
+
+		It("should accept parameters matching the schema", func() {
+			_, err := pipeline.NewTileRequest(newArgs(bounds, rang))
+			Expect(err).To(BeNil())
+		})
+
+		It("should report every invalid parameter at once", func() {
+			_, err := pipeline.NewTileRequest(newArgs(map[string]interface{}{
+				"xField":     "x",
+				"resolution": 1.5,
+				"left":       0.0,
+				"right":      256.0,
+				"bottom":     0.0,
+				"top":        256.0,
+				"unknown":    true,
+			}, map[string]interface{}{
+				"field": 4.0,
+			}))
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("`yField` parameter is required"))
+			Expect(err.Error()).To(ContainSubstring("`resolution` is not of type `integer`"))
+			Expect(err.Error()).To(ContainSubstring("`unknown` is not a recognized parameter"))
+			Expect(err.Error()).To(ContainSubstring("`field` is not of type `string`"))
+		})
+
+		It("should report the parse error if the schema is satisfied", func() {
+			_, err := pipeline.NewTileRequest(newArgs(bounds, map[string]interface{}{
+				"field": "age",
+				"gte":   19.0,
+				"gt":    18.0,
+			}))
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("both `gte` and `gt` have been provided"))
+		})
+
+	})
+
+	Describe("filter expressions", func() {
+
+		BeforeEach(func() {
+			pipeline.Tile("static", func() (veldt.Tile, error) {
+				return &staticTile{}, nil
+			})
+			pipeline.Query("has", func() (veldt.Query, error) {
+				return &query.Has{}, nil
+			})
+			pipeline.Query("range", func() (veldt.Query, error) {
+				return &query.Range{}, nil
+			})
+			pipeline.Binary(func() (veldt.Query, error) {
+				return &veldt.BinaryExpression{}, nil
+			})
+			pipeline.Unary(func() (veldt.Query, error) {
+				return &veldt.UnaryExpression{}, nil
+			})
+		})
+
+		newArgs := func(query interface{}) map[string]interface{} {
+			return map[string]interface{}{
+				"uri":   "test",
+				"coord": map[string]interface{}{"z": 0.0, "x": 0.0, "y": 0.0},
+				"tile":  map[string]interface{}{"static": map[string]interface{}{}},
+				"query": query,
+			}
+		}
+
+		It("should accept a filter expression as the query", func() {
+			req, err := pipeline.NewTileRequest(newArgs(`age >= 18 AND NOT tag IN ("a", "b")`))
+			Expect(err).To(BeNil())
+			binary, ok := req.Query.(*veldt.BinaryExpression)
+			Expect(ok).To(Equal(true))
+			Expect(binary.Op).To(Equal(veldt.And))
+		})
+
+		It("should hash a filter expression the same as its JSON form", func() {
+			a, err := pipeline.NewTileRequest(newArgs(`tag IN ("b", "a") OR age < 18`))
+			Expect(err).To(BeNil())
+			b, err := pipeline.NewTileRequest(newArgs([]interface{}{
+				map[string]interface{}{
+					"has": map[string]interface{}{
+						"field":  "tag",
+						"values": []interface{}{"a", "b"},
+					},
+				},
+				"OR",
+				map[string]interface{}{
+					"range": map[string]interface{}{
+						"field": "age",
+						"lt":    18.0,
+					},
+				},
+			}))
+			Expect(err).To(BeNil())
+			Expect(pipeline.GetRequestHash(a)).To(Equal(pipeline.GetRequestHash(b)))
+		})
+
+		It("should return the syntax error of an invalid filter expression", func() {
+			_, err := pipeline.NewTileRequest(newArgs(`age >= AND`))
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("expected a value, found `AND` at line 1, column 8"))
+		})
+
+		It("should return the error of an unregistered query type", func() {
+			_, err := pipeline.NewTileRequest(newArgs(`name = "john"`))
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should hash logically equivalent queries the same", func() {
+			a, err := pipeline.NewTileRequest(newArgs(`NOT (age >= 18 OR NOT tag IN ("a", "b"))`))
+			Expect(err).To(BeNil())
+			b, err := pipeline.NewTileRequest(newArgs(`tag IN ("b", "a") AND NOT NOT NOT age >= 18`))
+			Expect(err).To(BeNil())
+			Expect(pipeline.GetRequestHash(a)).To(Equal(pipeline.GetRequestHash(b)))
+		})
+
+		It("should translate the normalized query", func() {
+			req, err := pipeline.NewTileRequest(newArgs(`age >= 18 AND (age < 65 AND NOT NOT age > 20)`))
+			Expect(err).To(BeNil())
+			Expect(req.Query).To(Equal(&query.Range{
+				Field: "age",
+				GT:    20.0,
+				LT:    65.0,
+			}))
+		})
+
+		It("should remove a query that every document matches", func() {
+			req, err := pipeline.NewTileRequest(newArgs(`age >= 18 OR NOT age >= 18`))
+			Expect(err).To(BeNil())
+			Expect(req.Query).To(BeNil())
+		})
+
+	})
+
+	Describe("Describe", func() {
+
+		It("should list the registered types with their schemas", func() {
+			pipeline.Tile("heatmap", func() (veldt.Tile, error) {
+				return &bivariateTile{}, nil
+			})
+			pipeline.Tile("static", func() (veldt.Tile, error) {
+				return &staticTile{}, nil
+			})
+			pipeline.Query("range", func() (veldt.Query, error) {
+				return &query.Range{}, nil
+			})
+			desc := pipeline.Describe()
+			Expect(desc.Tiles["heatmap"].Schema.Required).To(ConsistOf(
+				"xField", "yField", "left", "right", "bottom", "top"))
+			Expect(desc.Tiles["static"].Schema).To(BeNil())
+			Expect(desc.Queries["range"].Schema.Required).To(Equal([]string{"field"}))
+			Expect(desc.Operators).To(Equal([]string{}))
+		})
+
+		It("should list the operators of query expressions", func() {
+			pipeline.Binary(func() (veldt.Query, error) {
+				return &veldt.BinaryExpression{}, nil
+			})
+			pipeline.Unary(func() (veldt.Query, error) {
+				return &veldt.UnaryExpression{}, nil
+			})
+			Expect(pipeline.Describe().Operators).To(Equal([]string{veldt.And, veldt.Or, veldt.Not}))
+		})
+
+		It("should list the encoding of the data generated by each type", func() {
+			pipeline.Tile("static", func() (veldt.Tile, error) {
+				return &staticTile{}, nil
+			})
+			pipeline.Tile("image", func() (veldt.Tile, error) {
+				return &imageTile{}, nil
+			})
+			desc := pipeline.Describe()
+			Expect(desc.Compression).To(Equal(codec.Gzip))
+			Expect(desc.Tiles["static"].Compression).To(Equal(codec.Gzip))
+			Expect(desc.Tiles["static"].ContentEncoding).To(Equal("gzip"))
+			Expect(desc.Tiles["image"].Compression).To(Equal(codec.Identity))
+			Expect(desc.Tiles["image"].ContentEncoding).To(Equal(""))
+		})
+
+	})
+
+	Describe("SetAdaptiveConcurrency", func() {
+
+		// generate generates a tile of each script in turn, at distinct
+		// coords such that none are retrieved from the store
+		generate := func(scripts ...*scriptedTile) {
+			for i, script := range scripts {
+				pipeline.Generate(&veldt.TileRequest{
+					URI: "test",
+					Coord: &binning.TileCoord{
+						X: uint32(i),
+						Z: 8,
+					},
+					Tile: script,
+				})
+			}
+		}
+
+		repeat := func(n int, script *scriptedTile) []*scriptedTile {
+			scripts := make([]*scriptedTile, n)
+			for i := range scripts {
+				scripts[i] = script
+			}
+			return scripts
+		}
+
+		BeforeEach(func() {
+			pipeline.SetAdaptiveConcurrency(2, 16)
+		})
+
+		It("should start each endpoint at the maximum limit", func() {
+			generate(&scriptedTile{endpoint: "a"}, &scriptedTile{endpoint: "b"})
+			Expect(pipeline.GetConcurrencyLimits()).To(Equal(map[string]int{
+				"a": 16,
+				"b": 16,
+			}))
+		})
+
+		It("should decrease the limit of an endpoint when it fails", func() {
+			failing := &scriptedTile{endpoint: "a", err: fmt.Errorf("failed")}
+			generate(repeat(3, failing)...)
+			Expect(pipeline.GetConcurrencyLimits()["a"]).To(Equal(6))
+		})
+
+		It("should not decrease the limits of other endpoints", func() {
+			failing := &scriptedTile{endpoint: "a", err: fmt.Errorf("failed")}
+			generate(failing, &scriptedTile{endpoint: "b"})
+			limits := pipeline.GetConcurrencyLimits()
+			Expect(limits["a"]).To(Equal(12))
+			Expect(limits["b"]).To(Equal(16))
+		})
+
+		It("should not decrease the limit below the minimum", func() {
+			failing := &scriptedTile{endpoint: "a", err: fmt.Errorf("failed")}
+			generate(repeat(10, failing)...)
+			Expect(pipeline.GetConcurrencyLimits()["a"]).To(Equal(2))
+		})
+
+	})
+
+	Describe("WithTilePolicy", func() {
+
+		var policy *resilience.Policy
+
+		newRequest := func(ctor veldt.TileCtor) *veldt.TileRequest {
+			t, err := ctor()
+			Expect(err).To(BeNil())
+			return &veldt.TileRequest{
+				URI:   "test",
+				Coord: &binning.TileCoord{},
+				Tile:  t,
+			}
+		}
+
+		newFlakyTile := func(failures int) veldt.TileCtor {
+			return func() (veldt.Tile, error) {
+				return &flakyTile{
+					failures: failures,
+				}, nil
+			}
+		}
+
+		BeforeEach(func() {
+			policy = resilience.NewPolicy()
+			policy.SetBackoff(time.Millisecond, time.Millisecond)
+		})
+
+		It("should generate tiles under the policy", func() {
+			policy.SetRetries(2)
+			req := newRequest(veldt.WithTilePolicy(newFlakyTile(2), policy))
+			res, err := pipeline.GenerateAndGet(req)
+			Expect(err).To(BeNil())
+			Expect(res).To(Equal([]byte("tile")))
+			Expect(req.Tile.(*flakyTile).attempts).To(Equal(3))
+		})
+
+		It("should fail fast while the breaker of the endpoint is open", func() {
+			policy.SetBreaker(1, time.Minute)
+			req := newRequest(veldt.WithTilePolicy(newFlakyTile(2), policy))
+			err := pipeline.Generate(req)
+			Expect(err).To(Equal(fmt.Errorf("failed")))
+			err = pipeline.Generate(&veldt.TileRequest{
+				URI:   "test",
+				Coord: &binning.TileCoord{X: 1, Z: 1},
+				Tile:  req.Tile,
+			})
+			Expect(err).To(BeAssignableToTypeOf(&resilience.OpenError{}))
+			Expect(req.Tile.(*flakyTile).attempts).To(Equal(1))
+		})
+
+		It("should return an error for tiles without policy support", func() {
+			ctor := veldt.WithTilePolicy(func() (veldt.Tile, error) {
+				return &staticTile{}, nil
+			}, policy)
+			_, err := ctor()
+			Expect(err).NotTo(BeNil())
+		})
+
+	})
+
+	Describe("Metrics", func() {
+
+		var sink *metrics.MemorySink
+		labels := metrics.Labels{
+			metrics.PipelineLabel: "metrics",
+		}
+
+		BeforeEach(func() {
+			sink = metrics.NewMemorySink()
+			metrics.SetSink(sink)
+			veldt.Register("metrics", pipeline)
+		})
+
+		AfterEach(func() {
+			metrics.SetSink(nil)
+		})
+
+		It("should record store hits and misses and create latency", func() {
+			req := &veldt.TileRequest{
+				URI:   "test",
+				Coord: &binning.TileCoord{},
+				Tile:  &staticTile{},
+			}
+			_, err := pipeline.GenerateAndGet(req)
+			Expect(err).To(BeNil())
+			_, err = pipeline.GenerateAndGet(req)
+			Expect(err).To(BeNil())
+			typeLabels := labels.With(metrics.TypeLabel, "staticTile").With(metrics.BackendLabel, "veldt_test")
+			Expect(sink.GetCounter(metrics.StoreMisses, typeLabels)).To(Equal(1.0))
+			Expect(sink.GetCounter(metrics.StoreHits, typeLabels)).To(Equal(1.0))
+			Expect(len(sink.GetHistogram(metrics.StoreLatency, typeLabels.With(metrics.OperationLabel, "get")))).To(Equal(2))
+			Expect(len(sink.GetHistogram(metrics.StoreLatency, typeLabels.With(metrics.OperationLabel, "set")))).To(Equal(1))
+			Expect(len(sink.GetHistogram(metrics.CreateLatency, typeLabels))).To(Equal(1))
+			Expect(sink.GetGauge(metrics.QueueDepth, labels)).To(Equal(0.0))
+		})
+
+		It("should record create errors", func() {
+			err := pipeline.Generate(&veldt.TileRequest{
+				URI:   "test",
+				Coord: &binning.TileCoord{},
+				Tile:  &failingTile{},
+			})
+			Expect(err).NotTo(BeNil())
+			createLabels := labels.With(metrics.TypeLabel, "failingTile").With(metrics.BackendLabel, "veldt_test")
+			Expect(sink.GetCounter(metrics.CreateErrors, createLabels)).To(Equal(1.0))
+		})
+
+		It("should record requests joining in-flight generation", func() {
+			wg := sync.WaitGroup{}
+			wg.Add(2)
+			for i := 0; i < 2; i++ {
+				go func() {
+					Expect(pipeline.Generate(req)).To(BeNil())
+					wg.Done()
+				}()
+			}
+			time.Sleep(time.Millisecond * 100)
+			tile.release <- true
+			wg.Wait()
+			promiseLabels := labels.With(metrics.TypeLabel, "blockingTile").With(metrics.BackendLabel, "veldt_test")
+			Expect(sink.GetCounter(metrics.PromiseHits, promiseLabels)).To(Equal(1.0))
+		})
+
+	})
+
+	Describe("Tracing", func() {
+
+		var exporter *trace.MemoryExporter
+		parent := trace.SpanContext{
+			TraceID: trace.TraceID{1},
+			SpanID:  trace.SpanID{2},
+			Sampled: true,
+		}
+
+		BeforeEach(func() {
+			exporter = trace.NewMemoryExporter()
+			trace.SetExporter(exporter)
+		})
+
+		AfterEach(func() {
+			trace.SetExporter(nil)
+		})
+
+		It("should trace generation as part of the caller's trace", func() {
+			ctx := trace.ContextWithRemoteSpanContext(context.Background(), parent)
+			_, err := pipeline.GenerateAndGetContext(ctx, &veldt.TileRequest{
+				URI:   "test",
+				Coord: &binning.TileCoord{},
+				Tile:  &staticTile{},
+			})
+			Expect(err).To(BeNil())
+			generate := exporter.GetSpans(trace.GenerateSpan)
+			Expect(len(generate)).To(Equal(1))
+			Expect(generate[0].ParentID).To(Equal(parent.SpanID))
+			Expect(generate[0].Attributes[trace.URIAttribute]).To(Equal("test"))
+			for _, span := range exporter.GetSpans() {
+				Expect(span.Context.TraceID).To(Equal(parent.TraceID))
+			}
+			// generation spans are children of the generate span
+			for _, name := range []string{trace.QueueSpan, trace.CreateSpan, trace.CompressSpan} {
+				spans := exporter.GetSpans(name)
+				Expect(len(spans)).To(Equal(1))
+				Expect(spans[0].ParentID).To(Equal(generate[0].Context.SpanID))
+			}
+			create := exporter.GetSpans(trace.CreateSpan)[0]
+			Expect(create.Attributes[trace.TypeAttribute]).To(Equal("staticTile"))
+			Expect(create.Attributes[trace.BackendAttribute]).To(Equal("veldt_test"))
+			store := exporter.GetSpans(trace.StoreSpan)
+			Expect(len(store)).To(Equal(3))
+			Expect(store[0].Attributes[trace.OperationAttribute]).To(Equal("exists"))
+			Expect(store[0].Attributes[trace.HitAttribute]).To(Equal(false))
+			Expect(store[1].Attributes[trace.OperationAttribute]).To(Equal("set"))
+			Expect(store[2].Attributes[trace.OperationAttribute]).To(Equal("get"))
+		})
+
+		It("should record errors on the spans", func() {
+			err := pipeline.GenerateContext(context.Background(), &veldt.TileRequest{
+				URI:   "test",
+				Coord: &binning.TileCoord{},
+				Tile:  &failingTile{},
+			})
+			Expect(err).NotTo(BeNil())
+			Expect(exporter.GetSpans(trace.CreateSpan)[0].Error).To(Equal(err))
+			Expect(exporter.GetSpans(trace.GenerateSpan)[0].Error).To(Equal(err))
+		})
+
+		It("should trace validation", func() {
+			_, err := pipeline.NewTileRequestContext(context.Background(), map[string]interface{}{})
+			Expect(err).NotTo(BeNil())
+			spans := exporter.GetSpans(trace.ValidateSpan)
+			Expect(len(spans)).To(Equal(1))
+			Expect(spans[0].Error).To(Equal(err))
+		})
+
+		It("should trace requests joining in-flight generation", func() {
+			wg := sync.WaitGroup{}
+			wg.Add(2)
+			for i := 0; i < 2; i++ {
+				go func() {
+					Expect(pipeline.Generate(req)).To(BeNil())
+					wg.Done()
+				}()
+			}
+			time.Sleep(time.Millisecond * 100)
+			tile.release <- true
+			wg.Wait()
+			Expect(len(exporter.GetSpans(trace.PromiseSpan))).To(Equal(1))
+			Expect(len(exporter.GetSpans(trace.CreateSpan))).To(Equal(1))
+		})
+
+		It("should not record spans of traces the caller has not sampled", func() {
+			unsampled := parent
+			unsampled.Sampled = false
+			ctx := trace.ContextWithRemoteSpanContext(context.Background(), unsampled)
+			_, err := pipeline.GenerateAndGetContext(ctx, &veldt.TileRequest{
+				URI:   "test",
+				Coord: &binning.TileCoord{},
+				Tile:  &staticTile{},
+			})
+			Expect(err).To(BeNil())
+			Expect(len(exporter.GetSpans())).To(Equal(0))
+		})
+
+	})
+
+})
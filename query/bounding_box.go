@@ -0,0 +1,28 @@
+package query
+
+import (
+	"github.com/unchartedsoftware/veldt/geometry"
+	"github.com/unchartedsoftware/veldt/schema"
+)
+
+// BoundingBox represents a bounding box query, checking that points are within
+// the bounds, inclusive of its edges. Geographic bounds are of longitude along
+// the x axis and latitude along the y axis.
+type BoundingBox struct {
+	Spatial
+	geometry.Bounds
+}
+
+// Parse parses the provided JSON object and populates the querys attributes.
+func (q *BoundingBox) Parse(params map[string]interface{}) error {
+	err := q.Spatial.Parse(params)
+	if err != nil {
+		return err
+	}
+	return q.Bounds.Parse(params)
+}
+
+// Schema returns the JSON Schema of the parameters of the query.
+func (q *BoundingBox) Schema() *schema.Schema {
+	return schema.Merge(q.Spatial.Schema(), q.Bounds.Schema())
+}
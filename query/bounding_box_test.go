@@ -0,0 +1,94 @@
+package query_test
+
+import (
+	"github.com/unchartedsoftware/veldt/query"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("BoundingBox", func() {
+
+	var box *query.BoundingBox
+
+	BeforeEach(func() {
+		box = &query.BoundingBox{}
+	})
+
+	Describe("Parse", func() {
+		It("should parse properties from the params argument", func() {
+			params := JSON(
+				`{
+					"field": "location",
+					"left": -10.0,
+					"right": 10.0,
+					"bottom": -5.0,
+					"top": 5.0
+				}`)
+			err := box.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(box.Field).To(Equal("location"))
+			Expect(box.Geographic).To(Equal(true))
+			Expect(box.IsPair()).To(Equal(false))
+			Expect(box.Left).To(Equal(-10.0))
+			Expect(box.Top).To(Equal(5.0))
+		})
+
+		It("should parse a pair of `xField` and `yField` properties", func() {
+			params := JSON(
+				`{
+					"xField": "x",
+					"yField": "y",
+					"left": 0.0,
+					"right": 256.0,
+					"bottom": 0.0,
+					"top": 256.0
+				}`)
+			err := box.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(box.XField).To(Equal("x"))
+			Expect(box.YField).To(Equal("y"))
+			Expect(box.Geographic).To(Equal(false))
+			Expect(box.IsPair()).To(Equal(true))
+		})
+
+		It("should return an error if both `field` and `xField` are specified", func() {
+			params := JSON(
+				`{
+					"field": "location",
+					"xField": "x",
+					"yField": "y",
+					"left": 0.0,
+					"right": 256.0,
+					"bottom": 0.0,
+					"top": 256.0
+				}`)
+			err := box.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should return an error if `yField` property is not specified", func() {
+			params := JSON(
+				`{
+					"xField": "x",
+					"left": 0.0,
+					"right": 256.0,
+					"bottom": 0.0,
+					"top": 256.0
+				}`)
+			err := box.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should return an error if the bounds are not specified", func() {
+			params := JSON(
+				`{
+					"field": "location"
+				}`)
+			err := box.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+})
@@ -3,6 +3,7 @@ package query
 import (
 	"fmt"
 
+	"github.com/unchartedsoftware/veldt/schema"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
@@ -27,3 +28,11 @@ func (q *Equals) Parse(params map[string]interface{}) error {
 	q.Value = value
 	return nil
 }
+
+// Schema returns the JSON Schema of the parameters of the query.
+func (q *Equals) Schema() *schema.Schema {
+	return schema.Object(map[string]*schema.Schema{
+		"field": schema.String("The field to compare."),
+		"value": schema.Any("The value the field must equal."),
+	}, "field", "value")
+}
@@ -3,6 +3,7 @@ package query
 import (
 	"fmt"
 
+	"github.com/unchartedsoftware/veldt/schema"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
@@ -20,3 +21,10 @@ func (q *Exists) Parse(params map[string]interface{}) error {
 	q.Field = field
 	return nil
 }
+
+// Schema returns the JSON Schema of the parameters of the query.
+func (q *Exists) Schema() *schema.Schema {
+	return schema.Object(map[string]*schema.Schema{
+		"field": schema.String("The field that must not be null."),
+	}, "field")
+}
@@ -0,0 +1,71 @@
+package query
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/unchartedsoftware/veldt/schema"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+const (
+	// AutoFuzziness selects the number of edits by the length of the value.
+	AutoFuzziness = -1
+	// MaxFuzziness is the maximum number of edits of a fuzzy query.
+	MaxFuzziness = 2
+)
+
+// Fuzzy represents a fuzzy query, checking if a string field is within a
+// number of single character insertions, deletions or substitutions of the
+// provided value.
+type Fuzzy struct {
+	Field     string
+	Value     string
+	Fuzziness int
+}
+
+// Parse parses the provided JSON object and populates the querys attributes.
+func (q *Fuzzy) Parse(params map[string]interface{}) error {
+	field, ok := json.GetString(params, "field")
+	if !ok {
+		return fmt.Errorf("`field` parameter missing from query")
+	}
+	value, ok := json.GetString(params, "value")
+	if !ok {
+		return fmt.Errorf("`value` parameter missing from query")
+	}
+	fuzziness := json.GetIntDefault(params, AutoFuzziness, "fuzziness")
+	if fuzziness != AutoFuzziness && (fuzziness < 0 || fuzziness > MaxFuzziness) {
+		return fmt.Errorf("`fuzziness` parameter must be between 0 and %d", MaxFuzziness)
+	}
+	q.Field = field
+	q.Value = value
+	q.Fuzziness = fuzziness
+	return nil
+}
+
+// Schema returns the JSON Schema of the parameters of the query.
+func (q *Fuzzy) Schema() *schema.Schema {
+	return schema.Object(map[string]*schema.Schema{
+		"field":     schema.String("The string field to match."),
+		"value":     schema.String("The value the field must approximately equal."),
+		"fuzziness": schema.Integer("The maximum number of edits, selected by the length of the value if not provided.").WithMinimum(0).WithMaximum(MaxFuzziness),
+	}, "field", "value")
+}
+
+// GetFuzziness returns the maximum number of edits. Automatic fuzziness allows
+// no edits of values of up to two characters, one edit of values of up to
+// five characters, and two edits otherwise.
+func (q *Fuzzy) GetFuzziness() int {
+	if q.Fuzziness != AutoFuzziness {
+		return q.Fuzziness
+	}
+	length := utf8.RuneCountInString(q.Value)
+	if length <= 2 {
+		return 0
+	}
+	if length <= 5 {
+		return 1
+	}
+	return 2
+}
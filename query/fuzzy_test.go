@@ -0,0 +1,69 @@
+package query_test
+
+import (
+	"github.com/unchartedsoftware/veldt/query"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("Fuzzy", func() {
+
+	var fuzzy *query.Fuzzy
+
+	BeforeEach(func() {
+		fuzzy = &query.Fuzzy{}
+	})
+
+	Describe("Parse", func() {
+		It("should parse properties from the params argument", func() {
+			params := JSON(
+				`{
+					"field": "name",
+					"value": "Alpha",
+					"fuzziness": 2
+				}`)
+			err := fuzzy.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(fuzzy.Field).To(Equal("name"))
+			Expect(fuzzy.Value).To(Equal("Alpha"))
+			Expect(fuzzy.Fuzziness).To(Equal(2))
+		})
+
+		It("should default to automatic fuzziness", func() {
+			params := JSON(
+				`{
+					"field": "name",
+					"value": "Alpha"
+				}`)
+			err := fuzzy.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(fuzzy.Fuzziness).To(Equal(query.AutoFuzziness))
+		})
+
+		It("should return an error if `fuzziness` property is out of range", func() {
+			params := JSON(
+				`{
+					"field": "name",
+					"value": "Alpha",
+					"fuzziness": 3
+				}`)
+			err := fuzzy.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("GetFuzziness", func() {
+		It("should select the number of edits by the length of the value", func() {
+			fuzzy.Fuzziness = query.AutoFuzziness
+			fuzzy.Value = "ab"
+			Expect(fuzzy.GetFuzziness()).To(Equal(0))
+			fuzzy.Value = "abcde"
+			Expect(fuzzy.GetFuzziness()).To(Equal(1))
+			fuzzy.Value = "abcdef"
+			Expect(fuzzy.GetFuzziness()).To(Equal(2))
+		})
+	})
+
+})
@@ -3,6 +3,7 @@ package query
 import (
 	"fmt"
 
+	"github.com/unchartedsoftware/veldt/schema"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
@@ -27,3 +28,16 @@ func (q *Has) Parse(params map[string]interface{}) error {
 	q.Values = values
 	return nil
 }
+
+// Schema returns the JSON Schema of the parameters of the query.
+func (q *Has) Schema() *schema.Schema {
+	return schema.Object(map[string]*schema.Schema{
+		"field":  schema.String("The field to compare."),
+		"values": schema.Array(schema.Any(""), "The values the field must have one or more of."),
+	}, "field", "values")
+}
+
+// Normalize sorts the `values` parameter, as its order is insignificant.
+func (q *Has) Normalize(params map[string]interface{}) {
+	json.SortArray(params, "values")
+}
@@ -3,6 +3,7 @@ package query
 import (
 	"fmt"
 
+	"github.com/unchartedsoftware/veldt/schema"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
@@ -28,3 +29,16 @@ func (q *MatchesString) Parse(params map[string]interface{}) error {
 	q.Match = match
 	return nil
 }
+
+// Schema returns the JSON Schema of the parameters of the query.
+func (q *MatchesString) Schema() *schema.Schema {
+	return schema.Object(map[string]*schema.Schema{
+		"match":  schema.String("The string to match."),
+		"fields": schema.Array(schema.String(""), "The fields to match the string against."),
+	}, "match", "fields")
+}
+
+// Normalize sorts the `fields` parameter, as its order is insignificant.
+func (q *MatchesString) Normalize(params map[string]interface{}) {
+	json.SortArray(params, "fields")
+}
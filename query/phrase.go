@@ -0,0 +1,49 @@
+package query
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt/schema"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// Phrase represents a phrase query, checking if a text field contains the
+// words of the phrase in order. Words are compared case-insensitively, and
+// the slop is the number of positions the words may be moved to match.
+type Phrase struct {
+	Field  string
+	Phrase string
+	Slop   int
+}
+
+// Parse parses the provided JSON object and populates the querys attributes.
+func (q *Phrase) Parse(params map[string]interface{}) error {
+	field, ok := json.GetString(params, "field")
+	if !ok {
+		return fmt.Errorf("`field` parameter missing from query")
+	}
+	phrase, ok := json.GetString(params, "phrase")
+	if !ok {
+		return fmt.Errorf("`phrase` parameter missing from query")
+	}
+	if phrase == "" {
+		return fmt.Errorf("`phrase` parameter is empty")
+	}
+	slop := json.GetIntDefault(params, 0, "slop")
+	if slop < 0 {
+		return fmt.Errorf("`slop` parameter is negative")
+	}
+	q.Field = field
+	q.Phrase = phrase
+	q.Slop = slop
+	return nil
+}
+
+// Schema returns the JSON Schema of the parameters of the query.
+func (q *Phrase) Schema() *schema.Schema {
+	return schema.Object(map[string]*schema.Schema{
+		"field":  schema.String("The text field to match."),
+		"phrase": schema.String("The phrase the field must contain."),
+		"slop":   schema.Integer("The number of positions the words may be moved to match.").WithMinimum(0).WithDefault(0),
+	}, "field", "phrase")
+}
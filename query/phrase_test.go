@@ -0,0 +1,46 @@
+package query_test
+
+import (
+	"github.com/unchartedsoftware/veldt/query"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("Phrase", func() {
+
+	var phrase *query.Phrase
+
+	BeforeEach(func() {
+		phrase = &query.Phrase{}
+	})
+
+	Describe("Parse", func() {
+		It("should parse properties from the params argument", func() {
+			params := JSON(
+				`{
+					"field": "text",
+					"phrase": "quick brown fox",
+					"slop": 1
+				}`)
+			err := phrase.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(phrase.Field).To(Equal("text"))
+			Expect(phrase.Phrase).To(Equal("quick brown fox"))
+			Expect(phrase.Slop).To(Equal(1))
+		})
+
+		It("should return an error if `slop` property is negative", func() {
+			params := JSON(
+				`{
+					"field": "text",
+					"phrase": "quick brown fox",
+					"slop": -1
+				}`)
+			err := phrase.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+})
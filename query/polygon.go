@@ -0,0 +1,45 @@
+package query
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt/geometry"
+	"github.com/unchartedsoftware/veldt/schema"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// Polygon represents a polygon query, checking that points are within a
+// GeoJSON polygon, and outside of its holes. Geographic polygons are of
+// longitude and latitude positions.
+type Polygon struct {
+	Spatial
+	Polygon *geometry.Polygon
+}
+
+// Parse parses the provided JSON object and populates the querys attributes.
+func (q *Polygon) Parse(params map[string]interface{}) error {
+	err := q.Spatial.Parse(params)
+	if err != nil {
+		return err
+	}
+	geom, ok := json.GetChild(params, "polygon")
+	if !ok {
+		return fmt.Errorf("`polygon` parameter missing from query")
+	}
+	polygon := &geometry.Polygon{}
+	err = polygon.Parse(geom)
+	if err != nil {
+		return err
+	}
+	q.Polygon = polygon
+	return nil
+}
+
+// Schema returns the JSON Schema of the parameters of the query.
+func (q *Polygon) Schema() *schema.Schema {
+	polygon := (&geometry.Polygon{}).Schema()
+	polygon.Description = "The GeoJSON Polygon geometry the points must be within."
+	return schema.Merge(q.Spatial.Schema(), schema.Object(map[string]*schema.Schema{
+		"polygon": polygon,
+	}, "polygon"))
+}
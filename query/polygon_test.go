@@ -0,0 +1,64 @@
+package query_test
+
+import (
+	"github.com/unchartedsoftware/veldt/query"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("Polygon", func() {
+
+	var polygon *query.Polygon
+
+	BeforeEach(func() {
+		polygon = &query.Polygon{}
+	})
+
+	Describe("Parse", func() {
+		It("should parse properties from the params argument", func() {
+			params := JSON(
+				`{
+					"xField": "x",
+					"yField": "y",
+					"polygon": {
+						"type": "Polygon",
+						"coordinates": [
+							[[0, 0], [10, 0], [10, 10], [0, 10], [0, 0]]
+						]
+					}
+				}`)
+			err := polygon.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(polygon.XField).To(Equal("x"))
+			Expect(polygon.YField).To(Equal("y"))
+			Expect(len(polygon.Polygon.Rings)).To(Equal(1))
+		})
+
+		It("should return an error if `polygon` property is not specified", func() {
+			params := JSON(
+				`{
+					"field": "location"
+				}`)
+			err := polygon.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should return an error if `polygon` property is not a valid polygon", func() {
+			params := JSON(
+				`{
+					"field": "location",
+					"polygon": {
+						"type": "Polygon",
+						"coordinates": [
+							[[0, 0], [10, 0], [10, 10]]
+						]
+					}
+				}`)
+			err := polygon.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+})
@@ -0,0 +1,44 @@
+package query
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt/schema"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// Prefix represents a prefix query, checking if a string field starts with the
+// provided prefix.
+type Prefix struct {
+	Field           string
+	Prefix          string
+	CaseInsensitive bool
+}
+
+// Parse parses the provided JSON object and populates the querys attributes.
+func (q *Prefix) Parse(params map[string]interface{}) error {
+	field, ok := json.GetString(params, "field")
+	if !ok {
+		return fmt.Errorf("`field` parameter missing from query")
+	}
+	prefix, ok := json.GetString(params, "prefix")
+	if !ok {
+		return fmt.Errorf("`prefix` parameter missing from query")
+	}
+	if prefix == "" {
+		return fmt.Errorf("`prefix` parameter is empty")
+	}
+	q.Field = field
+	q.Prefix = prefix
+	q.CaseInsensitive = json.GetBoolDefault(params, false, "caseInsensitive")
+	return nil
+}
+
+// Schema returns the JSON Schema of the parameters of the query.
+func (q *Prefix) Schema() *schema.Schema {
+	return schema.Object(map[string]*schema.Schema{
+		"field":           schema.String("The string field to match."),
+		"prefix":          schema.String("The prefix the field must start with."),
+		"caseInsensitive": schema.Boolean("Whether the match ignores case.").WithDefault(false),
+	}, "field", "prefix")
+}
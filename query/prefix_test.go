@@ -0,0 +1,54 @@
+package query_test
+
+import (
+	"github.com/unchartedsoftware/veldt/query"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("Prefix", func() {
+
+	var prefix *query.Prefix
+
+	BeforeEach(func() {
+		prefix = &query.Prefix{}
+	})
+
+	Describe("Parse", func() {
+		It("should parse properties from the params argument", func() {
+			params := JSON(
+				`{
+					"field": "name",
+					"prefix": "Al",
+					"caseInsensitive": true
+				}`)
+			err := prefix.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(prefix.Field).To(Equal("name"))
+			Expect(prefix.Prefix).To(Equal("Al"))
+			Expect(prefix.CaseInsensitive).To(Equal(true))
+		})
+
+		It("should return an error if `field` property is not specified", func() {
+			params := JSON(
+				`{
+					"prefix": "Al"
+				}`)
+			err := prefix.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should return an error if `prefix` property is empty", func() {
+			params := JSON(
+				`{
+					"field": "name",
+					"prefix": ""
+				}`)
+			err := prefix.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+})
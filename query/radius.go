@@ -0,0 +1,83 @@
+package query
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/unchartedsoftware/veldt/geometry"
+	"github.com/unchartedsoftware/veldt/schema"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// Radius represents a radius query, checking that points are within the
+// distance of the center, inclusive. Geographic centers are of longitude and
+// latitude, with distances in meters, otherwise distances are in the units of
+// the x and y fields.
+type Radius struct {
+	Spatial
+	Center   geometry.Coord
+	Distance float64
+}
+
+// Parse parses the provided JSON object and populates the querys attributes.
+func (q *Radius) Parse(params map[string]interface{}) error {
+	err := q.Spatial.Parse(params)
+	if err != nil {
+		return err
+	}
+	center, ok := json.GetFloatArray(params, "center")
+	if !ok || len(center) != 2 {
+		return fmt.Errorf("`center` parameter missing from query")
+	}
+	distance, ok := json.GetFloat(params, "distance")
+	if !ok {
+		return fmt.Errorf("`distance` parameter missing from query")
+	}
+	if distance < 0 {
+		return fmt.Errorf("`distance` parameter is negative")
+	}
+	q.Center = geometry.Coord{
+		X: center[0],
+		Y: center[1],
+	}
+	q.Distance = distance
+	return nil
+}
+
+// Schema returns the JSON Schema of the parameters of the query.
+func (q *Radius) Schema() *schema.Schema {
+	center := schema.Array(schema.Number(""), "The x and y, or longitude and latitude, of the center.").WithMinItems(2)
+	return schema.Merge(q.Spatial.Schema(), schema.Object(map[string]*schema.Schema{
+		"center":   center,
+		"distance": schema.Number("The distance from the center, in meters if geographic.").WithMinimum(0),
+	}, "center", "distance"))
+}
+
+// GetBounds returns the bounds that contain every point within the distance
+// of the center. Geographic bounds span every longitude if the radius
+// includes a pole or crosses the antimeridian.
+func (q *Radius) GetBounds() *geometry.Bounds {
+	if !q.Geographic {
+		return geometry.NewBounds(
+			q.Center.X-q.Distance,
+			q.Center.X+q.Distance,
+			q.Center.Y-q.Distance,
+			q.Center.Y+q.Distance)
+	}
+	// the angular distance in degrees
+	degrees := q.Distance / geometry.EarthRadius * 180 / math.Pi
+	bottom := q.Center.Y - degrees
+	top := q.Center.Y + degrees
+	if bottom <= -90 || top >= 90 {
+		return geometry.NewBounds(-180, 180, math.Max(bottom, -90), math.Min(top, 90))
+	}
+	// the widest longitude span of the circle about its center
+	angle := q.Distance / geometry.EarthRadius
+	span := math.Asin(math.Sin(angle)/math.Cos(q.Center.Y*math.Pi/180)) * 180 / math.Pi
+	left := q.Center.X - span
+	right := q.Center.X + span
+	if left < -180 || right > 180 {
+		return geometry.NewBounds(-180, 180, bottom, top)
+	}
+	return geometry.NewBounds(left, right, bottom, top)
+}
@@ -0,0 +1,109 @@
+package query_test
+
+import (
+	"github.com/unchartedsoftware/veldt/query"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("Radius", func() {
+
+	var radius *query.Radius
+
+	BeforeEach(func() {
+		radius = &query.Radius{}
+	})
+
+	Describe("Parse", func() {
+		It("should parse properties from the params argument", func() {
+			params := JSON(
+				`{
+					"field": "location",
+					"center": [-75.7, 45.4],
+					"distance": 1000.0
+				}`)
+			err := radius.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(radius.Field).To(Equal("location"))
+			Expect(radius.Center.X).To(Equal(-75.7))
+			Expect(radius.Center.Y).To(Equal(45.4))
+			Expect(radius.Distance).To(Equal(1000.0))
+		})
+
+		It("should return an error if `center` property is not specified", func() {
+			params := JSON(
+				`{
+					"field": "location",
+					"distance": 1000.0
+				}`)
+			err := radius.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should return an error if `distance` property is negative", func() {
+			params := JSON(
+				`{
+					"field": "location",
+					"center": [0, 0],
+					"distance": -1.0
+				}`)
+			err := radius.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("GetBounds", func() {
+		It("should return the planar bounds of the radius", func() {
+			params := JSON(
+				`{
+					"xField": "x",
+					"yField": "y",
+					"center": [10, 20],
+					"distance": 5.0
+				}`)
+			err := radius.Parse(params)
+			Expect(err).To(BeNil())
+			bounds := radius.GetBounds()
+			Expect(bounds.MinX()).To(Equal(5.0))
+			Expect(bounds.MaxX()).To(Equal(15.0))
+			Expect(bounds.MinY()).To(Equal(15.0))
+			Expect(bounds.MaxY()).To(Equal(25.0))
+		})
+
+		It("should return geographic bounds containing the radius", func() {
+			params := JSON(
+				`{
+					"field": "location",
+					"center": [0, 60],
+					"distance": 100000.0
+				}`)
+			err := radius.Parse(params)
+			Expect(err).To(BeNil())
+			bounds := radius.GetBounds()
+			// a degree of latitude is roughly 111km, a degree of longitude at
+			// 60 degrees latitude is half of that
+			Expect(bounds.MinY()).To(BeNumerically("~", 59.1, 0.01))
+			Expect(bounds.MaxY()).To(BeNumerically("~", 60.9, 0.01))
+			Expect(bounds.MinX()).To(BeNumerically("~", -1.8, 0.01))
+			Expect(bounds.MaxX()).To(BeNumerically("~", 1.8, 0.01))
+		})
+
+		It("should span every longitude if the radius includes a pole", func() {
+			params := JSON(
+				`{
+					"field": "location",
+					"center": [0, 89.5],
+					"distance": 100000.0
+				}`)
+			err := radius.Parse(params)
+			Expect(err).To(BeNil())
+			bounds := radius.GetBounds()
+			Expect(bounds.MinX()).To(Equal(-180.0))
+			Expect(bounds.MaxX()).To(Equal(180.0))
+			Expect(bounds.MaxY()).To(Equal(90.0))
+		})
+	})
+
+})
@@ -3,6 +3,7 @@ package query
 import (
 	"fmt"
 
+	"github.com/unchartedsoftware/veldt/schema"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
@@ -42,3 +43,14 @@ func (q *Range) Parse(params map[string]interface{}) error {
 	q.LT = lt
 	return nil
 }
+
+// Schema returns the JSON Schema of the parameters of the query.
+func (q *Range) Schema() *schema.Schema {
+	return schema.Object(map[string]*schema.Schema{
+		"field": schema.String("The field to compare."),
+		"gte":   schema.Any("The inclusive lower bound of the range."),
+		"gt":    schema.Any("The exclusive lower bound of the range."),
+		"lte":   schema.Any("The inclusive upper bound of the range."),
+		"lt":    schema.Any("The exclusive upper bound of the range."),
+	}, "field")
+}
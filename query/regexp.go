@@ -0,0 +1,48 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/unchartedsoftware/veldt/schema"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// Regexp represents a regular expression query, checking if the whole of a
+// string field matches the pattern. Backends differ in the syntax they
+// support beyond the common core of literals, character classes, grouping,
+// alternation and repetition, so the pattern is validated against that core.
+type Regexp struct {
+	Field           string
+	Pattern         string
+	CaseInsensitive bool
+}
+
+// Parse parses the provided JSON object and populates the querys attributes.
+func (q *Regexp) Parse(params map[string]interface{}) error {
+	field, ok := json.GetString(params, "field")
+	if !ok {
+		return fmt.Errorf("`field` parameter missing from query")
+	}
+	pattern, ok := json.GetString(params, "pattern")
+	if !ok {
+		return fmt.Errorf("`pattern` parameter missing from query")
+	}
+	_, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("`pattern` parameter is not a valid regular expression: %v", err)
+	}
+	q.Field = field
+	q.Pattern = pattern
+	q.CaseInsensitive = json.GetBoolDefault(params, false, "caseInsensitive")
+	return nil
+}
+
+// Schema returns the JSON Schema of the parameters of the query.
+func (q *Regexp) Schema() *schema.Schema {
+	return schema.Object(map[string]*schema.Schema{
+		"field":           schema.String("The string field to match."),
+		"pattern":         schema.String("The regular expression the whole field must match."),
+		"caseInsensitive": schema.Boolean("Whether the match ignores case.").WithDefault(false),
+	}, "field", "pattern")
+}
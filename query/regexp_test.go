@@ -0,0 +1,43 @@
+package query_test
+
+import (
+	"github.com/unchartedsoftware/veldt/query"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("Regexp", func() {
+
+	var re *query.Regexp
+
+	BeforeEach(func() {
+		re = &query.Regexp{}
+	})
+
+	Describe("Parse", func() {
+		It("should parse properties from the params argument", func() {
+			params := JSON(
+				`{
+					"field": "name",
+					"pattern": "[a-z]+(ta|ma)"
+				}`)
+			err := re.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(re.Field).To(Equal("name"))
+			Expect(re.Pattern).To(Equal("[a-z]+(ta|ma)"))
+		})
+
+		It("should return an error if `pattern` property is not a valid regular expression", func() {
+			params := JSON(
+				`{
+					"field": "name",
+					"pattern": "[a-z"
+				}`)
+			err := re.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+})
@@ -0,0 +1,64 @@
+package query
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt/schema"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// Spatial represents the location of the points filtered by a spatial query.
+// The location is either a single geo point field of longitude and latitude,
+// such as an elasticsearch geo_point or a PostGIS geometry, or a pair of
+// numeric x and y fields. A pair of fields holds planar coordinates, unless
+// it is geographic, in which case it holds longitude and latitude.
+type Spatial struct {
+	Field      string
+	XField     string
+	YField     string
+	Geographic bool
+}
+
+// Parse parses the provided JSON object and populates the location
+// attributes.
+func (s *Spatial) Parse(params map[string]interface{}) error {
+	field, ok := json.GetString(params, "field")
+	if ok {
+		if json.Exists(params, "xField") || json.Exists(params, "yField") {
+			return fmt.Errorf("`field` parameter may not be provided along with `xField` and `yField`")
+		}
+		s.Field = field
+		s.XField = ""
+		s.YField = ""
+		s.Geographic = true
+		return nil
+	}
+	xField, ok := json.GetString(params, "xField")
+	if !ok {
+		return fmt.Errorf("`field` or `xField` parameter missing from query")
+	}
+	yField, ok := json.GetString(params, "yField")
+	if !ok {
+		return fmt.Errorf("`yField` parameter missing from query")
+	}
+	s.Field = ""
+	s.XField = xField
+	s.YField = yField
+	s.Geographic = json.GetBoolDefault(params, false, "geographic")
+	return nil
+}
+
+// Schema returns the JSON Schema of the parameters of the location.
+func (s *Spatial) Schema() *schema.Schema {
+	return schema.Object(map[string]*schema.Schema{
+		"field":      schema.String("The geo point field of the longitude and latitude of each point."),
+		"xField":     schema.String("The field of the x value of each point, if `field` is not provided."),
+		"yField":     schema.String("The field of the y value of each point, if `field` is not provided."),
+		"geographic": schema.Boolean("Whether the x and y fields hold longitude and latitude, such that distances are in meters.").WithDefault(false),
+	})
+}
+
+// IsPair returns whether the location is a pair of x and y fields.
+func (s *Spatial) IsPair() bool {
+	return s.Field == ""
+}
@@ -0,0 +1,80 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/unchartedsoftware/veldt/schema"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// Wildcard represents a wildcard query, checking if the whole of a string
+// field matches the pattern, where `*` matches any sequence of characters and
+// `?` matches any single character. A backslash escapes the character that
+// follows it.
+type Wildcard struct {
+	Field           string
+	Pattern         string
+	CaseInsensitive bool
+}
+
+// Parse parses the provided JSON object and populates the querys attributes.
+func (q *Wildcard) Parse(params map[string]interface{}) error {
+	field, ok := json.GetString(params, "field")
+	if !ok {
+		return fmt.Errorf("`field` parameter missing from query")
+	}
+	pattern, ok := json.GetString(params, "pattern")
+	if !ok {
+		return fmt.Errorf("`pattern` parameter missing from query")
+	}
+	if pattern == "" {
+		return fmt.Errorf("`pattern` parameter is empty")
+	}
+	// an odd number of trailing backslashes leaves the last one unescaped
+	trailing := len(pattern) - len(strings.TrimRight(pattern, `\`))
+	if trailing%2 == 1 {
+		return fmt.Errorf("`pattern` parameter ends with an unescaped backslash")
+	}
+	q.Field = field
+	q.Pattern = pattern
+	q.CaseInsensitive = json.GetBoolDefault(params, false, "caseInsensitive")
+	return nil
+}
+
+// Schema returns the JSON Schema of the parameters of the query.
+func (q *Wildcard) Schema() *schema.Schema {
+	return schema.Object(map[string]*schema.Schema{
+		"field":           schema.String("The string field to match."),
+		"pattern":         schema.String("The pattern the field must match, where `*` matches any sequence of characters and `?` matches any single character."),
+		"caseInsensitive": schema.Boolean("Whether the match ignores case.").WithDefault(false),
+	}, "field", "pattern")
+}
+
+// WildcardToken represents a single element of a wildcard pattern.
+type WildcardToken struct {
+	// Any is `*` for any sequence of characters, `?` for any single
+	// character, or zero for a literal.
+	Any     rune
+	Literal rune
+}
+
+// GetTokens returns the pattern split into literal characters and unescaped
+// wildcards.
+func (q *Wildcard) GetTokens() []WildcardToken {
+	runes := []rune(q.Pattern)
+	tokens := make([]WildcardToken, 0, len(runes))
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\' && i+1 < len(runes):
+			i++
+			tokens = append(tokens, WildcardToken{Literal: runes[i]})
+		case r == '*' || r == '?':
+			tokens = append(tokens, WildcardToken{Any: r})
+		default:
+			tokens = append(tokens, WildcardToken{Literal: r})
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,72 @@
+package query_test
+
+import (
+	"github.com/unchartedsoftware/veldt/query"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var _ = Describe("Wildcard", func() {
+
+	var wildcard *query.Wildcard
+
+	BeforeEach(func() {
+		wildcard = &query.Wildcard{}
+	})
+
+	Describe("Parse", func() {
+		It("should parse properties from the params argument", func() {
+			params := JSON(
+				`{
+					"field": "name",
+					"pattern": "A*a?"
+				}`)
+			err := wildcard.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(wildcard.Field).To(Equal("name"))
+			Expect(wildcard.Pattern).To(Equal("A*a?"))
+			Expect(wildcard.CaseInsensitive).To(Equal(false))
+		})
+
+		It("should return an error if `pattern` property is not specified", func() {
+			params := JSON(
+				`{
+					"field": "name"
+				}`)
+			err := wildcard.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should return an error if `pattern` property ends with an unescaped backslash", func() {
+			params := JSON(
+				`{
+					"field": "name",
+					"pattern": "a*\\"
+				}`)
+			err := wildcard.Parse(params)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("GetTokens", func() {
+		It("should split the pattern into literals and unescaped wildcards", func() {
+			params := JSON(
+				`{
+					"field": "name",
+					"pattern": "a\\*b*?"
+				}`)
+			err := wildcard.Parse(params)
+			Expect(err).To(BeNil())
+			Expect(wildcard.GetTokens()).To(Equal([]query.WildcardToken{
+				{Literal: 'a'},
+				{Literal: '*'},
+				{Literal: 'b'},
+				{Any: '*'},
+				{Any: '?'},
+			}))
+		})
+	})
+
+})
@@ -2,23 +2,68 @@ package veldt
 
 import (
 	"fmt"
+	"sort"
+	"sync"
 )
 
 var (
 	// registry contains all registered tile generator constructors.
-	registry = make(map[string]*Pipeline)
+	registry      = make(map[string]*Pipeline)
+	registryMutex = sync.RWMutex{}
 )
 
 // Register registers a pipeline under the provided ID string.
 func Register(typeID string, p *Pipeline) {
+	p.setID(typeID)
+	registryMutex.Lock()
 	registry[typeID] = p
+	registryMutex.Unlock()
+}
+
+// Unregister removes the pipeline registered under the provided ID string.
+func Unregister(id string) {
+	registryMutex.Lock()
+	delete(registry, id)
+	registryMutex.Unlock()
+}
+
+// Replace unregisters the pipelines under the IDs to remove and registers the
+// provided pipelines in a single step, such that no request sees a partial
+// set of pipelines. Requests already made to a replaced pipeline complete
+// against it.
+func Replace(remove []string, pipelines map[string]*Pipeline) {
+	for id, p := range pipelines {
+		p.setID(id)
+	}
+	registryMutex.Lock()
+	for _, id := range remove {
+		delete(registry, id)
+	}
+	for id, p := range pipelines {
+		registry[id] = p
+	}
+	registryMutex.Unlock()
 }
 
 // GetPipeline retrieves the pipeline registered under the provided ID string.
 func GetPipeline(id string) (*Pipeline, error) {
+	registryMutex.RLock()
 	p, ok := registry[id]
+	registryMutex.RUnlock()
 	if !ok {
 		return nil, fmt.Errorf("Pipeline ID of '%s' is not recognized", id)
 	}
 	return p, nil
 }
+
+// GetPipelineIDs returns the sorted IDs of the registered pipelines.
+func GetPipelineIDs() []string {
+	registryMutex.RLock()
+	ids := make([]string, 0, len(registry))
+	for id := range registry {
+		ids = append(ids, id)
+	}
+	registryMutex.RUnlock()
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,34 @@
+package veldt
+
+import (
+	"github.com/unchartedsoftware/veldt/binning"
+)
+
+// Renderer represents an interface for rendering generated tile data into an
+// image.
+type Renderer interface {
+	// Parse parses a render request for future rendering
+	// parameter 1 (map[string]interface{}) Any parameters specifying how the
+	//             tile is to be rendered
+	Parse(map[string]interface{}) error
+	// Render renders the tile data generated for the request.
+	// parameter 1 (*TileRequest): the request the data was generated for
+	// parameter 2 ([]byte): the generated tile data
+	Render(*TileRequest, []byte) ([]byte, error)
+}
+
+// RendererCtor represents a function that instantiates and returns a new
+// renderer type.
+type RendererCtor func() (Renderer, error)
+
+// ExtremaTile represents an interface for tiles that can compute the extrema
+// of their values across every tile of a zoom level.
+type ExtremaTile interface {
+	Tile
+	// LevelExtrema returns the extrema of the tile values across a zoom level.
+	// parameter 1 (string): A dataset ID (typically called uri)
+	// parameter 2 (uint32): the zoom level
+	// parameter 3 (Query): A query to specify which data should be included in the
+	//             tile - essentially a filter
+	LevelExtrema(string, uint32, Query) (*binning.Extrema, error)
+}
@@ -0,0 +1,38 @@
+package render
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// decodeBins decodes heatmap or count tile data into its bins and resolution.
+// Heatmap tiles are encoded as little endian uint32 bins, while count tiles
+// are encoded as a JSON object and are treated as a single bin.
+func decodeBins(data []byte) ([]float64, int, error) {
+	// count tile
+	if len(data) > 0 && data[0] == '{' {
+		res, err := json.Unmarshal(data)
+		if err != nil {
+			return nil, 0, err
+		}
+		count, ok := json.GetFloat(res, "count")
+		if !ok {
+			return nil, 0, fmt.Errorf("count tile data is missing `count`")
+		}
+		return []float64{count}, 1, nil
+	}
+	// heatmap tile
+	numBins := len(data) / 4
+	resolution := int(math.Sqrt(float64(numBins)))
+	if len(data)%4 != 0 || resolution*resolution != numBins || numBins == 0 {
+		return nil, 0, fmt.Errorf("tile data of %d bytes is not a square grid of uint32 bins", len(data))
+	}
+	bins := make([]float64, numBins)
+	for i := range bins {
+		bins[i] = float64(binary.LittleEndian.Uint32(data[i*4 : i*4+4]))
+	}
+	return bins, resolution, nil
+}
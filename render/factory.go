@@ -0,0 +1,11 @@
+package render
+
+import (
+	"github.com/unchartedsoftware/veldt"
+)
+
+func init() {
+	veldt.RegisterRendererFactory("png", func(params map[string]interface{}) (veldt.RendererCtor, error) {
+		return NewPNGRenderer(), nil
+	})
+}
@@ -0,0 +1,181 @@
+package render
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"math"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/codec"
+	"github.com/unchartedsoftware/veldt/schema"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+// PNG represents a renderer that colours the bins of heatmap and count tiles
+// into a PNG image, with one pixel per bin. Empty bins are transparent. WebP
+// is not supported, as there is no pure Go encoder for it.
+//
+// Unless both `min` and `max` are provided, the missing extrema are computed
+// across the zoom level if the tile implements veldt.ExtremaTile, and from the
+// bins of the tile itself otherwise.
+//
+// Ex:
+//     {
+//         "png": {
+//             "ramp": "viridis",
+//             "transform": "log",
+//             "min": 0,
+//             "max": 1000
+//         }
+//     }
+//
+type PNG struct {
+	Ramp      string
+	Transform string
+	Min       *float64
+	Max       *float64
+}
+
+// NewPNGRenderer instantiates and returns a new renderer struct.
+func NewPNGRenderer() veldt.RendererCtor {
+	return func() (veldt.Renderer, error) {
+		return &PNG{}, nil
+	}
+}
+
+// Parse parses the provided JSON object and populates the renderers
+// attributes.
+func (p *PNG) Parse(params map[string]interface{}) error {
+	p.Ramp = json.GetStringDefault(params, "viridis", "ramp")
+	_, err := getRamp(p.Ramp)
+	if err != nil {
+		return err
+	}
+	p.Transform = json.GetStringDefault(params, "linear", "transform")
+	_, err = getTransform(p.Transform)
+	if err != nil {
+		return err
+	}
+	p.Min = nil
+	min, ok := json.GetFloat(params, "min")
+	if ok {
+		p.Min = &min
+	}
+	p.Max = nil
+	max, ok := json.GetFloat(params, "max")
+	if ok {
+		p.Max = &max
+	}
+	return nil
+}
+
+// Schema returns the JSON Schema of the parameters of the renderer.
+func (p *PNG) Schema() *schema.Schema {
+	ramps := Ramps()
+	names := make([]interface{}, len(ramps))
+	for i, name := range ramps {
+		names[i] = name
+	}
+	return schema.Object(map[string]*schema.Schema{
+		"ramp":      schema.String("The colour ramp of the bins.").WithEnum(names...).WithDefault("viridis"),
+		"transform": schema.String("The transform applied to the bin values.").WithEnum("linear", "log", "sqrt").WithDefault("linear"),
+		"min":       schema.Number("The minimum bin value, computed if omitted."),
+		"max":       schema.Number("The maximum bin value, computed if omitted."),
+	})
+}
+
+// Compression returns the `identity` codec, as PNG images are already
+// compressed.
+func (p *PNG) Compression() string {
+	return codec.Identity
+}
+
+// Render renders the tile data generated for the request into a PNG image.
+func (p *PNG) Render(req *veldt.TileRequest, data []byte) ([]byte, error) {
+	ramp, err := getRamp(p.Ramp)
+	if err != nil {
+		return nil, err
+	}
+	transform, err := getTransform(p.Transform)
+	if err != nil {
+		return nil, err
+	}
+	bins, resolution, err := decodeBins(data)
+	if err != nil {
+		return nil, err
+	}
+	extrema, err := p.getExtrema(req, bins)
+	if err != nil {
+		return nil, err
+	}
+	img := image.NewNRGBA(image.Rect(0, 0, resolution, resolution))
+	for i, bin := range bins {
+		if bin == 0 {
+			// leave empty bins transparent
+			continue
+		}
+		value := transform(bin, extrema.Min, extrema.Max)
+		// bins are indexed from the bottom-left, pixels from the top-left
+		x := i % resolution
+		y := resolution - 1 - i/resolution
+		img.SetNRGBA(x, y, ramp.color(value))
+	}
+	buffer := &bytes.Buffer{}
+	err = png.Encode(buffer, img)
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func (p *PNG) getExtrema(req *veldt.TileRequest, bins []float64) (*binning.Extrema, error) {
+	if p.Min != nil && p.Max != nil {
+		return &binning.Extrema{
+			Min: *p.Min,
+			Max: *p.Max,
+		}, nil
+	}
+	var extrema *binning.Extrema
+	tile, ok := req.Tile.(veldt.ExtremaTile)
+	if ok {
+		// compute across the zoom level
+		var err error
+		extrema, err = tile.LevelExtrema(req.URI, req.Coord.Z, req.Query)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if extrema == nil {
+		// compute from the tile itself
+		extrema = getBinExtrema(bins)
+	}
+	res := *extrema
+	if p.Min != nil {
+		res.Min = *p.Min
+	}
+	if p.Max != nil {
+		res.Max = *p.Max
+	}
+	return &res, nil
+}
+
+// getBinExtrema returns the extrema of the non-empty bins.
+func getBinExtrema(bins []float64) *binning.Extrema {
+	extrema := &binning.Extrema{
+		Min: math.Inf(1),
+		Max: math.Inf(-1),
+	}
+	for _, bin := range bins {
+		if bin != 0 {
+			extrema.Min = math.Min(extrema.Min, bin)
+			extrema.Max = math.Max(extrema.Max, bin)
+		}
+	}
+	if math.IsInf(extrema.Min, 1) {
+		// all bins are empty
+		return &binning.Extrema{}
+	}
+	return extrema
+}
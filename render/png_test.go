@@ -0,0 +1,138 @@
+package render_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/render"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+type heatmapTile struct{}
+
+func (t *heatmapTile) Parse(params map[string]interface{}) error {
+	return nil
+}
+
+func (t *heatmapTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return nil, nil
+}
+
+type extremaTile struct {
+	heatmapTile
+}
+
+func (t *extremaTile) LevelExtrema(uri string, z uint32, query veldt.Query) (*binning.Extrema, error) {
+	return &binning.Extrema{Min: 0, Max: 100}, nil
+}
+
+func encodeBins(bins []uint32) []byte {
+	data := make([]byte, len(bins)*4)
+	for i, bin := range bins {
+		binary.LittleEndian.PutUint32(data[i*4:i*4+4], bin)
+	}
+	return data
+}
+
+func renderImage(params string, tile veldt.Tile, data []byte) image.Image {
+	r, err := render.NewPNGRenderer()()
+	Expect(err).To(BeNil())
+	err = r.Parse(JSON(params))
+	Expect(err).To(BeNil())
+	res, err := r.Render(&veldt.TileRequest{
+		URI:   "test",
+		Coord: &binning.TileCoord{Z: 2},
+		Tile:  tile,
+	}, data)
+	Expect(err).To(BeNil())
+	img, err := png.Decode(bytes.NewReader(res))
+	Expect(err).To(BeNil())
+	return img
+}
+
+func nrgba(c color.Color) color.NRGBA {
+	return color.NRGBAModel.Convert(c).(color.NRGBA)
+}
+
+var _ = Describe("PNG", func() {
+
+	// bins are indexed from the bottom-left
+	data := encodeBins([]uint32{
+		0, 10,
+		5, 0,
+	})
+
+	Describe("Parse", func() {
+		It("should default to a linear viridis ramp", func() {
+			p := &render.PNG{}
+			err := p.Parse(JSON(`{}`))
+			Expect(err).To(BeNil())
+			Expect(p.Ramp).To(Equal("viridis"))
+			Expect(p.Transform).To(Equal("linear"))
+			Expect(p.Min).To(BeNil())
+			Expect(p.Max).To(BeNil())
+		})
+
+		It("should return an error for an unrecognized ramp", func() {
+			p := &render.PNG{}
+			err := p.Parse(JSON(`{"ramp": "rainbow"}`))
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should return an error for an unrecognized transform", func() {
+			p := &render.PNG{}
+			err := p.Parse(JSON(`{"transform": "cubic"}`))
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("Render", func() {
+		It("should render one pixel per bin, flipping the y axis", func() {
+			img := renderImage(`{"ramp": "greyscale"}`, &heatmapTile{}, data)
+			Expect(img.Bounds()).To(Equal(image.Rect(0, 0, 2, 2)))
+			// empty bins are transparent
+			Expect(nrgba(img.At(0, 1)).A).To(Equal(uint8(0)))
+			Expect(nrgba(img.At(1, 0)).A).To(Equal(uint8(0)))
+			// extrema are taken from the tile
+			Expect(nrgba(img.At(1, 1))).To(Equal(color.NRGBA{255, 255, 255, 255}))
+			Expect(nrgba(img.At(0, 0))).To(Equal(color.NRGBA{0, 0, 0, 255}))
+		})
+
+		It("should clamp values to the requested extrema", func() {
+			img := renderImage(`{"ramp": "greyscale", "min": 0, "max": 5}`, &heatmapTile{}, data)
+			Expect(nrgba(img.At(1, 1))).To(Equal(color.NRGBA{255, 255, 255, 255}))
+			Expect(nrgba(img.At(0, 0))).To(Equal(color.NRGBA{255, 255, 255, 255}))
+		})
+
+		It("should use the level extrema if the tile provides them", func() {
+			img := renderImage(`{"ramp": "greyscale"}`, &extremaTile{}, data)
+			Expect(nrgba(img.At(1, 1))).To(Equal(color.NRGBA{26, 26, 26, 255}))
+		})
+
+		It("should apply the value transform", func() {
+			img := renderImage(`{"ramp": "greyscale", "transform": "sqrt", "min": 0, "max": 100}`, &heatmapTile{}, data)
+			Expect(nrgba(img.At(1, 1))).To(Equal(color.NRGBA{81, 81, 81, 255}))
+		})
+
+		It("should render count tiles as a single pixel", func() {
+			img := renderImage(`{"ramp": "greyscale"}`, &heatmapTile{}, []byte(`{"count":3}`))
+			Expect(img.Bounds()).To(Equal(image.Rect(0, 0, 1, 1)))
+			Expect(nrgba(img.At(0, 0))).To(Equal(color.NRGBA{255, 255, 255, 255}))
+		})
+
+		It("should return an error if the data is not a square grid of bins", func() {
+			r, _ := render.NewPNGRenderer()()
+			Expect(r.Parse(JSON(`{}`))).To(BeNil())
+			_, err := r.Render(&veldt.TileRequest{Tile: &heatmapTile{}}, []byte{0, 0, 0})
+			Expect(err).NotTo(BeNil())
+		})
+	})
+})
@@ -0,0 +1,88 @@
+package render
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+)
+
+var (
+	ramps = map[string][]color.NRGBA{
+		"greyscale": {
+			{0, 0, 0, 255},
+			{255, 255, 255, 255},
+		},
+		"hot": {
+			{11, 0, 0, 255},
+			{230, 0, 0, 255},
+			{255, 210, 0, 255},
+			{255, 255, 255, 255},
+		},
+		"cool": {
+			{4, 32, 60, 255},
+			{20, 52, 120, 255},
+			{25, 90, 180, 255},
+			{180, 225, 255, 255},
+		},
+		"viridis": {
+			{68, 1, 84, 255},
+			{59, 82, 139, 255},
+			{33, 145, 140, 255},
+			{94, 201, 98, 255},
+			{253, 231, 37, 255},
+		},
+		"inferno": {
+			{0, 0, 4, 255},
+			{87, 16, 110, 255},
+			{188, 55, 84, 255},
+			{249, 142, 9, 255},
+			{252, 255, 164, 255},
+		},
+	}
+)
+
+// Ramps returns the names of the available colour ramps.
+func Ramps() []string {
+	names := make([]string, 0, len(ramps))
+	for name := range ramps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ramp represents a colour ramp of evenly spaced colour stops.
+type ramp []color.NRGBA
+
+func getRamp(name string) (ramp, error) {
+	stops, ok := ramps[name]
+	if !ok {
+		return nil, fmt.Errorf("unrecognized colour ramp `%s`", name)
+	}
+	return ramp(stops), nil
+}
+
+// color returns the interpolated colour of the normalized value in the range
+// [0 : 1].
+func (r ramp) color(value float64) color.NRGBA {
+	value = math.Max(0, math.Min(1, value))
+	scaled := value * float64(len(r)-1)
+	index := int(math.Floor(scaled))
+	if index >= len(r)-1 {
+		return r[len(r)-1]
+	}
+	t := scaled - float64(index)
+	a := r[index]
+	b := r[index+1]
+	return color.NRGBA{
+		R: lerp(a.R, b.R, t),
+		G: lerp(a.G, b.G, t),
+		B: lerp(a.B, b.B, t),
+		A: lerp(a.A, b.A, t),
+	}
+}
+
+func lerp(a uint8, b uint8, t float64) uint8 {
+	return uint8(math.Floor(float64(a) + (float64(b)-float64(a))*t + 0.5))
+}
@@ -0,0 +1,50 @@
+package render
+
+import (
+	"fmt"
+	"math"
+)
+
+// transform normalizes a value into the range [0 : 1] based on the extrema.
+type transform func(value float64, min float64, max float64) float64
+
+func getTransform(name string) (transform, error) {
+	switch name {
+	case "linear":
+		return linear, nil
+	case "log":
+		return logarithmic, nil
+	case "sqrt":
+		return squareRoot, nil
+	}
+	return nil, fmt.Errorf("unrecognized transform `%s`", name)
+}
+
+func normalize(value float64, min float64, max float64) float64 {
+	if max <= min {
+		// all values within the extrema are equal
+		if value < min {
+			return 0
+		}
+		return 1
+	}
+	return math.Max(0, math.Min(1, (value-min)/(max-min)))
+}
+
+func linear(value float64, min float64, max float64) float64 {
+	return normalize(value, min, max)
+}
+
+func logarithmic(value float64, min float64, max float64) float64 {
+	return normalize(
+		math.Log10(math.Max(0, value)+1),
+		math.Log10(math.Max(0, min)+1),
+		math.Log10(math.Max(0, max)+1))
+}
+
+func squareRoot(value float64, min float64, max float64) float64 {
+	return normalize(
+		math.Sqrt(math.Max(0, value)),
+		math.Sqrt(math.Max(0, min)),
+		math.Sqrt(math.Max(0, max)))
+}
@@ -1,59 +1,106 @@
 package veldt
 
 import (
-	"strings"
-
-	"github.com/davecgh/go-spew/spew"
+	"context"
 
 	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/util/queue"
 )
 
-var (
-	spewer *spew.ConfigState
-)
-
-func init() {
-	spewer = &spew.ConfigState{
-		Indent:                  "",
-		MaxDepth:                0,
-		DisableMethods:          true,
-		DisablePointerMethods:   true,
-		DisablePointerAddresses: true,
-		DisableCapacities:       true,
-		ContinueOnMethod:        false,
-		SortKeys:                true,
-		SpewKeys:                true,
-	}
-}
-
 // Request represents a basic request interface.
 type Request interface {
 	Create() ([]byte, error)
 	GetHash() string
+	GetURI() string
 }
 
-// TileRequest represents a tile data generation request.
+// TileRequest represents a tile data generation request. The priority and
+// client determine its scheduling in the pipeline queue, and are not part of
+// its hash.
 type TileRequest struct {
-	URI   string
-	Coord *binning.TileCoord
-	Query Query
-	Tile  Tile
+	URI      string
+	Coord    *binning.TileCoord
+	Query    Query
+	Tile     Tile
+	Render   Renderer
+	Priority queue.Priority
+	Client   string
+	hash     string
 }
 
 // Create generates and returns the tile for the request.
 func (r *TileRequest) Create() ([]byte, error) {
-	return r.Tile.Create(r.URI, r.Coord, r.Query)
+	data, err := r.Tile.Create(r.URI, r.Coord, r.Query)
+	if err != nil {
+		return nil, err
+	}
+	return r.render(data)
+}
+
+// CreateContext generates and returns the tile for the request. If the tile
+// supports cancellation, the context is passed through to it.
+func (r *TileRequest) CreateContext(ctx context.Context) ([]byte, error) {
+	tile, ok := r.Tile.(ContextTile)
+	if ok {
+		data, err := tile.CreateContext(ctx, r.URI, r.Coord, r.Query)
+		if err != nil {
+			return nil, err
+		}
+		return r.render(data)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return r.Create()
+}
+
+// render renders the generated tile data if the request has a renderer.
+func (r *TileRequest) render(data []byte) ([]byte, error) {
+	if r.Render == nil {
+		return data, nil
+	}
+	return r.Render.Render(r, data)
 }
 
-// GetHash returns a unique hash for the request.
+// GetHash returns a unique hash for the request, excluding its URI and
+// coordinate. Requests created from JSON are hashed from their normalized
+// JSON and the identity of the types registered to parse it. Requests
+// created directly are hashed from the exported fields of their tile, query
+// and renderer.
 func (r *TileRequest) GetHash() string {
-	return strings.Join(strings.Fields(spewer.Sdump(r)), "")
+	if r.hash != "" {
+		return r.hash
+	}
+	return getDigest(
+		getCanonical(r.Tile),
+		getCanonical(r.Query),
+		getCanonical(r.Render))
+}
+
+// GetURI returns the URI of the dataset the request is for.
+func (r *TileRequest) GetURI() string {
+	return r.URI
+}
+
+// GetPriority returns the scheduling priority of the request.
+func (r *TileRequest) GetPriority() queue.Priority {
+	return r.Priority
+}
+
+// GetClient returns the client the request is made on behalf of.
+func (r *TileRequest) GetClient() string {
+	return r.Client
 }
 
-// MetaRequest represents a meta data generation request.
+// MetaRequest represents a meta data generation request. The priority and
+// client determine its scheduling in the pipeline queue, and are not part of
+// its hash.
 type MetaRequest struct {
-	URI  string
-	Meta Meta
+	URI      string
+	Meta     Meta
+	Priority queue.Priority
+	Client   string
+	hash     string
 }
 
 // Create generates and returns the meta data for the request.
@@ -61,7 +108,41 @@ func (r *MetaRequest) Create() ([]byte, error) {
 	return r.Meta.Create(r.URI)
 }
 
-// GetHash returns a unique hash for the request.
+// CreateContext generates and returns the meta data for the request. If the
+// meta data supports cancellation, the context is passed through to it.
+func (r *MetaRequest) CreateContext(ctx context.Context) ([]byte, error) {
+	meta, ok := r.Meta.(ContextMeta)
+	if ok {
+		return meta.CreateContext(ctx, r.URI)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return r.Create()
+}
+
+// GetHash returns a unique hash for the request, excluding its URI. Requests
+// created from JSON are hashed from their normalized JSON and the identity of
+// the types registered to parse it. Requests created directly are hashed from
+// the exported fields of their metadata.
 func (r *MetaRequest) GetHash() string {
-	return strings.Join(strings.Fields(spewer.Sdump(r)), "")
+	if r.hash != "" {
+		return r.hash
+	}
+	return getDigest(getCanonical(r.Meta))
+}
+
+// GetURI returns the URI of the dataset the request is for.
+func (r *MetaRequest) GetURI() string {
+	return r.URI
+}
+
+// GetPriority returns the scheduling priority of the request.
+func (r *MetaRequest) GetPriority() queue.Priority {
+	return r.Priority
+}
+
+// GetClient returns the client the request is made on behalf of.
+func (r *MetaRequest) GetClient() string {
+	return r.Client
 }
@@ -0,0 +1,68 @@
+package veldt
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt/resilience"
+)
+
+// WithTilePolicy returns a tile constructor that sets the resilience policy of
+// the tiles it instantiates. The tile type must implement
+// resilience.Resilient.
+//
+// Ex:
+//     policy := resilience.NewPolicy()
+//     policy.SetTimeout(10 * time.Second)
+//     policy.SetRetries(2)
+//     policy.SetBreaker(5, 30 * time.Second)
+//     pipeline.Tile("heatmap", veldt.WithTilePolicy(elastic.NewHeatmapTile(host, port), policy))
+func WithTilePolicy(ctor TileCtor, policy *resilience.Policy) TileCtor {
+	return func() (Tile, error) {
+		tile, err := ctor()
+		if err != nil {
+			return nil, err
+		}
+		err = setPolicy(tile, policy)
+		if err != nil {
+			return nil, err
+		}
+		return tile, nil
+	}
+}
+
+// WithMetaPolicy returns a meta constructor that sets the resilience policy of
+// the metadata it instantiates. The meta type must implement
+// resilience.Resilient.
+func WithMetaPolicy(ctor MetaCtor, policy *resilience.Policy) MetaCtor {
+	return func() (Meta, error) {
+		meta, err := ctor()
+		if err != nil {
+			return nil, err
+		}
+		err = setPolicy(meta, policy)
+		if err != nil {
+			return nil, err
+		}
+		return meta, nil
+	}
+}
+
+func setPolicy(generator interface{}, policy *resilience.Policy) error {
+	resilient, ok := generator.(resilience.Resilient)
+	if !ok {
+		typ, backend := getTypeAndBackend(generator)
+		return fmt.Errorf("`%s.%s` does not support resilience policies", backend, typ)
+	}
+	resilient.SetPolicy(policy)
+	return nil
+}
+
+// getPolicy returns the resilience policy of the tile or metadata generator,
+// or nil if it has none.
+func getPolicy(generator interface{}) *resilience.Policy {
+	resilient, ok := generator.(resilience.Resilient)
+	if !ok {
+		return nil
+	}
+	return resilient.GetPolicy()
+}
@@ -0,0 +1,104 @@
+package resilience
+
+import (
+	"fmt"
+	"time"
+)
+
+// State represents the state of a circuit breaker.
+type State int
+
+const (
+	// Closed breakers let requests through.
+	Closed State = iota
+	// Open breakers fail requests fast until their cooldown has passed.
+	Open
+	// HalfOpen breakers have passed their cooldown, and let a single trial
+	// request through.
+	HalfOpen
+)
+
+// String returns the name of the state.
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("state(%d)", int(s))
+}
+
+// outcome represents the outcome of an attempt.
+type outcome int
+
+const (
+	succeeded outcome = iota
+	failed
+	abandoned
+)
+
+// breaker represents the circuit breaker of a single endpoint.
+type breaker struct {
+	failures int
+	open     bool
+	opened   time.Time
+	trial    bool
+}
+
+// getState returns the state of the breaker at the provided time.
+func (b *breaker) getState(now time.Time, cooldown time.Duration) State {
+	if !b.open {
+		return Closed
+	}
+	if now.Sub(b.opened) < cooldown {
+		return Open
+	}
+	return HalfOpen
+}
+
+// allow returns whether an attempt may be made, claiming the trial if the
+// breaker is half open.
+func (b *breaker) allow(now time.Time, cooldown time.Duration) bool {
+	switch b.getState(now, cooldown) {
+	case Closed:
+		return true
+	case HalfOpen:
+		if b.trial {
+			return false
+		}
+		b.trial = true
+		return true
+	}
+	return false
+}
+
+// getRetry returns the duration until the breaker lets a trial through.
+func (b *breaker) getRetry(now time.Time, cooldown time.Duration) time.Duration {
+	retry := cooldown - now.Sub(b.opened)
+	if retry < 0 {
+		return 0
+	}
+	return retry
+}
+
+// record records the outcome of an attempt.
+func (b *breaker) record(o outcome, now time.Time, threshold int) {
+	switch o {
+	case succeeded:
+		b.failures = 0
+		b.open = false
+		b.trial = false
+	case failed:
+		b.failures++
+		if b.trial || b.failures >= threshold {
+			b.open = true
+			b.opened = now
+		}
+		b.trial = false
+	case abandoned:
+		b.trial = false
+	}
+}
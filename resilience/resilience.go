@@ -0,0 +1,313 @@
+package resilience
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/unchartedsoftware/veldt/metrics"
+)
+
+const (
+	defaultTimeout    = time.Second * 60
+	defaultBackoff    = time.Millisecond * 100
+	defaultMaxBackoff = time.Second * 5
+	defaultCooldown   = time.Second * 30
+)
+
+// Resilient represents a tile or metadata type whose backend requests are made
+// under a policy.
+type Resilient interface {
+	SetPolicy(*Policy)
+	GetPolicy() *Policy
+}
+
+// OpenError is returned when a request is made to an endpoint while its
+// circuit breaker is open.
+type OpenError struct {
+	Endpoint string
+	Retry    time.Duration
+}
+
+// Error returns the error message.
+func (e *OpenError) Error() string {
+	return fmt.Sprintf("circuit breaker for endpoint `%s` is open, retry in %v",
+		e.Endpoint, e.Retry)
+}
+
+// PermanentError represents an error that will not be resolved by retrying
+// the request, such as a malformed query. Permanent errors are neither retried
+// nor counted against the circuit breaker, since the endpoint has responded.
+type PermanentError struct {
+	Err error
+}
+
+// Error returns the error message.
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+// Permanent wraps the error as a permanent error. A nil error is returned as
+// nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{
+		Err: err,
+	}
+}
+
+// IsPermanent returns whether the error is a permanent error.
+func IsPermanent(err error) bool {
+	_, ok := err.(*PermanentError)
+	return ok
+}
+
+// Policy represents the timeouts, retries and circuit breaking of the requests
+// made to a backend. Each endpoint of the backend has its own circuit breaker.
+// All methods are safe to call on a nil policy, which makes requests directly.
+type Policy struct {
+	mu         sync.Mutex
+	timeout    time.Duration
+	retries    int
+	backoff    time.Duration
+	maxBackoff time.Duration
+	threshold  int
+	cooldown   time.Duration
+	breakers   map[string]*breaker
+}
+
+// NewPolicy instantiates and returns a new policy, with no timeout, retries or
+// circuit breaker.
+func NewPolicy() *Policy {
+	return &Policy{
+		backoff:    defaultBackoff,
+		maxBackoff: defaultMaxBackoff,
+		cooldown:   defaultCooldown,
+		breakers:   make(map[string]*breaker),
+	}
+}
+
+// NewDefaultPolicy instantiates and returns a new policy with a 60 second
+// timeout, and no retries or circuit breaker. It is the policy of generators
+// that have not been given one.
+func NewDefaultPolicy() *Policy {
+	policy := NewPolicy()
+	policy.SetTimeout(defaultTimeout)
+	return policy
+}
+
+// SetTimeout sets the duration each attempt of a request may take before it is
+// cancelled. A duration of zero disables the timeout.
+func (p *Policy) SetTimeout(timeout time.Duration) {
+	p.mu.Lock()
+	p.timeout = timeout
+	p.mu.Unlock()
+}
+
+// SetRetries sets the number of times a failed request is retried. Retries
+// should only be enabled for backends where requests are idempotent.
+func (p *Policy) SetRetries(retries int) {
+	p.mu.Lock()
+	p.retries = retries
+	p.mu.Unlock()
+}
+
+// SetBackoff sets the backoff between retries, which doubles from the base
+// with each retry up to the maximum. Each backoff is jittered to between half
+// and all of its duration, such that retries of concurrent requests are
+// spread out.
+func (p *Policy) SetBackoff(base time.Duration, max time.Duration) {
+	p.mu.Lock()
+	p.backoff = base
+	p.maxBackoff = max
+	p.mu.Unlock()
+}
+
+// SetBreaker sets the number of consecutive failures after which the circuit
+// breaker of an endpoint opens, failing requests fast with an OpenError. Once
+// the cooldown has passed, a single trial request is let through, closing the
+// breaker if it succeeds, and opening it again if it fails. A threshold of
+// zero disables the breaker.
+func (p *Policy) SetBreaker(threshold int, cooldown time.Duration) {
+	p.mu.Lock()
+	p.threshold = threshold
+	p.cooldown = cooldown
+	p.mu.Unlock()
+}
+
+// GetState returns the state of the circuit breaker of the endpoint.
+func (p *Policy) GetState(endpoint string) State {
+	if p == nil {
+		return Closed
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	b, ok := p.breakers[endpoint]
+	if !ok {
+		return Closed
+	}
+	return b.getState(time.Now(), p.cooldown)
+}
+
+// Do makes the request to the endpoint under the policy. Each attempt is
+// passed a context that is cancelled once its timeout has passed, and is
+// abandoned if it does not return by then. Abandoned attempts are not
+// stopped, and no longer count against the concurrency of a queue, so
+// requests must honour the context to not overload the backend. Failed
+// attempts are retried unless the error is permanent or the provided context
+// is done. Permanent errors are returned as is, such that a queue does not
+// take them as a sign of congestion.
+func (p *Policy) Do(ctx context.Context, endpoint string, fn func(context.Context) ([]byte, error)) ([]byte, error) {
+	if p == nil {
+		return fn(ctx)
+	}
+	labels := metrics.Labels{
+		metrics.EndpointLabel: endpoint,
+	}
+	var res []byte
+	var err error
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			metrics.Inc(metrics.BackendRetries, labels)
+			if err := wait(ctx, p.getBackoff(attempt)); err != nil {
+				return nil, err
+			}
+		}
+		if err := p.allow(endpoint); err != nil {
+			metrics.Inc(metrics.BreakerRejections, labels)
+			return nil, err
+		}
+		res, err = p.attempt(ctx, fn)
+		if ctx.Err() != nil {
+			// abandoned requests say nothing of the endpoint
+			p.record(endpoint, abandoned)
+			return res, err
+		}
+		if err == nil {
+			p.record(endpoint, succeeded)
+			return res, nil
+		}
+		if IsPermanent(err) {
+			p.record(endpoint, succeeded)
+			return nil, err
+		}
+		p.record(endpoint, failed)
+		if attempt >= p.getRetries() {
+			return nil, err
+		}
+	}
+}
+
+type result struct {
+	res []byte
+	err error
+}
+
+// attempt makes a single attempt of the request, within the timeout. The
+// request is left to return by itself once the context is done.
+func (p *Policy) attempt(ctx context.Context, fn func(context.Context) ([]byte, error)) ([]byte, error) {
+	p.mu.Lock()
+	timeout := p.timeout
+	p.mu.Unlock()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	// buffered so that an abandoned attempt never blocks
+	done := make(chan result, 1)
+	go func() {
+		res, err := fn(ctx)
+		done <- result{
+			res: res,
+			err: err,
+		}
+	}()
+	select {
+	case r := <-done:
+		return r.res, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (p *Policy) getRetries() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.retries
+}
+
+// getBackoff returns the jittered backoff before the retry.
+func (p *Policy) getBackoff(attempt int) time.Duration {
+	p.mu.Lock()
+	backoff := p.backoff
+	max := p.maxBackoff
+	p.mu.Unlock()
+	for i := 1; i < attempt && backoff < max; i++ {
+		backoff *= 2
+	}
+	if backoff > max {
+		backoff = max
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(backoff-half)+1))
+}
+
+// allow returns an OpenError if the breaker of the endpoint is open.
+func (p *Policy) allow(endpoint string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.threshold <= 0 {
+		return nil
+	}
+	b := p.getBreaker(endpoint)
+	now := time.Now()
+	if b.allow(now, p.cooldown) {
+		return nil
+	}
+	return &OpenError{
+		Endpoint: endpoint,
+		Retry:    b.getRetry(now, p.cooldown),
+	}
+}
+
+// record records the outcome of an attempt against the breaker of the
+// endpoint.
+func (p *Policy) record(endpoint string, o outcome) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.threshold <= 0 {
+		return
+	}
+	p.getBreaker(endpoint).record(o, time.Now(), p.threshold)
+}
+
+// getBreaker returns the breaker of the endpoint. Must be called with the
+// lock held.
+func (p *Policy) getBreaker(endpoint string) *breaker {
+	b, ok := p.breakers[endpoint]
+	if !ok {
+		b = &breaker{}
+		p.breakers[endpoint] = b
+	}
+	return b
+}
+
+// wait waits for the duration, or until the context is done.
+func wait(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
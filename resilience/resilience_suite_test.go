@@ -0,0 +1,13 @@
+package resilience_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestResilience(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Resilience Suite")
+}
@@ -0,0 +1,194 @@
+package resilience_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/unchartedsoftware/veldt/resilience"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// script returns a request failing the provided number of times before
+// succeeding, and a pointer to the number of attempts made.
+func script(failures int, err error) (func(context.Context) ([]byte, error), *int) {
+	attempts := 0
+	return func(ctx context.Context) ([]byte, error) {
+		attempts++
+		if attempts <= failures {
+			return nil, err
+		}
+		return []byte("res"), nil
+	}, &attempts
+}
+
+var _ = Describe("Policy", func() {
+
+	var policy *resilience.Policy
+
+	BeforeEach(func() {
+		policy = resilience.NewPolicy()
+		policy.SetBackoff(time.Millisecond, time.Millisecond*10)
+	})
+
+	It("should make requests directly if nil", func() {
+		var nilPolicy *resilience.Policy
+		fn, attempts := script(0, nil)
+		res, err := nilPolicy.Do(context.Background(), "endpoint", fn)
+		Expect(err).To(BeNil())
+		Expect(res).To(Equal([]byte("res")))
+		Expect(*attempts).To(Equal(1))
+		Expect(nilPolicy.GetState("endpoint")).To(Equal(resilience.Closed))
+	})
+
+	Describe("NewDefaultPolicy", func() {
+
+		It("should pass attempts a context with a 60 second deadline", func() {
+			var deadline time.Time
+			var ok bool
+			fn := func(ctx context.Context) ([]byte, error) {
+				deadline, ok = ctx.Deadline()
+				return []byte("res"), nil
+			}
+			start := time.Now()
+			_, err := resilience.NewDefaultPolicy().Do(context.Background(), "endpoint", fn)
+			Expect(err).To(BeNil())
+			Expect(ok).To(BeTrue())
+			Expect(deadline.Sub(start)).To(BeNumerically("~", time.Second*60, time.Second))
+		})
+
+	})
+
+	Describe("SetTimeout", func() {
+
+		It("should cancel and abandon attempts once the timeout has passed", func() {
+			policy.SetTimeout(time.Millisecond * 50)
+			start := time.Now()
+			_, err := policy.Do(context.Background(), "endpoint", func(ctx context.Context) ([]byte, error) {
+				time.Sleep(time.Second)
+				return []byte("res"), nil
+			})
+			Expect(err).To(Equal(context.DeadlineExceeded))
+			Expect(time.Since(start)).To(BeNumerically("<", time.Millisecond*500))
+		})
+
+		It("should pass attempts a context with the deadline", func() {
+			policy.SetTimeout(time.Millisecond * 50)
+			_, err := policy.Do(context.Background(), "endpoint", func(ctx context.Context) ([]byte, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			})
+			Expect(err).To(Equal(context.DeadlineExceeded))
+		})
+
+	})
+
+	Describe("SetRetries", func() {
+
+		It("should retry failed requests", func() {
+			policy.SetRetries(2)
+			fn, attempts := script(2, fmt.Errorf("failed"))
+			res, err := policy.Do(context.Background(), "endpoint", fn)
+			Expect(err).To(BeNil())
+			Expect(res).To(Equal([]byte("res")))
+			Expect(*attempts).To(Equal(3))
+		})
+
+		It("should return the last error once retries are exhausted", func() {
+			policy.SetRetries(2)
+			fn, attempts := script(3, fmt.Errorf("failed"))
+			_, err := policy.Do(context.Background(), "endpoint", fn)
+			Expect(err).To(Equal(fmt.Errorf("failed")))
+			Expect(*attempts).To(Equal(3))
+		})
+
+		It("should not retry permanent errors", func() {
+			policy.SetRetries(2)
+			fn, attempts := script(3, resilience.Permanent(fmt.Errorf("bad request")))
+			_, err := policy.Do(context.Background(), "endpoint", fn)
+			Expect(resilience.IsPermanent(err)).To(BeTrue())
+			Expect(err.Error()).To(Equal("bad request"))
+			Expect(*attempts).To(Equal(1))
+		})
+
+		It("should not retry once the context is done", func() {
+			policy.SetRetries(2)
+			ctx, cancel := context.WithCancel(context.Background())
+			attempts := 0
+			_, err := policy.Do(ctx, "endpoint", func(ctx context.Context) ([]byte, error) {
+				attempts++
+				cancel()
+				return nil, fmt.Errorf("failed")
+			})
+			Expect(err).NotTo(BeNil())
+			Expect(attempts).To(Equal(1))
+		})
+
+	})
+
+	Describe("SetBreaker", func() {
+
+		BeforeEach(func() {
+			policy.SetBreaker(2, time.Millisecond*100)
+		})
+
+		It("should open once the threshold of consecutive failures is reached", func() {
+			fn, _ := script(2, fmt.Errorf("failed"))
+			policy.Do(context.Background(), "endpoint", fn)
+			Expect(policy.GetState("endpoint")).To(Equal(resilience.Closed))
+			policy.Do(context.Background(), "endpoint", fn)
+			Expect(policy.GetState("endpoint")).To(Equal(resilience.Open))
+		})
+
+		It("should fail fast with an open error while open", func() {
+			fn, attempts := script(2, fmt.Errorf("failed"))
+			policy.Do(context.Background(), "endpoint", fn)
+			policy.Do(context.Background(), "endpoint", fn)
+			_, err := policy.Do(context.Background(), "endpoint", fn)
+			Expect(err).To(BeAssignableToTypeOf(&resilience.OpenError{}))
+			Expect(err.(*resilience.OpenError).Endpoint).To(Equal("endpoint"))
+			Expect(*attempts).To(Equal(2))
+		})
+
+		It("should not affect other endpoints", func() {
+			fn, _ := script(2, fmt.Errorf("failed"))
+			policy.Do(context.Background(), "a", fn)
+			policy.Do(context.Background(), "a", fn)
+			res, err := policy.Do(context.Background(), "b", fn)
+			Expect(err).To(BeNil())
+			Expect(res).To(Equal([]byte("res")))
+			Expect(policy.GetState("b")).To(Equal(resilience.Closed))
+		})
+
+		It("should close once a trial succeeds after the cooldown", func() {
+			fn, _ := script(2, fmt.Errorf("failed"))
+			policy.Do(context.Background(), "endpoint", fn)
+			policy.Do(context.Background(), "endpoint", fn)
+			time.Sleep(time.Millisecond * 150)
+			Expect(policy.GetState("endpoint")).To(Equal(resilience.HalfOpen))
+			_, err := policy.Do(context.Background(), "endpoint", fn)
+			Expect(err).To(BeNil())
+			Expect(policy.GetState("endpoint")).To(Equal(resilience.Closed))
+		})
+
+		It("should open again once a trial fails after the cooldown", func() {
+			fn, _ := script(3, fmt.Errorf("failed"))
+			policy.Do(context.Background(), "endpoint", fn)
+			policy.Do(context.Background(), "endpoint", fn)
+			time.Sleep(time.Millisecond * 150)
+			policy.Do(context.Background(), "endpoint", fn)
+			Expect(policy.GetState("endpoint")).To(Equal(resilience.Open))
+		})
+
+		It("should not count permanent errors as failures", func() {
+			fn, _ := script(2, resilience.Permanent(fmt.Errorf("bad request")))
+			policy.Do(context.Background(), "endpoint", fn)
+			policy.Do(context.Background(), "endpoint", fn)
+			Expect(policy.GetState("endpoint")).To(Equal(resilience.Closed))
+		})
+
+	})
+
+})
@@ -0,0 +1,307 @@
+package schema
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// The JSON types of a schema.
+const (
+	ObjectType  = "object"
+	ArrayType   = "array"
+	StringType  = "string"
+	NumberType  = "number"
+	IntegerType = "integer"
+	BooleanType = "boolean"
+)
+
+// Schema represents the JSON Schema of a value, such as the parameters of a
+// tile. It marshals into a JSON Schema document, allowing clients to discover
+// the parameters of a type and generate forms or bindings from them.
+//
+// Ex:
+//     {
+//         "type": "object",
+//         "properties": {
+//             "xField": {
+//                 "type": "string",
+//                 "description": "The field of the x value of each point."
+//             },
+//             "resolution": {
+//                 "type": "integer",
+//                 "default": 256
+//             }
+//         },
+//         "required": ["xField"],
+//         "additionalProperties": false
+//     }
+//
+type Schema struct {
+	Type                 string             `json:"type,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+}
+
+// Object returns the schema of an object with the provided properties. Keys
+// that are not declared as properties are not permitted.
+func Object(properties map[string]*Schema, required ...string) *Schema {
+	additional := false
+	return &Schema{
+		Type:                 ObjectType,
+		Properties:           properties,
+		Required:             required,
+		AdditionalProperties: &additional,
+	}
+}
+
+// Array returns the schema of an array of the provided items.
+func Array(items *Schema, description string) *Schema {
+	return &Schema{
+		Type:        ArrayType,
+		Description: description,
+		Items:       items,
+	}
+}
+
+// String returns the schema of a string.
+func String(description string) *Schema {
+	return &Schema{
+		Type:        StringType,
+		Description: description,
+	}
+}
+
+// Number returns the schema of a number.
+func Number(description string) *Schema {
+	return &Schema{
+		Type:        NumberType,
+		Description: description,
+	}
+}
+
+// Integer returns the schema of a number without a fractional part.
+func Integer(description string) *Schema {
+	return &Schema{
+		Type:        IntegerType,
+		Description: description,
+	}
+}
+
+// Boolean returns the schema of a boolean.
+func Boolean(description string) *Schema {
+	return &Schema{
+		Type:        BooleanType,
+		Description: description,
+	}
+}
+
+// Any returns the schema of a value of any type.
+func Any(description string) *Schema {
+	return &Schema{
+		Description: description,
+	}
+}
+
+// WithDefault sets the value used when the value is omitted and returns the
+// schema.
+func (s *Schema) WithDefault(val interface{}) *Schema {
+	s.Default = val
+	return s
+}
+
+// WithEnum restricts the value to one of the provided values and returns the
+// schema.
+func (s *Schema) WithEnum(vals ...interface{}) *Schema {
+	s.Enum = vals
+	return s
+}
+
+// WithMinimum sets the inclusive minimum of a number and returns the schema.
+func (s *Schema) WithMinimum(min float64) *Schema {
+	s.Minimum = &min
+	return s
+}
+
+// WithMaximum sets the inclusive maximum of a number and returns the schema.
+func (s *Schema) WithMaximum(max float64) *Schema {
+	s.Maximum = &max
+	return s
+}
+
+// WithMinItems sets the minimum length of an array and returns the schema.
+func (s *Schema) WithMinItems(min int) *Schema {
+	s.MinItems = &min
+	return s
+}
+
+// Merge returns the schema of an object with the properties and required keys
+// of each of the provided object schemas, used by types composed of several
+// parameter groups. Nil schemas are ignored. Properties declared by more than
+// one schema take the declaration of the last.
+func Merge(schemas ...*Schema) *Schema {
+	properties := make(map[string]*Schema)
+	var required []string
+	seen := make(map[string]bool)
+	for _, s := range schemas {
+		if s == nil {
+			continue
+		}
+		for key, property := range s.Properties {
+			properties[key] = property
+		}
+		for _, key := range s.Required {
+			if !seen[key] {
+				seen[key] = true
+				required = append(required, key)
+			}
+		}
+	}
+	return Object(properties, required...)
+}
+
+// Validate validates the keys of the object against the schema, returning the
+// error of each invalid key such that every problem is reported at once.
+// Required keys that are missing and keys that are not permitted are
+// included. A nil result means the object is valid.
+func (s *Schema) Validate(obj map[string]interface{}) map[string]error {
+	errs := make(map[string]error)
+	for _, key := range s.Required {
+		if _, ok := obj[key]; !ok {
+			errs[key] = fmt.Errorf("`%s` parameter is required", key)
+		}
+	}
+	for key, val := range obj {
+		property, ok := s.Properties[key]
+		if !ok {
+			if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				errs[key] = fmt.Errorf("`%s` is not a recognized parameter, expected one of %v",
+					key, s.GetKeys())
+			}
+			continue
+		}
+		err := property.Check(key, val)
+		if err != nil {
+			errs[key] = err
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// GetKeys returns the sorted keys of the properties of the schema.
+func (s *Schema) GetKeys() []string {
+	keys := make([]string, 0, len(s.Properties))
+	for key := range s.Properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Check validates the value against the schema, returning the first error
+// found. The path identifies the value in error messages.
+func (s *Schema) Check(path string, val interface{}) error {
+	switch s.Type {
+	case ObjectType:
+		obj, ok := val.(map[string]interface{})
+		if !ok {
+			return typeError(path, s.Type)
+		}
+		errs := s.Validate(obj)
+		if errs != nil {
+			// report the first key in order, for stable messages
+			keys := make([]string, 0, len(errs))
+			for key := range errs {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			return fmt.Errorf("`%s`: %v", path, errs[keys[0]])
+		}
+	case ArrayType:
+		arr, ok := val.([]interface{})
+		if !ok {
+			return typeError(path, s.Type)
+		}
+		if s.MinItems != nil && len(arr) < *s.MinItems {
+			if *s.MinItems == 1 {
+				return fmt.Errorf("`%s` must not be empty", path)
+			}
+			return fmt.Errorf("`%s` must have at least %d items", path, *s.MinItems)
+		}
+		if s.Items != nil {
+			for i, item := range arr {
+				err := s.Items.Check(fmt.Sprintf("%s[%d]", path, i), item)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	case StringType:
+		if _, ok := val.(string); !ok {
+			return typeError(path, s.Type)
+		}
+	case BooleanType:
+		if _, ok := val.(bool); !ok {
+			return typeError(path, s.Type)
+		}
+	case NumberType, IntegerType:
+		num, ok := getNumber(val)
+		if !ok || (s.Type == IntegerType && num != math.Trunc(num)) {
+			return typeError(path, s.Type)
+		}
+		if s.Minimum != nil && num < *s.Minimum {
+			return fmt.Errorf("`%s` must be at least %v", path, *s.Minimum)
+		}
+		if s.Maximum != nil && num > *s.Maximum {
+			return fmt.Errorf("`%s` must be at most %v", path, *s.Maximum)
+		}
+	}
+	if len(s.Enum) > 0 && !contains(s.Enum, val) {
+		return fmt.Errorf("`%s` must be one of %v", path, s.Enum)
+	}
+	return nil
+}
+
+func typeError(path string, typ string) error {
+	return fmt.Errorf("`%s` is not of type `%s`", path, typ)
+}
+
+// getNumber returns the value as a float64 if it is of a numeric kind.
+func getNumber(val interface{}) (float64, bool) {
+	v := reflect.ValueOf(val)
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	}
+	return 0, false
+}
+
+func contains(vals []interface{}, val interface{}) bool {
+	for _, v := range vals {
+		if v == val {
+			return true
+		}
+		a, aok := getNumber(v)
+		b, bok := getNumber(val)
+		if aok && bok && a == b {
+			return true
+		}
+	}
+	return false
+}
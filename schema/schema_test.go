@@ -0,0 +1,88 @@
+package schema_test
+
+import (
+	"encoding/json"
+
+	"github.com/unchartedsoftware/veldt/schema"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("schema", func() {
+
+	s := schema.Object(map[string]*schema.Schema{
+		"field":  schema.String("The field."),
+		"count":  schema.Integer("The count.").WithMinimum(1),
+		"order":  schema.String("The order.").WithEnum("asc", "desc"),
+		"fields": schema.Array(schema.String(""), "The fields.").WithMinItems(1),
+		"value":  schema.Any("The value."),
+	}, "field", "count")
+
+	Describe("Validate", func() {
+
+		It("should accept a valid object", func() {
+			Expect(s.Validate(map[string]interface{}{
+				"field":  "name",
+				"count":  10.0,
+				"order":  "asc",
+				"fields": []interface{}{"a", "b"},
+				"value":  map[string]interface{}{},
+			})).To(BeNil())
+		})
+
+		It("should return an error for every invalid key", func() {
+			errs := s.Validate(map[string]interface{}{
+				"count":   0.0,
+				"order":   "random",
+				"fields":  []interface{}{"a", 1.0},
+				"unknown": true,
+			})
+			Expect(len(errs)).To(Equal(5))
+			Expect(errs["field"].Error()).To(Equal("`field` parameter is required"))
+			Expect(errs["count"].Error()).To(Equal("`count` must be at least 1"))
+			Expect(errs["order"].Error()).To(Equal("`order` must be one of [asc desc]"))
+			Expect(errs["fields"].Error()).To(Equal("`fields[1]` is not of type `string`"))
+			Expect(errs["unknown"].Error()).To(Equal("`unknown` is not a recognized parameter, expected one of [count field fields order value]"))
+		})
+
+		It("should reject numbers with a fractional part for integers", func() {
+			errs := s.Validate(map[string]interface{}{
+				"field": "name",
+				"count": 1.5,
+			})
+			Expect(errs["count"].Error()).To(Equal("`count` is not of type `integer`"))
+		})
+
+		It("should reject arrays shorter than the minimum", func() {
+			errs := s.Validate(map[string]interface{}{
+				"field":  "name",
+				"count":  1.0,
+				"fields": []interface{}{},
+			})
+			Expect(errs["fields"].Error()).To(Equal("`fields` must not be empty"))
+		})
+
+	})
+
+	Describe("Merge", func() {
+
+		It("should combine the properties and required keys of each schema", func() {
+			merged := schema.Merge(s, nil, schema.Object(map[string]*schema.Schema{
+				"other": schema.Boolean("The other."),
+			}, "other", "field"))
+			Expect(merged.GetKeys()).To(Equal([]string{"count", "field", "fields", "order", "other", "value"}))
+			Expect(merged.Required).To(Equal([]string{"field", "count", "other"}))
+		})
+
+	})
+
+	It("should marshal into a JSON Schema document", func() {
+		bytes, err := json.Marshal(schema.Object(map[string]*schema.Schema{
+			"resolution": schema.Integer("The resolution.").WithDefault(256),
+		}, "resolution"))
+		Expect(err).To(BeNil())
+		Expect(string(bytes)).To(Equal(`{"type":"object","properties":{"resolution":{"type":"integer","description":"The resolution.","default":256}},"required":["resolution"],"additionalProperties":false}`))
+	})
+
+})
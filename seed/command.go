@@ -0,0 +1,133 @@
+package seed
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+const (
+	reportInterval = time.Second
+)
+
+// Command runs a seeding job from command line arguments against the
+// registered pipelines, reporting progress to stdout. An interrupt signal
+// stops the job, saving its checkpoint so that it may be resumed by running
+// the same command again. It is intended to be called from the main function
+// of an application once its pipelines are registered.
+//
+// Ex:
+//     veldt.Register("elastic", pipeline)
+//     err := seed.Command(os.Args[1:])
+//
+// Usage:
+//     -job string          path of the JSON job file
+//     -checkpoint string   path of the checkpoint file, overriding the job
+//     -rate float          maximum tiles per second, overriding the job
+//     -concurrency int     maximum tiles in flight, overriding the job
+//
+func Command(args []string) error {
+	return command(args, os.Stdout)
+}
+
+func command(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	jobPath := flags.String("job", "", "path of the JSON job file")
+	checkpoint := flags.String("checkpoint", "", "path of the checkpoint file, overriding the job")
+	rate := flags.Float64("rate", -1, "maximum tiles per second, overriding the job")
+	concurrency := flags.Int("concurrency", -1, "maximum tiles in flight, overriding the job")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if *jobPath == "" {
+		return fmt.Errorf("`-job` flag is required")
+	}
+	job, err := loadJob(*jobPath)
+	if err != nil {
+		return err
+	}
+	if *checkpoint != "" {
+		job.Checkpoint = *checkpoint
+	}
+	if *rate >= 0 {
+		job.Rate = *rate
+	}
+	if *concurrency > 0 {
+		job.Concurrency = *concurrency
+	}
+	pipeline, err := veldt.GetPipeline(job.Pipeline)
+	if err != nil {
+		return err
+	}
+	// report progress at most once per interval
+	mutex := sync.Mutex{}
+	reported := time.Time{}
+	job.OnProgress = func(progress *Progress) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if time.Since(reported) < reportInterval && progress.Processed() < progress.Total {
+			return
+		}
+		reported = time.Now()
+		fmt.Fprintln(out, formatProgress(progress))
+	}
+	// stop the job on interrupt
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	defer signal.Stop(signals)
+	go func() {
+		select {
+		case <-signals:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	progress, err := Run(ctx, pipeline, job)
+	if err != nil {
+		if progress != nil && job.Checkpoint != "" {
+			fmt.Fprintf(out, "stopped, resume from checkpoint `%s`\n", job.Checkpoint)
+		}
+		return err
+	}
+	fmt.Fprintln(out, formatProgress(progress))
+	return nil
+}
+
+func loadJob(path string) (*Job, error) {
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	params, err := json.Unmarshal(bytes)
+	if err != nil {
+		return nil, err
+	}
+	job := &Job{}
+	err = job.Parse(params)
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func formatProgress(progress *Progress) string {
+	return fmt.Sprintf("%d / %d tiles, done: %d, failed: %d, skipped: %d, eta: %s",
+		progress.Processed(),
+		progress.Total,
+		progress.Done,
+		progress.Failed,
+		progress.Skipped,
+		progress.ETA-progress.ETA%time.Second)
+}
@@ -0,0 +1,213 @@
+package seed
+
+import (
+	"crypto/sha1"
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/geometry"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+const (
+	defaultConcurrency = 4
+	// maxZoomLevel is the deepest zoom level that can be seeded, as the tile
+	// counts of deeper levels overflow.
+	maxZoomLevel = 31
+)
+
+// Job represents a seeding job, generating every tile of a request within a
+// zoom range and bounding box.
+//
+// Ex:
+//     {
+//         "pipeline": "elastic",
+//         "request": {
+//             "uri": "sample_index0",
+//             "tile": {
+//                 "heatmap": { ... }
+//             }
+//         },
+//         "minZoom": 0,
+//         "maxZoom": 8,
+//         "bounds": {
+//             "left": -180,
+//             "right": 180,
+//             "bottom": -85,
+//             "top": 85
+//         },
+//         "rate": 50,
+//         "concurrency": 8,
+//         "checkpoint": "./seed.checkpoint"
+//     }
+//
+type Job struct {
+	// Pipeline is the ID of the registered pipeline, used by the CLI.
+	Pipeline string
+	// Request is the tile request JSON, without the `coord`.
+	Request map[string]interface{}
+	// MinZoom and MaxZoom are the inclusive zoom range to seed.
+	MinZoom uint32
+	MaxZoom uint32
+	// Bounds is the bounding box to seed. It is in longitude / latitude
+	// unless an Extent is provided, in which case it is in data space.
+	Bounds *geometry.Bounds
+	// Extent is the data space extent of the tile pyramid, such as the
+	// `left`, `right`, `bottom` and `top` of a heatmap tile.
+	Extent *geometry.Bounds
+	// Rate is the maximum number of tiles sent to the pipeline per second.
+	// A rate of 0 is unlimited.
+	Rate float64
+	// Concurrency is the maximum number of tiles in flight.
+	Concurrency int
+	// Checkpoint is the path of the file progress is saved to. If it exists
+	// when the job is run, the job resumes from it.
+	Checkpoint string
+	// OnProgress is called after each tile is processed.
+	OnProgress func(*Progress)
+}
+
+// Parse parses the provided JSON object and populates the jobs attributes.
+func (j *Job) Parse(params map[string]interface{}) error {
+	j.Pipeline = json.GetStringDefault(params, "", "pipeline")
+	request, ok := json.GetChild(params, "request")
+	if !ok {
+		return fmt.Errorf("`request` parameter missing from job")
+	}
+	j.Request = request
+	minZoom, ok := json.GetInt(params, "minZoom")
+	if !ok {
+		return fmt.Errorf("`minZoom` parameter missing from job")
+	}
+	maxZoom, ok := json.GetInt(params, "maxZoom")
+	if !ok {
+		return fmt.Errorf("`maxZoom` parameter missing from job")
+	}
+	if minZoom < 0 || minZoom > maxZoom {
+		return fmt.Errorf("`minZoom` must be between 0 and `maxZoom`")
+	}
+	if maxZoom > maxZoomLevel {
+		return fmt.Errorf("`maxZoom` must not be greater than %d", maxZoomLevel)
+	}
+	j.MinZoom = uint32(minZoom)
+	j.MaxZoom = uint32(maxZoom)
+	j.Bounds = nil
+	bounds, ok := json.GetChild(params, "bounds")
+	if ok {
+		j.Bounds = &geometry.Bounds{}
+		err := j.Bounds.Parse(bounds)
+		if err != nil {
+			return err
+		}
+	}
+	j.Extent = nil
+	extent, ok := json.GetChild(params, "extent")
+	if ok {
+		j.Extent = &geometry.Bounds{}
+		err := j.Extent.Parse(extent)
+		if err != nil {
+			return err
+		}
+	}
+	j.Rate = json.GetFloatDefault(params, 0, "rate")
+	j.Concurrency = json.GetIntDefault(params, defaultConcurrency, "concurrency")
+	if j.Concurrency < 1 {
+		return fmt.Errorf("`concurrency` must be greater than 0")
+	}
+	j.Checkpoint = json.GetStringDefault(params, "", "checkpoint")
+	return nil
+}
+
+// GetRanges returns the tile range of every level of the job, ordered by
+// zoom level.
+func (j *Job) GetRanges() []*binning.TileRange {
+	var ranges []*binning.TileRange
+	for z := uint64(j.MinZoom); z <= uint64(j.MaxZoom); z++ {
+		ranges = append(ranges, j.getLevelRange(uint32(z)))
+	}
+	return ranges
+}
+
+// Count returns the number of tiles of the job.
+func (j *Job) Count() uint64 {
+	var count uint64
+	for _, r := range j.GetRanges() {
+		count += r.Count()
+	}
+	return count
+}
+
+// GetCoords returns an iterator over the coordinates of every tile of the
+// job, ordered by zoom level, starting from the tile at the provided index.
+// As deep levels hold too many tiles to be held in memory, coordinates are
+// computed as they are iterated.
+func (j *Job) GetCoords(from uint64) *CoordIterator {
+	it := &CoordIterator{
+		ranges: j.GetRanges(),
+		index:  from,
+	}
+	// skip the levels before the index
+	for it.level < len(it.ranges) && it.index >= it.ranges[it.level].Count() {
+		it.index -= it.ranges[it.level].Count()
+		it.level++
+	}
+	return it
+}
+
+// CoordIterator iterates over the tile coordinates of a job.
+type CoordIterator struct {
+	ranges []*binning.TileRange
+	level  int
+	index  uint64
+}
+
+// Next returns the next tile coordinate, or false once every coordinate has
+// been returned.
+func (it *CoordIterator) Next() (*binning.TileCoord, bool) {
+	for it.level < len(it.ranges) {
+		r := it.ranges[it.level]
+		if it.index < r.Count() {
+			coord := r.At(it.index)
+			it.index++
+			return coord, true
+		}
+		it.level++
+		it.index = 0
+	}
+	return nil, false
+}
+
+func (j *Job) getLevelRange(z uint32) *binning.TileRange {
+	if j.Extent != nil {
+		bounds := j.Extent
+		if j.Bounds != nil {
+			bounds = j.Bounds
+		}
+		min := binning.CoordToFractionalTile(geometry.NewCoord(bounds.Left, bounds.Bottom), z, j.Extent)
+		max := binning.CoordToFractionalTile(geometry.NewCoord(bounds.Right, bounds.Top), z, j.Extent)
+		return binning.GetTileRange(min, max)
+	}
+	bounds := j.Bounds
+	if bounds == nil {
+		bounds = geometry.NewBounds(-180, 180, -90, 90)
+	}
+	min := binning.LonLatToFractionalTile(binning.NewLonLat(bounds.Left, bounds.Bottom), z)
+	max := binning.LonLatToFractionalTile(binning.NewLonLat(bounds.Right, bounds.Top), z)
+	return binning.GetTileRange(min, max)
+}
+
+// getHash returns a hash identifying the tiles of the job, used to ensure a
+// checkpoint belongs to the job.
+func (j *Job) getHash() (string, error) {
+	bytes, err := json.Marshal(map[string]interface{}{
+		"request": j.Request,
+		"minZoom": j.MinZoom,
+		"maxZoom": j.MaxZoom,
+		"bounds":  j.Bounds,
+		"extent":  j.Extent,
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha1.Sum(bytes)), nil
+}
@@ -0,0 +1,79 @@
+package seed
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Progress represents the progress of a seeding job.
+type Progress struct {
+	// Total is the number of tiles of the job.
+	Total int `json:"total"`
+	// Done is the number of tiles generated.
+	Done int `json:"done"`
+	// Failed is the number of tiles that failed to generate.
+	Failed int `json:"failed"`
+	// Skipped is the number of tiles that already existed in the store.
+	Skipped int `json:"skipped"`
+	// ETA is the estimated time remaining, based on the rate of the current
+	// run.
+	ETA time.Duration `json:"-"`
+}
+
+// Processed returns the number of tiles processed.
+func (p *Progress) Processed() int {
+	return p.Done + p.Failed + p.Skipped
+}
+
+// checkpoint represents the saved progress of a seeding job. Tiles are
+// processed concurrently, so only the tiles before the first unprocessed tile
+// are recorded as processed.
+type checkpoint struct {
+	Hash     string    `json:"hash"`
+	Next     int       `json:"next"`
+	Progress *Progress `json:"progress"`
+}
+
+func loadCheckpoint(path string) (*checkpoint, error) {
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	c := &checkpoint{}
+	err = json.Unmarshal(bytes, c)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func saveCheckpoint(path string, c *checkpoint) error {
+	bytes, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	// write to a temporary file and rename it into place, so an interrupted
+	// save never corrupts the checkpoint
+	file, err := ioutil.TempFile(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(bytes)
+	if err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	err = file.Close()
+	if err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
@@ -0,0 +1,268 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/util/queue"
+)
+
+const (
+	checkpointInterval = time.Second
+	// seedClient is the client seeding requests are queued on behalf of.
+	seedClient = "seed"
+)
+
+type status int
+
+const (
+	done status = iota
+	failed
+	skipped
+)
+
+type task struct {
+	index int
+	coord *binning.TileCoord
+}
+
+type result struct {
+	index  int
+	status status
+}
+
+// Run generates every tile of the job through the pipeline queue, skipping
+// tiles already in the store. Progress is saved to the checkpoint of the job,
+// if provided, and an interrupted job resumes from it. The checkpoint is
+// removed once the job completes. Failed tiles are logged and counted, but
+// are not retried on resume.
+//
+// If the context is done, in-flight tiles are abandoned and the progress is
+// returned with the context error.
+func Run(ctx context.Context, pipeline *veldt.Pipeline, job *Job) (*Progress, error) {
+	total := int(job.Count())
+	hash, err := job.getHash()
+	if err != nil {
+		return nil, err
+	}
+	progress := &Progress{
+		Total: total,
+	}
+	next := 0
+	// resume from checkpoint
+	if job.Checkpoint != "" {
+		c, err := loadCheckpoint(job.Checkpoint)
+		if err != nil {
+			return nil, err
+		}
+		if c != nil {
+			if c.Hash != hash {
+				return nil, fmt.Errorf("checkpoint `%s` does not belong to the job", job.Checkpoint)
+			}
+			next = c.Next
+			progress = c.Progress
+			Infof("Resuming from tile %d of %d", next, total)
+		}
+	}
+	committed := *progress
+	s := &seeder{
+		pipeline:  pipeline,
+		job:       job,
+		hash:      hash,
+		progress:  progress,
+		committed: &committed,
+		next:      next,
+		initial:   progress.Processed(),
+		began:     time.Now(),
+		finished:  make(map[int]status),
+	}
+	err = s.run(ctx)
+	if err != nil {
+		return progress, err
+	}
+	if job.Checkpoint != "" {
+		err = os.Remove(job.Checkpoint)
+		if err != nil && !os.IsNotExist(err) {
+			return progress, err
+		}
+	}
+	return progress, nil
+}
+
+type seeder struct {
+	pipeline *veldt.Pipeline
+	job      *Job
+	hash     string
+	progress *Progress
+	// committed is the progress of the tiles before next
+	committed *Progress
+	// next is the index of the first unprocessed tile
+	next int
+	// finished holds the status of processed tiles after next
+	finished map[int]status
+	// initial is the number of tiles processed before this run
+	initial int
+	began   time.Time
+	saved   time.Time
+}
+
+func (s *seeder) run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	concurrency := s.job.Concurrency
+	if concurrency < 1 {
+		concurrency = defaultConcurrency
+	}
+	tasks := make(chan task)
+	results := make(chan result)
+	// workers
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			for t := range tasks {
+				res := result{
+					index:  t.index,
+					status: s.seedTile(ctx, t.coord),
+				}
+				select {
+				case results <- res:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	// producer, throttled to the rate of the job
+	go func() {
+		defer close(tasks)
+		var ticker *time.Ticker
+		if s.job.Rate > 0 {
+			ticker = time.NewTicker(time.Duration(float64(time.Second) / s.job.Rate))
+			defer ticker.Stop()
+		}
+		coords := s.job.GetCoords(uint64(s.next))
+		for index := s.next; ; index++ {
+			coord, ok := coords.Next()
+			if !ok {
+				return
+			}
+			if ticker != nil {
+				select {
+				case <-ticker.C:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case tasks <- task{index: index, coord: coord}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	// collect results
+	for pending := s.progress.Total - s.next; pending > 0; pending-- {
+		select {
+		case res := <-results:
+			s.record(res)
+		case <-ctx.Done():
+			s.save()
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (s *seeder) seedTile(ctx context.Context, coord *binning.TileCoord) status {
+	// copy the request to add the coord
+	args := make(map[string]interface{}, len(s.job.Request)+1)
+	for key, val := range s.job.Request {
+		args[key] = val
+	}
+	args["coord"] = map[string]interface{}{
+		"z": float64(coord.Z),
+		"x": float64(coord.X),
+		"y": float64(coord.Y),
+	}
+	req, err := s.pipeline.NewTileRequest(args)
+	if err != nil {
+		Warnf("Failed to seed tile %d/%d/%d: %s", coord.Z, coord.X, coord.Y, err)
+		return failed
+	}
+	// seeding must not starve interactive requests
+	req.Priority = queue.Background
+	req.Client = seedClient
+	// skip tiles already in the store
+	store, err := s.pipeline.GetStore()
+	if err == nil {
+		exists, err := store.Exists(s.pipeline.GetRequestHash(req))
+		store.Close()
+		if err == nil && exists {
+			return skipped
+		}
+	}
+	err = s.pipeline.GenerateContext(ctx, req)
+	if err != nil {
+		Warnf("Failed to seed tile %d/%d/%d: %s", coord.Z, coord.X, coord.Y, err)
+		return failed
+	}
+	return done
+}
+
+func (s *seeder) record(res result) {
+	increment(s.progress, res.status)
+	// advance past all processed tiles
+	s.finished[res.index] = res.status
+	for {
+		status, ok := s.finished[s.next]
+		if !ok {
+			break
+		}
+		increment(s.committed, status)
+		delete(s.finished, s.next)
+		s.next++
+	}
+	// estimate the time remaining from the rate of this run
+	processed := s.progress.Processed() - s.initial
+	remaining := s.progress.Total - s.progress.Processed()
+	if processed > 0 {
+		elapsed := time.Since(s.began)
+		s.progress.ETA = time.Duration(float64(elapsed) / float64(processed) * float64(remaining))
+	}
+	if s.job.OnProgress != nil {
+		s.job.OnProgress(s.progress)
+	}
+	if time.Since(s.saved) > checkpointInterval {
+		s.save()
+	}
+}
+
+func (s *seeder) save() {
+	if s.job.Checkpoint == "" {
+		return
+	}
+	err := saveCheckpoint(s.job.Checkpoint, &checkpoint{
+		Hash:     s.hash,
+		Next:     s.next,
+		Progress: s.committed,
+	})
+	if err != nil {
+		Warnf("Failed to save checkpoint `%s`: %s", s.job.Checkpoint, err)
+		return
+	}
+	s.saved = time.Now()
+}
+
+func increment(progress *Progress, status status) {
+	switch status {
+	case done:
+		progress.Done++
+	case failed:
+		progress.Failed++
+	case skipped:
+		progress.Skipped++
+	}
+}
@@ -0,0 +1,274 @@
+package seed_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/geometry"
+	"github.com/unchartedsoftware/veldt/seed"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+var (
+	mutex  = sync.Mutex{}
+	counts = make(map[string]int)
+)
+
+func getCount(name string) int {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return counts[name]
+}
+
+// countTile counts the tiles created under its name, failing tile 1/1/1.
+type countTile struct {
+	name string
+}
+
+func (t *countTile) Parse(params map[string]interface{}) error {
+	return nil
+}
+
+func (t *countTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	counts[t.name]++
+	if coord.Z == 1 && coord.X == 1 && coord.Y == 1 {
+		return nil, fmt.Errorf("failed")
+	}
+	return []byte("tile"), nil
+}
+
+var _ = Describe("Seed", func() {
+
+	newPipeline := func(name string) *veldt.Pipeline {
+		pipeline := veldt.NewPipeline()
+		pipeline.Tile("count", func() (veldt.Tile, error) {
+			return &countTile{name: name}, nil
+		})
+		pipeline.Store(NewMapStore().Ctor())
+		return pipeline
+	}
+
+	var pipeline *veldt.Pipeline
+
+	BeforeEach(func() {
+		mutex.Lock()
+		counts = make(map[string]int)
+		mutex.Unlock()
+		pipeline = newPipeline("")
+	})
+
+	newJob := func() *seed.Job {
+		job := &seed.Job{}
+		err := job.Parse(JSON(`{
+			"request": {
+				"uri": "test",
+				"tile": {
+					"count": {}
+				}
+			},
+			"minZoom": 0,
+			"maxZoom": 1
+		}`))
+		Expect(err).To(BeNil())
+		return job
+	}
+
+	collect := func(it *seed.CoordIterator) []*binning.TileCoord {
+		var coords []*binning.TileCoord
+		for {
+			coord, ok := it.Next()
+			if !ok {
+				return coords
+			}
+			coords = append(coords, coord)
+		}
+	}
+
+	Describe("Job", func() {
+		It("should return the coordinates within lon / lat bounds", func() {
+			job := newJob()
+			job.Bounds = geometry.NewBounds(1, 179, 1, 80)
+			Expect(collect(job.GetCoords(0))).To(Equal([]*binning.TileCoord{
+				{Z: 0, X: 0, Y: 0},
+				{Z: 1, X: 1, Y: 1},
+			}))
+		})
+
+		It("should return the coordinates within data space bounds", func() {
+			job := newJob()
+			job.MaxZoom = 2
+			job.Extent = geometry.NewBounds(0, 100, 0, 100)
+			job.Bounds = geometry.NewBounds(0, 25, 0, 50)
+			Expect(collect(job.GetCoords(0))).To(Equal([]*binning.TileCoord{
+				{Z: 0, X: 0, Y: 0},
+				{Z: 1, X: 0, Y: 0},
+				{Z: 2, X: 0, Y: 0},
+				{Z: 2, X: 0, Y: 1},
+			}))
+		})
+
+		It("should return the coordinates from the provided index", func() {
+			job := newJob()
+			job.MaxZoom = 2
+			Expect(job.Count()).To(Equal(uint64(21)))
+			Expect(collect(job.GetCoords(19))).To(Equal([]*binning.TileCoord{
+				{Z: 2, X: 3, Y: 2},
+				{Z: 2, X: 3, Y: 3},
+			}))
+			Expect(collect(job.GetCoords(21))).To(BeEmpty())
+		})
+
+		It("should count the tiles of the deepest zoom level", func() {
+			job := newJob()
+			job.MinZoom = 31
+			job.MaxZoom = 31
+			Expect(job.Count()).To(Equal(uint64(1) << 62))
+			coord, ok := job.GetCoords(job.Count() - 1).Next()
+			Expect(ok).To(BeTrue())
+			Expect(coord).To(Equal(&binning.TileCoord{
+				Z: 31,
+				X: 1<<31 - 1,
+				Y: 1<<31 - 1,
+			}))
+		})
+
+		It("should return an error if the zoom range is invalid", func() {
+			job := &seed.Job{}
+			err := job.Parse(JSON(`{
+				"request": {},
+				"minZoom": 4,
+				"maxZoom": 2
+			}`))
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should return an error if the max zoom is too deep", func() {
+			job := &seed.Job{}
+			err := job.Parse(JSON(`{
+				"request": {},
+				"minZoom": 0,
+				"maxZoom": 32
+			}`))
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("Run", func() {
+		It("should generate every tile and report progress", func() {
+			job := newJob()
+			var reports int
+			job.OnProgress = func(progress *seed.Progress) {
+				reports++
+			}
+			progress, err := seed.Run(context.Background(), pipeline, job)
+			Expect(err).To(BeNil())
+			Expect(progress.Total).To(Equal(5))
+			Expect(progress.Done).To(Equal(4))
+			Expect(progress.Failed).To(Equal(1))
+			Expect(progress.Skipped).To(Equal(0))
+			Expect(reports).To(Equal(5))
+		})
+
+		It("should skip tiles already in the store", func() {
+			job := newJob()
+			_, err := seed.Run(context.Background(), pipeline, job)
+			Expect(err).To(BeNil())
+			progress, err := seed.Run(context.Background(), pipeline, job)
+			Expect(err).To(BeNil())
+			Expect(progress.Skipped).To(Equal(4))
+			Expect(progress.Failed).To(Equal(1))
+		})
+
+		It("should return the context error if cancelled", func() {
+			job := newJob()
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			_, err := seed.Run(ctx, pipeline, job)
+			Expect(err).To(Equal(context.Canceled))
+		})
+
+		It("should resume from a checkpoint", func() {
+			dir, err := ioutil.TempDir("", "veldt-seed")
+			Expect(err).To(BeNil())
+			defer os.RemoveAll(dir)
+			path := filepath.Join(dir, "seed.checkpoint")
+			// stop the job after the first tile
+			job := newJob()
+			job.Checkpoint = path
+			job.Concurrency = 1
+			ctx, cancel := context.WithCancel(context.Background())
+			job.OnProgress = func(progress *seed.Progress) {
+				cancel()
+			}
+			_, err = seed.Run(ctx, newPipeline("stopped"), job)
+			Expect(err).To(Equal(context.Canceled))
+			_, err = os.Stat(path)
+			Expect(err).To(BeNil())
+			// resume with an empty store, so only the remaining tiles are
+			// generated
+			job.OnProgress = nil
+			progress, err := seed.Run(context.Background(), newPipeline("resumed"), job)
+			Expect(err).To(BeNil())
+			Expect(getCount("resumed")).To(Equal(4))
+			Expect(progress.Done).To(Equal(4))
+			Expect(progress.Failed).To(Equal(1))
+			// checkpoint is removed once complete
+			_, err = os.Stat(path)
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+
+		It("should return an error if the checkpoint belongs to another job", func() {
+			dir, err := ioutil.TempDir("", "veldt-seed")
+			Expect(err).To(BeNil())
+			defer os.RemoveAll(dir)
+			path := filepath.Join(dir, "seed.checkpoint")
+			err = ioutil.WriteFile(path, []byte(`{"hash":"other","next":1}`), 0644)
+			Expect(err).To(BeNil())
+			job := newJob()
+			job.Checkpoint = path
+			_, err = seed.Run(context.Background(), pipeline, job)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("Command", func() {
+		It("should run the job file against the registered pipeline", func() {
+			dir, err := ioutil.TempDir("", "veldt-seed")
+			Expect(err).To(BeNil())
+			defer os.RemoveAll(dir)
+			path := filepath.Join(dir, "job.json")
+			err = ioutil.WriteFile(path, []byte(`{
+				"pipeline": "seed",
+				"request": {
+					"uri": "test",
+					"tile": {
+						"count": {}
+					}
+				},
+				"minZoom": 0,
+				"maxZoom": 0
+			}`), 0644)
+			Expect(err).To(BeNil())
+			veldt.Register("seed", newPipeline("command"))
+			err = seed.Command([]string{"-job", path, "-rate", "100"})
+			Expect(err).To(BeNil())
+			Expect(getCount("command")).To(Equal(1))
+		})
+
+		It("should return an error if the job flag is missing", func() {
+			err := seed.Command([]string{})
+			Expect(err).NotTo(BeNil())
+		})
+	})
+})
@@ -0,0 +1,309 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/codec"
+	"github.com/unchartedsoftware/veldt/trace"
+)
+
+const (
+	maxBodySize = 1 << 20
+	// sniffLength is the length of the prefix used to sniff content types,
+	// as per http.DetectContentType.
+	sniffLength = 512
+)
+
+func (s *Server) handleTile(w http.ResponseWriter, r *http.Request) {
+	var id string
+	var args map[string]interface{}
+	var err error
+	switch r.Method {
+	case http.MethodPost:
+		id, args, err = parsePostRequest(r, "/tile/")
+	case http.MethodGet:
+		id, args, err = parseGetTileRequest(r)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		err = newRequestError(http.StatusMethodNotAllowed,
+			fmt.Errorf("method `%s` is not allowed", r.Method))
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	ctx, span := startRequestSpan(trace.Extract(r.Context(), r.Header), id)
+	defer span.End()
+	r = r.WithContext(ctx)
+	pipeline, req, err := newTileRequest(ctx, id, getClient(r), args)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	serve(w, r, pipeline, req)
+}
+
+func (s *Server) handleMeta(w http.ResponseWriter, r *http.Request) {
+	var id string
+	var args map[string]interface{}
+	var err error
+	switch r.Method {
+	case http.MethodPost:
+		id, args, err = parsePostRequest(r, "/meta/")
+	case http.MethodGet:
+		id, args, err = parseGetMetaRequest(r)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		err = newRequestError(http.StatusMethodNotAllowed,
+			fmt.Errorf("method `%s` is not allowed", r.Method))
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	ctx, span := startRequestSpan(trace.Extract(r.Context(), r.Header), id)
+	defer span.End()
+	r = r.WithContext(ctx)
+	pipeline, req, err := newMetaRequest(ctx, id, getClient(r), args)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	serve(w, r, pipeline, req)
+}
+
+func (s *Server) handlePipeline(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		writeError(w, newRequestError(http.StatusMethodNotAllowed,
+			fmt.Errorf("method `%s` is not allowed", r.Method)))
+		return
+	}
+	segments := splitPath(r.URL.Path, "/pipeline")
+	var res interface{}
+	switch len(segments) {
+	case 0:
+		// describe every registered pipeline
+		descs := make([]*veldt.Description, 0)
+		for _, id := range veldt.GetPipelineIDs() {
+			pipeline, err := veldt.GetPipeline(id)
+			if err != nil {
+				// unregistered since listing the IDs
+				continue
+			}
+			descs = append(descs, pipeline.Describe())
+		}
+		res = descs
+	case 1:
+		pipeline, err := veldt.GetPipeline(segments[0])
+		if err != nil {
+			writeError(w, newRequestError(http.StatusNotFound, err))
+			return
+		}
+		res = pipeline.Describe()
+	default:
+		writeError(w, newRequestError(http.StatusNotFound,
+			fmt.Errorf("expected path of the form `/pipeline/{pipeline}`")))
+		return
+	}
+	data, err := json.Marshal(res)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func serve(w http.ResponseWriter, r *http.Request, pipeline *veldt.Pipeline, req veldt.Request) {
+	name := pipeline.GetRequestCompression(req)
+	encoding := getContentEncoding(r.Header.Get("Accept-Encoding"), name)
+	etag := getETag(pipeline, req, encoding)
+	w.Header().Set("ETag", etag)
+	if name != codec.Identity {
+		w.Header().Set("Vary", "Accept-Encoding")
+	}
+	// the request hash fully determines the response
+	if matchETag(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	var data []byte
+	var contentType string
+	var err error
+	if encoding != "" {
+		// pass the stored data through without decompressing it
+		data, _, err = pipeline.GenerateAndGetEncodedContext(r.Context(), req)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		contentType, err = sniffContentType(name, data)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		w.Header().Set("Content-Encoding", encoding)
+	} else {
+		data, err = pipeline.GenerateAndGetContext(r.Context(), req)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		contentType = getContentType(data)
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := getStatus(err)
+	if status >= http.StatusInternalServerError {
+		Errorf("%d: %v", status, err)
+	} else {
+		Debugf("%d: %v", status, err)
+	}
+	http.Error(w, err.Error(), status)
+}
+
+// splitPath returns the path segments following the provided prefix.
+func splitPath(path string, prefix string) []string {
+	path = strings.Trim(strings.TrimPrefix(path, prefix), "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+// Parses a request of the form:
+//
+//     POST /{type}/{pipeline}
+//
+// with the JSON request in the body.
+func parsePostRequest(r *http.Request, prefix string) (string, map[string]interface{}, error) {
+	segments := splitPath(r.URL.Path, prefix)
+	if len(segments) != 1 {
+		return "", nil, newRequestError(http.StatusNotFound,
+			fmt.Errorf("expected path of the form `%s{pipeline}`", prefix))
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		return "", nil, newRequestError(http.StatusBadRequest, err)
+	}
+	args := make(map[string]interface{})
+	err = json.Unmarshal(body, &args)
+	if err != nil {
+		return "", nil, newRequestError(http.StatusBadRequest,
+			fmt.Errorf("request body is not valid JSON: %v", err))
+	}
+	return segments[0], args, nil
+}
+
+// splitEscapedPath returns the unescaped path segments following the provided
+// prefix, such that escaped slashes do not separate segments.
+func splitEscapedPath(r *http.Request, prefix string) ([]string, error) {
+	segments := splitPath(r.URL.EscapedPath(), prefix)
+	for i, segment := range segments {
+		// url.PathUnescape is not available before Go 1.8, so a plus is
+		// escaped to not be unescaped as a space
+		unescaped, err := url.QueryUnescape(strings.Replace(segment, "+", "%2B", -1))
+		if err != nil {
+			return nil, newRequestError(http.StatusBadRequest,
+				fmt.Errorf("path segment `%s` is not validly escaped", segment))
+		}
+		segments[i] = unescaped
+	}
+	return segments, nil
+}
+
+// Parses a request of the form:
+//
+//     GET /tile/{pipeline}/{uri}/{z}/{x}/{y}?tile={...}&query={...}&render={...}
+//
+// where the `tile`, `query` and `render` parameters are URL encoded JSON. The
+// URI may span several segments, such as `index/type`.
+func parseGetTileRequest(r *http.Request) (string, map[string]interface{}, error) {
+	segments, err := splitEscapedPath(r, "/tile/")
+	if err != nil {
+		return "", nil, err
+	}
+	if len(segments) < 5 {
+		return "", nil, newRequestError(http.StatusNotFound,
+			fmt.Errorf("expected path of the form `/tile/{pipeline}/{uri}/{z}/{x}/{y}`"))
+	}
+	n := len(segments)
+	coord := make(map[string]interface{})
+	for i, key := range []string{"z", "x", "y"} {
+		val, err := strconv.ParseUint(segments[n-3+i], 10, 32)
+		if err != nil {
+			return "", nil, newRequestError(http.StatusBadRequest,
+				fmt.Errorf("`%s` coordinate `%s` is not a valid integer", key, segments[n-3+i]))
+		}
+		coord[key] = float64(val)
+	}
+	args := map[string]interface{}{
+		"uri":   strings.Join(segments[1:n-3], "/"),
+		"coord": coord,
+	}
+	for _, key := range []string{"tile", "query", "render"} {
+		err = parseParam(r, key, args)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	return segments[0], args, nil
+}
+
+// Parses a request of the form:
+//
+//     GET /meta/{pipeline}/{uri}?meta={...}
+//
+// where the `meta` parameter is URL encoded JSON. The URI may span several
+// segments, such as `index/type`.
+func parseGetMetaRequest(r *http.Request) (string, map[string]interface{}, error) {
+	segments, err := splitEscapedPath(r, "/meta/")
+	if err != nil {
+		return "", nil, err
+	}
+	if len(segments) < 2 {
+		return "", nil, newRequestError(http.StatusNotFound,
+			fmt.Errorf("expected path of the form `/meta/{pipeline}/{uri}`"))
+	}
+	args := map[string]interface{}{
+		"uri": strings.Join(segments[1:], "/"),
+	}
+	err = parseParam(r, "meta", args)
+	if err != nil {
+		return "", nil, err
+	}
+	return segments[0], args, nil
+}
+
+// parseParam parses the JSON value of the URL parameter into the args, if it
+// is present.
+func parseParam(r *http.Request, key string, args map[string]interface{}) error {
+	param := r.URL.Query().Get(key)
+	if param == "" {
+		return nil
+	}
+	var val interface{}
+	err := json.Unmarshal([]byte(param), &val)
+	if err != nil {
+		return newRequestError(http.StatusBadRequest,
+			fmt.Errorf("`%s` parameter is not valid JSON: %v", key, err))
+	}
+	args[key] = val
+	return nil
+}
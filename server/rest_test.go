@@ -0,0 +1,303 @@
+package server_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	"github.com/unchartedsoftware/veldt/server"
+	"github.com/unchartedsoftware/veldt/trace"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func post(ts *httptest.Server, path string, body interface{}) *http.Response {
+	bs, err := json.Marshal(body)
+	Expect(err).To(BeNil())
+	res, err := http.Post(ts.URL+path, "application/json", bytes.NewReader(bs))
+	Expect(err).To(BeNil())
+	return res
+}
+
+func get(ts *httptest.Server, path string, header http.Header) *http.Response {
+	req, err := http.NewRequest(http.MethodGet, ts.URL+path, nil)
+	Expect(err).To(BeNil())
+	for key := range header {
+		req.Header.Set(key, header.Get(key))
+	}
+	res, err := http.DefaultClient.Do(req)
+	Expect(err).To(BeNil())
+	return res
+}
+
+func readBody(res *http.Response) []byte {
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	Expect(err).To(BeNil())
+	return body
+}
+
+var _ = Describe("REST", func() {
+
+	var ts *httptest.Server
+
+	BeforeEach(func() {
+		ts = httptest.NewServer(server.NewServer())
+	})
+
+	AfterEach(func() {
+		ts.Close()
+	})
+
+	Describe("POST /tile/{pipeline}", func() {
+
+		It("should respond with the generated tile", func() {
+			res := post(ts, "/tile/test", tileRequest("json", 1, 0, 1))
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			Expect(res.Header.Get("Content-Type")).To(Equal("application/json"))
+			Expect(res.Header.Get("ETag")).NotTo(BeEmpty())
+			tile := make(map[string]interface{})
+			Expect(json.Unmarshal(readBody(res), &tile)).To(Succeed())
+			Expect(tile["uri"]).To(Equal("test"))
+			Expect(tile["z"]).To(Equal(1.0))
+			Expect(tile["y"]).To(Equal(1.0))
+		})
+
+		It("should respond with binary tiles as an octet stream", func() {
+			res := post(ts, "/tile/test", tileRequest("binary", 1, 0, 1))
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			Expect(res.Header.Get("Content-Type")).To(Equal("application/octet-stream"))
+			Expect(readBody(res)).To(Equal([]byte{0, 0, 128, 63}))
+		})
+
+		It("should respond with 400 for invalid requests", func() {
+			res := post(ts, "/tile/test", map[string]interface{}{
+				"uri": "test",
+			})
+			Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+			Expect(string(readBody(res))).To(ContainSubstring("invalid tile request"))
+		})
+
+		It("should respond with 400 for malformed JSON", func() {
+			res, err := http.Post(ts.URL+"/tile/test", "application/json", bytes.NewReader([]byte("{")))
+			Expect(err).To(BeNil())
+			Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should respond with 404 for unrecognized pipelines", func() {
+			res := post(ts, "/tile/missing", tileRequest("json", 1, 0, 1))
+			Expect(res.StatusCode).To(Equal(http.StatusNotFound))
+		})
+
+		It("should respond with 500 for generation errors", func() {
+			res := post(ts, "/tile/test", tileRequest("error", 1, 0, 1))
+			Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+			Expect(string(readBody(res))).To(ContainSubstring("backend unavailable"))
+		})
+
+		It("should respond with 400 for permanent errors of the request", func() {
+			res := post(ts, "/tile/test", tileRequest("invalid", 1, 0, 1))
+			Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+			Expect(string(readBody(res))).To(ContainSubstring("column `missing` does not exist"))
+		})
+
+		It("should respond with 503 when the queue is full", func() {
+			for i := 0; i < 2; i++ {
+				go func(x int) {
+					post(ts, "/tile/limited", tileRequest("block", 2, x, 0))
+				}(i)
+			}
+			time.Sleep(time.Millisecond * 100)
+			res := post(ts, "/tile/limited", tileRequest("block", 2, 2, 0))
+			Expect(res.StatusCode).To(Equal(http.StatusServiceUnavailable))
+			release <- true
+			release <- true
+		})
+
+	})
+
+	Describe("GET /tile/{pipeline}/{uri}/{z}/{x}/{y}", func() {
+
+		It("should respond with the generated tile", func() {
+			res := get(ts, "/tile/test/test/3/2/1?tile="+url.QueryEscape(`{"json":{}}`), nil)
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			tile := make(map[string]interface{})
+			Expect(json.Unmarshal(readBody(res), &tile)).To(Succeed())
+			Expect(tile["z"]).To(Equal(3.0))
+			Expect(tile["x"]).To(Equal(2.0))
+			Expect(tile["y"]).To(Equal(1.0))
+		})
+
+		It("should share the ETag of the equivalent POST request", func() {
+			a := get(ts, "/tile/test/test/3/2/1?tile="+url.QueryEscape(`{"json":{}}`), nil)
+			b := post(ts, "/tile/test", tileRequest("json", 3, 2, 1))
+			Expect(a.Header.Get("ETag")).To(Equal(b.Header.Get("ETag")))
+		})
+
+		It("should respond with 304 if the ETag matches", func() {
+			res := get(ts, "/tile/test/test/3/2/1?tile="+url.QueryEscape(`{"json":{}}`), nil)
+			etag := res.Header.Get("ETag")
+			res = get(ts, "/tile/test/test/3/2/1?tile="+url.QueryEscape(`{"json":{}}`), http.Header{
+				"If-None-Match": []string{etag},
+			})
+			Expect(res.StatusCode).To(Equal(http.StatusNotModified))
+		})
+
+		It("should continue the trace of the traceparent header", func() {
+			exporter := trace.NewMemoryExporter()
+			trace.SetExporter(exporter)
+			defer trace.SetExporter(nil)
+			res := get(ts, "/tile/test/test/3/2/0?tile="+url.QueryEscape(`{"json":{}}`), http.Header{
+				"Traceparent": []string{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+			})
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			readBody(res)
+			// the span ends once the handler returns
+			Eventually(func() int {
+				return len(exporter.GetSpans(trace.RequestSpan))
+			}).Should(Equal(1))
+			spans := exporter.GetSpans(trace.RequestSpan)
+			Expect(spans[0].Context.TraceID.String()).To(Equal("4bf92f3577b34da6a3ce929d0e0e4736"))
+			Expect(spans[0].ParentID.String()).To(Equal("00f067aa0ba902b7"))
+			Expect(spans[0].Attributes[trace.PipelineAttribute]).To(Equal("test"))
+			Expect(len(exporter.GetSpans(trace.ValidateSpan))).To(Equal(1))
+		})
+
+		It("should pass compressed tiles through if the encoding is accepted", func() {
+			res := get(ts, "/tile/test/test/3/2/1?tile="+url.QueryEscape(`{"json":{}}`), http.Header{
+				"Accept-Encoding": []string{"br;q=0, gzip"},
+			})
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			Expect(res.Header.Get("Content-Encoding")).To(Equal("gzip"))
+			Expect(res.Header.Get("Content-Type")).To(Equal("application/json"))
+			reader, err := gzip.NewReader(bytes.NewReader(readBody(res)))
+			Expect(err).To(BeNil())
+			tile := make(map[string]interface{})
+			Expect(json.NewDecoder(reader).Decode(&tile)).To(Succeed())
+			Expect(tile["uri"]).To(Equal("test"))
+		})
+
+		It("should decompress tiles if the encoding is not accepted", func() {
+			res := get(ts, "/tile/test/test/3/2/1?tile="+url.QueryEscape(`{"json":{}}`), http.Header{
+				"Accept-Encoding": []string{"gzip;q=0"},
+			})
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			Expect(res.Header.Get("Content-Encoding")).To(BeEmpty())
+			tile := make(map[string]interface{})
+			Expect(json.Unmarshal(readBody(res), &tile)).To(Succeed())
+			Expect(tile["uri"]).To(Equal("test"))
+		})
+
+		It("should accept URIs spanning several segments", func() {
+			for _, path := range []string{"/tile/test/index/type/3/2/1", "/tile/test/index%2Ftype/3/2/1"} {
+				res := get(ts, path+"?tile="+url.QueryEscape(`{"json":{}}`), nil)
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				tile := make(map[string]interface{})
+				Expect(json.Unmarshal(readBody(res), &tile)).To(Succeed())
+				Expect(tile["uri"]).To(Equal("index/type"))
+			}
+		})
+
+		It("should unescape the segments of the URI", func() {
+			res := get(ts, "/tile/test/a+b%20c/3/2/1?tile="+url.QueryEscape(`{"json":{}}`), nil)
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			tile := make(map[string]interface{})
+			Expect(json.Unmarshal(readBody(res), &tile)).To(Succeed())
+			Expect(tile["uri"]).To(Equal("a+b c"))
+		})
+
+		It("should render the tile with the render parameter", func() {
+			res := get(ts, "/tile/test/test/3/2/1?tile="+url.QueryEscape(`{"binary":{}}`)+
+				"&render="+url.QueryEscape(`{"prefix":{"prefix":"rendered:"}}`), nil)
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			Expect(readBody(res)).To(Equal(append([]byte("rendered:"), 0, 0, 128, 63)))
+		})
+
+		It("should respond with 400 for invalid coordinates", func() {
+			res := get(ts, "/tile/test/test/3/a/1?tile="+url.QueryEscape(`{"json":{}}`), nil)
+			Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should respond with 404 for malformed paths", func() {
+			res := get(ts, "/tile/test/test/3/2", nil)
+			Expect(res.StatusCode).To(Equal(http.StatusNotFound))
+		})
+
+	})
+
+	Describe("/meta/{pipeline}", func() {
+
+		It("should respond with the generated meta data for POST requests", func() {
+			res := post(ts, "/meta/test", map[string]interface{}{
+				"uri": "test",
+				"meta": map[string]interface{}{
+					"default": map[string]interface{}{},
+				},
+			})
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			Expect(res.Header.Get("Content-Type")).To(Equal("application/json"))
+			Expect(string(readBody(res))).To(Equal(`{"uri":"test"}`))
+		})
+
+		It("should respond with the generated meta data for GET requests", func() {
+			res := get(ts, "/meta/test/test?meta="+url.QueryEscape(`{"default":{}}`), nil)
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			Expect(string(readBody(res))).To(Equal(`{"uri":"test"}`))
+		})
+
+		It("should accept URIs spanning several segments for GET requests", func() {
+			res := get(ts, "/meta/test/index/type?meta="+url.QueryEscape(`{"default":{}}`), nil)
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			Expect(string(readBody(res))).To(Equal(`{"uri":"index/type"}`))
+		})
+
+		It("should respond with 405 for unsupported methods", func() {
+			req, err := http.NewRequest(http.MethodDelete, ts.URL+"/meta/test", nil)
+			Expect(err).To(BeNil())
+			res, err := http.DefaultClient.Do(req)
+			Expect(err).To(BeNil())
+			Expect(res.StatusCode).To(Equal(http.StatusMethodNotAllowed))
+		})
+
+	})
+
+	Describe("GET /pipeline", func() {
+
+		It("should respond with the description of the pipeline", func() {
+			res := get(ts, "/pipeline/test", nil)
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			Expect(res.Header.Get("Content-Type")).To(Equal("application/json"))
+			desc := make(map[string]interface{})
+			Expect(json.Unmarshal(readBody(res), &desc)).To(Succeed())
+			Expect(desc["id"]).To(Equal("test"))
+			Expect(desc["tiles"]).To(HaveKey("json"))
+			Expect(desc["metas"]).To(HaveKey("default"))
+		})
+
+		It("should respond with the descriptions of every pipeline", func() {
+			res := get(ts, "/pipeline", nil)
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			var descs []map[string]interface{}
+			Expect(json.Unmarshal(readBody(res), &descs)).To(Succeed())
+			ids := make([]interface{}, len(descs))
+			for i, desc := range descs {
+				ids[i] = desc["id"]
+			}
+			Expect(ids).To(ContainElement("test"))
+			Expect(ids).To(ContainElement("limited"))
+		})
+
+		It("should respond with 404 for unrecognized pipelines", func() {
+			res := get(ts, "/pipeline/missing", nil)
+			Expect(res.StatusCode).To(Equal(http.StatusNotFound))
+		})
+
+	})
+
+})
@@ -0,0 +1,260 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/codec"
+	"github.com/unchartedsoftware/veldt/resilience"
+	"github.com/unchartedsoftware/veldt/trace"
+	"github.com/unchartedsoftware/veldt/util/queue"
+)
+
+// Server represents an HTTP handler that exposes the registered pipelines
+// over REST and WebSocket endpoints. Requests are traced as part of the trace
+// provided in the W3C `traceparent` header or message field, if any.
+//
+// Routes:
+//     POST /tile/{pipeline}                    JSON tile request in the body
+//     GET  /tile/{pipeline}/{uri}/{z}/{x}/{y}  `tile` and `query` JSON params
+//     POST /meta/{pipeline}                    JSON meta request in the body
+//     GET  /meta/{pipeline}/{uri}              `meta` JSON param
+//     GET  /pipeline                           descriptions of all pipelines
+//     GET  /pipeline/{pipeline}                description of the pipeline
+//     GET  /ws                                 multiplexed WebSocket requests
+//
+type Server struct {
+	mux      *http.ServeMux
+	upgrader *websocket.Upgrader
+}
+
+// NewServer instantiates and returns a new server.
+func NewServer() *Server {
+	s := &Server{
+		mux: http.NewServeMux(),
+		upgrader: &websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+		},
+	}
+	s.mux.HandleFunc("/tile/", s.handleTile)
+	s.mux.HandleFunc("/meta/", s.handleMeta)
+	s.mux.HandleFunc("/pipeline", s.handlePipeline)
+	s.mux.HandleFunc("/pipeline/", s.handlePipeline)
+	s.mux.HandleFunc("/ws", s.handleWebSocket)
+	return s
+}
+
+// SetCheckOrigin sets the function used to accept or reject the origin of
+// incoming WebSocket connections. By default cross-origin connections are
+// rejected.
+func (s *Server) SetCheckOrigin(fn func(*http.Request) bool) {
+	s.upgrader.CheckOrigin = fn
+}
+
+// ServeHTTP dispatches the request to the matching endpoint.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// requestError represents an error caused by the client request.
+type requestError struct {
+	status int
+	err    error
+}
+
+func (e *requestError) Error() string {
+	return e.err.Error()
+}
+
+func newRequestError(status int, err error) error {
+	return &requestError{
+		status: status,
+		err:    err,
+	}
+}
+
+// getStatus returns the HTTP status code corresponding to the error.
+// Permanent errors are those of the request rather than the backend, such as
+// an unknown table or column, so are client errors.
+func getStatus(err error) int {
+	switch e := err.(type) {
+	case *requestError:
+		return e.status
+	case *resilience.PermanentError:
+		return http.StatusBadRequest
+	case *queue.FullError, *queue.ExpiredError, *resilience.OpenError:
+		return http.StatusServiceUnavailable
+	}
+	if err == context.DeadlineExceeded {
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
+
+func newTileRequest(ctx context.Context, id string, client string, args map[string]interface{}) (*veldt.Pipeline, veldt.Request, error) {
+	pipeline, err := veldt.GetPipeline(id)
+	if err != nil {
+		return nil, nil, newRequestError(http.StatusNotFound, err)
+	}
+	req, err := pipeline.NewTileRequestContext(ctx, args)
+	if err != nil {
+		return nil, nil, newRequestError(http.StatusBadRequest, err)
+	}
+	req.Client = client
+	return pipeline, req, nil
+}
+
+func newMetaRequest(ctx context.Context, id string, client string, args map[string]interface{}) (*veldt.Pipeline, veldt.Request, error) {
+	pipeline, err := veldt.GetPipeline(id)
+	if err != nil {
+		return nil, nil, newRequestError(http.StatusNotFound, err)
+	}
+	req, err := pipeline.NewMetaRequestContext(ctx, args)
+	if err != nil {
+		return nil, nil, newRequestError(http.StatusBadRequest, err)
+	}
+	req.Client = client
+	return pipeline, req, nil
+}
+
+// getClient returns the client the request is made on behalf of, used to
+// share the pipeline queues fairly between clients. Clients are identified by
+// their remote host.
+func getClient(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// startRequestSpan starts the span of a request for the pipeline ID.
+func startRequestSpan(ctx context.Context, id string) (context.Context, *trace.Span) {
+	ctx, span := trace.Start(ctx, trace.RequestSpan)
+	span.SetAttribute(trace.PipelineAttribute, id)
+	return ctx, span
+}
+
+// getETag returns a strong entity tag derived from the request hash and the
+// content coding of the response, if any.
+func getETag(pipeline *veldt.Pipeline, req veldt.Request, encoding string) string {
+	hash := sha1.Sum([]byte(pipeline.GetRequestHash(req)))
+	if encoding == "" {
+		return fmt.Sprintf(`"%x"`, hash)
+	}
+	return fmt.Sprintf(`"%x-%s"`, hash, encoding)
+}
+
+// matchETag returns whether the `If-None-Match` header matches the tag.
+func matchETag(header string, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// getContentEncoding returns the content coding the data compressed with the
+// codec can be served with as is, or an empty string if the codec has no
+// content coding, or the `Accept-Encoding` header does not accept it, in
+// which case the data must be decompressed.
+func getContentEncoding(header string, name string) string {
+	c, err := codec.Get(name)
+	if err != nil {
+		return ""
+	}
+	encoding := c.ContentEncoding()
+	if encoding == "" || encoding == codec.Identity {
+		return ""
+	}
+	if !acceptsEncoding(header, encoding) {
+		return ""
+	}
+	return encoding
+}
+
+// acceptsEncoding returns whether the `Accept-Encoding` header accepts the
+// content coding.
+func acceptsEncoding(header string, encoding string) bool {
+	accepted := false
+	for _, value := range strings.Split(header, ",") {
+		params := strings.Split(value, ";")
+		coding := strings.ToLower(strings.TrimSpace(params[0]))
+		if coding != encoding && coding != "*" {
+			continue
+		}
+		rejected := false
+		for _, param := range params[1:] {
+			param = strings.Replace(param, " ", "", -1)
+			if strings.HasPrefix(param, "q=") {
+				q, err := strconv.ParseFloat(param[2:], 64)
+				rejected = err != nil || q == 0
+			}
+		}
+		if coding == encoding {
+			// an explicit coding takes precedence over the wildcard
+			return !rejected
+		}
+		accepted = !rejected
+	}
+	return accepted
+}
+
+// sniffContentType returns the content type of the data compressed with the
+// codec. Only the prefix needed to sniff the content type is decompressed.
+func sniffContentType(name string, data []byte) (string, error) {
+	c, err := codec.Get(name)
+	if err != nil {
+		return "", err
+	}
+	reader, err := c.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+	prefix, err := ioutil.ReadAll(io.LimitReader(reader, sniffLength+1))
+	if err != nil {
+		return "", err
+	}
+	if len(prefix) <= sniffLength {
+		// the prefix is the entire payload
+		return getContentType(prefix), nil
+	}
+	trimmed := bytes.TrimSpace(prefix)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		return "application/json", nil
+	}
+	return getContentType(prefix), nil
+}
+
+// getContentType returns the content type of the generated data. Tiles do
+// not declare their encoding so it is sniffed from the payload.
+func getContentType(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		var raw json.RawMessage
+		if json.Unmarshal(trimmed, &raw) == nil {
+			return "application/json"
+		}
+	}
+	contentType := http.DetectContentType(data)
+	if strings.HasPrefix(contentType, "image/") {
+		return contentType
+	}
+	return "application/octet-stream"
+}
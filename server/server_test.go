@@ -0,0 +1,146 @@
+package server_test
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/resilience"
+	"github.com/unchartedsoftware/veldt/store/freecache"
+)
+
+var (
+	release = make(chan bool)
+)
+
+type jsonTile struct{}
+
+func (t *jsonTile) Parse(params map[string]interface{}) error {
+	return nil
+}
+
+func (t *jsonTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"uri": uri,
+		"z":   coord.Z,
+		"x":   coord.X,
+		"y":   coord.Y,
+	})
+}
+
+type binaryTile struct{}
+
+func (t *binaryTile) Parse(params map[string]interface{}) error {
+	return nil
+}
+
+func (t *binaryTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return []byte{0, 0, 128, 63}, nil
+}
+
+type blockingTile struct{}
+
+func (t *blockingTile) Parse(params map[string]interface{}) error {
+	return nil
+}
+
+func (t *blockingTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	<-release
+	return []byte{}, nil
+}
+
+type errorTile struct{}
+
+func (t *errorTile) Parse(params map[string]interface{}) error {
+	return nil
+}
+
+func (t *errorTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return nil, fmt.Errorf("backend unavailable")
+}
+
+type prefixRenderer struct {
+	prefix string
+}
+
+func (r *prefixRenderer) Parse(params map[string]interface{}) error {
+	r.prefix, _ = params["prefix"].(string)
+	return nil
+}
+
+func (r *prefixRenderer) Render(req *veldt.TileRequest, data []byte) ([]byte, error) {
+	return append([]byte(r.prefix), data...), nil
+}
+
+type invalidTile struct{}
+
+func (t *invalidTile) Parse(params map[string]interface{}) error {
+	return nil
+}
+
+func (t *invalidTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return nil, resilience.Permanent(fmt.Errorf("column `missing` does not exist in table `test`"))
+}
+
+type testMeta struct{}
+
+func (m *testMeta) Parse(params map[string]interface{}) error {
+	return nil
+}
+
+func (m *testMeta) Create(uri string) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"uri": uri,
+	})
+}
+
+func init() {
+	pipeline := veldt.NewPipeline()
+	pipeline.Tile("json", func() (veldt.Tile, error) {
+		return &jsonTile{}, nil
+	})
+	pipeline.Tile("binary", func() (veldt.Tile, error) {
+		return &binaryTile{}, nil
+	})
+	pipeline.Tile("block", func() (veldt.Tile, error) {
+		return &blockingTile{}, nil
+	})
+	pipeline.Tile("error", func() (veldt.Tile, error) {
+		return &errorTile{}, nil
+	})
+	pipeline.Tile("invalid", func() (veldt.Tile, error) {
+		return &invalidTile{}, nil
+	})
+	pipeline.Meta("default", func() (veldt.Meta, error) {
+		return &testMeta{}, nil
+	})
+	pipeline.Render("prefix", func() (veldt.Renderer, error) {
+		return &prefixRenderer{}, nil
+	})
+	pipeline.Store(freecache.NewConnection(1024*1024, 60))
+	veldt.Register("test", pipeline)
+
+	limited := veldt.NewPipeline()
+	limited.Tile("block", func() (veldt.Tile, error) {
+		return &blockingTile{}, nil
+	})
+	limited.Store(freecache.NewConnection(1024*1024, 60))
+	limited.SetMaxConcurrent(1)
+	limited.SetQueueLength(0)
+	veldt.Register("limited", limited)
+}
+
+func tileRequest(tile string, z, x, y int) map[string]interface{} {
+	return map[string]interface{}{
+		"uri": "test",
+		"coord": map[string]interface{}{
+			"z": z,
+			"x": x,
+			"y": y,
+		},
+		"tile": map[string]interface{}{
+			tile: map[string]interface{}{},
+		},
+	}
+}
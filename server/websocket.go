@@ -0,0 +1,245 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/trace"
+)
+
+// message represents a request sent over the WebSocket connection.
+//
+// Ex:
+//     {
+//         "id": "42",
+//         "type": "tile",
+//         "pipeline": "elastic",
+//         "request": {
+//             "uri": "twitter",
+//             "coord": { "z": 4, "x": 3, "y": 5 },
+//             "tile": { ... }
+//         },
+//         "traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+//     }
+//
+// A message of type `cancel` abandons the in-flight request with the same ID.
+// Cancelled requests do not receive a response. The optional `traceparent`
+// continues the caller's trace.
+type message struct {
+	ID          string                 `json:"id"`
+	Type        string                 `json:"type"`
+	Pipeline    string                 `json:"pipeline"`
+	Request     map[string]interface{} `json:"request"`
+	TraceParent string                 `json:"traceparent"`
+}
+
+// response represents a response sent over the WebSocket connection. The data
+// is base64 encoded.
+type response struct {
+	ID          string `json:"id"`
+	Success     bool   `json:"success"`
+	Status      int    `json:"status"`
+	Error       string `json:"error,omitempty"`
+	ETag        string `json:"etag,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+	Data        []byte `json:"data,omitempty"`
+}
+
+// socket represents a single WebSocket connection multiplexing many requests.
+type socket struct {
+	conn     *websocket.Conn
+	client   string
+	writeMu  sync.Mutex
+	mu       sync.Mutex
+	inflight map[string]*inflightRequest
+	wg       sync.WaitGroup
+}
+
+// inflightRequest represents a request in progress on the connection. Each
+// request has its own entry, so that an ID reused after a cancel does not
+// refer to the abandoned request.
+type inflightRequest struct {
+	cancel context.CancelFunc
+}
+
+func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// upgrader has already responded with the failure
+		Warnf("Unable to upgrade connection: %v", err)
+		return
+	}
+	// messages are limited to the size of REST request bodies
+	conn.SetReadLimit(maxBodySize)
+	sock := &socket{
+		conn:     conn,
+		client:   getClient(r),
+		inflight: make(map[string]*inflightRequest),
+	}
+	sock.listen()
+}
+
+func (s *socket) listen() {
+	// all in-flight requests are abandoned once the connection closes
+	ctx, cancel := context.WithCancel(context.Background())
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				Warnf("Connection closed unexpectedly: %v", err)
+			}
+			break
+		}
+		msg := &message{}
+		err = json.Unmarshal(data, msg)
+		if err != nil {
+			s.write(&response{
+				Status: http.StatusBadRequest,
+				Error:  fmt.Sprintf("message is not valid JSON: %v", err),
+			})
+			continue
+		}
+		s.handle(ctx, msg)
+	}
+	cancel()
+	s.wg.Wait()
+	s.conn.Close()
+}
+
+func (s *socket) handle(ctx context.Context, msg *message) {
+	if msg.ID == "" {
+		s.write(&response{
+			Status: http.StatusBadRequest,
+			Error:  "message is missing `id`",
+		})
+		return
+	}
+	var newRequest func(context.Context, string, string, map[string]interface{}) (*veldt.Pipeline, veldt.Request, error)
+	switch msg.Type {
+	case "tile":
+		newRequest = newTileRequest
+	case "meta":
+		newRequest = newMetaRequest
+	case "cancel":
+		s.cancel(msg.ID)
+		return
+	default:
+		s.writeError(msg.ID, newRequestError(http.StatusBadRequest,
+			fmt.Errorf("unrecognized message type `%s`", msg.Type)))
+		return
+	}
+	reqCtx, entry, ok := s.register(ctx, msg.ID)
+	if !ok {
+		s.writeError(msg.ID, newRequestError(http.StatusConflict,
+			fmt.Errorf("request `%s` is already in progress", msg.ID)))
+		return
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer s.release(msg.ID, entry)
+		ctx, span := startRequestSpan(extractTraceParent(reqCtx, msg.TraceParent), msg.Pipeline)
+		defer span.End()
+		pipeline, req, err := newRequest(ctx, msg.Pipeline, s.client, msg.Request)
+		if err != nil {
+			s.writeError(msg.ID, err)
+			return
+		}
+		data, err := pipeline.GenerateAndGetContext(ctx, req)
+		if reqCtx.Err() == context.Canceled {
+			// either the client cancelled or the connection is gone
+			return
+		}
+		if err != nil {
+			s.writeError(msg.ID, err)
+			return
+		}
+		s.write(&response{
+			ID:          msg.ID,
+			Success:     true,
+			Status:      http.StatusOK,
+			ETag:        getETag(pipeline, req, ""),
+			ContentType: getContentType(data),
+			Data:        data,
+		})
+	}()
+}
+
+// extractTraceParent returns a context continuing the trace of the message's
+// `traceparent`, if present and valid.
+func extractTraceParent(ctx context.Context, traceParent string) context.Context {
+	if traceParent == "" {
+		return ctx
+	}
+	sc, err := trace.Parse(traceParent)
+	if err != nil {
+		Debugf("Ignoring invalid trace context: %v", err)
+		return ctx
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, sc)
+}
+
+func (s *socket) register(ctx context.Context, id string) (context.Context, *inflightRequest, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.inflight[id]; ok {
+		return nil, nil, false
+	}
+	reqCtx, cancel := context.WithCancel(ctx)
+	entry := &inflightRequest{
+		cancel: cancel,
+	}
+	s.inflight[id] = entry
+	return reqCtx, entry, true
+}
+
+// cancel abandons the in-flight request with the ID, if any.
+func (s *socket) cancel(id string) {
+	s.mu.Lock()
+	entry, ok := s.inflight[id]
+	delete(s.inflight, id)
+	s.mu.Unlock()
+	if ok {
+		entry.cancel()
+	}
+}
+
+// release releases the context of the completed request, removing it from
+// the in-flight requests unless its ID has since been reused.
+func (s *socket) release(id string, entry *inflightRequest) {
+	s.mu.Lock()
+	if s.inflight[id] == entry {
+		delete(s.inflight, id)
+	}
+	s.mu.Unlock()
+	entry.cancel()
+}
+
+func (s *socket) writeError(id string, err error) {
+	status := getStatus(err)
+	if status >= http.StatusInternalServerError {
+		Errorf("%d: %v", status, err)
+	} else {
+		Debugf("%d: %v", status, err)
+	}
+	s.write(&response{
+		ID:     id,
+		Status: status,
+		Error:  err.Error(),
+	})
+}
+
+func (s *socket) write(res *response) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	err := s.conn.WriteJSON(res)
+	if err != nil {
+		Warnf("Unable to write response: %v", err)
+	}
+}
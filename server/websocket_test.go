@@ -0,0 +1,189 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/unchartedsoftware/veldt/server"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type wsResponse struct {
+	ID          string `json:"id"`
+	Success     bool   `json:"success"`
+	Status      int    `json:"status"`
+	Error       string `json:"error"`
+	ETag        string `json:"etag"`
+	ContentType string `json:"contentType"`
+	Data        []byte `json:"data"`
+}
+
+func read(conn *websocket.Conn) *wsResponse {
+	res := &wsResponse{}
+	Expect(conn.ReadJSON(res)).To(Succeed())
+	return res
+}
+
+var _ = Describe("WebSocket", func() {
+
+	var ts *httptest.Server
+	var conn *websocket.Conn
+
+	BeforeEach(func() {
+		ts = httptest.NewServer(server.NewServer())
+		var err error
+		url := "ws" + strings.TrimPrefix(ts.URL, "http") + "/ws"
+		conn, _, err = websocket.DefaultDialer.Dial(url, nil)
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		conn.Close()
+		ts.Close()
+	})
+
+	It("should respond to tile requests with the matching ID", func() {
+		Expect(conn.WriteJSON(map[string]interface{}{
+			"id":       "a",
+			"type":     "tile",
+			"pipeline": "test",
+			"request":  tileRequest("json", 4, 1, 2),
+		})).To(Succeed())
+		res := read(conn)
+		Expect(res.ID).To(Equal("a"))
+		Expect(res.Success).To(BeTrue())
+		Expect(res.Status).To(Equal(http.StatusOK))
+		Expect(res.ContentType).To(Equal("application/json"))
+		Expect(res.ETag).NotTo(BeEmpty())
+		tile := make(map[string]interface{})
+		Expect(json.Unmarshal(res.Data, &tile)).To(Succeed())
+		Expect(tile["z"]).To(Equal(4.0))
+	})
+
+	It("should respond to meta requests", func() {
+		Expect(conn.WriteJSON(map[string]interface{}{
+			"id":       "b",
+			"type":     "meta",
+			"pipeline": "test",
+			"request": map[string]interface{}{
+				"uri": "test",
+				"meta": map[string]interface{}{
+					"default": map[string]interface{}{},
+				},
+			},
+		})).To(Succeed())
+		res := read(conn)
+		Expect(res.ID).To(Equal("b"))
+		Expect(res.Success).To(BeTrue())
+		Expect(string(res.Data)).To(Equal(`{"uri":"test"}`))
+	})
+
+	It("should multiplex concurrent requests", func() {
+		Expect(conn.WriteJSON(map[string]interface{}{
+			"id":       "slow",
+			"type":     "tile",
+			"pipeline": "test",
+			"request":  tileRequest("block", 4, 0, 0),
+		})).To(Succeed())
+		Expect(conn.WriteJSON(map[string]interface{}{
+			"id":       "fast",
+			"type":     "tile",
+			"pipeline": "test",
+			"request":  tileRequest("json", 4, 0, 0),
+		})).To(Succeed())
+		res := read(conn)
+		Expect(res.ID).To(Equal("fast"))
+		release <- true
+		res = read(conn)
+		Expect(res.ID).To(Equal("slow"))
+	})
+
+	It("should not respond to cancelled requests", func() {
+		Expect(conn.WriteJSON(map[string]interface{}{
+			"id":       "cancelled",
+			"type":     "tile",
+			"pipeline": "test",
+			"request":  tileRequest("block", 4, 1, 1),
+		})).To(Succeed())
+		time.Sleep(time.Millisecond * 100)
+		Expect(conn.WriteJSON(map[string]interface{}{
+			"id":   "cancelled",
+			"type": "cancel",
+		})).To(Succeed())
+		Expect(conn.WriteJSON(map[string]interface{}{
+			"id":       "next",
+			"type":     "tile",
+			"pipeline": "test",
+			"request":  tileRequest("json", 4, 1, 1),
+		})).To(Succeed())
+		res := read(conn)
+		Expect(res.ID).To(Equal("next"))
+		// unblock the abandoned tile
+		release <- true
+	})
+
+	It("should respond to a request reusing the ID of a cancelled request", func() {
+		Expect(conn.WriteJSON(map[string]interface{}{
+			"id":       "reused",
+			"type":     "tile",
+			"pipeline": "test",
+			"request":  tileRequest("block", 4, 2, 2),
+		})).To(Succeed())
+		time.Sleep(time.Millisecond * 100)
+		Expect(conn.WriteJSON(map[string]interface{}{
+			"id":   "reused",
+			"type": "cancel",
+		})).To(Succeed())
+		Expect(conn.WriteJSON(map[string]interface{}{
+			"id":       "reused",
+			"type":     "tile",
+			"pipeline": "test",
+			"request":  tileRequest("json", 4, 2, 3),
+		})).To(Succeed())
+		res := read(conn)
+		Expect(res.ID).To(Equal("reused"))
+		Expect(res.Status).To(Equal(http.StatusOK))
+		// unblock the abandoned tile
+		release <- true
+	})
+
+	It("should close the connection for messages larger than the limit", func() {
+		Expect(conn.WriteMessage(websocket.TextMessage, make([]byte, 2<<20))).To(Succeed())
+		_, _, err := conn.ReadMessage()
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should respond with the status of failed requests", func() {
+		Expect(conn.WriteJSON(map[string]interface{}{
+			"id":       "c",
+			"type":     "tile",
+			"pipeline": "test",
+			"request": map[string]interface{}{
+				"uri": "test",
+			},
+		})).To(Succeed())
+		res := read(conn)
+		Expect(res.ID).To(Equal("c"))
+		Expect(res.Success).To(BeFalse())
+		Expect(res.Status).To(Equal(http.StatusBadRequest))
+		Expect(res.Error).To(ContainSubstring("invalid tile request"))
+	})
+
+	It("should reject unrecognized message types", func() {
+		Expect(conn.WriteJSON(map[string]interface{}{
+			"id":   "d",
+			"type": "unknown",
+		})).To(Succeed())
+		res := read(conn)
+		Expect(res.ID).To(Equal("d"))
+		Expect(res.Status).To(Equal(http.StatusBadRequest))
+	})
+
+})
@@ -1,5 +1,14 @@
 package veldt
 
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/unchartedsoftware/veldt/binning"
+)
+
 // Store represents an interface for connecting to, setting, and retrieving
 // values from a key-value database or in-memory storage server.
 type Store interface {
@@ -9,6 +18,92 @@ type Store interface {
 	Close()
 }
 
+// InvalidatingStore represents an interface for stores that support removing
+// and expiring values, as well as batch operations.
+type InvalidatingStore interface {
+	Store
+	// Delete removes the value under the key, if it exists.
+	Delete(string) error
+	// DeleteByPrefix removes all values under keys beginning with the
+	// prefix.
+	DeleteByPrefix(string) error
+	// SetExpiry stores the value under the key, expiring it after the
+	// provided number of seconds. An expiry of 0 never expires the value.
+	SetExpiry(string, []byte, int) error
+	// MGet retrieves the values under each key. The value of any key that
+	// does not exist is nil.
+	MGet([]string) ([][]byte, error)
+	// MSet stores each value under its key.
+	MSet(map[string][]byte) error
+}
+
+// EncodedStore represents an interface for stores that record the name of the
+// codec the data of each key is compressed with, such that readers other than
+// the pipeline may decode it.
+type EncodedStore interface {
+	Store
+	// SetEncoded stores the value under the key, along with the name of the
+	// codec it is compressed with.
+	SetEncoded(string, []byte, string) error
+}
+
 // StoreCtor represents a function that instantiates and returns a new storage
 // type.
 type StoreCtor func() (Store, error)
+
+const (
+	// keySeparator separates the segments of the keys generated by a
+	// pipeline.
+	keySeparator = "/"
+	// metaSegment replaces the coordinate segments of metadata keys.
+	metaSegment = "meta"
+)
+
+// ParseKey parses the URI and, for tile data, the tile coordinate from a key
+// generated by a pipeline. The coordinate is nil for metadata keys.
+//
+// Ex:
+//     sample_index0/4/12/8/{digest}
+//     sample_index0/meta/{digest}
+//
+func ParseKey(key string) (string, *binning.TileCoord, error) {
+	parts := strings.Split(key, keySeparator)
+	var coord *binning.TileCoord
+	switch {
+	case len(parts) == 3 && parts[1] == metaSegment:
+	case len(parts) == 5:
+		var ok bool
+		coord, ok = parseCoordSegments(parts[1:4])
+		if !ok {
+			return "", nil, fmt.Errorf("key `%s` does not contain a valid coordinate", key)
+		}
+	default:
+		return "", nil, fmt.Errorf("key `%s` is not a pipeline key", key)
+	}
+	uri, err := url.QueryUnescape(parts[0])
+	if err != nil {
+		return "", nil, err
+	}
+	return uri, coord, nil
+}
+
+// getCoordSegment returns the `z/x/y` key segment of the tile coordinate.
+func getCoordSegment(coord *binning.TileCoord) string {
+	return fmt.Sprintf("%d%s%d%s%d", coord.Z, keySeparator, coord.X, keySeparator, coord.Y)
+}
+
+func parseCoordSegments(parts []string) (*binning.TileCoord, bool) {
+	vals := make([]uint32, 3)
+	for i, part := range parts {
+		val, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			return nil, false
+		}
+		vals[i] = uint32(val)
+	}
+	return &binning.TileCoord{
+		Z: vals[0],
+		X: vals[1],
+		Y: vals[2],
+	}, true
+}
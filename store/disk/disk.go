@@ -0,0 +1,147 @@
+package disk
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/unchartedsoftware/veldt"
+)
+
+const (
+	metaDir = "meta"
+)
+
+// Connection represents a single connection to a directory of the local
+// filesystem.
+type Connection struct {
+	dir string
+}
+
+// NewStore instantiates and returns a new disk store connection. Data is
+// written under the provided directory, sharded by URI and tile coordinate:
+//
+//     {dir}/{uri}/{z}/{x}/{y}/{hash}
+//     {dir}/{uri}/meta/{hash}
+//
+// where the hash identifies the full key, so that different tiles of the same
+// coordinate do not collide. Data is never expired.
+func NewStore(dir string) veldt.StoreCtor {
+	return func() (veldt.Store, error) {
+		err := os.MkdirAll(dir, 0755)
+		if err != nil {
+			return nil, err
+		}
+		return &Connection{
+			dir: dir,
+		}, nil
+	}
+}
+
+// Get when given a string key will return a byte slice of data from disk.
+func (c *Connection) Get(key string) ([]byte, error) {
+	path, err := c.getPath(key)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadFile(path)
+}
+
+// Set will store a byte slice under a given key on disk. The data is written
+// to a temporary file and renamed into place, so that concurrent readers never
+// observe a partially written file.
+func (c *Connection) Set(key string, value []byte) error {
+	path, err := c.getPath(key)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+	// the temporary file must be on the same filesystem for the rename to
+	// be atomic
+	file, err := ioutil.TempFile(dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(value)
+	if err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	err = file.Close()
+	if err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	err = os.Rename(file.Name(), path)
+	if err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	return nil
+}
+
+// Exists returns whether or not a key exists on disk.
+func (c *Connection) Exists(key string) (bool, error) {
+	path, err := c.getPath(key)
+	if err != nil {
+		return false, err
+	}
+	_, err = os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Close closes the disk connection.
+func (c *Connection) Close() {
+	// no-op
+}
+
+// getPath returns the path of the file of the key, which is always within the
+// directory of the store.
+func (c *Connection) getPath(key string) (string, error) {
+	path := c.getUnsafePath(key)
+	root := filepath.Clean(c.dir) + string(filepath.Separator)
+	if !strings.HasPrefix(filepath.Clean(path), root) {
+		return "", fmt.Errorf("key `%s` is outside of the store directory", key)
+	}
+	return path, nil
+}
+
+func (c *Connection) getUnsafePath(key string) string {
+	hash := fmt.Sprintf("%x", sha1.Sum([]byte(key)))
+	uri, coord, err := veldt.ParseKey(key)
+	if err != nil || uri == "" {
+		// not a pipeline key, store at the root
+		return filepath.Join(c.dir, hash)
+	}
+	dir := filepath.Join(c.dir, escapeURI(uri))
+	if coord == nil {
+		return filepath.Join(dir, metaDir, hash)
+	}
+	return filepath.Join(dir,
+		strconv.FormatUint(uint64(coord.Z), 10),
+		strconv.FormatUint(uint64(coord.X), 10),
+		strconv.FormatUint(uint64(coord.Y), 10),
+		hash)
+}
+
+// escapeURI escapes the URI to a single directory name. Dots are escaped as
+// well, such that the name is never `.` or `..`.
+func escapeURI(uri string) string {
+	return strings.Replace(url.QueryEscape(uri), ".", "%2E", -1)
+}
@@ -0,0 +1,109 @@
+package disk_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/store/disk"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type staticTile struct{}
+
+func (t *staticTile) Parse(params map[string]interface{}) error {
+	return nil
+}
+
+func (t *staticTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return []byte("tile"), nil
+}
+
+var _ = Describe("Connection", func() {
+
+	var dir string
+	var store veldt.Store
+	var pipeline *veldt.Pipeline
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "veldt-disk")
+		Expect(err).To(BeNil())
+		store, err = disk.NewStore(dir)()
+		Expect(err).To(BeNil())
+		pipeline = veldt.NewPipeline()
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("should get a value that was set", func() {
+		err := store.Set("key", []byte("value"))
+		Expect(err).To(BeNil())
+		exists, err := store.Exists("key")
+		Expect(err).To(BeNil())
+		Expect(exists).To(BeTrue())
+		val, err := store.Get("key")
+		Expect(err).To(BeNil())
+		Expect(val).To(Equal([]byte("value")))
+	})
+
+	It("should return an error for a missing key", func() {
+		exists, err := store.Exists("key")
+		Expect(err).To(BeNil())
+		Expect(exists).To(BeFalse())
+		_, err = store.Get("key")
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should overwrite an existing value", func() {
+		Expect(store.Set("key", []byte("a"))).To(BeNil())
+		Expect(store.Set("key", []byte("b"))).To(BeNil())
+		val, err := store.Get("key")
+		Expect(err).To(BeNil())
+		Expect(val).To(Equal([]byte("b")))
+	})
+
+	It("should shard tiles by URI and coordinate", func() {
+		key := pipeline.GetRequestHash(&veldt.TileRequest{
+			URI:   "a/b",
+			Coord: &binning.TileCoord{Z: 4, X: 12, Y: 8},
+			Tile:  &staticTile{},
+		})
+		Expect(store.Set(key, []byte("tile"))).To(BeNil())
+		files, err := ioutil.ReadDir(filepath.Join(dir, "a%2Fb", "4", "12", "8"))
+		Expect(err).To(BeNil())
+		// no temporary files are left behind
+		Expect(len(files)).To(Equal(1))
+	})
+
+	It("should keep dot URIs within the store directory", func() {
+		root := filepath.Join(dir, "store")
+		store, err := disk.NewStore(root)()
+		Expect(err).To(BeNil())
+		for _, uri := range []string{"..", ".", "a.b"} {
+			key := pipeline.GetRequestHash(&veldt.TileRequest{
+				URI:   uri,
+				Coord: &binning.TileCoord{Z: 0, X: 0, Y: 0},
+				Tile:  &staticTile{},
+			})
+			Expect(store.Set(key, []byte("tile"))).To(BeNil())
+			val, err := store.Get(key)
+			Expect(err).To(BeNil())
+			Expect(val).To(Equal([]byte("tile")))
+		}
+		_, err = os.Stat(filepath.Join(root, "%2E%2E", "0", "0", "0"))
+		Expect(err).To(BeNil())
+		_, err = os.Stat(filepath.Join(root, "a%2Eb", "0", "0", "0"))
+		Expect(err).To(BeNil())
+		// nothing is written beside the store directory
+		files, err := ioutil.ReadDir(dir)
+		Expect(err).To(BeNil())
+		Expect(len(files)).To(Equal(1))
+	})
+})
@@ -0,0 +1,18 @@
+package disk
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+func init() {
+	veldt.RegisterStoreFactory("disk", func(params map[string]interface{}) (veldt.StoreCtor, error) {
+		dir, ok := json.GetString(params, "dir")
+		if !ok {
+			return nil, fmt.Errorf("`dir` parameter missing from disk store")
+		}
+		return NewStore(dir), nil
+	})
+}
@@ -0,0 +1,19 @@
+package freecache
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+func init() {
+	veldt.RegisterStoreFactory("freecache", func(params map[string]interface{}) (veldt.StoreCtor, error) {
+		size, ok := json.GetInt(params, "size")
+		if !ok || size <= 0 {
+			return nil, fmt.Errorf("`size` parameter missing from freecache store")
+		}
+		expiry := json.GetIntDefault(params, -1, "expiry")
+		return NewConnection(size, expiry), nil
+	})
+}
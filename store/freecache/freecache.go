@@ -1,6 +1,7 @@
 package freecache
 
 import (
+	"bytes"
 	"runtime"
 	"sync"
 
@@ -61,6 +62,59 @@ func (r *Connection) Exists(key string) (bool, error) {
 	return true, nil
 }
 
+// Delete removes the value under the key from freecache.
+func (r *Connection) Delete(key string) error {
+	r.cache.Del([]byte(key))
+	return nil
+}
+
+// DeleteByPrefix removes all values under keys beginning with the prefix from
+// freecache.
+func (r *Connection) DeleteByPrefix(prefix string) error {
+	// collect the keys first, as deleting while iterating is unsafe
+	var keys [][]byte
+	iter := r.cache.NewIterator()
+	for entry := iter.Next(); entry != nil; entry = iter.Next() {
+		if bytes.HasPrefix(entry.Key, []byte(prefix)) {
+			keys = append(keys, entry.Key)
+		}
+	}
+	for _, key := range keys {
+		r.cache.Del(key)
+	}
+	return nil
+}
+
+// SetExpiry will store a byte slice under a given key in freecache, expiring
+// it after the provided number of seconds.
+func (r *Connection) SetExpiry(key string, value []byte, expirySeconds int) error {
+	return r.cache.Set([]byte(key), value, expirySeconds)
+}
+
+// MGet when given string keys will return the byte slices of data from
+// freecache. The value of any key that does not exist is nil.
+func (r *Connection) MGet(keys []string) ([][]byte, error) {
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		value, err := r.cache.Get([]byte(key))
+		if err == nil {
+			values[i] = value
+		}
+	}
+	return values, nil
+}
+
+// MSet will store each byte slice under its key in freecache.
+func (r *Connection) MSet(values map[string][]byte) error {
+	for key, value := range values {
+		err := r.Set(key, value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Close closes the freecache connection.
 func (r *Connection) Close() {
 	// no-op
@@ -0,0 +1,28 @@
+package redis
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+func init() {
+	veldt.RegisterStoreFactory("redis", func(params map[string]interface{}) (veldt.StoreCtor, error) {
+		host, ok := json.GetString(params, "host")
+		if !ok {
+			return nil, fmt.Errorf("`host` parameter missing from redis store")
+		}
+		port, ok := json.GetString(params, "port")
+		if !ok {
+			num, ok := json.GetInt(params, "port")
+			if !ok {
+				return nil, fmt.Errorf("`port` parameter missing from redis store")
+			}
+			port = strconv.Itoa(num)
+		}
+		expiry := json.GetIntDefault(params, -1, "expiry")
+		return NewStore(host, port, expiry), nil
+	})
+}
@@ -1,11 +1,27 @@
 package redis
 
 import (
+	"strings"
+
 	"github.com/garyburd/redigo/redis"
 
 	"github.com/unchartedsoftware/veldt"
 )
 
+const (
+	scanCount = 1000
+)
+
+var (
+	// escapes the special characters of a redis glob pattern
+	globEscaper = strings.NewReplacer(
+		`\`, `\\`,
+		"*", `\*`,
+		"?", `\?`,
+		"[", `\[`,
+		"]", `\]`)
+)
+
 // Store represents a single connection to a redis server.
 type Store struct {
 	conn   redis.Conn
@@ -38,6 +54,89 @@ func (r *Store) Set(key string, value []byte) error {
 	return err
 }
 
+// SetExpiry will store a byte slice under a given key in redis, expiring it
+// after the provided number of seconds.
+func (r *Store) SetExpiry(key string, value []byte, expirySeconds int) error {
+	var err error
+	if expirySeconds > 0 {
+		_, err = r.conn.Do("SET", key, value, "EX", expirySeconds)
+	} else {
+		_, err = r.conn.Do("SET", key, value)
+	}
+	return err
+}
+
+// Delete removes the value under the key from redis.
+func (r *Store) Delete(key string) error {
+	_, err := r.conn.Do("DEL", key)
+	return err
+}
+
+// DeleteByPrefix removes all values under keys beginning with the prefix from
+// redis. Keys are scanned incrementally to avoid blocking the server.
+func (r *Store) DeleteByPrefix(prefix string) error {
+	match := globEscaper.Replace(prefix) + "*"
+	cursor := 0
+	for {
+		values, err := redis.Values(r.conn.Do("SCAN", cursor, "MATCH", match, "COUNT", scanCount))
+		if err != nil {
+			return err
+		}
+		var keys []string
+		_, err = redis.Scan(values, &cursor, &keys)
+		if err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			_, err = r.conn.Do("DEL", redis.Args{}.AddFlat(keys)...)
+			if err != nil {
+				return err
+			}
+		}
+		if cursor == 0 {
+			return nil
+		}
+	}
+}
+
+// MGet when given string keys will return the byte slices of data from redis.
+// The value of any key that does not exist is nil.
+func (r *Store) MGet(keys []string) ([][]byte, error) {
+	if len(keys) == 0 {
+		return [][]byte{}, nil
+	}
+	return redis.ByteSlices(r.conn.Do("MGET", redis.Args{}.AddFlat(keys)...))
+}
+
+// MSet will store each byte slice under its key in redis. Values are stored
+// with the same semantics as Set.
+func (r *Store) MSet(values map[string][]byte) error {
+	if len(values) == 0 {
+		return nil
+	}
+	if r.expiry <= 0 {
+		args := redis.Args{}
+		for key, value := range values {
+			args = args.Add(key, value)
+		}
+		_, err := r.conn.Do("MSET", args...)
+		return err
+	}
+	// MSET does not support expiry, so pipeline the individual commands
+	err := r.conn.Send("MULTI")
+	if err != nil {
+		return err
+	}
+	for key, value := range values {
+		err = r.conn.Send("SET", key, value, "NX", "EX", r.expiry)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = r.conn.Do("EXEC")
+	return err
+}
+
 // Exists returns whether or not a key exists in redis.
 func (r *Store) Exists(key string) (bool, error) {
 	return redis.Bool(r.conn.Do("Exists", key))
@@ -0,0 +1,23 @@
+package s3
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+func init() {
+	veldt.RegisterStoreFactory("s3", func(params map[string]interface{}) (veldt.StoreCtor, error) {
+		bucket, ok := json.GetString(params, "bucket")
+		if !ok {
+			return nil, fmt.Errorf("`bucket` parameter missing from s3 store")
+		}
+		prefix := json.GetStringDefault(params, "", "prefix")
+		ext, ok := json.GetString(params, "ext")
+		if !ok {
+			return nil, fmt.Errorf("`ext` parameter missing from s3 store")
+		}
+		return NewStore(bucket, prefix, ext), nil
+	})
+}
@@ -0,0 +1,191 @@
+package s3
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/codec"
+	s3util "github.com/unchartedsoftware/veldt/generation/s3"
+)
+
+const (
+	metaDir     = "meta"
+	keyMetadata = "Veldt-Key"
+)
+
+// Connection represents a single connection to an S3 bucket.
+type Connection struct {
+	client *s3.S3
+	bucket string
+	prefix string
+	ext    string
+}
+
+// NewStore instantiates and returns a new S3 store connection. Tiles are
+// written under the same padded coordinate layout read by the
+// `generation/s3` tile:
+//
+//     {prefix}/{uri}/{z}/{x}/{y}.{ext}
+//
+// so that tiles stored by one deployment can be served read-only by another
+// using the URI `{bucket}/{prefix}/{uri}`. As each coordinate holds a single
+// tile, the full key is recorded in the object metadata and a tile stored
+// under a different key is treated as missing. Metadata is written under
+// `{prefix}/{uri}/meta/`. Data is stored as compressed by the pipeline, with
+// the name of its codec as the content encoding of the object, by which the
+// `generation/s3` tile decodes it. The S3 client shares the session of the
+// `generation/s3` tile, set by its `SetSession`. Keys whose URI leads outside
+// of the prefix are rejected.
+func NewStore(bucket string, prefix string, ext string) veldt.StoreCtor {
+	return func() (veldt.Store, error) {
+		client, err := s3util.NewS3Client()
+		if err != nil {
+			return nil, err
+		}
+		return &Connection{
+			client: client,
+			bucket: bucket,
+			prefix: prefix,
+			ext:    ext,
+		}, nil
+	}
+}
+
+// Get when given a string key will return a byte slice of data from S3.
+func (c *Connection) Get(key string) ([]byte, error) {
+	objectKey, err := c.getObjectKey(key)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s3util.GetObject(c.client, &s3.GetObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(objectKey),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if !matchesKey(res.Metadata, key) {
+		return nil, fmt.Errorf("key `%s` not found", key)
+	}
+	return ioutil.ReadAll(res.Body)
+}
+
+// Set will store a byte slice under a given key in S3.
+func (c *Connection) Set(key string, value []byte) error {
+	return c.SetEncoded(key, value, codec.Identity)
+}
+
+// SetEncoded will store a byte slice under a given key in S3, with the name
+// of the codec it is compressed with as its content encoding.
+func (c *Connection) SetEncoded(key string, value []byte, encoding string) error {
+	objectKey, err := c.getObjectKey(key)
+	if err != nil {
+		return err
+	}
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(objectKey),
+		Body:   bytes.NewReader(value),
+		Metadata: map[string]*string{
+			keyMetadata: aws.String(hashKey(key)),
+		},
+	}
+	if encoding != codec.Identity {
+		input.ContentEncoding = aws.String(encoding)
+	}
+	_, err = c.client.PutObject(input)
+	return err
+}
+
+// Exists returns whether or not a key exists in S3.
+func (c *Connection) Exists(key string) (bool, error) {
+	objectKey, err := c.getObjectKey(key)
+	if err != nil {
+		return false, err
+	}
+	res, err := c.client.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(objectKey),
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return matchesKey(res.Metadata, key), nil
+}
+
+// Close closes the S3 connection.
+func (c *Connection) Close() {
+	// no-op
+}
+
+// getObjectKey returns the object key of the key, which is always under the
+// prefix of the store.
+func (c *Connection) getObjectKey(key string) (string, error) {
+	hash := hashKey(key)
+	uri, coord, err := veldt.ParseKey(key)
+	if err != nil {
+		// not a pipeline key, store under the prefix
+		return path.Join(c.prefix, hash), nil
+	}
+	// the URI is unescaped, so may contain `..` segments
+	dir := path.Join(c.prefix, uri)
+	if !c.isUnderPrefix(dir) {
+		return "", fmt.Errorf("key `%s` is outside of the store prefix", key)
+	}
+	if coord == nil {
+		return path.Join(dir, metaDir, hash), nil
+	}
+	// must match the padded layout of the `generation/s3` tile
+	digits := strconv.Itoa(int(math.Floor(math.Log10(float64(int(1)<<coord.Z)))) + 1)
+	format := "%s/%02d/%0" + digits + "d/%0" + digits + "d.%s"
+	return fmt.Sprintf(format,
+		dir,
+		coord.Z,
+		coord.X,
+		coord.Y,
+		c.ext), nil
+}
+
+// isUnderPrefix returns whether the cleaned path is strictly under the prefix
+// of the store.
+func (c *Connection) isUnderPrefix(dir string) bool {
+	if dir == ".." || strings.HasPrefix(dir, "../") {
+		return false
+	}
+	if c.prefix == "" {
+		return dir != "." && dir != "/"
+	}
+	root := strings.TrimSuffix(path.Clean(c.prefix), "/")
+	return strings.HasPrefix(dir, root+"/")
+}
+
+func hashKey(key string) string {
+	return fmt.Sprintf("%x", sha1.Sum([]byte(key)))
+}
+
+func matchesKey(metadata map[string]*string, key string) bool {
+	return aws.StringValue(metadata[keyMetadata]) == hashKey(key)
+}
+
+func isNotFound(err error) bool {
+	aerr, ok := err.(awserr.Error)
+	if !ok {
+		return false
+	}
+	return aerr.Code() == "NotFound" || aerr.Code() == s3.ErrCodeNoSuchKey
+}
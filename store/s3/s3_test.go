@@ -0,0 +1,152 @@
+package s3_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/codec"
+	s3tile "github.com/unchartedsoftware/veldt/generation/s3"
+	"github.com/unchartedsoftware/veldt/store/s3"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type object struct {
+	header http.Header
+	body   []byte
+}
+
+// newBucketServer returns a server emulating the object operations of S3
+// with path style addressing.
+func newBucketServer() *httptest.Server {
+	objects := make(map[string]*object)
+	mu := &sync.Mutex{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case "PUT":
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			header := http.Header{}
+			for key, values := range r.Header {
+				if key == "Content-Encoding" || strings.HasPrefix(key, "X-Amz-Meta-") {
+					header[key] = values
+				}
+			}
+			objects[r.URL.Path] = &object{
+				header: header,
+				body:   body,
+			}
+		case "GET", "HEAD":
+			obj, ok := objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				if r.Method == "GET" {
+					w.Write([]byte(`<Error><Code>NoSuchKey</Code></Error>`))
+				}
+				return
+			}
+			for key, values := range obj.header {
+				w.Header()[key] = values
+			}
+			if r.Method == "GET" {
+				w.Write(obj.body)
+			}
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+}
+
+type staticTile struct{}
+
+func (t *staticTile) Parse(params map[string]interface{}) error {
+	return nil
+}
+
+func (t *staticTile) Create(uri string, coord *binning.TileCoord, query veldt.Query) ([]byte, error) {
+	return []byte("tile"), nil
+}
+
+var _ = Describe("Connection", func() {
+
+	var server *httptest.Server
+
+	BeforeEach(func() {
+		server = newBucketServer()
+		sess, err := session.NewSession(&aws.Config{
+			Endpoint:         aws.String(server.URL),
+			Region:           aws.String("us-east-1"),
+			S3ForcePathStyle: aws.Bool(true),
+			Credentials:      credentials.NewStaticCredentials("id", "secret", ""),
+		})
+		Expect(err).To(BeNil())
+		s3tile.SetSession(sess)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should store tiles that are served decoded by the s3 tile", func() {
+		pipeline := veldt.NewPipeline()
+		pipeline.SetCompression(codec.Gzip)
+		pipeline.Tile("static", func() (veldt.Tile, error) {
+			return &staticTile{}, nil
+		})
+		pipeline.Store(s3.NewStore("bucket", "tiles", "bin"))
+		coord := &binning.TileCoord{Z: 2, X: 1, Y: 3}
+		req := &veldt.TileRequest{
+			URI:   "sample",
+			Coord: coord,
+			Tile:  &staticTile{},
+		}
+		Expect(pipeline.Generate(req)).To(BeNil())
+
+		// the pipeline reads back its own compressed tile
+		data, err := pipeline.Get(req)
+		Expect(err).To(BeNil())
+		Expect(data).To(Equal([]byte("tile")))
+
+		// another deployment serves the tile through the s3 tile
+		t, err := s3tile.NewTile()()
+		Expect(err).To(BeNil())
+		Expect(t.Parse(map[string]interface{}{})).To(BeNil())
+		data, err = t.Create("bucket/tiles/sample", coord, nil)
+		Expect(err).To(BeNil())
+		Expect(data).To(Equal([]byte("tile")))
+	})
+
+	It("should reject keys whose URI leads outside of the prefix", func() {
+		for _, prefix := range []string{"tiles", ""} {
+			conn, err := s3.NewStore("bucket", prefix, "bin")()
+			Expect(err).To(BeNil())
+			for _, uri := range []string{"../other", "sample/../../other", ".."} {
+				tileKey := url.QueryEscape(uri) + "/2/1/3/digest"
+				metaKey := url.QueryEscape(uri) + "/meta/digest"
+				for _, key := range []string{tileKey, metaKey} {
+					Expect(conn.Set(key, []byte("tile"))).NotTo(BeNil())
+					_, err = conn.Get(key)
+					Expect(err).NotTo(BeNil())
+					_, err = conn.Exists(key)
+					Expect(err).NotTo(BeNil())
+				}
+			}
+		}
+	})
+})
@@ -0,0 +1,33 @@
+package tiered
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+func init() {
+	veldt.RegisterStoreFactory("tiered", func(params map[string]interface{}) (veldt.StoreCtor, error) {
+		tiers, ok := json.GetChildArray(params, "tiers")
+		if !ok || len(tiers) == 0 {
+			return nil, fmt.Errorf("`tiers` parameter missing from tiered store")
+		}
+		ctors := make([]veldt.StoreCtor, len(tiers))
+		for i, tier := range tiers {
+			typ, ok := json.GetString(tier, "type")
+			if !ok {
+				return nil, fmt.Errorf("`type` parameter missing from tier %d of tiered store", i)
+			}
+			factory, err := veldt.GetStoreFactory(typ)
+			if err != nil {
+				return nil, err
+			}
+			ctors[i], err = factory(tier)
+			if err != nil {
+				return nil, fmt.Errorf("tier %d of tiered store: %v", i, err)
+			}
+		}
+		return NewStore(ctors...), nil
+	})
+}
@@ -0,0 +1,215 @@
+package tiered
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/unchartedsoftware/veldt"
+)
+
+// Stats represents the hit counts of a tiered store, shared across all
+// connections created by the same constructor.
+type Stats struct {
+	hits   []uint64
+	misses uint64
+}
+
+// Hits returns the number of gets served by each tier, in tier order.
+func (s *Stats) Hits() []uint64 {
+	hits := make([]uint64, len(s.hits))
+	for i := range s.hits {
+		hits[i] = atomic.LoadUint64(&s.hits[i])
+	}
+	return hits
+}
+
+// Misses returns the number of gets not served by any tier.
+func (s *Stats) Misses() uint64 {
+	return atomic.LoadUint64(&s.misses)
+}
+
+// Store represents a composite store of multiple tiers, ordered from fastest
+// to slowest. Gets check each tier in order and promote hits into the faster
+// tiers, while sets write through to every tier. Each tier applies its own
+// expiry.
+type Store struct {
+	tiers []veldt.Store
+	stats *Stats
+}
+
+// NewStore instantiates and returns a new tiered store connection from the
+// provided tiers, ordered from fastest to slowest.
+//
+// Ex:
+//     tiered.NewStore(
+//         freecache.NewConnection(1024*1024*256, 60),
+//         redis.NewStore("localhost", "6379", 3600))
+//
+func NewStore(tiers ...veldt.StoreCtor) veldt.StoreCtor {
+	stats := &Stats{
+		hits: make([]uint64, len(tiers)),
+	}
+	return func() (veldt.Store, error) {
+		if len(tiers) == 0 {
+			return nil, fmt.Errorf("tiered store has no tiers")
+		}
+		s := &Store{
+			tiers: make([]veldt.Store, 0, len(tiers)),
+			stats: stats,
+		}
+		for _, ctor := range tiers {
+			tier, err := ctor()
+			if err != nil {
+				// close any tiers already opened
+				s.Close()
+				return nil, err
+			}
+			s.tiers = append(s.tiers, tier)
+		}
+		return s, nil
+	}
+}
+
+// Stats returns the hit counts of the store.
+func (s *Store) Stats() *Stats {
+	return s.stats
+}
+
+// Get when given a string key will return a byte slice of data from the
+// fastest tier containing it. The data is promoted into all faster tiers,
+// except those implementing the veldt.EncodedStore interface, as the codec of
+// the data is unknown.
+func (s *Store) Get(key string) ([]byte, error) {
+	var err error
+	for i, tier := range s.tiers {
+		var value []byte
+		value, err = tier.Get(key)
+		if err != nil {
+			// treat as a miss and fall through to the next tier
+			continue
+		}
+		atomic.AddUint64(&s.stats.hits[i], 1)
+		// promote into the faster tiers
+		for _, faster := range s.tiers[:i] {
+			_, ok := faster.(veldt.EncodedStore)
+			if ok {
+				continue
+			}
+			// promotion is best effort, the value is still valid
+			faster.Set(key, value)
+		}
+		return value, nil
+	}
+	atomic.AddUint64(&s.stats.misses, 1)
+	return nil, err
+}
+
+// Set will store a byte slice under a given key in every tier.
+func (s *Store) Set(key string, value []byte) error {
+	return s.each(func(tier veldt.Store) error {
+		return tier.Set(key, value)
+	})
+}
+
+// SetEncoded will store a byte slice under a given key in every tier, along
+// with the name of the codec it is compressed with in the tiers that record
+// it.
+func (s *Store) SetEncoded(key string, value []byte, encoding string) error {
+	return s.each(func(tier veldt.Store) error {
+		encoded, ok := tier.(veldt.EncodedStore)
+		if ok {
+			return encoded.SetEncoded(key, value, encoding)
+		}
+		return tier.Set(key, value)
+	})
+}
+
+// Exists returns whether or not a key exists in any tier.
+func (s *Store) Exists(key string) (bool, error) {
+	var err error
+	for _, tier := range s.tiers {
+		var exists bool
+		exists, err = tier.Exists(key)
+		if err == nil && exists {
+			return true, nil
+		}
+	}
+	return false, err
+}
+
+// Delete removes the value under the key from every tier.
+func (s *Store) Delete(key string) error {
+	return s.eachInvalidating(func(tier veldt.InvalidatingStore) error {
+		return tier.Delete(key)
+	})
+}
+
+// DeleteByPrefix removes all values under keys beginning with the prefix from
+// every tier.
+func (s *Store) DeleteByPrefix(prefix string) error {
+	return s.eachInvalidating(func(tier veldt.InvalidatingStore) error {
+		return tier.DeleteByPrefix(prefix)
+	})
+}
+
+// SetExpiry will store a byte slice under a given key in every tier, expiring
+// it after the provided number of seconds.
+func (s *Store) SetExpiry(key string, value []byte, expirySeconds int) error {
+	return s.eachInvalidating(func(tier veldt.InvalidatingStore) error {
+		return tier.SetExpiry(key, value, expirySeconds)
+	})
+}
+
+// MGet when given string keys will return the byte slices of data from the
+// fastest tier containing each. The value of any key that does not exist in
+// any tier is nil.
+func (s *Store) MGet(keys []string) ([][]byte, error) {
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		value, err := s.Get(key)
+		if err == nil {
+			values[i] = value
+		}
+	}
+	return values, nil
+}
+
+// MSet will store each byte slice under its key in every tier.
+func (s *Store) MSet(values map[string][]byte) error {
+	return s.eachInvalidating(func(tier veldt.InvalidatingStore) error {
+		return tier.MSet(values)
+	})
+}
+
+// Close closes the connection of every tier.
+func (s *Store) Close() {
+	for _, tier := range s.tiers {
+		tier.Close()
+	}
+}
+
+// each applies the function to every tier, returning the first error.
+func (s *Store) each(fn func(veldt.Store) error) error {
+	var first error
+	for _, tier := range s.tiers {
+		err := fn(tier)
+		if err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// eachInvalidating applies the function to every tier, returning an error if
+// any tier does not implement the veldt.InvalidatingStore interface.
+func (s *Store) eachInvalidating(fn func(veldt.InvalidatingStore) error) error {
+	for i, tier := range s.tiers {
+		_, ok := tier.(veldt.InvalidatingStore)
+		if !ok {
+			return fmt.Errorf("tier %d does not support invalidation", i)
+		}
+	}
+	return s.each(func(tier veldt.Store) error {
+		return fn(tier.(veldt.InvalidatingStore))
+	})
+}
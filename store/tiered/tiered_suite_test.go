@@ -0,0 +1,13 @@
+package tiered_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestTiered(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Tiered Suite")
+}
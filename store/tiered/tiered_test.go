@@ -0,0 +1,172 @@
+package tiered_test
+
+import (
+	"fmt"
+
+	"github.com/unchartedsoftware/veldt"
+	"github.com/unchartedsoftware/veldt/store/tiered"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+// value returns the value of the key in the tier, or nil if it does not exist.
+func value(tier *MapStore, key string) []byte {
+	val, _ := tier.Get(key)
+	return val
+}
+
+// basicStore hides the invalidation methods of the wrapped store.
+type basicStore struct {
+	store *MapStore
+}
+
+func (s *basicStore) Get(key string) ([]byte, error) {
+	return s.store.Get(key)
+}
+
+func (s *basicStore) Set(key string, val []byte) error {
+	return s.store.Set(key, val)
+}
+
+func (s *basicStore) Exists(key string) (bool, error) {
+	return s.store.Exists(key)
+}
+
+func (s *basicStore) Close() {
+	s.store.Close()
+}
+
+var _ = Describe("Store", func() {
+
+	var l1 *MapStore
+	var l2 *MapStore
+	var store *tiered.Store
+
+	BeforeEach(func() {
+		l1 = NewMapStore()
+		l2 = NewMapStore()
+		conn, err := tiered.NewStore(l1.Ctor(), l2.Ctor())()
+		Expect(err).To(BeNil())
+		store = conn.(*tiered.Store)
+	})
+
+	Describe("NewStore", func() {
+		It("should return an error if no tiers are provided", func() {
+			_, err := tiered.NewStore()()
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should close opened tiers if a tier fails to connect", func() {
+			_, err := tiered.NewStore(l1.Ctor(), func() (veldt.Store, error) {
+				return nil, fmt.Errorf("unavailable")
+			})()
+			Expect(err).NotTo(BeNil())
+			Expect(l1.Closed()).To(BeTrue())
+		})
+	})
+
+	Describe("Get", func() {
+		It("should return the value from the first tier", func() {
+			l1.Set("a", []byte("one"))
+			l2.Set("a", []byte("two"))
+			val, err := store.Get("a")
+			Expect(err).To(BeNil())
+			Expect(val).To(Equal([]byte("one")))
+			Expect(store.Stats().Hits()).To(Equal([]uint64{1, 0}))
+		})
+
+		It("should promote values found in slower tiers", func() {
+			l2.Set("a", []byte("two"))
+			val, err := store.Get("a")
+			Expect(err).To(BeNil())
+			Expect(val).To(Equal([]byte("two")))
+			Expect(value(l1, "a")).To(Equal([]byte("two")))
+			Expect(store.Stats().Hits()).To(Equal([]uint64{0, 1}))
+			_, err = store.Get("a")
+			Expect(err).To(BeNil())
+			Expect(store.Stats().Hits()).To(Equal([]uint64{1, 1}))
+		})
+
+		It("should return an error and count a miss if no tier has the key", func() {
+			_, err := store.Get("a")
+			Expect(err).NotTo(BeNil())
+			Expect(store.Stats().Misses()).To(Equal(uint64(1)))
+		})
+
+		It("should share stats across connections", func() {
+			ctor := tiered.NewStore(l1.Ctor(), l2.Ctor())
+			a, _ := ctor()
+			b, _ := ctor()
+			l2.Set("a", []byte("two"))
+			a.Get("a")
+			b.Get("a")
+			Expect(b.(*tiered.Store).Stats().Hits()).To(Equal([]uint64{1, 1}))
+		})
+	})
+
+	Describe("Set", func() {
+		It("should write through to every tier", func() {
+			err := store.Set("a", []byte("one"))
+			Expect(err).To(BeNil())
+			Expect(value(l1, "a")).To(Equal([]byte("one")))
+			Expect(value(l2, "a")).To(Equal([]byte("one")))
+		})
+	})
+
+	Describe("Exists", func() {
+		It("should return true if any tier has the key", func() {
+			l2.Set("a", []byte("two"))
+			exists, err := store.Exists("a")
+			Expect(err).To(BeNil())
+			Expect(exists).To(BeTrue())
+			exists, err = store.Exists("b")
+			Expect(err).To(BeNil())
+			Expect(exists).To(BeFalse())
+		})
+	})
+
+	Describe("Invalidation", func() {
+		It("should delete from every tier", func() {
+			store.MSet(map[string][]byte{
+				"uri:a": []byte("a"),
+				"uri:b": []byte("b"),
+				"other": []byte("c"),
+			})
+			err := store.DeleteByPrefix("uri:")
+			Expect(err).To(BeNil())
+			Expect(l1.Len()).To(Equal(1))
+			Expect(l2.Len()).To(Equal(1))
+			err = store.Delete("other")
+			Expect(err).To(BeNil())
+			Expect(l1.Len()).To(Equal(0))
+			Expect(l2.Len()).To(Equal(0))
+		})
+
+		It("should return the values of multiple keys", func() {
+			l1.Set("a", []byte("one"))
+			l2.Set("b", []byte("two"))
+			vals, err := store.MGet([]string{"a", "b", "c"})
+			Expect(err).To(BeNil())
+			Expect(vals).To(Equal([][]byte{[]byte("one"), []byte("two"), nil}))
+		})
+
+		It("should return an error if a tier does not support invalidation", func() {
+			conn, err := tiered.NewStore(l1.Ctor(), func() (veldt.Store, error) {
+				return &basicStore{store: l2}, nil
+			})()
+			Expect(err).To(BeNil())
+			err = conn.(veldt.InvalidatingStore).Delete("a")
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("Close", func() {
+		It("should close every tier", func() {
+			store.Close()
+			Expect(l1.Closed()).To(BeTrue())
+			Expect(l2.Closed()).To(BeTrue())
+		})
+	})
+})
@@ -1,6 +1,8 @@
 package veldt
 
 import (
+	"context"
+
 	"github.com/unchartedsoftware/veldt/binning"
 )
 
@@ -18,6 +20,20 @@ type Tile interface {
 	Create(string, *binning.TileCoord, Query) ([]byte, error)
 }
 
+// ContextTile represents an interface for generating tile data that can be
+// cancelled through a context.
+type ContextTile interface {
+	Tile
+	// CreateContext creates a tile, abandoning the work if the context is
+	// done.
+	// parameter 1 (context.Context): the context of the request
+	// parameter 2 (string): A dataset ID (typically called uri)
+	// parameter 3 (*binning.TileCoord): the coordinates of the requested tile
+	// parameter 4 (Query): A query to specify which data should be included in the
+	//             tile - essentially a filter
+	CreateContext(context.Context, string, *binning.TileCoord, Query) ([]byte, error)
+}
+
 // TileCtor represents a function that instantiates and returns a new tile
 // data type.
 type TileCtor func() (Tile, error)
@@ -6,6 +6,7 @@ import (
 
 	"github.com/unchartedsoftware/veldt/binning"
 	"github.com/unchartedsoftware/veldt/geometry"
+	"github.com/unchartedsoftware/veldt/schema"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
@@ -46,6 +47,15 @@ func (b *Bivariate) Parse(params map[string]interface{}) error {
 	return b.globalBounds.Parse(params)
 }
 
+// Schema returns the JSON Schema of the parameters of the tile.
+func (b *Bivariate) Schema() *schema.Schema {
+	return schema.Merge(schema.Object(map[string]*schema.Schema{
+		"xField":     schema.String("The field of the x value of each point."),
+		"yField":     schema.String("The field of the y value of each point."),
+		"resolution": schema.Integer("The number of bins along each axis of the tile.").WithMinimum(1).WithDefault(256),
+	}, "xField", "yField"), (&geometry.Bounds{}).Schema())
+}
+
 // TileBounds computes and returns the tile bounds for the provided tile coord.
 func (b *Bivariate) TileBounds(coord *binning.TileCoord) *geometry.Bounds {
 	if b.tileBounds == nil {
@@ -54,6 +64,11 @@ func (b *Bivariate) TileBounds(coord *binning.TileCoord) *geometry.Bounds {
 	return b.tileBounds
 }
 
+// GlobalBounds returns the global bounds of the tile pyramid.
+func (b *Bivariate) GlobalBounds() *geometry.Bounds {
+	return b.globalBounds
+}
+
 // BinSizeX computes and returns the size of a bin across the x axis for the
 // provided tile coord.
 func (b *Bivariate) BinSizeX(coord *binning.TileCoord) float64 {
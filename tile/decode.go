@@ -30,7 +30,7 @@ func DecodeImage(ext string, reader io.Reader) ([]byte, error) {
 // Decode takes a io.Reader and decodes the data based on the provided
 // extension.
 func Decode(ext string, reader io.Reader) ([]byte, error) {
-	if isImage(ext) {
+	if IsImage(ext) {
 		return DecodeImage(ext, reader)
 	}
 	// return result directly
@@ -44,6 +44,8 @@ func Decode(ext string, reader io.Reader) ([]byte, error) {
 	return bytes, nil
 }
 
-func isImage(ext string) bool {
+// IsImage returns whether the extension is that of an image format decoded by
+// Decode.
+func IsImage(ext string) bool {
 	return ext == "png" || ext == "jpg" || ext == "jpeg"
 }
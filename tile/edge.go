@@ -6,6 +6,7 @@ import (
 
 	"github.com/unchartedsoftware/veldt/binning"
 	"github.com/unchartedsoftware/veldt/geometry"
+	"github.com/unchartedsoftware/veldt/schema"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
@@ -74,6 +75,19 @@ func (e *Edge) Parse(params map[string]interface{}) error {
 	return e.globalBounds.Parse(params)
 }
 
+// Schema returns the JSON Schema of the parameters of the tile.
+func (e *Edge) Schema() *schema.Schema {
+	return schema.Merge(schema.Object(map[string]*schema.Schema{
+		"srcXField":   schema.String("The field of the x value of the source of each edge."),
+		"srcYField":   schema.String("The field of the y value of the source of each edge."),
+		"dstXField":   schema.String("The field of the x value of the destination of each edge."),
+		"dstYField":   schema.String("The field of the y value of the destination of each edge."),
+		"requireSrc":  schema.Boolean("Whether the source of each edge must be within the tile.").WithDefault(true),
+		"requireDst":  schema.Boolean("Whether the destination of each edge must be within the tile.").WithDefault(false),
+		"weightField": schema.String("The field of the weight of each edge."),
+	}, "srcXField", "srcYField", "dstXField", "dstYField", "weightField"), (&geometry.Bounds{}).Schema())
+}
+
 // TileBounds computes and returns the tile bounds for the provided tile coord.
 func (e *Edge) TileBounds(coord *binning.TileCoord) *geometry.Bounds {
 	if e.tileBounds == nil {
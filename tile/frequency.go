@@ -3,6 +3,7 @@ package tile
 import (
 	"fmt"
 
+	"github.com/unchartedsoftware/veldt/schema"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
@@ -47,3 +48,15 @@ func (t *Frequency) Parse(params map[string]interface{}) error {
 	t.Interval = interval
 	return nil
 }
+
+// Schema returns the JSON Schema of the parameters of the tile.
+func (t *Frequency) Schema() *schema.Schema {
+	return schema.Object(map[string]*schema.Schema{
+		"frequencyField": schema.String("The field of the time of each document."),
+		"gte":            schema.Any("The inclusive lower bound of the range."),
+		"gt":             schema.Any("The exclusive lower bound of the range."),
+		"lte":            schema.Any("The inclusive upper bound of the range."),
+		"lt":             schema.Any("The exclusive upper bound of the range."),
+		"interval":       schema.String("The interval of each bucket, such as `1d`."),
+	}, "frequencyField", "interval")
+}
@@ -1,24 +1,42 @@
 package tile
 
 import (
+	"github.com/unchartedsoftware/veldt/schema"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
 // Macro represents a tile which returns a point for any bin that contains a
 // data point.
 type Macro struct {
-	LOD int
+	LOD      int
+	Encoding string
 }
 
 // Parse parses the provided JSON object and populates the structs attributes.
 func (m *Macro) Parse(params map[string]interface{}) error {
 	// parse LOD
 	m.LOD = json.GetIntDefault(params, 0, "lod")
+	// parse encoding
+	encoding, err := parseEncoding(params, m.LOD)
+	if err != nil {
+		return err
+	}
+	m.Encoding = encoding
 	return nil
 }
 
-// Encode will encode the tile results based on the LOD property.
+// Schema returns the JSON Schema of the parameters of the tile.
+func (m *Macro) Schema() *schema.Schema {
+	return getEncodingSchema()
+}
+
+// Encode will encode the tile results based on the LOD and encoding
+// properties.
 func (m *Macro) Encode(points []float32) ([]byte, error) {
+	// encode as MVT
+	if m.Encoding == MVTEncoding {
+		return EncodeMVTPoints("points", points, nil), nil
+	}
 	// encode the results
 	if m.LOD > 0 {
 		return EncodeLOD(points, m.LOD), nil
@@ -1,22 +1,35 @@
 package tile
 
 import (
+	"github.com/unchartedsoftware/veldt/schema"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
 // MacroEdge represents a tile that returns individual data edges with optional
 // included attributes.
 type MacroEdge struct {
-	LOD int
+	LOD      int
+	Encoding string
 }
 
 // Parse parses the provided JSON object and populates the structs attributes.
 func (e *MacroEdge) Parse(params map[string]interface{}) error {
 	// parse LOD
 	e.LOD = json.GetIntDefault(params, 0, "lod")
+	// parse encoding
+	encoding, err := parseEncoding(params, e.LOD)
+	if err != nil {
+		return err
+	}
+	e.Encoding = encoding
 	return nil
 }
 
+// Schema returns the JSON Schema of the parameters of the tile.
+func (e *MacroEdge) Schema() *schema.Schema {
+	return getEncodingSchema()
+}
+
 // ParseIncludes parses the included attributes to ensure they include the raw
 // data coordinates.
 func (e *MacroEdge) ParseIncludes(includes []string, srcXField string, srcYField string, dstXField string, dstYField string, weightField string) []string {
@@ -41,8 +54,13 @@ func (e *MacroEdge) ParseIncludes(includes []string, srcXField string, srcYField
 	return includes
 }
 
-// Encode will encode the tile results
+// Encode will encode the tile results based on the LOD and encoding
+// properties.
 func (e *MacroEdge) Encode(edges []float32) ([]byte, error) {
+	// encode as MVT
+	if e.Encoding == MVTEncoding {
+		return EncodeMVTEdges("edges", edges, nil), nil
+	}
 	// encode the results
 	if e.LOD > 0 {
 		return EncodeEdgeLOD(edges, e.LOD), nil
@@ -3,6 +3,7 @@ package tile
 import (
 	"sort"
 
+	"github.com/unchartedsoftware/veldt/schema"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
@@ -10,6 +11,7 @@ import (
 // included attributes.
 type Micro struct {
 	LOD       int
+	Encoding  string
 	xField    string
 	yField    string
 	xIncluded bool
@@ -20,9 +22,20 @@ type Micro struct {
 func (m *Micro) Parse(params map[string]interface{}) error {
 	// parse LOD
 	m.LOD = json.GetIntDefault(params, 0, "lod")
+	// parse encoding
+	encoding, err := parseEncoding(params, m.LOD)
+	if err != nil {
+		return err
+	}
+	m.Encoding = encoding
 	return nil
 }
 
+// Schema returns the JSON Schema of the parameters of the tile.
+func (m *Micro) Schema() *schema.Schema {
+	return getEncodingSchema()
+}
+
 // ParseIncludes parses the included attributes to ensure they include the raw
 // data coordinates.
 func (m *Micro) ParseIncludes(includes []string, xField string, yField string) []string {
@@ -43,7 +56,8 @@ func (m *Micro) ParseIncludes(includes []string, xField string, yField string) [
 	return includes
 }
 
-// Encode will encode the tile results based on the LOD property.
+// Encode will encode the tile results based on the LOD and encoding
+// properties.
 func (m *Micro) Encode(hits []map[string]interface{}, points []float32) ([]byte, error) {
 	emptyHits := true
 	// remove any non-included fields from hits
@@ -68,6 +82,11 @@ func (m *Micro) Encode(hits []map[string]interface{}, points []float32) ([]byte,
 		hits = nil
 	}
 
+	// encode as MVT
+	if m.Encoding == MVTEncoding {
+		return EncodeMVTPoints("points", points, hits), nil
+	}
+
 	// encode using LOD
 	if m.LOD > 0 {
 		// NOTE: during LOD points are sorted by morton code, therefore we sort
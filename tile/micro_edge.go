@@ -1,13 +1,15 @@
 package tile
 
 import (
+	"github.com/unchartedsoftware/veldt/schema"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
 // MicroEdge represents a tile that returns individual data edges with optional
 // included attributes.
 type MicroEdge struct {
-	LOD int
+	LOD      int
+	Encoding string
 	// src
 	srcXField    string
 	srcYField    string
@@ -24,9 +26,20 @@ type MicroEdge struct {
 func (e *MicroEdge) Parse(params map[string]interface{}) error {
 	// parse LOD
 	e.LOD = json.GetIntDefault(params, 0, "lod")
+	// parse encoding
+	encoding, err := parseEncoding(params, e.LOD)
+	if err != nil {
+		return err
+	}
+	e.Encoding = encoding
 	return nil
 }
 
+// Schema returns the JSON Schema of the parameters of the tile.
+func (e *MicroEdge) Schema() *schema.Schema {
+	return getEncodingSchema()
+}
+
 // ParseIncludes parses the included attributes to ensure they include the raw
 // data coordinates.
 func (e *MicroEdge) ParseIncludes(includes []string, srcXField string, srcYField string, dstXField string, dstYField string) []string {
@@ -60,7 +73,9 @@ func (e *MicroEdge) ParseIncludes(includes []string, srcXField string, srcYField
 	return includes
 }
 
-// Encode will encode the tile results
+// Encode will encode the tile results based on the LOD and encoding
+// properties. When encoding as MVT, the points must use the same
+// `srcX, srcY, weight, dstX, dstY, weight` layout as the macro edge tile.
 func (e *MicroEdge) Encode(hits []map[string]interface{}, points []float32) ([]byte, error) {
 	emptyHits := true
 	// remove any non-included fields from hits
@@ -92,6 +107,11 @@ func (e *MicroEdge) Encode(hits []map[string]interface{}, points []float32) ([]b
 		hits = nil
 	}
 
+	// encode as MVT
+	if e.Encoding == MVTEncoding {
+		return EncodeMVTEdges("edges", points, hits), nil
+	}
+
 	// encode using LOD
 	if e.LOD > 0 {
 		// NOTE: during LOD points are sorted by morton code, therefore we sort
@@ -0,0 +1,296 @@
+package tile
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/schema"
+	"github.com/unchartedsoftware/veldt/util/json"
+)
+
+const (
+	// MVTExtent represents the extent of the MVT tile coordinate space.
+	MVTExtent = 4096
+	// MVTEncoding represents the `encoding` tile option value selecting
+	// Mapbox Vector Tile output.
+	MVTEncoding = "mvt"
+
+	mvtVersion = 2
+	// geometry types
+	mvtPoint      = 1
+	mvtLineString = 2
+	// geometry commands
+	mvtMoveTo = 1
+	mvtLineTo = 2
+	// protobuf wire types
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+)
+
+// parseEncoding parses the optional `encoding` tile option. An empty string
+// represents the native encoding of the tile.
+func parseEncoding(params map[string]interface{}, lod int) (string, error) {
+	encoding := json.GetStringDefault(params, "", "encoding")
+	if encoding != "" && encoding != MVTEncoding {
+		return "", fmt.Errorf("`encoding` must be `%s` if specified", MVTEncoding)
+	}
+	if encoding == MVTEncoding && lod > 0 {
+		return "", fmt.Errorf("`lod` is not supported with `%s` encoding", MVTEncoding)
+	}
+	return encoding, nil
+}
+
+// getEncodingSchema returns the JSON Schema of the `lod` and `encoding` tile
+// options.
+func getEncodingSchema() *schema.Schema {
+	return schema.Object(map[string]*schema.Schema{
+		"lod":      schema.Integer("The level of detail of the point offsets, or 0 to disable them.").WithMinimum(0).WithDefault(0),
+		"encoding": schema.String("The encoding of the tile, if not its native encoding.").WithEnum(MVTEncoding),
+	})
+}
+
+// EncodeMVTPoints takes a []float32 of x / y tile coordinates and the
+// optional hits of each point, and returns a Mapbox Vector Tile containing a
+// single layer of point features. Tile coordinates are in the range
+// [0 : 256) with the origin at the bottom-left and are scaled to the MVT
+// extent with the origin at the top-left. Hit attributes become feature
+// properties, with nested attributes flattened using dot notation.
+func EncodeMVTPoints(name string, points []float32, hits []map[string]interface{}) []byte {
+	layer := newMVTLayer(name)
+	numPoints := len(points) / 2
+	for i := 0; i < numPoints; i++ {
+		x, y := toMVT(points[i*2], points[i*2+1])
+		geometry := []uint32{
+			mvtCommand(mvtMoveTo, 1),
+			zigzag(x),
+			zigzag(y),
+		}
+		layer.addFeature(mvtPoint, geometry, getHit(hits, i), nil)
+	}
+	return layer.encode()
+}
+
+// EncodeMVTEdges takes a []float32 of edges, each represented by six values
+// `srcX, srcY, weight, dstX, dstY, weight` in tile coordinates, and the
+// optional hits of each edge, and returns a Mapbox Vector Tile containing a
+// single layer of line string features. The edge weight is included as the
+// `weight` property of each feature.
+func EncodeMVTEdges(name string, edges []float32, hits []map[string]interface{}) []byte {
+	layer := newMVTLayer(name)
+	numEdges := len(edges) / 6
+	for i := 0; i < numEdges; i++ {
+		srcX, srcY := toMVT(edges[i*6], edges[i*6+1])
+		dstX, dstY := toMVT(edges[i*6+3], edges[i*6+4])
+		geometry := []uint32{
+			mvtCommand(mvtMoveTo, 1),
+			zigzag(srcX),
+			zigzag(srcY),
+			mvtCommand(mvtLineTo, 1),
+			zigzag(dstX - srcX),
+			zigzag(dstY - srcY),
+		}
+		weight := map[string]interface{}{
+			"weight": float64(edges[i*6+2]),
+		}
+		layer.addFeature(mvtLineString, geometry, getHit(hits, i), weight)
+	}
+	return layer.encode()
+}
+
+func getHit(hits []map[string]interface{}, index int) map[string]interface{} {
+	if index < len(hits) {
+		return hits[index]
+	}
+	return nil
+}
+
+func toMVT(x float32, y float32) (int64, int64) {
+	scale := MVTExtent / binning.MaxTileResolution
+	// flip y, as the MVT origin is the top-left
+	return int64(math.Floor(float64(x)*scale + 0.5)),
+		int64(math.Floor((binning.MaxTileResolution-float64(y))*scale + 0.5))
+}
+
+func mvtCommand(id uint32, count uint32) uint32 {
+	return (id & 0x7) | (count << 3)
+}
+
+func zigzag(n int64) uint32 {
+	return uint32((n << 1) ^ (n >> 63))
+}
+
+// mvtLayer accumulates the features of a single MVT layer, de-duplicating the
+// property keys and values.
+type mvtLayer struct {
+	name     string
+	features [][]byte
+	keys     []string
+	keyIndex map[string]int
+	values   [][]byte
+	valIndex map[string]int
+}
+
+func newMVTLayer(name string) *mvtLayer {
+	return &mvtLayer{
+		name:     name,
+		keyIndex: make(map[string]int),
+		valIndex: make(map[string]int),
+	}
+}
+
+func (l *mvtLayer) addFeature(typ uint64, geometry []uint32, props ...map[string]interface{}) {
+	// flatten properties
+	flat := make(map[string]interface{})
+	for _, p := range props {
+		flattenProperties(flat, p, "")
+	}
+	// sort keys for a deterministic encoding
+	keys := make([]string, 0, len(flat))
+	for key := range flat {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	tags := make([]uint32, 0, len(keys)*2)
+	for _, key := range keys {
+		val, ok := encodeMVTValue(flat[key])
+		if !ok {
+			continue
+		}
+		tags = append(tags, uint32(l.getKey(key)), uint32(l.getValue(val)))
+	}
+	var feature []byte
+	if len(tags) > 0 {
+		feature = appendPacked(feature, 2, tags)
+	}
+	feature = appendVarintField(feature, 3, typ)
+	feature = appendPacked(feature, 4, geometry)
+	l.features = append(l.features, feature)
+}
+
+func (l *mvtLayer) getKey(key string) int {
+	index, ok := l.keyIndex[key]
+	if !ok {
+		index = len(l.keys)
+		l.keys = append(l.keys, key)
+		l.keyIndex[key] = index
+	}
+	return index
+}
+
+func (l *mvtLayer) getValue(val []byte) int {
+	index, ok := l.valIndex[string(val)]
+	if !ok {
+		index = len(l.values)
+		l.values = append(l.values, val)
+		l.valIndex[string(val)] = index
+	}
+	return index
+}
+
+func (l *mvtLayer) encode() []byte {
+	var layer []byte
+	layer = appendBytesField(layer, 1, []byte(l.name))
+	for _, feature := range l.features {
+		layer = appendBytesField(layer, 2, feature)
+	}
+	for _, key := range l.keys {
+		layer = appendBytesField(layer, 3, []byte(key))
+	}
+	for _, val := range l.values {
+		layer = appendBytesField(layer, 4, val)
+	}
+	layer = appendVarintField(layer, 5, MVTExtent)
+	layer = appendVarintField(layer, 15, mvtVersion)
+	// wrap in tile
+	return appendBytesField(nil, 3, layer)
+}
+
+// flattenProperties flattens nested attributes into dot notation keys.
+func flattenProperties(flat map[string]interface{}, props map[string]interface{}, path string) {
+	for key, val := range props {
+		subpath := key
+		if path != "" {
+			subpath = path + "." + key
+		}
+		child, ok := val.(map[string]interface{})
+		if ok {
+			flattenProperties(flat, child, subpath)
+			continue
+		}
+		flat[subpath] = val
+	}
+}
+
+// encodeMVTValue encodes a property as an MVT value message. Arrays are
+// encoded as JSON strings.
+func encodeMVTValue(val interface{}) ([]byte, bool) {
+	switch v := val.(type) {
+	case string:
+		return appendBytesField(nil, 1, []byte(v)), true
+	case bool:
+		b := uint64(0)
+		if v {
+			b = 1
+		}
+		return appendVarintField(nil, 7, b), true
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			// sint value
+			n := int64(v)
+			return appendVarintField(nil, 6, uint64((n<<1)^(n>>63))), true
+		}
+		// double value
+		buf := appendTag(nil, 3, wireFixed64)
+		bits := make([]byte, 8)
+		binary.LittleEndian.PutUint64(bits, math.Float64bits(v))
+		return append(buf, bits...), true
+	case int:
+		return encodeMVTValue(float64(v))
+	case int64:
+		return encodeMVTValue(float64(v))
+	case float32:
+		return encodeMVTValue(float64(v))
+	case nil:
+		return nil, false
+	}
+	str, err := json.Marshal(val)
+	if err != nil {
+		return nil, false
+	}
+	return appendBytesField(nil, 1, str), true
+}
+
+func appendTag(buf []byte, field uint64, wire uint64) []byte {
+	return appendVarint(buf, field<<3|wire)
+}
+
+func appendVarint(buf []byte, n uint64) []byte {
+	for n >= 0x80 {
+		buf = append(buf, byte(n)|0x80)
+		n >>= 7
+	}
+	return append(buf, byte(n))
+}
+
+func appendVarintField(buf []byte, field uint64, n uint64) []byte {
+	buf = appendTag(buf, field, wireVarint)
+	return appendVarint(buf, n)
+}
+
+func appendBytesField(buf []byte, field uint64, bytes []byte) []byte {
+	buf = appendTag(buf, field, wireBytes)
+	buf = appendVarint(buf, uint64(len(bytes)))
+	return append(buf, bytes...)
+}
+
+func appendPacked(buf []byte, field uint64, vals []uint32) []byte {
+	var packed []byte
+	for _, val := range vals {
+		packed = appendVarint(packed, uint64(val))
+	}
+	return appendBytesField(buf, field, packed)
+}
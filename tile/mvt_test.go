@@ -0,0 +1,170 @@
+package tile_test
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/unchartedsoftware/veldt/tile"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/unchartedsoftware/veldt/util/test"
+)
+
+type field struct {
+	num   uint64
+	value uint64
+	bytes []byte
+}
+
+// readFields decodes the top level fields of a protobuf message.
+func readFields(buf []byte) []field {
+	var fields []field
+	for len(buf) > 0 {
+		tag, n := binary.Uvarint(buf)
+		Expect(n > 0).To(BeTrue())
+		buf = buf[n:]
+		f := field{num: tag >> 3}
+		switch tag & 0x7 {
+		case 0:
+			f.value, n = binary.Uvarint(buf)
+			buf = buf[n:]
+		case 1:
+			f.value = binary.LittleEndian.Uint64(buf[:8])
+			buf = buf[8:]
+		case 2:
+			length, n := binary.Uvarint(buf)
+			buf = buf[n:]
+			f.bytes = buf[:length]
+			buf = buf[length:]
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+func readPacked(buf []byte) []uint64 {
+	var vals []uint64
+	for len(buf) > 0 {
+		val, n := binary.Uvarint(buf)
+		vals = append(vals, val)
+		buf = buf[n:]
+	}
+	return vals
+}
+
+func getFields(fields []field, num uint64) []field {
+	var res []field
+	for _, f := range fields {
+		if f.num == num {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+func readLayer(bytes []byte) []field {
+	layers := getFields(readFields(bytes), 3)
+	Expect(len(layers)).To(Equal(1))
+	return readFields(layers[0].bytes)
+}
+
+var _ = Describe("MVT", func() {
+
+	Describe("EncodeMVTPoints", func() {
+		It("should encode a layer of point features", func() {
+			bytes := tile.EncodeMVTPoints("points", []float32{
+				64, 64,
+				128, 0,
+			}, nil)
+			layer := readLayer(bytes)
+			Expect(string(getFields(layer, 1)[0].bytes)).To(Equal("points"))
+			Expect(getFields(layer, 5)[0].value).To(Equal(uint64(tile.MVTExtent)))
+			Expect(getFields(layer, 15)[0].value).To(Equal(uint64(2)))
+			features := getFields(layer, 2)
+			Expect(len(features)).To(Equal(2))
+			// first point, y is flipped
+			feature := readFields(features[0].bytes)
+			Expect(getFields(feature, 3)[0].value).To(Equal(uint64(1)))
+			Expect(readPacked(getFields(feature, 4)[0].bytes)).To(Equal([]uint64{
+				9, 2048, 6144,
+			}))
+			// second point
+			feature = readFields(features[1].bytes)
+			Expect(readPacked(getFields(feature, 4)[0].bytes)).To(Equal([]uint64{
+				9, 4096, 8192,
+			}))
+		})
+
+		It("should encode hit attributes as properties", func() {
+			bytes := tile.EncodeMVTPoints("points", []float32{
+				0, 0,
+				0, 0,
+			}, []map[string]interface{}{
+				{"name": "a", "meta": map[string]interface{}{"count": 3.0}},
+				{"name": "a", "score": 0.5},
+			})
+			layer := readLayer(bytes)
+			keys := getFields(layer, 3)
+			Expect(len(keys)).To(Equal(3))
+			Expect(string(keys[0].bytes)).To(Equal("meta.count"))
+			Expect(string(keys[1].bytes)).To(Equal("name"))
+			Expect(string(keys[2].bytes)).To(Equal("score"))
+			values := getFields(layer, 4)
+			// shared values are de-duplicated
+			Expect(len(values)).To(Equal(3))
+			// sint value
+			Expect(readFields(values[0].bytes)[0]).To(Equal(field{num: 6, value: 6}))
+			// string value
+			Expect(string(readFields(values[1].bytes)[0].bytes)).To(Equal("a"))
+			// double value
+			Expect(readFields(values[2].bytes)[0]).To(Equal(field{num: 3, value: math.Float64bits(0.5)}))
+			features := getFields(layer, 2)
+			tags := readPacked(getFields(readFields(features[0].bytes), 2)[0].bytes)
+			Expect(tags).To(Equal([]uint64{0, 0, 1, 1}))
+			tags = readPacked(getFields(readFields(features[1].bytes), 2)[0].bytes)
+			Expect(tags).To(Equal([]uint64{1, 1, 2, 2}))
+		})
+	})
+
+	Describe("EncodeMVTEdges", func() {
+		It("should encode a layer of line string features", func() {
+			bytes := tile.EncodeMVTEdges("edges", []float32{
+				0, 256, 2, 64, 192, 2,
+			}, nil)
+			layer := readLayer(bytes)
+			features := getFields(layer, 2)
+			Expect(len(features)).To(Equal(1))
+			feature := readFields(features[0].bytes)
+			Expect(getFields(feature, 3)[0].value).To(Equal(uint64(2)))
+			Expect(readPacked(getFields(feature, 4)[0].bytes)).To(Equal([]uint64{
+				9, 0, 0, 10, 2048, 2048,
+			}))
+			Expect(string(getFields(layer, 3)[0].bytes)).To(Equal("weight"))
+		})
+	})
+
+	Describe("Encoding", func() {
+		It("should encode macro tiles as MVT", func() {
+			macro := &tile.Macro{}
+			err := macro.Parse(JSON(`{"encoding": "mvt"}`))
+			Expect(err).To(BeNil())
+			Expect(macro.Encoding).To(Equal(tile.MVTEncoding))
+			bytes, err := macro.Encode([]float32{64, 64})
+			Expect(err).To(BeNil())
+			Expect(bytes).To(Equal(tile.EncodeMVTPoints("points", []float32{64, 64}, nil)))
+		})
+
+		It("should return an error for an unrecognized encoding", func() {
+			macro := &tile.Macro{}
+			err := macro.Parse(JSON(`{"encoding": "xml"}`))
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should return an error if `lod` is combined with MVT", func() {
+			micro := &tile.Micro{}
+			err := micro.Parse(JSON(`{"encoding": "mvt", "lod": 4}`))
+			Expect(err).NotTo(BeNil())
+		})
+	})
+})
@@ -3,6 +3,7 @@ package tile
 import (
 	"fmt"
 
+	"github.com/unchartedsoftware/veldt/schema"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
@@ -27,3 +28,16 @@ func (t *TargetTerms) Parse(params map[string]interface{}) error {
 	t.Terms = terms
 	return nil
 }
+
+// Schema returns the JSON Schema of the parameters of the tile.
+func (t *TargetTerms) Schema() *schema.Schema {
+	return schema.Object(map[string]*schema.Schema{
+		"termsField": schema.String("The field of the terms of each document."),
+		"terms":      schema.Array(schema.String(""), "The terms to count.").WithMinItems(1),
+	}, "termsField", "terms")
+}
+
+// Normalize sorts the `terms` parameter, as its order is insignificant.
+func (t *TargetTerms) Normalize(params map[string]interface{}) {
+	json.SortArray(params, "terms")
+}
@@ -3,6 +3,7 @@ package tile
 import (
 	"fmt"
 
+	"github.com/unchartedsoftware/veldt/schema"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
@@ -36,3 +37,19 @@ func (t *TopHits) Parse(params map[string]interface{}) error {
 	t.IncludeFields = includeFields
 	return nil
 }
+
+// Schema returns the JSON Schema of the parameters of the tile.
+func (t *TopHits) Schema() *schema.Schema {
+	return schema.Object(map[string]*schema.Schema{
+		"sortField":     schema.String("The field the hits are sorted by."),
+		"sortOrder":     schema.String("The order the hits are sorted in.").WithEnum("desc", "asc").WithDefault("desc"),
+		"hitsCount":     schema.Integer("The maximum number of hits.").WithMinimum(0),
+		"includeFields": schema.Array(schema.String(""), "The fields included in each hit, or all fields if empty."),
+	}, "hitsCount")
+}
+
+// Normalize sorts the `includeFields` parameter, as its order is
+// insignificant.
+func (t *TopHits) Normalize(params map[string]interface{}) {
+	json.SortArray(params, "includeFields")
+}
@@ -3,6 +3,7 @@ package tile
 import (
 	"fmt"
 
+	"github.com/unchartedsoftware/veldt/schema"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
@@ -27,3 +28,11 @@ func (t *TopTerms) Parse(params map[string]interface{}) error {
 	t.TermsCount = termsCount
 	return nil
 }
+
+// Schema returns the JSON Schema of the parameters of the tile.
+func (t *TopTerms) Schema() *schema.Schema {
+	return schema.Object(map[string]*schema.Schema{
+		"termsField": schema.String("The field of the terms of each document."),
+		"termsCount": schema.Integer("The number of most occurring terms.").WithMinimum(0),
+	}, "termsField", "termsCount")
+}
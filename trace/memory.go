@@ -0,0 +1,54 @@
+package trace
+
+import (
+	"sync"
+)
+
+// MemoryExporter represents an exporter that holds finished spans in memory,
+// useful for testing.
+type MemoryExporter struct {
+	mutex sync.Mutex
+	spans []*SpanData
+}
+
+// NewMemoryExporter instantiates and returns a new in-memory exporter.
+func NewMemoryExporter() *MemoryExporter {
+	return &MemoryExporter{}
+}
+
+// ExportSpan records the finished span.
+func (e *MemoryExporter) ExportSpan(span *SpanData) {
+	e.mutex.Lock()
+	e.spans = append(e.spans, span)
+	e.mutex.Unlock()
+}
+
+// GetSpans returns the finished spans in the order they ended. If any names
+// are provided, only the spans with those names are returned.
+func (e *MemoryExporter) GetSpans(names ...string) []*SpanData {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	res := make([]*SpanData, 0, len(e.spans))
+	for _, span := range e.spans {
+		if len(names) == 0 || contains(names, span.Name) {
+			res = append(res, span)
+		}
+	}
+	return res
+}
+
+// Reset removes all recorded spans.
+func (e *MemoryExporter) Reset() {
+	e.mutex.Lock()
+	e.spans = nil
+	e.mutex.Unlock()
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package trace
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	// TraceParentHeader is the W3C trace context header, propagated to HTTP
+	// backends and as an AMQP message header.
+	TraceParentHeader = "traceparent"
+
+	traceParentVersion = "00"
+	sampledFlag        = 0x01
+)
+
+// Format formats the span context as a W3C `traceparent` value.
+//
+// Ex:
+//     00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+//
+func Format(sc SpanContext) string {
+	flags := 0
+	if sc.Sampled {
+		flags = sampledFlag
+	}
+	return fmt.Sprintf("%s-%s-%s-%02x", traceParentVersion, sc.TraceID, sc.SpanID, flags)
+}
+
+// Parse parses a W3C `traceparent` value into a span context.
+func Parse(value string) (SpanContext, error) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 {
+		return SpanContext{}, fmt.Errorf("`%s` is not a valid traceparent", value)
+	}
+	if len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == traceParentVersion && len(parts) != 4) {
+		return SpanContext{}, fmt.Errorf("`%s` is not a supported traceparent version", value)
+	}
+	sc := SpanContext{}
+	err := decodeHex(sc.TraceID[:], parts[1])
+	if err != nil {
+		return SpanContext{}, fmt.Errorf("`%s` is not a valid trace ID", parts[1])
+	}
+	err = decodeHex(sc.SpanID[:], parts[2])
+	if err != nil {
+		return SpanContext{}, fmt.Errorf("`%s` is not a valid span ID", parts[2])
+	}
+	var flags [1]byte
+	err = decodeHex(flags[:], parts[3])
+	if err != nil {
+		return SpanContext{}, fmt.Errorf("`%s` is not a valid trace flag", parts[3])
+	}
+	if !sc.IsValid() {
+		return SpanContext{}, fmt.Errorf("`%s` contains an all zero ID", value)
+	}
+	sc.Sampled = flags[0]&sampledFlag != 0
+	return sc, nil
+}
+
+func decodeHex(dst []byte, str string) error {
+	if len(str) != len(dst)*2 || strings.ToLower(str) != str {
+		return fmt.Errorf("invalid length or case")
+	}
+	_, err := hex.Decode(dst, []byte(str))
+	return err
+}
+
+// Inject sets the `traceparent` header of the span context held by the
+// context, if any.
+func Inject(ctx context.Context, header http.Header) {
+	sc, ok := SpanContextFromContext(ctx)
+	if ok {
+		header.Set(TraceParentHeader, Format(sc))
+	}
+}
+
+// Extract returns a context holding the span context of the `traceparent`
+// header, if present and valid.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	return extract(ctx, header.Get(TraceParentHeader))
+}
+
+// InjectTable sets the `traceparent` entry of a message header table, such as
+// an `amqp.Table`, from the span context held by the context, if any.
+func InjectTable(ctx context.Context, table map[string]interface{}) {
+	sc, ok := SpanContextFromContext(ctx)
+	if ok {
+		table[TraceParentHeader] = Format(sc)
+	}
+}
+
+// ExtractTable returns a context holding the span context of the
+// `traceparent` entry of a message header table, if present and valid.
+func ExtractTable(ctx context.Context, table map[string]interface{}) context.Context {
+	value, _ := table[TraceParentHeader].(string)
+	return extract(ctx, value)
+}
+
+func extract(ctx context.Context, value string) context.Context {
+	if value == "" {
+		return ctx
+	}
+	sc, err := Parse(value)
+	if err != nil {
+		// invalid trace context is ignored, as per the W3C specification
+		return ctx
+	}
+	return ContextWithRemoteSpanContext(ctx, sc)
+}
+
+// Transport represents an http.RoundTripper that injects the trace context of
+// each request's context into its headers.
+type Transport struct {
+	Base http.RoundTripper
+}
+
+// NewTransport instantiates and returns a new transport wrapping the provided
+// round tripper. If nil, the default transport is used.
+func NewTransport(base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{
+		Base: base,
+	}
+}
+
+// RoundTrip injects the trace context and executes the request.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if _, ok := SpanContextFromContext(req.Context()); ok {
+		// round trippers must not modify the provided request
+		clone := *req
+		clone.Header = make(http.Header, len(req.Header)+1)
+		for key, values := range req.Header {
+			clone.Header[key] = values
+		}
+		Inject(req.Context(), clone.Header)
+		req = &clone
+	}
+	return t.Base.RoundTrip(req)
+}
@@ -0,0 +1,271 @@
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// The spans recorded by veldt.
+const (
+	// RequestSpan is a request received from a caller, spanning its
+	// validation and generation.
+	RequestSpan = "veldt.request"
+	// GenerateSpan is the generation and retrieval of a request's data.
+	GenerateSpan = "veldt.generate"
+	// GetSpan is the retrieval of a request's data.
+	GetSpan = "veldt.get"
+	// ValidateSpan is the validation of a request.
+	ValidateSpan = "veldt.validate"
+	// PromiseSpan is the wait on the in-flight generation of an identical
+	// request.
+	PromiseSpan = "veldt.promise"
+	// QueueSpan is the wait of a request in the pipeline queue.
+	QueueSpan = "veldt.queue"
+	// CreateSpan is the creation of a tile or metadata.
+	CreateSpan = "veldt.create"
+	// CompressSpan is the compression of generated data.
+	CompressSpan = "veldt.compress"
+	// StoreSpan is a store operation.
+	StoreSpan = "veldt.store"
+)
+
+// The attributes recorded by veldt.
+const (
+	// PipelineAttribute is the ID the pipeline is registered under.
+	PipelineAttribute = "veldt.pipeline"
+	// TypeAttribute is the type of the tile or metadata.
+	TypeAttribute = "veldt.type"
+	// BackendAttribute is the generation backend of the tile or metadata,
+	// such as `elastic`.
+	BackendAttribute = "veldt.backend"
+	// OperationAttribute is the store operation.
+	OperationAttribute = "veldt.operation"
+	// HitAttribute is whether the data was found in the store.
+	HitAttribute = "veldt.hit"
+	// URIAttribute is the URI of the request.
+	URIAttribute = "veldt.uri"
+)
+
+var (
+	mutex    = sync.RWMutex{}
+	exporter Exporter
+)
+
+type spanKey struct{}
+
+type remoteKey struct{}
+
+// TraceID represents the ID of a trace.
+type TraceID [16]byte
+
+// String returns the hex encoding of the ID.
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// SpanID represents the ID of a span.
+type SpanID [8]byte
+
+// String returns the hex encoding of the ID.
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// SpanContext represents the identity of a span that is propagated to its
+// children, including across process boundaries.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+// IsValid returns whether the trace and span IDs are set.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != TraceID{} && sc.SpanID != SpanID{}
+}
+
+// SpanData represents a finished span as passed to an exporter.
+type SpanData struct {
+	Name       string
+	Context    SpanContext
+	ParentID   SpanID
+	Start      time.Time
+	End        time.Time
+	Attributes map[string]interface{}
+	Error      error
+}
+
+// Duration returns the duration of the span.
+func (s *SpanData) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// Exporter represents an interface for exporting finished spans.
+type Exporter interface {
+	ExportSpan(span *SpanData)
+}
+
+// SetExporter sets the exporter finished spans are sent to. A nil exporter
+// disables recording, although trace context received from callers is still
+// propagated to the backends.
+func SetExporter(e Exporter) {
+	mutex.Lock()
+	exporter = e
+	mutex.Unlock()
+}
+
+func getExporter() Exporter {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return exporter
+}
+
+// Span represents a single timed operation within a trace. All methods are
+// safe to call on a nil span, which is returned when recording is disabled.
+type Span struct {
+	mutex    sync.Mutex
+	data     *SpanData
+	exporter Exporter
+	ended    bool
+}
+
+// Start starts a span as a child of the span or remote span context held by
+// the context, and returns a context holding the new span. If there is no
+// parent, the span starts a new trace. Spans of traces the caller has not
+// sampled are not recorded. The span must be ended by the caller.
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	e := getExporter()
+	if e == nil {
+		return ctx, nil
+	}
+	parent, ok := SpanContextFromContext(ctx)
+	if ok && !parent.Sampled {
+		return ctx, nil
+	}
+	sc := SpanContext{
+		TraceID: parent.TraceID,
+		SpanID:  newSpanID(),
+		Sampled: true,
+	}
+	if !ok {
+		sc.TraceID = newTraceID()
+	}
+	span := &Span{
+		data: &SpanData{
+			Name:       name,
+			Context:    sc,
+			ParentID:   parent.SpanID,
+			Start:      time.Now(),
+			Attributes: make(map[string]interface{}),
+		},
+		exporter: e,
+	}
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// FromContext returns the span held by the context, or nil if there is none.
+func FromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// ContextWithRemoteSpanContext returns a context holding the span context
+// received from a caller, such that spans started from it continue the
+// caller's trace.
+func ContextWithRemoteSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, remoteKey{}, sc)
+}
+
+// SpanContextFromContext returns the span context of the span held by the
+// context, falling back to a remote span context received from a caller.
+func SpanContextFromContext(ctx context.Context) (SpanContext, bool) {
+	span := FromContext(ctx)
+	if span != nil {
+		return span.SpanContext(), true
+	}
+	sc, ok := ctx.Value(remoteKey{}).(SpanContext)
+	if ok && sc.IsValid() {
+		return sc, true
+	}
+	return SpanContext{}, false
+}
+
+// Detach returns a background context that carries the trace of the provided
+// context, but not its cancellation or deadline. It is used for work that
+// outlives the request which initiated it.
+func Detach(ctx context.Context) context.Context {
+	detached := context.Background()
+	span := FromContext(ctx)
+	if span != nil {
+		detached = context.WithValue(detached, spanKey{}, span)
+	}
+	sc, ok := ctx.Value(remoteKey{}).(SpanContext)
+	if ok {
+		detached = context.WithValue(detached, remoteKey{}, sc)
+	}
+	return detached
+}
+
+// SpanContext returns the span context of the span.
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.data.Context
+}
+
+// SetAttribute sets an attribute on the span.
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	if !s.ended {
+		s.data.Attributes[key] = value
+	}
+	s.mutex.Unlock()
+}
+
+// SetError records the error on the span. A nil error is ignored.
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mutex.Lock()
+	if !s.ended {
+		s.data.Error = err
+	}
+	s.mutex.Unlock()
+}
+
+// End ends the span and sends it to the exporter. Subsequent calls have no
+// effect.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	if s.ended {
+		s.mutex.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+	s.mutex.Unlock()
+	s.exporter.ExportSpan(s.data)
+}
+
+func newTraceID() TraceID {
+	var id TraceID
+	rand.Read(id[:])
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	rand.Read(id[:])
+	return id
+}
@@ -0,0 +1,159 @@
+package trace_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/unchartedsoftware/veldt/trace"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("trace", func() {
+
+	var exporter *trace.MemoryExporter
+	traceParent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	BeforeEach(func() {
+		exporter = trace.NewMemoryExporter()
+		trace.SetExporter(exporter)
+	})
+
+	AfterEach(func() {
+		trace.SetExporter(nil)
+	})
+
+	Describe("Start", func() {
+		It("should record spans as children of the span held by the context", func() {
+			ctx, parent := trace.Start(context.Background(), "parent")
+			_, child := trace.Start(ctx, "child")
+			child.SetAttribute("key", "value")
+			child.SetError(fmt.Errorf("failed"))
+			child.End()
+			parent.End()
+			spans := exporter.GetSpans()
+			Expect(len(spans)).To(Equal(2))
+			Expect(spans[0].Name).To(Equal("child"))
+			Expect(spans[0].Context.TraceID).To(Equal(spans[1].Context.TraceID))
+			Expect(spans[0].ParentID).To(Equal(spans[1].Context.SpanID))
+			Expect(spans[0].Attributes["key"]).To(Equal("value"))
+			Expect(spans[0].Error).To(Equal(fmt.Errorf("failed")))
+			Expect(spans[1].ParentID).To(Equal(trace.SpanID{}))
+		})
+
+		It("should only export a span once", func() {
+			_, span := trace.Start(context.Background(), "span")
+			span.End()
+			span.End()
+			Expect(len(exporter.GetSpans())).To(Equal(1))
+		})
+
+		It("should return a nil span if there is no exporter", func() {
+			trace.SetExporter(nil)
+			ctx, span := trace.Start(context.Background(), "span")
+			Expect(span).To(BeNil())
+			Expect(trace.FromContext(ctx)).To(BeNil())
+			// nil spans are safe to use
+			span.SetAttribute("key", "value")
+			span.SetError(fmt.Errorf("failed"))
+			span.End()
+		})
+	})
+
+	Describe("Detach", func() {
+		It("should carry the trace but not the cancellation", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			ctx, span := trace.Start(ctx, "span")
+			detached := trace.Detach(ctx)
+			cancel()
+			Expect(detached.Err()).To(BeNil())
+			Expect(trace.FromContext(detached)).To(Equal(span))
+		})
+	})
+
+	Describe("Parse", func() {
+		It("should parse a W3C traceparent", func() {
+			sc, err := trace.Parse(traceParent)
+			Expect(err).To(BeNil())
+			Expect(sc.TraceID.String()).To(Equal("4bf92f3577b34da6a3ce929d0e0e4736"))
+			Expect(sc.SpanID.String()).To(Equal("00f067aa0ba902b7"))
+			Expect(sc.Sampled).To(BeTrue())
+			Expect(trace.Format(sc)).To(Equal(traceParent))
+		})
+
+		It("should return an error for an invalid traceparent", func() {
+			invalid := []string{
+				"",
+				"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+				"00-4bf92f3577b34da6a3ce929d0e0e473-00f067aa0ba902b7-01",
+				"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+				"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+				"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-00",
+				"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			}
+			for _, value := range invalid {
+				_, err := trace.Parse(value)
+				Expect(err).NotTo(BeNil())
+			}
+		})
+	})
+
+	Describe("Inject / Extract", func() {
+		It("should continue the caller's trace", func() {
+			header := http.Header{}
+			header.Set(trace.TraceParentHeader, traceParent)
+			ctx := trace.Extract(context.Background(), header)
+			ctx, span := trace.Start(ctx, "span")
+			span.End()
+			Expect(span.SpanContext().TraceID.String()).To(Equal("4bf92f3577b34da6a3ce929d0e0e4736"))
+			Expect(exporter.GetSpans()[0].ParentID.String()).To(Equal("00f067aa0ba902b7"))
+			table := make(map[string]interface{})
+			trace.InjectTable(ctx, table)
+			Expect(table[trace.TraceParentHeader]).To(Equal(trace.Format(span.SpanContext())))
+			Expect(trace.ExtractTable(context.Background(), table)).NotTo(Equal(context.Background()))
+		})
+
+		It("should propagate the caller's trace when not recording", func() {
+			trace.SetExporter(nil)
+			header := http.Header{}
+			header.Set(trace.TraceParentHeader, traceParent)
+			ctx := trace.Extract(context.Background(), header)
+			out := http.Header{}
+			trace.Inject(ctx, out)
+			Expect(out.Get(trace.TraceParentHeader)).To(Equal(traceParent))
+		})
+
+		It("should ignore an invalid traceparent", func() {
+			header := http.Header{}
+			header.Set(trace.TraceParentHeader, "invalid")
+			ctx := trace.Extract(context.Background(), header)
+			_, ok := trace.SpanContextFromContext(ctx)
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("Transport", func() {
+		It("should inject the trace context into requests", func() {
+			received := make(chan string, 1)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received <- r.Header.Get(trace.TraceParentHeader)
+			}))
+			defer server.Close()
+			ctx, span := trace.Start(context.Background(), "span")
+			defer span.End()
+			req, err := http.NewRequest("GET", server.URL, nil)
+			Expect(err).To(BeNil())
+			client := &http.Client{
+				Transport: trace.NewTransport(nil),
+			}
+			res, err := client.Do(req.WithContext(ctx))
+			Expect(err).To(BeNil())
+			res.Body.Close()
+			Expect(<-received).To(Equal(trace.Format(span.SpanContext())))
+			Expect(req.Header.Get(trace.TraceParentHeader)).To(Equal(""))
+		})
+	})
+})
@@ -2,6 +2,7 @@ package json
 
 import (
 	"encoding/json"
+	"sort"
 )
 
 // Get returns an interface{} under the given path.
@@ -280,6 +281,63 @@ func GetChildMap(json map[string]interface{}, path ...string) (map[string]map[st
 	return children, true
 }
 
+// SortArray replaces the array under the given key with a copy sorted by the
+// JSON encoding of its elements. It is used to normalize arrays whose order
+// is insignificant, and does nothing if there is no array under the key.
+func SortArray(json map[string]interface{}, path ...string) {
+	if len(path) == 0 {
+		return
+	}
+	parent := json
+	if len(path) > 1 {
+		child, ok := GetChild(json, path[:len(path)-1]...)
+		if !ok {
+			return
+		}
+		parent = child
+	}
+	key := path[len(path)-1]
+	arr, ok := parent[key].([]interface{})
+	if !ok {
+		return
+	}
+	sorted := make(encodedArray, len(arr))
+	for i, val := range arr {
+		encoded, err := Marshal(val)
+		if err != nil {
+			// leave arrays of values that cannot be encoded as is
+			return
+		}
+		sorted[i] = encodedValue{
+			value:   val,
+			encoded: string(encoded),
+		}
+	}
+	sort.Stable(sorted)
+	res := make([]interface{}, len(sorted))
+	for i, val := range sorted {
+		res[i] = val.value
+	}
+	parent[key] = res
+}
+
+type encodedValue struct {
+	value   interface{}
+	encoded string
+}
+
+type encodedArray []encodedValue
+
+func (a encodedArray) Len() int {
+	return len(a)
+}
+func (a encodedArray) Swap(i, j int) {
+	a[i], a[j] = a[j], a[i]
+}
+func (a encodedArray) Less(i, j int) bool {
+	return a[i].encoded < a[j].encoded
+}
+
 // Marshal marhsals JSON into a byte slice, convenience wrapper for the native
 // package so no need to import both and get a name collision.
 func Marshal(j interface{}) ([]byte, error) {
@@ -623,4 +623,33 @@ var _ = Describe("json", func() {
 		})
 	})
 
+	Describe("SortArray", func() {
+		It("should sort the array by the JSON encoding of its elements", func() {
+			j := JSON(
+				`{
+					"test": {
+						"array": ["b", 1, "a", true]
+					}
+				}`)
+			original, _ := json.GetArray(j, "test", "array")
+			json.SortArray(j, "test", "array")
+			val, ok := json.GetArray(j, "test", "array")
+			Expect(ok).To(Equal(true))
+			Expect(val).To(Equal([]interface{}{"a", "b", 1.0, true}))
+			// the original array is not modified
+			Expect(original).To(Equal([]interface{}{"b", 1.0, "a", true}))
+		})
+		It("should do nothing if there is no array in the provided path", func() {
+			j := JSON(
+				`{
+					"test": {
+						"array": "a"
+					}
+				}`)
+			json.SortArray(j, "test", "array")
+			json.SortArray(j, "missing", "array")
+			Expect(j["test"]).To(Equal(map[string]interface{}{"array": "a"}))
+		})
+	})
+
 })
@@ -62,3 +62,14 @@ func (m *Map) Remove(key string) {
 	m.mutex.Unlock()
 	runtime.Gosched()
 }
+
+// CompareAndRemove will remove a promise from the map only if it is the
+// promise currently stored under the provided key.
+func (m *Map) CompareAndRemove(key string, p *Promise) {
+	m.mutex.Lock()
+	if m.promises[key] == p {
+		delete(m.promises, key)
+	}
+	m.mutex.Unlock()
+	runtime.Gosched()
+}
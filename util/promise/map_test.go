@@ -160,4 +160,24 @@ var _ = Describe("map", func() {
 		})
 	})
 
+	Describe("CompareAndRemove", func() {
+		It("should remove the promise if it matches", func() {
+			m := promise.NewMap()
+			p := promise.NewPromise()
+			m.Set("test", p)
+			m.CompareAndRemove("test", p)
+			_, ok := m.Get("test")
+			Expect(ok).To(Equal(false))
+		})
+		It("should not remove the promise if it does not match", func() {
+			m := promise.NewMap()
+			p := promise.NewPromise()
+			m.Set("test", p)
+			m.CompareAndRemove("test", promise.NewPromise())
+			o, ok := m.Get("test")
+			Expect(ok).To(Equal(true))
+			Expect(o).To(Equal(p))
+		})
+	})
+
 })
@@ -1,18 +1,26 @@
 package promise
 
 import (
+	"context"
+	"errors"
 	"runtime"
 	"sync"
 )
 
+// ErrAbandoned is returned when waiting on a promise that every previous
+// waiter has abandoned.
+var ErrAbandoned = errors.New("promise was abandoned")
+
 // Promise represents a channel that will be shared by a variable number of
 // users.
 type Promise struct {
-	Chan     chan error
-	count    int
-	resolved bool
-	response error
-	mutex    sync.Mutex
+	Chan      chan error
+	count     int
+	resolved  bool
+	response  error
+	abandoned bool
+	onAbandon func()
+	mutex     sync.Mutex
 }
 
 // NewPromise instantiates and returns a new promise.
@@ -27,9 +35,13 @@ func NewPromise() *Promise {
 }
 
 // Wait returns a channel that the response will be passed once the promise is
-// resolved.
+// resolved. If the promise has been abandoned, ErrAbandoned is returned.
 func (p *Promise) Wait() error {
 	p.mutex.Lock()
+	if p.abandoned {
+		p.mutex.Unlock()
+		return ErrAbandoned
+	}
 	if p.resolved {
 		p.mutex.Unlock()
 		runtime.Gosched()
@@ -41,17 +53,74 @@ func (p *Promise) Wait() error {
 	return <-p.Chan
 }
 
+// WaitContext blocks until the promise is resolved or the provided context is
+// done, whichever comes first. If the context is done, the context error is
+// returned. If a function is registered with OnAbandon, it is called once
+// every waiter has abandoned the unresolved promise, and any later waiter
+// receives ErrAbandoned rather than joining the abandoned work.
+func (p *Promise) WaitContext(ctx context.Context) error {
+	p.mutex.Lock()
+	if p.abandoned {
+		p.mutex.Unlock()
+		return ErrAbandoned
+	}
+	if p.resolved {
+		p.mutex.Unlock()
+		runtime.Gosched()
+		return p.response
+	}
+	p.count++
+	p.mutex.Unlock()
+	runtime.Gosched()
+	select {
+	case res := <-p.Chan:
+		return res
+	case <-ctx.Done():
+		p.mutex.Lock()
+		if p.resolved {
+			// resolution has already accounted for this waiter, so the
+			// response must still be consumed
+			p.mutex.Unlock()
+			<-p.Chan
+			return ctx.Err()
+		}
+		p.count--
+		var onAbandon func()
+		if p.count == 0 && p.onAbandon != nil {
+			p.abandoned = true
+			onAbandon = p.onAbandon
+		}
+		p.mutex.Unlock()
+		if onAbandon != nil {
+			onAbandon()
+		}
+		return ctx.Err()
+	}
+}
+
+// OnAbandon registers a function to be called if every waiter abandons the
+// promise before it is resolved.
+func (p *Promise) OnAbandon(fn func()) {
+	p.mutex.Lock()
+	p.onAbandon = fn
+	p.mutex.Unlock()
+}
+
 // Resolve waits the response and sends it to all clients waiting on the channel.
 func (p *Promise) Resolve(res error) {
 	p.mutex.Lock()
-	defer runtime.Gosched()
-	defer p.mutex.Unlock()
 	if p.resolved {
+		p.mutex.Unlock()
+		runtime.Gosched()
 		return
 	}
 	p.resolved = true
 	p.response = res
-	for i := 0; i < p.count; i++ {
+	count := p.count
+	p.mutex.Unlock()
+	// send outside of the lock so that abandoning waiters are not blocked
+	for i := 0; i < count; i++ {
 		p.Chan <- res
 	}
+	runtime.Gosched()
 }
@@ -1,6 +1,7 @@
 package promise_test
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
@@ -77,4 +78,95 @@ var _ = Describe("promise", func() {
 		})
 	})
 
+	Describe("WaitContext", func() {
+		It("should block until promise is resolved", func() {
+			p := promise.NewPromise()
+			err := fmt.Errorf("error")
+			go func() {
+				time.Sleep(time.Millisecond * 100)
+				p.Resolve(err)
+			}()
+			Expect(p.WaitContext(context.Background())).To(Equal(err))
+		})
+		It("should return the context error if cancelled before resolution", func() {
+			p := promise.NewPromise()
+			ctx, cancel := context.WithCancel(context.Background())
+			go func() {
+				time.Sleep(time.Millisecond * 100)
+				cancel()
+			}()
+			Expect(p.WaitContext(ctx)).To(Equal(context.Canceled))
+			// resolution must not block on the departed waiter
+			p.Resolve(nil)
+			Expect(p.Wait()).To(BeNil())
+		})
+		It("should call the abandon function once all waiters have left", func() {
+			p := promise.NewPromise()
+			abandoned := make(chan bool, 1)
+			p.OnAbandon(func() {
+				abandoned <- true
+			})
+			ctx0, cancel0 := context.WithCancel(context.Background())
+			ctx1, cancel1 := context.WithCancel(context.Background())
+			wg := sync.WaitGroup{}
+			wg.Add(2)
+			go func() {
+				Expect(p.WaitContext(ctx0)).To(Equal(context.Canceled))
+				wg.Done()
+			}()
+			go func() {
+				Expect(p.WaitContext(ctx1)).To(Equal(context.Canceled))
+				wg.Done()
+			}()
+			time.Sleep(time.Millisecond * 100)
+			cancel0()
+			time.Sleep(time.Millisecond * 100)
+			Expect(len(abandoned)).To(Equal(0))
+			cancel1()
+			wg.Wait()
+			Expect(len(abandoned)).To(Equal(1))
+		})
+		It("should return ErrAbandoned to waiters after the promise was abandoned", func() {
+			p := promise.NewPromise()
+			p.OnAbandon(func() {})
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			Expect(p.WaitContext(ctx)).To(Equal(context.Canceled))
+			Expect(p.WaitContext(context.Background())).To(Equal(promise.ErrAbandoned))
+			Expect(p.Wait()).To(Equal(promise.ErrAbandoned))
+		})
+		It("should not abandon a promise without an abandon function", func() {
+			p := promise.NewPromise()
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			Expect(p.WaitContext(ctx)).To(Equal(context.Canceled))
+			p.Resolve(nil)
+			Expect(p.WaitContext(context.Background())).To(BeNil())
+		})
+		It("should not call the abandon function while other waiters remain", func() {
+			p := promise.NewPromise()
+			abandoned := false
+			p.OnAbandon(func() {
+				abandoned = true
+			})
+			ctx, cancel := context.WithCancel(context.Background())
+			wg := sync.WaitGroup{}
+			wg.Add(2)
+			go func() {
+				Expect(p.WaitContext(ctx)).To(Equal(context.Canceled))
+				wg.Done()
+			}()
+			go func() {
+				Expect(p.Wait()).To(BeNil())
+				wg.Done()
+			}()
+			time.Sleep(time.Millisecond * 100)
+			cancel()
+			time.Sleep(time.Millisecond * 100)
+			p.Resolve(nil)
+			wg.Wait()
+			Expect(abandoned).To(BeFalse())
+		})
+	})
+
 })
@@ -0,0 +1,160 @@
+package queue
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/unchartedsoftware/veldt/resilience"
+)
+
+const (
+	// defaultBackoff is the factor the limit is multiplied by when the
+	// backend is congested.
+	defaultBackoff = 0.75
+	// defaultTolerance is the latency, relative to the baseline, above which
+	// the backend is considered congested.
+	defaultTolerance = 2.0
+	// baselineWindow is the number of samples over which the minimum
+	// latency is tracked. The baseline is the minimum of the current and
+	// previous windows, such that it adapts as the backend changes.
+	baselineWindow = 100
+)
+
+// Limiter represents an adaptive concurrency limit using additive increase,
+// multiplicative decrease (AIMD). Each request completing within the latency
+// tolerance increases the limit by one over the current limit, raising it by
+// one once a full limit of requests has completed. Requests that fail, or
+// complete slower than the tolerance allows, decrease it by the backoff
+// factor. Permanent errors, such as malformed queries, are answered by the
+// backend, so only their latency is judged. Only requests started after the
+// last decrease may decrease it again, such that one congestion event is not
+// counted once per in-flight request.
+//
+// Latency is judged against a baseline, the minimum latency observed
+// recently, or against a fixed threshold if one is set.
+type Limiter struct {
+	mu           sync.Mutex
+	min          int
+	max          int
+	limit        float64
+	backoff      float64
+	tolerance    float64
+	threshold    time.Duration
+	lastDecrease time.Time
+	samples      int
+	windowMin    time.Duration
+	previousMin  time.Duration
+	baseline     time.Duration
+}
+
+// NewLimiter instantiates and returns a new limiter, starting at the maximum
+// and adjusting within the provided bounds.
+func NewLimiter(min int, max int) *Limiter {
+	if min < 1 {
+		min = 1
+	}
+	if max < min {
+		max = min
+	}
+	return &Limiter{
+		min:       min,
+		max:       max,
+		limit:     float64(max),
+		backoff:   defaultBackoff,
+		tolerance: defaultTolerance,
+	}
+}
+
+// SetBackoff sets the factor, between zero and one, the limit is multiplied by
+// on congestion.
+func (l *Limiter) SetBackoff(backoff float64) {
+	l.mu.Lock()
+	l.backoff = backoff
+	l.mu.Unlock()
+}
+
+// SetTolerance sets the latency, relative to the baseline, above which the
+// backend is considered congested.
+func (l *Limiter) SetTolerance(tolerance float64) {
+	l.mu.Lock()
+	l.tolerance = tolerance
+	l.mu.Unlock()
+}
+
+// SetThreshold sets a fixed latency above which the backend is considered
+// congested, in place of the baseline. A threshold of zero restores the
+// baseline.
+func (l *Limiter) SetThreshold(threshold time.Duration) {
+	l.mu.Lock()
+	l.threshold = threshold
+	l.mu.Unlock()
+}
+
+// Limit returns the current concurrency limit.
+func (l *Limiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.getLimit()
+}
+
+// Observe records the outcome of a request started at the provided time, and
+// returns the updated limit.
+func (l *Limiter) Observe(start time.Time, latency time.Duration, err error) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var congested bool
+	switch {
+	case err == nil:
+		l.observeBaseline(latency)
+		congested = l.isSlow(latency)
+	case resilience.IsPermanent(err):
+		// permanent errors may be answered faster than any real request, so
+		// they are judged against the baseline without adding to it
+		congested = l.isSlow(latency)
+	default:
+		congested = true
+	}
+	if congested {
+		if start.After(l.lastDecrease) {
+			l.limit = math.Max(float64(l.min), l.limit*l.backoff)
+			l.lastDecrease = time.Now()
+		}
+	} else {
+		l.limit = math.Min(float64(l.max), l.limit+1/l.limit)
+	}
+	return l.getLimit()
+}
+
+// observeBaseline adds the latency to the baseline.
+func (l *Limiter) observeBaseline(latency time.Duration) {
+	if l.samples == 0 || latency < l.windowMin {
+		l.windowMin = latency
+	}
+	l.samples++
+	l.baseline = l.windowMin
+	if l.previousMin > 0 && l.previousMin < l.baseline {
+		l.baseline = l.previousMin
+	}
+	if l.samples == baselineWindow {
+		l.previousMin = l.windowMin
+		l.samples = 0
+	}
+}
+
+// isSlow returns whether the latency exceeds the threshold if set, or the
+// tolerance of the baseline otherwise. Without a baseline, no latency is
+// slow.
+func (l *Limiter) isSlow(latency time.Duration) bool {
+	if l.threshold > 0 {
+		return latency > l.threshold
+	}
+	if l.baseline == 0 {
+		return false
+	}
+	return float64(latency) > float64(l.baseline)*l.tolerance
+}
+
+func (l *Limiter) getLimit() int {
+	return int(l.limit)
+}
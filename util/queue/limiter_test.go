@@ -0,0 +1,138 @@
+package queue_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/unchartedsoftware/veldt/resilience"
+	"github.com/unchartedsoftware/veldt/util/queue"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Limiter", func() {
+
+	var l *queue.Limiter
+
+	BeforeEach(func() {
+		l = queue.NewLimiter(2, 16)
+	})
+
+	// observe observes n requests of the provided latency, each started
+	// after the last
+	observe := func(n int, latency time.Duration, err error) int {
+		limit := l.Limit()
+		for i := 0; i < n; i++ {
+			limit = l.Observe(time.Now().Add(time.Second), latency, err)
+		}
+		return limit
+	}
+
+	It("should start at the maximum", func() {
+		Expect(l.Limit()).To(Equal(16))
+	})
+
+	It("should decrease the limit on errors", func() {
+		limit := observe(1, time.Millisecond, fmt.Errorf("failed"))
+		Expect(limit).To(Equal(12))
+	})
+
+	It("should not decrease the limit on permanent errors", func() {
+		observe(10, time.Millisecond*10, nil)
+		limit := observe(20, time.Millisecond*10, resilience.Permanent(fmt.Errorf("bad request")))
+		Expect(limit).To(Equal(16))
+	})
+
+	It("should decrease the limit when permanent errors exceed the tolerance", func() {
+		observe(10, time.Millisecond*10, nil)
+		limit := observe(1, time.Millisecond*50, resilience.Permanent(fmt.Errorf("bad request")))
+		Expect(limit).To(Equal(12))
+	})
+
+	It("should not lower the baseline with permanent errors", func() {
+		observe(10, time.Millisecond*10, nil)
+		observe(10, time.Millisecond, resilience.Permanent(fmt.Errorf("bad request")))
+		limit := observe(1, time.Millisecond*15, nil)
+		Expect(limit).To(Equal(16))
+	})
+
+	It("should decrease the limit when latency exceeds the tolerance", func() {
+		observe(10, time.Millisecond*10, nil)
+		Expect(l.Limit()).To(Equal(16))
+		limit := observe(1, time.Millisecond*50, nil)
+		Expect(limit).To(Equal(12))
+	})
+
+	It("should not decrease below the minimum", func() {
+		limit := observe(20, time.Millisecond, fmt.Errorf("failed"))
+		Expect(limit).To(Equal(2))
+	})
+
+	It("should decrease once for requests started before the last decrease", func() {
+		start := time.Now().Add(-time.Minute)
+		l.Observe(time.Now().Add(time.Second), time.Millisecond, fmt.Errorf("failed"))
+		limit := l.Observe(start, time.Millisecond, fmt.Errorf("failed"))
+		Expect(limit).To(Equal(12))
+	})
+
+	It("should increase the limit additively while latency is tolerable", func() {
+		l.SetBackoff(0.5)
+		observe(1, time.Millisecond, fmt.Errorf("failed"))
+		Expect(l.Limit()).To(Equal(8))
+		// roughly one increment per full limit of requests
+		limit := observe(8, time.Millisecond, nil)
+		Expect(limit).To(Equal(8))
+		limit = observe(1, time.Millisecond, nil)
+		Expect(limit).To(Equal(9))
+	})
+
+	It("should not increase above the maximum", func() {
+		limit := observe(100, time.Millisecond, nil)
+		Expect(limit).To(Equal(16))
+	})
+
+	It("should decrease the limit once when latency spikes", func() {
+		l.SetThreshold(time.Millisecond * 50)
+		observe(10, time.Millisecond*10, nil)
+		Expect(observe(1, time.Millisecond*100, nil)).To(Equal(12))
+		Expect(observe(1, time.Millisecond*10, nil)).To(Equal(12))
+	})
+
+	It("should judge latency against a fixed threshold if set", func() {
+		l.SetThreshold(time.Millisecond * 100)
+		observe(10, time.Millisecond*10, nil)
+		Expect(observe(1, time.Millisecond*90, nil)).To(Equal(16))
+		Expect(observe(1, time.Millisecond*110, nil)).To(Equal(12))
+	})
+
+})
+
+type permanentRequest struct{}
+
+func (r *permanentRequest) Create() ([]byte, error) {
+	return nil, resilience.Permanent(fmt.Errorf("bad request"))
+}
+
+var _ = Describe("Queue with limiter", func() {
+
+	It("should adapt the maximum concurrent requests", func() {
+		q := queue.NewQueue()
+		q.SetLimiter(queue.NewLimiter(1, 8))
+		Expect(q.GetMaxConcurrent()).To(Equal(8))
+		_, err := q.Send(&failingRequest{})
+		Expect(err).NotTo(BeNil())
+		Expect(q.GetMaxConcurrent()).To(Equal(6))
+	})
+
+	It("should not adapt the maximum concurrent requests to permanent errors", func() {
+		q := queue.NewQueue()
+		q.SetLimiter(queue.NewLimiter(1, 8))
+		for i := 0; i < 10; i++ {
+			_, err := q.Send(&permanentRequest{})
+			Expect(err).NotTo(BeNil())
+		}
+		Expect(q.GetMaxConcurrent()).To(Equal(8))
+	})
+
+})
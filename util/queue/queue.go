@@ -1,91 +1,314 @@
 package queue
 
 import (
+	"context"
 	"fmt"
 	"runtime"
 	"sync"
+	"time"
+
+	"github.com/unchartedsoftware/veldt/metrics"
+	"github.com/unchartedsoftware/veldt/trace"
+)
+
+// Priority represents the scheduling class of a request.
+type Priority int
+
+const (
+	// Interactive requests are made on behalf of a user waiting on the
+	// result, such as a map viewport. They are dispatched before any
+	// background request, newest first, as older requests are the most
+	// likely to have been panned away from.
+	Interactive Priority = iota
+	// Background requests are bulk work such as seeding. They are dispatched
+	// oldest first, only once no interactive request is waiting.
+	Background
+	numPriorities
 )
 
+// String returns the name of the priority.
+func (p Priority) String() string {
+	switch p {
+	case Interactive:
+		return "interactive"
+	case Background:
+		return "background"
+	}
+	return fmt.Sprintf("priority(%d)", int(p))
+}
+
 // Request represents a basic request interface.
 type Request interface {
 	Create() ([]byte, error)
 }
 
+// ContextRequest represents a request that can be cancelled through a context.
+type ContextRequest interface {
+	Request
+	CreateContext(context.Context) ([]byte, error)
+}
+
+// ScheduledRequest represents a request with a scheduling priority and the
+// client it is made on behalf of. Requests of the same priority are shared
+// between clients by weighted round robin, such that one heavy client cannot
+// starve the others. Requests that do not implement the interface are
+// interactive requests of an anonymous client.
+type ScheduledRequest interface {
+	Request
+	GetPriority() Priority
+	GetClient() string
+}
+
+// FullError is returned when a request is sent to a queue that has reached
+// its maximum length.
+type FullError struct {
+	Length int
+}
+
+// Error returns the error message.
+func (e *FullError) Error() string {
+	return fmt.Sprintf("queue has reached maximum length of %d and is no longer accepting requests",
+		e.Length)
+}
+
+// ExpiredError is returned when a request waits in the queue for longer than
+// its maximum wait.
+type ExpiredError struct {
+	Wait time.Duration
+}
+
+// Error returns the error message.
+func (e *ExpiredError) Error() string {
+	return fmt.Sprintf("request expired after waiting %v in queue", e.Wait)
+}
+
+// waiter represents a request waiting in the queue.
+type waiter struct {
+	ready      chan struct{}
+	client     *client
+	dispatched bool
+}
+
+// client represents the waiting requests of a single client within a
+// priority class.
+type client struct {
+	id      string
+	waiters []*waiter
+	served  int
+}
+
+// class represents the waiting requests of a priority class, served by
+// weighted round robin across its clients.
+type class struct {
+	lifo    bool
+	clients map[string]*client
+	ring    []*client
+	next    int
+}
+
+func newClass(lifo bool) *class {
+	return &class{
+		lifo:    lifo,
+		clients: make(map[string]*client),
+	}
+}
+
+func (c *class) push(id string) *waiter {
+	cl, ok := c.clients[id]
+	if !ok {
+		cl = &client{
+			id: id,
+		}
+		c.clients[id] = cl
+		c.ring = append(c.ring, cl)
+	}
+	w := &waiter{
+		ready:  make(chan struct{}),
+		client: cl,
+	}
+	cl.waiters = append(cl.waiters, w)
+	return w
+}
+
+func (c *class) pop(weights map[string]int) *waiter {
+	if len(c.ring) == 0 {
+		return nil
+	}
+	if c.next >= len(c.ring) {
+		c.next = 0
+	}
+	cl := c.ring[c.next]
+	var w *waiter
+	if c.lifo {
+		w = cl.waiters[len(cl.waiters)-1]
+		cl.waiters = cl.waiters[:len(cl.waiters)-1]
+	} else {
+		w = cl.waiters[0]
+		cl.waiters = cl.waiters[1:]
+	}
+	cl.served++
+	if len(cl.waiters) == 0 {
+		// remove the client from the ring, the next client takes its place
+		c.removeClient(c.next)
+	} else if cl.served >= getWeight(weights, cl.id) {
+		// the client has used its share, move on to the next
+		cl.served = 0
+		c.next++
+	}
+	return w
+}
+
+func (c *class) remove(w *waiter) {
+	cl := w.client
+	for i, other := range cl.waiters {
+		if other == w {
+			cl.waiters = append(cl.waiters[:i], cl.waiters[i+1:]...)
+			break
+		}
+	}
+	if len(cl.waiters) > 0 {
+		return
+	}
+	for i, other := range c.ring {
+		if other == cl {
+			c.removeClient(i)
+			if i < c.next {
+				c.next--
+			}
+			return
+		}
+	}
+}
+
+func (c *class) removeClient(index int) {
+	delete(c.clients, c.ring[index].id)
+	c.ring = append(c.ring[:index], c.ring[index+1:]...)
+}
+
+func getWeight(weights map[string]int, id string) int {
+	weight, ok := weights[id]
+	if !ok || weight < 1 {
+		return 1
+	}
+	return weight
+}
+
 // Queue represents a queue for orchestating concurrent requests.
 type Queue struct {
-	ready      chan bool
+	classes    [numPriorities]*class
+	weights    map[string]int
+	running    int
 	pending    int
 	mu         *sync.Mutex
 	maxPending int
 	maxLength  int
+	maxWait    time.Duration
+	limiter    *Limiter
+	labels     metrics.Labels
 }
 
 // NewQueue instantiates and returns a new queue struct.
 func NewQueue() *Queue {
 	q := &Queue{
-		ready:      make(chan bool),
+		weights:    make(map[string]int),
 		mu:         &sync.Mutex{},
 		maxPending: 32,
 		maxLength:  256 * 8,
 	}
-	// store in intermediate here in case max is change before the following
-	// loop completes
-	currentMax := q.maxPending
-	go func() {
-		// send as many ready messages as there are expected listeners
-		for i := 0; i < currentMax; i++ {
-			q.ready <- true
-		}
-	}()
+	q.classes[Interactive] = newClass(true)
+	q.classes[Background] = newClass(false)
 	return q
 }
 
 // Send will put the request on the queue and send it when ready.
 func (q *Queue) Send(req Request) ([]byte, error) {
-	// increment the q.pending query count
-	err := q.incrementPending()
+	// wait until the request is dispatched
+	err := q.acquire(context.Background(), req)
 	if err != nil {
 		return nil, err
 	}
-	// wait until equalizer is ready
-	<-q.ready
 	// dispatch the query
+	start := time.Now()
 	res, err := req.Create()
-	// decrement the q.pending count
-	q.decrementPending()
-	go func() {
-		// inform Queue that it is ready to generate another tile
-		q.ready <- true
-	}()
+	// inform the queue that it is ready to generate another tile
+	q.release(start, err)
+	return res, err
+}
+
+// SendContext will put the request on the queue and send it when ready. If the
+// context is done before the request leaves the queue, it is removed and the
+// context error is returned. Requests implementing ContextRequest are passed
+// the context during creation. The time spent waiting in the queue is traced
+// as part of the context's trace.
+func (q *Queue) SendContext(ctx context.Context, req Request) ([]byte, error) {
+	_, span := trace.Start(ctx, trace.QueueSpan)
+	// wait until the request is dispatched or the context is done
+	err := q.acquire(ctx, req)
+	span.SetError(err)
+	span.End()
+	if err != nil {
+		return nil, err
+	}
+	// dispatch the query
+	start := time.Now()
+	var res []byte
+	creq, ok := req.(ContextRequest)
+	if ok {
+		res, err = creq.CreateContext(ctx)
+	} else {
+		res, err = req.Create()
+	}
+	if ctx.Err() != nil {
+		// abandoned requests say nothing of the backend
+		q.release(time.Time{}, nil)
+		return res, err
+	}
+	// inform the queue that it is ready to generate another tile
+	q.release(start, err)
 	return res, err
 }
 
 // SetMaxConcurrent sets the maximum concurrent pending requests for the queue.
+// The maximum is overridden by the limiter, if set.
 func (q *Queue) SetMaxConcurrent(max int) {
 	q.mu.Lock()
-	diff := max - q.maxPending
-	q.maxPending = max
-	q.mu.Unlock()
-	if diff > 0 {
-		// add ready instances to the chan
-		go func() {
-			// send as many ready messages as there are expected listeners
-			for i := 0; i < diff; i++ {
-				q.ready <- true
-			}
-		}()
-	} else {
-		// remove ready instances from the chan
-		go func() {
-			// send as many ready messages as there are expected listeners
-			for i := diff; i < 0; i++ {
-				<-q.ready
-			}
-		}()
+	if q.limiter == nil {
+		q.maxPending = max
 	}
+	// requests already running above a lowered max are left to complete
+	q.dispatch()
+	q.mu.Unlock()
 	runtime.Gosched()
 }
 
+// GetMaxConcurrent returns the current maximum concurrent pending requests
+// for the queue.
+func (q *Queue) GetMaxConcurrent() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.maxPending
+}
+
+// SetLimiter sets the limiter adapting the maximum concurrent pending
+// requests of the queue to the latency and errors of the requests it
+// dispatches. A nil limiter leaves the current maximum in place.
+func (q *Queue) SetLimiter(limiter *Limiter) {
+	q.mu.Lock()
+	q.limiter = limiter
+	if limiter != nil {
+		q.setMaxPending(limiter.Limit())
+	}
+	q.dispatch()
+	q.mu.Unlock()
+}
+
+// SetLabels sets the labels of the metrics recorded by the queue.
+func (q *Queue) SetLabels(labels metrics.Labels) {
+	q.mu.Lock()
+	q.labels = labels
+	q.mu.Unlock()
+}
+
 // SetLength sets the maximum length of the queue.
 func (q *Queue) SetLength(length int) {
 	q.mu.Lock()
@@ -94,22 +317,131 @@ func (q *Queue) SetLength(length int) {
 	runtime.Gosched()
 }
 
-func (q *Queue) incrementPending() error {
+// SetMaxWait sets the maximum duration a request may wait in the queue before
+// it expires, returning an ExpiredError. A duration of zero disables expiry.
+func (q *Queue) SetMaxWait(wait time.Duration) {
 	q.mu.Lock()
-	defer runtime.Gosched()
-	defer q.mu.Unlock()
+	q.maxWait = wait
+	q.mu.Unlock()
+}
+
+// SetWeight sets the weight of the client, which is the number of its
+// requests dispatched in turn before moving on to the next client of the same
+// priority. Clients have a weight of one by default.
+func (q *Queue) SetWeight(client string, weight int) {
+	q.mu.Lock()
+	q.weights[client] = weight
+	q.mu.Unlock()
+}
+
+// acquire waits until the request is dispatched, the context is done, or the
+// request expires.
+func (q *Queue) acquire(ctx context.Context, req Request) error {
+	priority, id := getSchedule(req)
+	q.mu.Lock()
+	// increment the q.pending query count
 	if q.pending-q.maxPending > q.maxLength {
-		return fmt.Errorf("queue has reached maximum length of %d and is no longer accepting requests",
-			q.maxLength)
+		metrics.Inc(metrics.QueueRejections, q.labels)
+		q.mu.Unlock()
+		return &FullError{
+			Length: q.maxLength,
+		}
 	}
-	// increment count
 	q.pending++
-	return nil
+	metrics.Set(metrics.QueueDepth, q.labels, float64(q.pending))
+	w := q.classes[priority].push(id)
+	q.dispatch()
+	maxWait := q.maxWait
+	q.mu.Unlock()
+	runtime.Gosched()
+	// expire the request if it waits too long
+	var expired <-chan time.Time
+	if maxWait > 0 {
+		timer := time.NewTimer(maxWait)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	var err error
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-expired:
+		metrics.Inc(metrics.QueueExpirations, q.labels)
+		err = &ExpiredError{
+			Wait: maxWait,
+		}
+	}
+	q.mu.Lock()
+	if w.dispatched {
+		// dispatched concurrently, hand the slot on
+		q.running--
+	} else {
+		q.classes[priority].remove(w)
+	}
+	q.pending--
+	metrics.Set(metrics.QueueDepth, q.labels, float64(q.pending))
+	q.dispatch()
+	q.mu.Unlock()
+	return err
 }
 
-func (q *Queue) decrementPending() {
+// release frees the slot of a request dispatched at the provided time,
+// adapting the maximum concurrent requests to its outcome if the queue has a
+// limiter. A zero time is not observed.
+func (q *Queue) release(start time.Time, err error) {
 	q.mu.Lock()
+	if q.limiter != nil && !start.IsZero() {
+		q.setMaxPending(q.limiter.Observe(start, time.Since(start), err))
+	}
+	q.running--
 	q.pending--
+	metrics.Set(metrics.QueueDepth, q.labels, float64(q.pending))
+	q.dispatch()
 	q.mu.Unlock()
 	runtime.Gosched()
 }
+
+// setMaxPending sets the maximum concurrent requests and records it. Must be
+// called with the lock held.
+func (q *Queue) setMaxPending(max int) {
+	q.maxPending = max
+	metrics.Set(metrics.ConcurrencyLimit, q.labels, float64(max))
+}
+
+// dispatch dispatches waiting requests while there is availability, in order
+// of priority. Must be called with the lock held.
+func (q *Queue) dispatch() {
+	for q.running < q.maxPending {
+		w := q.next()
+		if w == nil {
+			return
+		}
+		w.dispatched = true
+		q.running++
+		close(w.ready)
+	}
+}
+
+func (q *Queue) next() *waiter {
+	for _, c := range q.classes {
+		w := c.pop(q.weights)
+		if w != nil {
+			return w
+		}
+	}
+	return nil
+}
+
+func getSchedule(req Request) (Priority, string) {
+	sreq, ok := req.(ScheduledRequest)
+	if !ok {
+		return Interactive, ""
+	}
+	priority := sreq.GetPriority()
+	if priority < 0 || priority >= numPriorities {
+		priority = Background
+	}
+	return priority, sreq.GetClient()
+}
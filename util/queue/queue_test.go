@@ -1,7 +1,10 @@
 package queue_test
 
 import (
+	"context"
+	"fmt"
 	"sync"
+	"time"
 
 	"github.com/unchartedsoftware/veldt/util/queue"
 
@@ -58,6 +61,59 @@ func (r *pauseRequest) Unpause() {
 	r.c <- true
 }
 
+type blockingRequest struct {
+	c chan bool
+}
+
+func newBlockingRequest() *blockingRequest {
+	return &blockingRequest{
+		c: make(chan bool),
+	}
+}
+
+func (r *blockingRequest) Create() ([]byte, error) {
+	<-r.c
+	return nil, nil
+}
+
+func (r *blockingRequest) CreateContext(ctx context.Context) ([]byte, error) {
+	select {
+	case <-r.c:
+		return nil, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+type failingRequest struct{}
+
+func (r *failingRequest) Create() ([]byte, error) {
+	return nil, fmt.Errorf("failed")
+}
+
+type orderRequest struct {
+	name     string
+	priority queue.Priority
+	client   string
+	order    *[]string
+	mu       *sync.Mutex
+}
+
+func (r *orderRequest) Create() ([]byte, error) {
+	r.mu.Lock()
+	*r.order = append(*r.order, r.name)
+	r.mu.Unlock()
+	return nil, nil
+}
+
+func (r *orderRequest) GetPriority() queue.Priority {
+	return r.priority
+}
+
+func (r *orderRequest) GetClient() string {
+	return r.client
+}
+
 var _ = Describe("Queue", func() {
 
 	var q *queue.Queue
@@ -131,6 +187,153 @@ var _ = Describe("Queue", func() {
 
 	})
 
+	Describe("SendContext", func() {
+
+		It("should execute the requests when there is availability", func() {
+			req := newTestRequest()
+			for i := 0; i < m; i++ {
+				_, err := q.SendContext(context.Background(), req)
+				Expect(err).To(BeNil())
+			}
+			count := req.Count()
+			Expect(count).To(Equal(m))
+		})
+
+		It("should pass the context to requests that accept one", func() {
+			req := newBlockingRequest()
+			ctx, cancel := context.WithCancel(context.Background())
+			go func() {
+				time.Sleep(time.Millisecond * 100)
+				cancel()
+			}()
+			_, err := q.SendContext(ctx, req)
+			Expect(err).To(Equal(context.Canceled))
+		})
+
+		It("should remove requests from the queue if the context is done", func() {
+			q.SetMaxConcurrent(1)
+			blocking := newPauseRequest()
+			go func() {
+				q.Send(blocking)
+			}()
+			time.Sleep(time.Millisecond * 100)
+			ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+			defer cancel()
+			req := newTestRequest()
+			_, err := q.SendContext(ctx, req)
+			Expect(err).To(Equal(context.DeadlineExceeded))
+			Expect(req.Count()).To(Equal(0))
+			blocking.Unpause()
+			_, err = q.SendContext(context.Background(), req)
+			Expect(err).To(BeNil())
+			Expect(req.Count()).To(Equal(1))
+		})
+
+	})
+
+	Describe("Scheduling", func() {
+
+		var order []string
+		var mu *sync.Mutex
+		var blocking *pauseRequest
+		var wg *sync.WaitGroup
+
+		// enqueue sends the request once the previous have been queued
+		enqueue := func(name string, priority queue.Priority, client string) {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				_, err := q.Send(&orderRequest{
+					name:     name,
+					priority: priority,
+					client:   client,
+					order:    &order,
+					mu:       mu,
+				})
+				Expect(err).To(BeNil())
+				wg.Done()
+			}()
+			time.Sleep(time.Millisecond * 20)
+		}
+
+		// run releases the blocking request and returns the dispatch order
+		run := func() []string {
+			blocking.Unpause()
+			wg.Wait()
+			mu.Lock()
+			defer mu.Unlock()
+			return order
+		}
+
+		BeforeEach(func() {
+			order = nil
+			mu = &sync.Mutex{}
+			wg = &sync.WaitGroup{}
+			q.SetMaxConcurrent(1)
+			blocking = newPauseRequest()
+			go func() {
+				q.Send(blocking)
+			}()
+			time.Sleep(time.Millisecond * 20)
+		})
+
+		It("should dispatch interactive requests before background requests", func() {
+			enqueue("b0", queue.Background, "")
+			enqueue("i0", queue.Interactive, "")
+			enqueue("b1", queue.Background, "")
+			Expect(run()).To(Equal([]string{"i0", "b0", "b1"}))
+		})
+
+		It("should dispatch the newest interactive request first", func() {
+			enqueue("i0", queue.Interactive, "")
+			enqueue("i1", queue.Interactive, "")
+			enqueue("i2", queue.Interactive, "")
+			Expect(run()).To(Equal([]string{"i2", "i1", "i0"}))
+		})
+
+		It("should share the queue between clients by round robin", func() {
+			enqueue("a0", queue.Background, "a")
+			enqueue("a1", queue.Background, "a")
+			enqueue("a2", queue.Background, "a")
+			enqueue("b0", queue.Background, "b")
+			enqueue("b1", queue.Background, "b")
+			Expect(run()).To(Equal([]string{"a0", "b0", "a1", "b1", "a2"}))
+		})
+
+		It("should weight the share of each client", func() {
+			q.SetWeight("a", 2)
+			enqueue("a0", queue.Background, "a")
+			enqueue("a1", queue.Background, "a")
+			enqueue("a2", queue.Background, "a")
+			enqueue("b0", queue.Background, "b")
+			enqueue("b1", queue.Background, "b")
+			Expect(run()).To(Equal([]string{"a0", "a1", "b0", "a2", "b1"}))
+		})
+
+	})
+
+	Describe("SetMaxWait", func() {
+
+		It("should expire requests that wait longer than the maximum", func() {
+			q.SetMaxConcurrent(1)
+			q.SetMaxWait(time.Millisecond * 100)
+			blocking := newPauseRequest()
+			go func() {
+				q.Send(blocking)
+			}()
+			time.Sleep(time.Millisecond * 20)
+			req := newTestRequest()
+			_, err := q.Send(req)
+			Expect(err).To(BeAssignableToTypeOf(&queue.ExpiredError{}))
+			Expect(req.Count()).To(Equal(0))
+			blocking.Unpause()
+			_, err = q.Send(req)
+			Expect(err).To(BeNil())
+			Expect(req.Count()).To(Equal(1))
+		})
+
+	})
+
 	Describe("SetLength", func() {
 
 		It("should set the queue length, returning an error when surpassed", func() {
@@ -150,6 +353,7 @@ var _ = Describe("Queue", func() {
 					reqs[index] = req
 					_, err := q.Send(req)
 					if err != nil {
+						Expect(err).To(BeAssignableToTypeOf(&queue.FullError{}))
 						mu.Lock()
 						errCount++
 						reqs[index] = nil
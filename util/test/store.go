@@ -0,0 +1,127 @@
+package test
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/unchartedsoftware/veldt"
+)
+
+// MapStore represents an in-memory store backed by a map, which is safe for
+// concurrent use.
+// NOTE: for use only in unit tests.
+type MapStore struct {
+	mu     sync.Mutex
+	data   map[string][]byte
+	closed bool
+}
+
+// NewMapStore instantiates and returns a new empty store.
+func NewMapStore() *MapStore {
+	return &MapStore{
+		data: make(map[string][]byte),
+	}
+}
+
+// Ctor returns a store constructor that always returns this store.
+func (s *MapStore) Ctor() veldt.StoreCtor {
+	return func() (veldt.Store, error) {
+		return s, nil
+	}
+}
+
+// Get gets a value from the store, returning an error if it does not exist.
+func (s *MapStore) Get(key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.data[key]
+	if !ok {
+		return nil, fmt.Errorf("key `%s` does not exist", key)
+	}
+	return value, nil
+}
+
+// Set sets a value in the store.
+func (s *MapStore) Set(key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[key] = value
+	return nil
+}
+
+// SetExpiry sets a value in the store, ignoring the expiry.
+func (s *MapStore) SetExpiry(key string, value []byte, expirySeconds int) error {
+	return s.Set(key, value)
+}
+
+// Exists returns whether or not the key exists in the store.
+func (s *MapStore) Exists(key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.data[key]
+	return ok, nil
+}
+
+// MGet gets multiple values from the store, with nil for keys that do not
+// exist.
+func (s *MapStore) MGet(keys []string) ([][]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		values[i] = s.data[key]
+	}
+	return values, nil
+}
+
+// MSet sets multiple values in the store.
+func (s *MapStore) MSet(values map[string][]byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, value := range values {
+		s.data[key] = value
+	}
+	return nil
+}
+
+// Delete removes a key from the store.
+func (s *MapStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, key)
+	return nil
+}
+
+// DeleteByPrefix removes every key with the provided prefix from the store.
+func (s *MapStore) DeleteByPrefix(prefix string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key := range s.data {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.data, key)
+		}
+	}
+	return nil
+}
+
+// Len returns the number of keys in the store.
+func (s *MapStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.data)
+}
+
+// Close records that the store has been closed. The store remains usable.
+func (s *MapStore) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+}
+
+// Closed returns whether or not the store has been closed.
+func (s *MapStore) Closed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
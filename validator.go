@@ -2,8 +2,11 @@ package veldt
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/unchartedsoftware/veldt/binning"
+	"github.com/unchartedsoftware/veldt/filter"
 	"github.com/unchartedsoftware/veldt/util/json"
 )
 
@@ -17,19 +20,52 @@ const (
 )
 
 // validator parses a JSON query expression into its typed format. It
-// ensure all types are correct and that the syntax is valid.
+// ensure all types are correct and that the syntax is valid. The parameters
+// of each parsed type are normalized in place, and the identities of the
+// types are recorded, to produce the hash of the request.
 type validator struct {
 	json.Validator
-	pipeline *Pipeline
+	pipeline   *Pipeline
+	identities map[string]string
 }
 
 func newValidator(pipeline *Pipeline) *validator {
 	v := &validator{
-		pipeline: pipeline,
+		pipeline:   pipeline,
+		identities: make(map[string]string),
 	}
 	return v
 }
 
+// addIdentity records the identity of the type registered under the kind and
+// ID as used by the request.
+func (v *validator) addIdentity(kind string, id string) {
+	key := getIdentityKey(kind, id)
+	v.identities[key] = v.pipeline.identities[key]
+}
+
+// getHash returns the hash of the validated request from its normalized
+// parameters under the provided keys, and the identities of its types.
+func (v *validator) getHash(args map[string]interface{}, keys ...string) (string, error) {
+	params := make(map[string]interface{})
+	for _, key := range keys {
+		val, ok := args[key]
+		if ok {
+			params[key] = val
+		}
+	}
+	// map keys are sorted when marshalled
+	bytes, err := json.Marshal(params)
+	if err != nil {
+		return "", err
+	}
+	identities, err := json.Marshal(v.identities)
+	if err != nil {
+		return "", err
+	}
+	return getDigest(string(bytes), string(identities)), nil
+}
+
 func (v *validator) validateTileRequest(args map[string]interface{}) (*TileRequest, error) {
 
 	v.StartObject()
@@ -48,6 +84,9 @@ func (v *validator) validateTileRequest(args map[string]interface{}) (*TileReque
 	// validate query
 	req.Query = v.validateQuery(args)
 
+	// validate render
+	req.Render = v.validateRender(args)
+
 	v.EndObject()
 
 	// check for any errors
@@ -55,6 +94,12 @@ func (v *validator) validateTileRequest(args map[string]interface{}) (*TileReque
 	if err != nil {
 		return nil, err
 	}
+
+	// hash the request
+	req.hash, err = v.getHash(args, "tile", "query", "render")
+	if err != nil {
+		return nil, err
+	}
 	return req, nil
 }
 
@@ -77,9 +122,55 @@ func (v *validator) validateMetaRequest(args map[string]interface{}) (*MetaReque
 	if err != nil {
 		return nil, err
 	}
+
+	// hash the request
+	req.hash, err = v.getHash(args, "meta")
+	if err != nil {
+		return nil, err
+	}
 	return req, nil
 }
 
+// bufferParams buffers the parameters of the type registered under the kind
+// and ID. If the type declares a schema, the parameters are validated against
+// it and buffered key by key, annotating every invalid parameter rather than
+// only the first error returned when parsing them.
+func (v *validator) bufferParams(kind string, id string, params interface{}, err error) {
+	s := v.pipeline.schemas[getIdentityKey(kind, id)]
+	obj, ok := params.(map[string]interface{})
+	if s == nil || !ok {
+		v.BufferKeyValue(id, params, err)
+		return
+	}
+	errs := s.Validate(obj)
+	if errs == nil {
+		// the schema cannot express every constraint, such as mutually
+		// exclusive parameters, so fall back to the error of the parse
+		v.BufferKeyValue(id, params, err)
+		return
+	}
+	// buffer the provided parameters along with any that are missing
+	keys := make([]string, 0, len(obj)+len(errs))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	for key := range errs {
+		if _, ok := obj[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	v.StartSubObject(id)
+	for _, key := range keys {
+		val, ok := obj[key]
+		if !ok {
+			val = missing
+		}
+		v.BufferKeyValue(key, val, errs[key])
+	}
+	v.EndObject()
+}
+
 // Parses the tile request JSON for the provided URI.
 //
 // Ex:
@@ -192,6 +283,8 @@ func (v *validator) parseTile(args map[string]interface{}) (string, interface{},
 	if err != nil {
 		return id, params, nil, err
 	}
+	normalize(tile, params)
+	v.addIdentity(tileKind, id)
 	return id, params, tile, nil
 }
 
@@ -217,7 +310,7 @@ func (v *validator) validateTile(args map[string]interface{}) Tile {
 		id = missing
 		params = missing
 	}
-	v.BufferKeyValue(id, params, err)
+	v.bufferParams(tileKind, id, params, err)
 	v.EndObject()
 	return tile
 }
@@ -240,6 +333,8 @@ func (v *validator) parseMeta(args map[string]interface{}) (string, interface{},
 	if err != nil {
 		return id, params, nil, err
 	}
+	normalize(tile, params)
+	v.addIdentity(metaKind, id)
 	return id, params, tile, nil
 }
 
@@ -265,11 +360,63 @@ func (v *validator) validateMeta(args map[string]interface{}) Meta {
 		id = missing
 		params = missing
 	}
-	v.BufferKeyValue(id, params, err)
+	v.bufferParams(metaKind, id, params, err)
 	v.EndObject()
 	return meta
 }
 
+// Parses the tile request JSON for the optional render type and parameters.
+//
+// Ex:
+//     {
+//         "render": {
+//             "png": {
+//                  "ramp": "viridis",
+//                  "transform": "log"
+//             }
+//         }
+//     }
+//
+func (v *validator) parseRender(args map[string]interface{}) (string, interface{}, Renderer, error) {
+	id, params, ok := json.GetRandomChild(args)
+	if !ok {
+		return id, params, nil, fmt.Errorf("no render type found")
+	}
+	renderer, err := v.pipeline.GetRenderer(id, params)
+	if err != nil {
+		return id, params, nil, err
+	}
+	normalize(renderer, params)
+	v.addIdentity(renderKind, id)
+	return id, params, renderer, nil
+}
+
+func (v *validator) validateRender(args map[string]interface{}) Renderer {
+	// render is optional
+	arg, ok := args["render"]
+	if !ok {
+		return nil
+	}
+
+	// check if the render value is an object
+	val, ok := arg.(map[string]interface{})
+	if !ok {
+		v.BufferKeyValue("render", arg, fmt.Errorf("`render` is not of correct type"))
+		return nil
+	}
+
+	// check if render is correct
+	v.StartSubObject("render")
+	id, params, renderer, err := v.parseRender(val)
+	if id == "" {
+		id = missing
+		params = missing
+	}
+	v.bufferParams(renderKind, id, params, err)
+	v.EndObject()
+	return renderer
+}
+
 func (v *validator) validateQuery(args map[string]interface{}) Query {
 	val, ok := args["query"]
 	if !ok {
@@ -279,10 +426,28 @@ func (v *validator) validateQuery(args map[string]interface{}) Query {
 	if val == nil {
 		return nil
 	}
+	// compile a filter expression into its JSON form, such that it is
+	// validated and hashed the same as the equivalent JSON expression
+	str, ok := val.(string)
+	if ok {
+		compiled, err := filter.Compile(str)
+		if err != nil {
+			v.BufferKeyValue("query", str, err)
+			return nil
+		}
+		args["query"] = compiled
+		val = compiled
+	}
 	// validate the query
 	v.StartObject()
 	validated := v.validateToken(val, true)
 	v.EndObject()
+	if !v.HasError() {
+		validated = v.normalizeQuery(args, validated)
+		if validated == nil {
+			return nil
+		}
+	}
 	// parse the expression
 	query, err := newExpressionParser(v.pipeline).Parse(validated)
 	if err != nil {
@@ -291,6 +456,56 @@ func (v *validator) validateQuery(args map[string]interface{}) Query {
 	return query
 }
 
+// normalizeQuery rewrites the validated query of the request into its normal
+// form, such that logically equivalent queries are parsed into the same
+// expression and share a hash. The validated tokens of the normalized query
+// are returned, or nil if every document matches it. If the normalized query
+// does not validate, such as when it uses a query type that is not
+// registered, the original query is retained.
+func (v *validator) normalizeQuery(args map[string]interface{}, validated interface{}) interface{} {
+	node, err := filter.FromJSON(args["query"])
+	if err != nil {
+		return validated
+	}
+	normalized := filter.Normalize(node)
+	nv := newValidator(v.pipeline)
+	var query interface{}
+	var tokens interface{}
+	if normalized != nil {
+		query = filter.ToJSON(normalized)
+		nv.StartObject()
+		tokens = nv.validateToken(query, true)
+		nv.EndObject()
+		if nv.HasError() {
+			return validated
+		}
+	}
+	// replace the identities of the original query with the normalized
+	for key := range v.identities {
+		if isQueryIdentity(key) {
+			delete(v.identities, key)
+		}
+	}
+	for key, identity := range nv.identities {
+		v.identities[key] = identity
+	}
+	if normalized == nil {
+		delete(args, "query")
+		return nil
+	}
+	args["query"] = query
+	return tokens
+}
+
+func isQueryIdentity(key string) bool {
+	for _, kind := range []string{queryKind, binaryKind, unaryKind} {
+		if strings.HasPrefix(key, getIdentityKey(kind, "")) {
+			return true
+		}
+	}
+	return false
+}
+
 // Parses the query request JSON for the provided query expression.
 //
 // Ex:
@@ -310,6 +525,8 @@ func (v *validator) parseQuery(args map[string]interface{}) (string, interface{}
 	if err != nil {
 		return id, params, nil, err
 	}
+	normalize(query, params)
+	v.addIdentity(queryKind, id)
 	return id, params, query, nil
 }
 
@@ -321,10 +538,10 @@ func (v *validator) validateQueryToken(args map[string]interface{}, first bool)
 	}
 	if first {
 		v.StartSubObject("query")
-		v.BufferKeyValue(id, params, err)
+		v.bufferParams(queryKind, id, params, err)
 		v.EndObject()
 	} else {
-		v.BufferKeyValue(id, params, err)
+		v.bufferParams(queryKind, id, params, err)
 	}
 	return query
 }
@@ -346,6 +563,11 @@ func (v *validator) validateOperatorToken(op string) interface{} {
 		v.BufferValue(op, fmt.Errorf("invalid operator"))
 		return nil
 	}
+	if isValidBinaryOperator(op) {
+		v.addIdentity(binaryKind, "")
+	} else {
+		v.addIdentity(unaryKind, "")
+	}
 	v.BufferValue(op, nil)
 	return op
 }
@@ -359,6 +581,9 @@ func (v *validator) validateExpressionToken(exp []interface{}, first bool) inter
 	}
 	// track last token to ensure next is valid
 	var last interface{}
+	// the parsed tokens are returned in a new array, leaving the JSON intact
+	// to be hashed
+	validated := make([]interface{}, len(exp))
 	// for each component
 	for i, current := range exp {
 		// next line
@@ -366,15 +591,16 @@ func (v *validator) validateExpressionToken(exp []interface{}, first bool) inter
 			v.StartError("unexpected token")
 			v.validateToken(current, false)
 			v.EndError()
+			validated[i] = current
 			last = current
 			continue
 		}
-		exp[i] = v.validateToken(current, false)
+		validated[i] = v.validateToken(current, false)
 		last = current
 	}
 	// close paren
 	v.EndArray()
-	return exp
+	return validated
 }
 
 func (v *validator) validateToken(arg interface{}, first bool) interface{} {